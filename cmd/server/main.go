@@ -1,13 +1,16 @@
 package main
 
 import (
+	"context"
 	"log"
 	"payroll-system/internal/config"
 	httpRoutes "payroll-system/internal/delivery/http"
 	"payroll-system/internal/delivery/http/handler"
+	"payroll-system/internal/domain"
 	"payroll-system/internal/repository/postgres"
 	admin_service "payroll-system/internal/service/admin"
 	employee_service "payroll-system/internal/service/employee"
+	"payroll-system/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,6 +26,32 @@ func main() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	roundingPolicies, err := _config.ParseRoundingPolicies()
+	if err != nil {
+		log.Fatalf("Invalid ROUNDING_POLICIES: %v", err)
+	}
+	rounding, err := domain.NewRoundingPolicies(_config.Currency, roundingPolicies...)
+	if err != nil {
+		log.Fatalf("Invalid ROUNDING_POLICIES: %v", err)
+	}
+	smtpConfig, mailEnabled, err := _config.ParseSMTPConfig()
+	if err != nil {
+		log.Fatalf("Invalid SMTP settings: %v", err)
+	}
+	var mailer admin_service.Mailer
+	if mailEnabled {
+		mailer = utils.NewSMTPMailer(smtpConfig)
+	} else {
+		log.Println("SMTP_HOST is not set, payslips will not be emailed")
+	}
+
+	payslipKeySealer, err := utils.NewKeySealer(_config.PayslipKeySecret)
+	if err != nil {
+		log.Fatalf("Invalid PAYSLIP_KEY_SECRET: %v", err)
+	}
+
+	withholder := domain.TaxWithholder{Name: _config.EmployerName, NPWP: _config.EmployerNPWP}
+
 	pool := config.InitDB(_config.DBUrl)
 	defer pool.Close()
 
@@ -34,9 +63,81 @@ func main() {
 	attendanceRepo := postgres.NewAttendanceRepository(pool)
 	overtimeRepo := postgres.NewOvertimeRepository(pool)
 	reimbursementRepo := postgres.NewReimbursementRepository(pool)
+	payRuleRepo := postgres.NewPayRuleRepository(pool)
+	taxProfileRepo := postgres.NewTaxProfileRepository(pool)
+	bpjsRepo := postgres.NewBPJSRepository(pool)
+	payrollJobRepo := postgres.NewPayrollJobRepository(pool)
+	holidayRepo := postgres.NewHolidayRepository(pool)
+	salaryRepo := postgres.NewSalaryRepository(pool)
+	payComponentRepo := postgres.NewPayComponentRepository(pool)
+	loanRepo := postgres.NewLoanRepository(pool)
+	bonusRepo := postgres.NewBonusRepository(pool)
+	taxStatementRepo := postgres.NewTaxStatementRepository(pool)
+	glAccountRepo := postgres.NewGLAccountRepository(pool)
+	bankAccountRepo := postgres.NewBankAccountRepository(pool)
+	disbursementRepo := postgres.NewDisbursementRepository(pool)
+	payslipTemplateRepo := postgres.NewPayslipTemplateRepository(pool)
+	payslipEmailRepo := postgres.NewPayslipEmailRepository(pool)
+	payslipPasswordRepo := postgres.NewPayslipPasswordRepository(pool, payslipKeySealer)
 
-	adminService := admin_service.NewAdminService(adminRepo, employeeRepo, payrollRepo, attendanceRepo, overtimeRepo, reimbursementRepo)
-	empService := employee_service.NewEmployeeService(employeeRepo, payrollRepo, attendanceRepo, overtimeRepo, reimbursementRepo)
+	adminService := admin_service.NewAdminService(admin_service.AdminDependencies{
+		AdminRepository:           adminRepo,
+		EmployeeRepository:        employeeRepo,
+		PayrollRepository:         payrollRepo,
+		AttendanceRepository:      attendanceRepo,
+		OvertimeRepository:        overtimeRepo,
+		ReimbursementRepository:   reimbursementRepo,
+		PayRuleRepository:         payRuleRepo,
+		TaxProfileRepository:      taxProfileRepo,
+		BPJSRepository:            bpjsRepo,
+		PayrollJobRepository:      payrollJobRepo,
+		HolidayRepository:         holidayRepo,
+		SalaryRepository:          salaryRepo,
+		PayComponentRepository:    payComponentRepo,
+		LoanRepository:            loanRepo,
+		BonusRepository:           bonusRepo,
+		TaxStatementRepository:    taxStatementRepo,
+		GLAccountRepository:       glAccountRepo,
+		BankAccountRepository:     bankAccountRepo,
+		DisbursementRepository:    disbursementRepo,
+		PayslipTemplateRepository: payslipTemplateRepo,
+		PayslipEmailRepository:    payslipEmailRepo,
+		PayslipPasswordRepository: payslipPasswordRepo,
+		Mailer:                    mailer,
+		PayslipEmailSettings: domain.PayslipEmailSettings{
+			SendOnLock:        _config.PayslipEmailOnLock,
+			ProtectAttachment: _config.PayslipEmailProtect,
+		},
+		TaxWithholder:    withholder,
+		RoundingPolicies: rounding,
+		DisbursementSource: domain.DisbursementSource{
+			Name:          _config.EmployerName,
+			BankCode:      _config.DisbursementBankCode,
+			AccountNumber: _config.DisbursementAccountNumber,
+		},
+	})
+	empService := employee_service.NewEmployeeService(employee_service.EmployeeDependencies{
+		EmployeeRepository:        employeeRepo,
+		PayrollRepository:         payrollRepo,
+		AttendanceRepository:      attendanceRepo,
+		OvertimeRepository:        overtimeRepo,
+		ReimbursementRepository:   reimbursementRepo,
+		HolidayRepository:         holidayRepo,
+		LoanRepository:            loanRepo,
+		BonusRepository:           bonusRepo,
+		TaxStatementRepository:    taxStatementRepo,
+		PayslipTemplateRepository: payslipTemplateRepo,
+		PayslipPasswordRepository: payslipPasswordRepo,
+		TaxWithholder:             withholder,
+		RoundingPolicies:          rounding,
+	})
+
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+	go adminService.RunPayrollJobWorker(workerCtx)
+	if mailEnabled {
+		go adminService.RunPayslipEmailWorker(workerCtx)
+	}
 
 	adminHandler := handler.NewAdminHandler(adminService, empService)
 	employeeHandler := handler.NewEmployeeHandler(empService)
@@ -0,0 +1,3 @@
+-- 002_create_pay_rule_sets.down.sql
+ALTER TABLE payroll_periods DROP COLUMN IF EXISTS rule_set_version;
+DROP TABLE IF EXISTS pay_rule_sets;
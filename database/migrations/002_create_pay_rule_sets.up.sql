@@ -0,0 +1,20 @@
+-- 002_create_pay_rule_sets.up.sql
+CREATE TABLE IF NOT EXISTS pay_rule_sets (
+    id SERIAL PRIMARY KEY,
+    version INT UNIQUE NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    base_pay_formula VARCHAR(50) NOT NULL DEFAULT 'attendance_ratio',
+    hourly_divisor NUMERIC(10,4) NOT NULL DEFAULT 20,
+    overtime_multiplier NUMERIC(10,4) NOT NULL DEFAULT 2,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    updated_by VARCHAR(100) NOT NULL DEFAULT 'system'
+);
+
+-- version 1 is the behaviour that used to be hard-coded in RunPayrollPeriod
+INSERT INTO pay_rule_sets (version, description, base_pay_formula, hourly_divisor, overtime_multiplier)
+VALUES (1, 'Default rule set', 'attendance_ratio', 20, 2)
+ON CONFLICT (version) DO NOTHING;
+
+ALTER TABLE payroll_periods ADD COLUMN IF NOT EXISTS rule_set_version INT REFERENCES pay_rule_sets(version);
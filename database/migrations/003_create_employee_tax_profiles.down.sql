@@ -0,0 +1,2 @@
+-- 003_create_employee_tax_profiles.down.sql
+DROP TABLE IF EXISTS employee_tax_profiles;
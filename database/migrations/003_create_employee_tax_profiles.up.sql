@@ -0,0 +1,11 @@
+-- 003_create_employee_tax_profiles.up.sql
+CREATE TABLE IF NOT EXISTS employee_tax_profiles (
+    employee_id INT PRIMARY KEY REFERENCES employees(id),
+    npwp VARCHAR(16) NOT NULL DEFAULT '',
+    marital_status VARCHAR(2) NOT NULL DEFAULT 'TK' CHECK (marital_status IN ('TK', 'K')),
+    dependents INT NOT NULL DEFAULT 0 CHECK (dependents BETWEEN 0 AND 3),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    updated_by VARCHAR(100) NOT NULL DEFAULT 'system'
+);
@@ -0,0 +1,2 @@
+-- 004_create_bpjs_rates.down.sql
+DROP TABLE IF EXISTS bpjs_rates;
@@ -0,0 +1,24 @@
+-- 004_create_bpjs_rates.up.sql
+CREATE TABLE IF NOT EXISTS bpjs_rates (
+    id SERIAL PRIMARY KEY,
+    program VARCHAR(10) NOT NULL CHECK (program IN ('JHT', 'JP', 'JKK', 'JKM', 'KES')),
+    employee_rate NUMERIC(7,4) NOT NULL DEFAULT 0,
+    employer_rate NUMERIC(7,4) NOT NULL DEFAULT 0,
+    wage_cap NUMERIC(12,2) NOT NULL DEFAULT 0, -- 0 means no cap
+    effective_from DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    updated_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    UNIQUE(program, effective_from)
+);
+
+-- statutory rates, JKK uses the lowest risk class (0.24%)
+INSERT INTO bpjs_rates (program, employee_rate, employer_rate, wage_cap, effective_from) VALUES
+    ('JHT', 0.0200, 0.0370, 0, '2015-07-01'),
+    ('JP', 0.0100, 0.0200, 10042300, '2024-03-01'),
+    ('JP', 0.0100, 0.0200, 10547400, '2025-03-01'),
+    ('JKK', 0.0000, 0.0024, 0, '2015-07-01'),
+    ('JKM', 0.0000, 0.0030, 0, '2015-07-01'),
+    ('KES', 0.0100, 0.0400, 12000000, '2020-07-01')
+ON CONFLICT (program, effective_from) DO NOTHING;
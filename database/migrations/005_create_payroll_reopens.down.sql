@@ -0,0 +1,6 @@
+-- 005_create_payroll_reopens.down.sql
+DROP INDEX IF EXISTS payrolls_active_employee_period_key;
+ALTER TABLE payrolls DROP COLUMN IF EXISTS reopen_id;
+ALTER TABLE payrolls DROP COLUMN IF EXISTS voided_by;
+ALTER TABLE payrolls DROP COLUMN IF EXISTS voided_at;
+DROP TABLE IF EXISTS payroll_period_reopens;
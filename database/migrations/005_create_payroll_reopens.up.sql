@@ -0,0 +1,33 @@
+-- 005_create_payroll_reopens.up.sql
+CREATE TABLE IF NOT EXISTS payroll_period_reopens (
+    id SERIAL PRIMARY KEY,
+    period_id INT NOT NULL REFERENCES payroll_periods(id),
+    reason TEXT NOT NULL CHECK (reason <> ''),
+    voided_payrolls INT NOT NULL DEFAULT 0,
+    diff JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    updated_by VARCHAR(100) NOT NULL DEFAULT 'system'
+);
+
+-- voided payrolls are kept for history and ignored everywhere else
+ALTER TABLE payrolls ADD COLUMN IF NOT EXISTS voided_at TIMESTAMP;
+ALTER TABLE payrolls ADD COLUMN IF NOT EXISTS voided_by VARCHAR(100);
+ALTER TABLE payrolls ADD COLUMN IF NOT EXISTS reopen_id INT REFERENCES payroll_period_reopens(id);
+
+-- earlier re-runs could leave duplicates; keep the newest row of each employee and period
+UPDATE payrolls p
+SET voided_at = NOW(), voided_by = 'system'
+WHERE p.voided_at IS NULL
+  AND EXISTS (
+      SELECT 1 FROM payrolls newer
+      WHERE newer.employee_id = p.employee_id
+        AND newer.period_id = p.period_id
+        AND newer.voided_at IS NULL
+        AND newer.id > p.id
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS payrolls_active_employee_period_key
+    ON payrolls (employee_id, period_id)
+    WHERE voided_at IS NULL;
@@ -0,0 +1,2 @@
+-- 006_create_payroll_jobs.down.sql
+DROP TABLE IF EXISTS payroll_jobs;
@@ -0,0 +1,23 @@
+-- 006_create_payroll_jobs.up.sql
+CREATE TABLE IF NOT EXISTS payroll_jobs (
+    id SERIAL PRIMARY KEY,
+    period_id INT NOT NULL REFERENCES payroll_periods(id),
+    status VARCHAR(10) NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'running', 'succeeded', 'failed')),
+    total_employees INT NOT NULL DEFAULT 0,
+    processed_employees INT NOT NULL DEFAULT 0,
+    errors JSONB NOT NULL DEFAULT '[]'::jsonb,
+    attempts INT NOT NULL DEFAULT 0,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    updated_by VARCHAR(100) NOT NULL DEFAULT 'system'
+);
+
+-- a period has at most one queued or running job
+CREATE UNIQUE INDEX IF NOT EXISTS payroll_jobs_active_period_key
+    ON payroll_jobs (period_id)
+    WHERE status IN ('queued', 'running');
+
+CREATE INDEX IF NOT EXISTS payroll_jobs_status_idx ON payroll_jobs (status, id);
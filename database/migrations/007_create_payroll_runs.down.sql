@@ -0,0 +1,7 @@
+-- 007_create_payroll_runs.down.sql
+ALTER TABLE payroll_jobs DROP COLUMN IF EXISTS run_id;
+ALTER TABLE payroll_jobs DROP COLUMN IF EXISTS reason;
+ALTER TABLE payroll_jobs DROP COLUMN IF EXISTS employee_ids;
+ALTER TABLE payroll_jobs DROP COLUMN IF EXISTS run_type;
+ALTER TABLE payrolls DROP COLUMN IF EXISTS run_id;
+DROP TABLE IF EXISTS payroll_runs;
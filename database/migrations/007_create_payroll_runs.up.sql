@@ -0,0 +1,23 @@
+-- 007_create_payroll_runs.up.sql
+CREATE TABLE IF NOT EXISTS payroll_runs (
+    id SERIAL PRIMARY KEY,
+    period_id INT NOT NULL REFERENCES payroll_periods(id),
+    run_type VARCHAR(10) NOT NULL CHECK (run_type IN ('regular', 'off_cycle')),
+    reason TEXT NOT NULL DEFAULT '',
+    employee_count INT NOT NULL DEFAULT 0,
+    diff JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    updated_by VARCHAR(100) NOT NULL DEFAULT 'system'
+);
+
+CREATE INDEX IF NOT EXISTS payroll_runs_period_idx ON payroll_runs (period_id);
+
+ALTER TABLE payrolls ADD COLUMN IF NOT EXISTS run_id INT REFERENCES payroll_runs(id);
+
+-- off-cycle jobs carry the selected employees and the reason for the run
+ALTER TABLE payroll_jobs ADD COLUMN IF NOT EXISTS run_type VARCHAR(10) NOT NULL DEFAULT 'regular' CHECK (run_type IN ('regular', 'off_cycle'));
+ALTER TABLE payroll_jobs ADD COLUMN IF NOT EXISTS employee_ids INT[] NOT NULL DEFAULT '{}';
+ALTER TABLE payroll_jobs ADD COLUMN IF NOT EXISTS reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE payroll_jobs ADD COLUMN IF NOT EXISTS run_id INT REFERENCES payroll_runs(id);
@@ -0,0 +1,3 @@
+-- 008_create_holidays.down.sql
+ALTER TABLE pay_rule_sets DROP COLUMN IF EXISTS rest_day_overtime_multiplier;
+DROP TABLE IF EXISTS holidays;
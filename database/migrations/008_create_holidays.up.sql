@@ -0,0 +1,16 @@
+-- 008_create_holidays.up.sql
+CREATE TABLE IF NOT EXISTS holidays (
+    id SERIAL PRIMARY KEY,
+    date DATE UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL DEFAULT 'national' CHECK (type IN ('national', 'cuti_bersama', 'company')),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    updated_by VARCHAR(100) NOT NULL DEFAULT 'system'
+);
+
+-- overtime on weekends and holidays; existing rule sets keep paying it like weekday overtime
+ALTER TABLE pay_rule_sets ADD COLUMN IF NOT EXISTS rest_day_overtime_multiplier NUMERIC(10,4);
+UPDATE pay_rule_sets SET rest_day_overtime_multiplier = overtime_multiplier WHERE rest_day_overtime_multiplier IS NULL;
+ALTER TABLE pay_rule_sets ALTER COLUMN rest_day_overtime_multiplier SET NOT NULL;
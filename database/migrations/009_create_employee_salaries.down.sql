@@ -0,0 +1,2 @@
+-- 009_create_employee_salaries.down.sql
+DROP TABLE IF EXISTS employee_salaries;
@@ -0,0 +1,18 @@
+-- 009_create_employee_salaries.up.sql
+CREATE TABLE IF NOT EXISTS employee_salaries (
+    id SERIAL PRIMARY KEY,
+    employee_id INT NOT NULL REFERENCES employees(id),
+    salary NUMERIC(12,2) NOT NULL CHECK (salary > 0),
+    effective_from DATE NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    UNIQUE (employee_id, effective_from)
+);
+
+-- the current salary opens every history, so periods paid before the history existed keep it
+INSERT INTO employee_salaries (employee_id, salary, effective_from, reason)
+SELECT id, salary, DATE '1900-01-01', 'opening salary'
+FROM employees
+WHERE salary > 0
+ON CONFLICT (employee_id, effective_from) DO NOTHING;
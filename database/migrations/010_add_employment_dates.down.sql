@@ -0,0 +1,6 @@
+-- 010_add_employment_dates.down.sql
+ALTER TABLE employees DROP CONSTRAINT IF EXISTS employees_employment_dates_check;
+ALTER TABLE employees DROP CONSTRAINT IF EXISTS employees_employment_status_check;
+ALTER TABLE employees DROP COLUMN IF EXISTS employment_status;
+ALTER TABLE employees DROP COLUMN IF EXISTS end_date;
+ALTER TABLE employees DROP COLUMN IF EXISTS start_date;
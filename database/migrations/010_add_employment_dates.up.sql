@@ -0,0 +1,12 @@
+-- 010_add_employment_dates.up.sql
+-- existing employees keep open-ended employment, so earlier periods are paid as before
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS start_date DATE;
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS end_date DATE;
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS employment_status VARCHAR(20) NOT NULL DEFAULT 'active';
+
+ALTER TABLE employees DROP CONSTRAINT IF EXISTS employees_employment_status_check;
+ALTER TABLE employees ADD CONSTRAINT employees_employment_status_check
+    CHECK (employment_status IN ('active', 'inactive', 'terminated'));
+ALTER TABLE employees DROP CONSTRAINT IF EXISTS employees_employment_dates_check;
+ALTER TABLE employees ADD CONSTRAINT employees_employment_dates_check
+    CHECK (end_date IS NULL OR start_date IS NULL OR end_date >= start_date);
@@ -0,0 +1,3 @@
+-- 011_create_pay_components.down.sql
+DROP TABLE IF EXISTS employee_pay_components;
+DROP TABLE IF EXISTS pay_components;
@@ -0,0 +1,29 @@
+-- 011_create_pay_components.up.sql
+CREATE TABLE IF NOT EXISTS pay_components (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(30) UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    category VARCHAR(20) NOT NULL CHECK (category IN ('earning', 'deduction')),
+    taxable BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    updated_by VARCHAR(100) NOT NULL DEFAULT 'system'
+);
+
+CREATE TABLE IF NOT EXISTS employee_pay_components (
+    id SERIAL PRIMARY KEY,
+    employee_id INT NOT NULL REFERENCES employees(id),
+    component_id INT NOT NULL REFERENCES pay_components(id),
+    amount NUMERIC(12,2) NOT NULL CHECK (amount > 0),
+    taxable BOOLEAN, -- NULL uses the component's flag
+    effective_from DATE NOT NULL,
+    effective_to DATE CHECK (effective_to IS NULL OR effective_to >= effective_from),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    updated_by VARCHAR(100) NOT NULL DEFAULT 'system'
+);
+
+CREATE INDEX IF NOT EXISTS employee_pay_components_employee_idx ON employee_pay_components (employee_id, effective_from);
@@ -0,0 +1,3 @@
+-- 012_create_loans.down.sql
+DROP TABLE IF EXISTS loan_repayments;
+DROP TABLE IF EXISTS loans;
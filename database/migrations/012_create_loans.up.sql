@@ -0,0 +1,29 @@
+-- 012_create_loans.up.sql
+CREATE TABLE IF NOT EXISTS loans (
+    id SERIAL PRIMARY KEY,
+    employee_id INT NOT NULL REFERENCES employees(id),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('loan', 'salary_advance')),
+    principal NUMERIC(12,2) NOT NULL CHECK (principal > 0),
+    installments INT NOT NULL CHECK (installments > 0),
+    installment_amount NUMERIC(12,2) NOT NULL CHECK (installment_amount > 0),
+    start_date DATE NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paid_off', 'cancelled')),
+    outstanding NUMERIC(12,2) NOT NULL CHECK (outstanding >= 0),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    updated_by VARCHAR(100) NOT NULL DEFAULT 'system'
+);
+
+CREATE INDEX IF NOT EXISTS loans_employee_idx ON loans (employee_id);
+
+-- installments deducted in locked periods, rebuilt from the period's payslips whenever they change
+CREATE TABLE IF NOT EXISTS loan_repayments (
+    loan_id INT NOT NULL REFERENCES loans(id),
+    period_id INT NOT NULL REFERENCES payroll_periods(id),
+    payroll_id INT NOT NULL REFERENCES payrolls(id),
+    amount NUMERIC(12,2) NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (loan_id, period_id)
+);
@@ -0,0 +1,3 @@
+-- 013_create_bonus_runs.down.sql
+DROP TABLE IF EXISTS bonus_payslips;
+DROP TABLE IF EXISTS bonus_runs;
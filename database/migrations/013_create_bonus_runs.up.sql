@@ -0,0 +1,36 @@
+-- 013_create_bonus_runs.up.sql
+CREATE TABLE IF NOT EXISTS bonus_runs (
+    id SERIAL PRIMARY KEY,
+    period_id INT NOT NULL REFERENCES payroll_periods(id),
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('thr', 'bonus')),
+    reason TEXT NOT NULL DEFAULT '',
+    reference_date DATE,
+    employee_count INT NOT NULL DEFAULT 0,
+    total_gross NUMERIC(14,2) NOT NULL DEFAULT 0,
+    total_net NUMERIC(14,2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    updated_by VARCHAR(100) NOT NULL DEFAULT 'system'
+);
+
+CREATE INDEX IF NOT EXISTS bonus_runs_period_idx ON bonus_runs (period_id);
+
+CREATE TABLE IF NOT EXISTS bonus_payslips (
+    id SERIAL PRIMARY KEY,
+    run_id INT NOT NULL REFERENCES bonus_runs(id),
+    employee_id INT NOT NULL REFERENCES employees(id),
+    period_id INT NOT NULL REFERENCES payroll_periods(id),
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('thr', 'bonus')),
+    tax_year INT NOT NULL,
+    payslip JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system'
+);
+
+CREATE INDEX IF NOT EXISTS bonus_payslips_period_idx ON bonus_payslips (period_id, employee_id);
+
+-- THR is paid once a year
+CREATE UNIQUE INDEX IF NOT EXISTS bonus_payslips_thr_employee_year_key
+    ON bonus_payslips (employee_id, tax_year)
+    WHERE kind = 'thr';
@@ -0,0 +1,2 @@
+-- 014_create_payroll_ytd.down.sql
+DROP TABLE IF EXISTS payroll_ytd;
@@ -0,0 +1,19 @@
+-- 014_create_payroll_ytd.up.sql
+-- per-employee totals of the payslips in locked periods of a tax year, rebuilt from the
+-- payslips whenever a period is locked, reopened or paid a bonus
+CREATE TABLE IF NOT EXISTS payroll_ytd (
+    employee_id INT NOT NULL REFERENCES employees(id),
+    tax_year INT NOT NULL,
+    months INT NOT NULL DEFAULT 0,
+    gross NUMERIC(14,2) NOT NULL DEFAULT 0,
+    taxable_income NUMERIC(14,2) NOT NULL DEFAULT 0,
+    tax_withheld NUMERIC(14,2) NOT NULL DEFAULT 0,
+    bpjs_employee NUMERIC(14,2) NOT NULL DEFAULT 0,
+    bpjs_employer NUMERIC(14,2) NOT NULL DEFAULT 0,
+    pension NUMERIC(14,2) NOT NULL DEFAULT 0,
+    net NUMERIC(14,2) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (employee_id, tax_year)
+);
+
+CREATE INDEX IF NOT EXISTS payroll_ytd_tax_year_idx ON payroll_ytd (tax_year);
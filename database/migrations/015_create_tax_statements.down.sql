@@ -0,0 +1,2 @@
+-- 015_create_tax_statements.down.sql
+DROP TABLE IF EXISTS tax_statements;
@@ -0,0 +1,13 @@
+-- 015_create_tax_statements.up.sql
+-- 1721-A1 statements; regenerating a statement keeps its number
+CREATE TABLE IF NOT EXISTS tax_statements (
+    id SERIAL PRIMARY KEY,
+    employee_id INT NOT NULL REFERENCES employees(id),
+    tax_year INT NOT NULL,
+    sequence INT NOT NULL,
+    statement JSONB NOT NULL,
+    generated_at TIMESTAMP DEFAULT NOW(),
+    generated_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    UNIQUE (employee_id, tax_year),
+    UNIQUE (tax_year, sequence)
+);
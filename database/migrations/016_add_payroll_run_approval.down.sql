@@ -0,0 +1,5 @@
+-- 016_add_payroll_run_approval.down.sql
+DROP TABLE IF EXISTS payroll_run_transitions;
+DROP INDEX IF EXISTS payroll_runs_pending_period_key;
+ALTER TABLE payroll_runs DROP COLUMN IF EXISTS pending_payrolls;
+ALTER TABLE payroll_runs DROP COLUMN IF EXISTS status;
@@ -0,0 +1,23 @@
+-- 016_add_payroll_run_approval.up.sql
+-- runs stored before approval existed were applied directly
+ALTER TABLE payroll_runs ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'approved'
+    CHECK (status IN ('pending_approval', 'approved', 'rejected'));
+-- payslips of a run waiting for approval; moved to payrolls once approved
+ALTER TABLE payroll_runs ADD COLUMN IF NOT EXISTS pending_payrolls JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+-- a period has at most one run waiting for approval
+CREATE UNIQUE INDEX IF NOT EXISTS payroll_runs_pending_period_key
+    ON payroll_runs (period_id)
+    WHERE status = 'pending_approval';
+
+CREATE TABLE IF NOT EXISTS payroll_run_transitions (
+    id SERIAL PRIMARY KEY,
+    run_id INT NOT NULL REFERENCES payroll_runs(id),
+    from_status VARCHAR(20) NOT NULL DEFAULT '',
+    to_status VARCHAR(20) NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system'
+);
+
+CREATE INDEX IF NOT EXISTS payroll_run_transitions_run_idx ON payroll_run_transitions (run_id, id);
@@ -0,0 +1,3 @@
+-- 017_create_gl_accounts.down.sql
+DROP TABLE IF EXISTS gl_accounts;
+ALTER TABLE employees DROP COLUMN IF EXISTS cost_center;
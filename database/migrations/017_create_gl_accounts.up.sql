@@ -0,0 +1,18 @@
+-- 017_create_gl_accounts.up.sql
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS cost_center VARCHAR(30) NOT NULL DEFAULT '';
+
+-- an empty cost center is the component's default account
+CREATE TABLE IF NOT EXISTS gl_accounts (
+    id SERIAL PRIMARY KEY,
+    component VARCHAR(30) NOT NULL CHECK (component IN (
+        'salary_expense', 'overtime_expense', 'reimbursement_expense', 'bpjs_expense', 'tax_payable',
+        'bpjs_payable', 'loan_receivable', 'other_deductions_payable', 'net_salary_payable')),
+    cost_center VARCHAR(30) NOT NULL DEFAULT '',
+    account_code VARCHAR(30) NOT NULL CHECK (account_code <> ''),
+    account_name VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    updated_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    UNIQUE (component, cost_center)
+);
@@ -0,0 +1,4 @@
+-- 018_create_disbursements.down.sql
+DROP TABLE IF EXISTS disbursement_items;
+DROP TABLE IF EXISTS disbursement_batches;
+DROP TABLE IF EXISTS employee_bank_accounts;
@@ -0,0 +1,46 @@
+-- 018_create_disbursements.up.sql
+CREATE TABLE IF NOT EXISTS employee_bank_accounts (
+    employee_id INT PRIMARY KEY REFERENCES employees(id),
+    bank_code VARCHAR(11) NOT NULL,
+    account_number VARCHAR(20) NOT NULL,
+    account_holder VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    updated_by VARCHAR(100) NOT NULL DEFAULT 'system'
+);
+
+CREATE TABLE IF NOT EXISTS disbursement_batches (
+    id SERIAL PRIMARY KEY,
+    period_id INT NOT NULL REFERENCES payroll_periods(id),
+    format VARCHAR(30) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'exported' CHECK (status IN ('exported', 'cancelled')),
+    value_date DATE NOT NULL,
+    source JSONB NOT NULL DEFAULT '{}'::jsonb,
+    currency VARCHAR(3) NOT NULL,
+    item_count INT NOT NULL,
+    total_amount NUMERIC(18,2) NOT NULL,
+    checksum VARCHAR(64) NOT NULL,
+    cancel_reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    updated_by VARCHAR(100) NOT NULL DEFAULT 'system'
+);
+
+-- a period is paid by at most one exported batch
+CREATE UNIQUE INDEX IF NOT EXISTS idx_disbursement_batches_exported_period
+    ON disbursement_batches (period_id) WHERE status = 'exported';
+
+CREATE TABLE IF NOT EXISTS disbursement_items (
+    id SERIAL PRIMARY KEY,
+    batch_id INT NOT NULL REFERENCES disbursement_batches(id),
+    line INT NOT NULL,
+    employee_id INT NOT NULL REFERENCES employees(id),
+    employee_name VARCHAR(100) NOT NULL DEFAULT '',
+    bank_code VARCHAR(11) NOT NULL,
+    account_number VARCHAR(20) NOT NULL,
+    account_holder VARCHAR(100) NOT NULL,
+    amount NUMERIC(18,2) NOT NULL,
+    UNIQUE (batch_id, line)
+);
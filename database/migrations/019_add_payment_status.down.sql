@@ -0,0 +1,12 @@
+-- 019_add_payment_status.down.sql
+DROP TABLE IF EXISTS bank_statement_entries;
+DROP TABLE IF EXISTS bank_statement_imports;
+ALTER TABLE disbursement_items DROP COLUMN IF EXISTS reissued_batch_id;
+ALTER TABLE disbursement_items DROP COLUMN IF EXISTS reissue_of_item_id;
+ALTER TABLE disbursement_items DROP COLUMN IF EXISTS status_updated_by;
+ALTER TABLE disbursement_items DROP COLUMN IF EXISTS status_updated_at;
+ALTER TABLE disbursement_items DROP COLUMN IF EXISTS bank_reference;
+ALTER TABLE disbursement_items DROP COLUMN IF EXISTS status_note;
+ALTER TABLE disbursement_items DROP COLUMN IF EXISTS status;
+ALTER TABLE disbursement_batches DROP COLUMN IF EXISTS sent_by;
+ALTER TABLE disbursement_batches DROP COLUMN IF EXISTS sent_at;
@@ -0,0 +1,41 @@
+-- 019_add_payment_status.up.sql
+ALTER TABLE disbursement_batches ADD COLUMN IF NOT EXISTS sent_at TIMESTAMP;
+ALTER TABLE disbursement_batches ADD COLUMN IF NOT EXISTS sent_by VARCHAR(100) NOT NULL DEFAULT '';
+
+ALTER TABLE disbursement_items ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'sent', 'paid', 'failed', 'returned'));
+ALTER TABLE disbursement_items ADD COLUMN IF NOT EXISTS status_note TEXT NOT NULL DEFAULT '';
+ALTER TABLE disbursement_items ADD COLUMN IF NOT EXISTS bank_reference VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE disbursement_items ADD COLUMN IF NOT EXISTS status_updated_at TIMESTAMP;
+ALTER TABLE disbursement_items ADD COLUMN IF NOT EXISTS status_updated_by VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE disbursement_items ADD COLUMN IF NOT EXISTS reissue_of_item_id INT REFERENCES disbursement_items(id);
+ALTER TABLE disbursement_items ADD COLUMN IF NOT EXISTS reissued_batch_id INT REFERENCES disbursement_batches(id);
+
+CREATE TABLE IF NOT EXISTS bank_statement_imports (
+    id SERIAL PRIMARY KEY,
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'mt940')),
+    account_number VARCHAR(35) NOT NULL DEFAULT '',
+    entry_count INT NOT NULL DEFAULT 0,
+    duplicate_count INT NOT NULL DEFAULT 0,
+    matched_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system'
+);
+
+-- the fingerprint skips entries imported again in a later, overlapping statement
+CREATE TABLE IF NOT EXISTS bank_statement_entries (
+    id SERIAL PRIMARY KEY,
+    import_id INT NOT NULL REFERENCES bank_statement_imports(id),
+    line INT NOT NULL,
+    booking_date DATE NOT NULL,
+    direction VARCHAR(6) NOT NULL CHECK (direction IN ('debit', 'credit')),
+    amount NUMERIC(18,2) NOT NULL,
+    reference VARCHAR(100) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    account_number VARCHAR(35) NOT NULL DEFAULT '',
+    fingerprint VARCHAR(64) NOT NULL UNIQUE,
+    matched_item_id INT REFERENCES disbursement_items(id),
+    matched_status VARCHAR(20) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_bank_statement_entries_unmatched ON bank_statement_entries (booking_date) WHERE matched_item_id IS NULL;
@@ -0,0 +1,3 @@
+-- 020_create_payslip_templates.down.sql
+DROP TABLE IF EXISTS payslip_templates;
+ALTER TABLE employees DROP COLUMN IF EXISTS company;
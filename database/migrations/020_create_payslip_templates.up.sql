@@ -0,0 +1,15 @@
+-- 020_create_payslip_templates.up.sql
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS company VARCHAR(30) NOT NULL DEFAULT '';
+
+-- every save adds a version; payslips use the latest version of the employee's company
+CREATE TABLE IF NOT EXISTS payslip_templates (
+    id SERIAL PRIMARY KEY,
+    company VARCHAR(30) NOT NULL CHECK (company <> ''),
+    version INT NOT NULL CHECK (version > 0),
+    name VARCHAR(100) NOT NULL DEFAULT '',
+    language VARCHAR(10) NOT NULL DEFAULT 'en',
+    body TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    UNIQUE (company, version)
+);
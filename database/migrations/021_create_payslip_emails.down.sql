@@ -0,0 +1,2 @@
+-- 021_create_payslip_emails.down.sql
+DROP TABLE IF EXISTS payslip_emails;
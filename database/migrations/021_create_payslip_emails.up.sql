@@ -0,0 +1,21 @@
+-- 021_create_payslip_emails.up.sql
+-- one delivery per employee and period; resending reuses the row
+CREATE TABLE IF NOT EXISTS payslip_emails (
+    id SERIAL PRIMARY KEY,
+    period_id INT NOT NULL REFERENCES payroll_periods(id),
+    employee_id INT NOT NULL REFERENCES employees(id),
+    recipient VARCHAR(255) NOT NULL,
+    protected BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(10) NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'sending', 'sent', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    updated_by VARCHAR(100) NOT NULL DEFAULT 'system',
+    UNIQUE (period_id, employee_id)
+);
+
+CREATE INDEX IF NOT EXISTS payslip_emails_status_idx ON payslip_emails (status, next_attempt_at);
@@ -0,0 +1,9 @@
+-- 022_add_payroll_run_type.down.sql
+-- fails while an employee has an off-cycle payslip next to the regular one
+DROP INDEX IF EXISTS payrolls_active_off_cycle_key;
+DROP INDEX IF EXISTS payrolls_active_regular_key;
+ALTER TABLE payrolls DROP COLUMN IF EXISTS run_type;
+
+CREATE UNIQUE INDEX IF NOT EXISTS payrolls_active_employee_period_key
+    ON payrolls (employee_id, period_id)
+    WHERE voided_at IS NULL;
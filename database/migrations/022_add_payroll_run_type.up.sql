@@ -0,0 +1,19 @@
+-- 022_add_payroll_run_type.up.sql
+-- off-cycle payslips add to the regular payslip instead of replacing it
+ALTER TABLE payrolls ADD COLUMN IF NOT EXISTS run_type VARCHAR(10) NOT NULL DEFAULT 'regular' CHECK (run_type IN ('regular', 'off_cycle'));
+
+UPDATE payrolls p
+SET run_type = r.run_type
+FROM payroll_runs r
+WHERE r.id = p.run_id AND p.run_type <> r.run_type;
+
+-- an employee has one active regular payslip per period and one per off-cycle run
+DROP INDEX IF EXISTS payrolls_active_employee_period_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS payrolls_active_regular_key
+    ON payrolls (employee_id, period_id)
+    WHERE voided_at IS NULL AND run_type = 'regular';
+
+CREATE UNIQUE INDEX IF NOT EXISTS payrolls_active_off_cycle_key
+    ON payrolls (employee_id, period_id, run_id)
+    WHERE voided_at IS NULL AND run_type = 'off_cycle';
@@ -0,0 +1,2 @@
+-- 023_create_payslip_passwords.down.sql
+DROP TABLE IF EXISTS payslip_passwords;
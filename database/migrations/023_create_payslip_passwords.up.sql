@@ -0,0 +1,11 @@
+-- 023_create_payslip_passwords.up.sql
+-- what protected payslips are encrypted with, never the password: user_entry is the salted hash
+-- PDF readers check the password against; sealed_key is the key derived from the password,
+-- which opens every payslip encrypted for it, encrypted with PAYSLIP_KEY_SECRET
+CREATE TABLE IF NOT EXISTS payslip_passwords (
+    employee_id INT PRIMARY KEY REFERENCES employees(id),
+    user_entry BYTEA NOT NULL,
+    sealed_key BYTEA NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
@@ -0,0 +1,3 @@
+-- 024_allow_supplementary_disbursements.down.sql
+CREATE UNIQUE INDEX IF NOT EXISTS idx_disbursement_batches_exported_period
+    ON disbursement_batches (period_id) WHERE status = 'exported';
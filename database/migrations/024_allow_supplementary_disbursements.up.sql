@@ -0,0 +1,3 @@
+-- 024_allow_supplementary_disbursements.up.sql
+-- later batches of a period pay what corrections and re-runs added since the first
+DROP INDEX IF EXISTS idx_disbursement_batches_exported_period;
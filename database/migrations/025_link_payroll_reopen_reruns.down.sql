@@ -0,0 +1,3 @@
+-- 025_link_payroll_reopen_reruns.down.sql
+ALTER TABLE payroll_period_reopens DROP COLUMN IF EXISTS run_id;
+ALTER TABLE payroll_period_reopens DROP COLUMN IF EXISTS job_id;
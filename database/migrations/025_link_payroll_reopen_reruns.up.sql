@@ -0,0 +1,4 @@
+-- 025_link_payroll_reopen_reruns.up.sql
+-- a reopen queues the job re-running the period; approving the run records it with its diff
+ALTER TABLE payroll_period_reopens ADD COLUMN IF NOT EXISTS job_id INT REFERENCES payroll_jobs(id);
+ALTER TABLE payroll_period_reopens ADD COLUMN IF NOT EXISTS run_id INT REFERENCES payroll_runs(id);
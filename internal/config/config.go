@@ -1,24 +1,122 @@
 package config
 
 import (
+	"net/mail"
 	"os"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"payroll-system/internal/utils"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	DBUrl      string
-	JWTSecret  string
-	ServerPort string
-	Env        string // add Env for environment
+	DBUrl            string
+	JWTSecret        string
+	ServerPort       string
+	Env              string // add Env for environment
+	Currency         string // payroll currency, defaults to IDR
+	RoundingPolicies string // per currency, e.g. "IDR:half_up:0,USD:half_even:2"
+	EmployerName     string // withholder named on the 1721-A1 statements
+	EmployerNPWP     string
+	// the company account disbursement files debit
+	DisbursementBankCode      string
+	DisbursementAccountNumber string
+	// outgoing mail; payslips are not emailed when SMTPHost is empty
+	SMTPHost            string
+	SMTPPort            string // defaults to 587, or 465 with implicit TLS
+	SMTPTLS             string // starttls (default), implicit or none
+	SMTPUsername        string
+	SMTPPassword        string
+	SMTPFrom            string
+	PayslipEmailOnLock  bool   // email the payslips when a payroll run is approved
+	PayslipEmailProtect bool   // protect emailed payslips with the employee's payslip password unless a distribution chooses
+	PayslipKeySecret    string // encrypts the stored keys of the payslip passwords, at least 32 characters
 }
 
 func Load() *Config {
 	_ = godotenv.Load() // Load .env file if present
 	return &Config{
-		DBUrl:      os.Getenv("DATABASE_URL"),
-		JWTSecret:  os.Getenv("JWT_SECRET"),
-		ServerPort: os.Getenv("SERVER_PORT"),
-		Env:        os.Getenv("ENV"), // load ENV from environment
+		DBUrl:            os.Getenv("DATABASE_URL"),
+		JWTSecret:        os.Getenv("JWT_SECRET"),
+		ServerPort:       os.Getenv("SERVER_PORT"),
+		Env:              os.Getenv("ENV"), // load ENV from environment
+		Currency:         os.Getenv("CURRENCY"),
+		RoundingPolicies: os.Getenv("ROUNDING_POLICIES"),
+		EmployerName:     os.Getenv("EMPLOYER_NAME"),
+		EmployerNPWP:     os.Getenv("EMPLOYER_NPWP"),
+
+		DisbursementBankCode:      os.Getenv("DISBURSEMENT_BANK_CODE"),
+		DisbursementAccountNumber: os.Getenv("DISBURSEMENT_ACCOUNT_NUMBER"),
+
+		SMTPHost:            os.Getenv("SMTP_HOST"),
+		SMTPPort:            os.Getenv("SMTP_PORT"),
+		SMTPTLS:             os.Getenv("SMTP_TLS"),
+		SMTPUsername:        os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:        os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:            os.Getenv("SMTP_FROM"),
+		PayslipEmailOnLock:  os.Getenv("PAYSLIP_EMAIL_ON_LOCK") == "true",
+		PayslipEmailProtect: os.Getenv("PAYSLIP_EMAIL_PROTECT") == "true",
+		PayslipKeySecret:    os.Getenv("PAYSLIP_KEY_SECRET"),
+	}
+}
+
+// ParseRoundingPolicies parses ROUNDING_POLICIES entries of the form CURRENCY:MODE:SCALE.
+func (c *Config) ParseRoundingPolicies() ([]domain.RoundingPolicy, error) {
+	var policies []domain.RoundingPolicy
+	for _, entry := range strings.Split(c.RoundingPolicies, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			return nil, error_const.ErrInvalidRoundingPolicy
+		}
+		scale, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return nil, error_const.ErrInvalidRoundingPolicy
+		}
+		policy := domain.RoundingPolicy{
+			Currency: strings.ToUpper(parts[0]),
+			Mode:     domain.RoundingMode(parts[1]),
+			Scale:    scale,
+		}
+		if err := policy.Validate(); err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// ParseSMTPConfig returns the server payslips are emailed through, or false when SMTP_HOST is
+// not set.
+func (c *Config) ParseSMTPConfig() (utils.SMTPConfig, bool, error) {
+	if c.SMTPHost == "" {
+		return utils.SMTPConfig{}, false, nil
+	}
+	smtp := utils.SMTPConfig{
+		Host:     c.SMTPHost,
+		TLS:      c.SMTPTLS,
+		Username: c.SMTPUsername,
+		Password: c.SMTPPassword,
+		From:     c.SMTPFrom,
+	}
+	if c.SMTPPort != "" {
+		port, err := strconv.Atoi(c.SMTPPort)
+		if err != nil || port <= 0 || port > 65535 {
+			return utils.SMTPConfig{}, false, error_const.ErrInvalidSMTPConfig
+		}
+		smtp.Port = port
+	}
+	if _, err := mail.ParseAddress(smtp.From); err != nil {
+		return utils.SMTPConfig{}, false, error_const.ErrInvalidSMTPConfig
+	}
+	if smtp.TLS != "" && !utils.IsValidSMTPTLS(smtp.TLS) {
+		return utils.SMTPConfig{}, false, error_const.ErrInvalidSMTPConfig
 	}
+	return smtp, true, nil
 }
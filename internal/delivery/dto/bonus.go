@@ -0,0 +1,14 @@
+package dto
+
+import "payroll-system/internal/domain"
+
+// BonusRunRequest pays THR or uploaded bonuses in a locked payroll period.
+type BonusRunRequest struct {
+	PeriodID      int                  `json:"period_id"`
+	Kind          string               `json:"kind" binding:"required"` // thr or bonus
+	Reason        string               `json:"reason"`
+	ReferenceDate string               `json:"reference_date"` // THR: YYYY-MM-DD, defaults to the period end date
+	EmployeeIDs   []int                `json:"employee_ids"`   // THR: empty pays every eligible employee
+	Amounts       []domain.BonusAmount `json:"amounts"`        // bonus: one amount per employee
+	ActorEmail    string               `json:"actor_email"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+import "payroll-system/internal/domain"
+
+type BPJSRateRequest struct {
+	Program       string       `json:"program" binding:"required"`
+	EmployeeRate  domain.Rate  `json:"employee_rate"`
+	EmployerRate  domain.Rate  `json:"employer_rate"`
+	WageCap       domain.Money `json:"wage_cap"`
+	EffectiveFrom string       `json:"effective_from" binding:"required"`
+	ActorEmail    string       `json:"actor_email"`
+}
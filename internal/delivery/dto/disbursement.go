@@ -0,0 +1,43 @@
+package dto
+
+type BankAccountRequest struct {
+	EmployeeID    int    `json:"employee_id"`
+	BankCode      string `json:"bank_code" binding:"required"`
+	AccountNumber string `json:"account_number" binding:"required"`
+	AccountHolder string `json:"account_holder" binding:"required"`
+	ActorEmail    string `json:"actor_email"`
+}
+
+type DisbursementRequest struct {
+	PeriodID   int    `json:"period_id"`
+	Format     string `json:"format" binding:"required"` // csv or fixed_width
+	ValueDate  string `json:"value_date"`                // YYYY-MM-DD, defaults to today
+	Reissue    bool   `json:"reissue"`                   // also pay failed and returned payments of earlier batches
+	ActorEmail string `json:"-"`
+}
+
+type CancelDisbursementRequest struct {
+	BatchID    int    `json:"batch_id"`
+	Reason     string `json:"reason"`
+	ActorEmail string `json:"-"`
+}
+
+type DisbursementSentRequest struct {
+	BatchID    int    `json:"batch_id"`
+	ActorEmail string `json:"-"`
+}
+
+type PaymentStatusRequest struct {
+	BatchID    int    `json:"batch_id"`
+	ItemID     int    `json:"item_id"`
+	Status     string `json:"status" binding:"required"`
+	Note       string `json:"note"`
+	ActorEmail string `json:"-"`
+}
+
+// BankStatementImportRequest is a statement file of the company account, as text.
+type BankStatementImportRequest struct {
+	Format     string `json:"format" binding:"required"` // csv or mt940
+	Content    string `json:"content" binding:"required"`
+	ActorEmail string `json:"-"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+type EmploymentRequest struct {
+	EmployeeID int     `json:"employee_id"`
+	StartDate  string  `json:"start_date"` // YYYY-MM-DD, empty when unknown
+	EndDate    string  `json:"end_date"`   // YYYY-MM-DD, empty while employed
+	Status     string  `json:"status" binding:"required"`
+	CostCenter *string `json:"cost_center"` // nil keeps the current cost center
+	Company    *string `json:"company"`     // nil keeps the current company
+	ActorEmail string  `json:"actor_email"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+type HolidayRequest struct {
+	Date       string `json:"date" binding:"required"`
+	Name       string `json:"name" binding:"required"`
+	Type       string `json:"type"` // defaults to national
+	ActorEmail string `json:"actor_email"`
+}
+
+// HolidayImportRequest is a full year's holiday list, e.g. the SKB on national holidays and cuti bersama.
+type HolidayImportRequest struct {
+	Year       int              `json:"year" binding:"required"`
+	Holidays   []HolidayRequest `json:"holidays" binding:"required,dive"`
+	Replace    bool             `json:"replace"` // remove the year's holidays missing from the list
+	ActorEmail string           `json:"actor_email"`
+}
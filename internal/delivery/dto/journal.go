@@ -0,0 +1,9 @@
+package dto
+
+type GLAccountRequest struct {
+	Component   string `json:"component" binding:"required"`
+	CostCenter  string `json:"cost_center"` // empty sets the component's default account
+	AccountCode string `json:"account_code" binding:"required"`
+	AccountName string `json:"account_name"`
+	ActorEmail  string `json:"actor_email"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "payroll-system/internal/domain"
+
+type LoanRequest struct {
+	EmployeeID   int          `json:"employee_id"`
+	Type         string       `json:"type"` // loan or salary_advance, defaults to loan
+	Principal    domain.Money `json:"principal"`
+	Installments int          `json:"installments"`                  // number of payroll periods, defaults to 1
+	StartDate    string       `json:"start_date" binding:"required"` // YYYY-MM-DD, the first installment is due in the period containing it
+	Description  string       `json:"description"`
+	ActorEmail   string       `json:"actor_email"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "payroll-system/internal/domain"
+
+type PayComponentRequest struct {
+	Code       string `json:"code"` // ignored on update
+	Name       string `json:"name" binding:"required"`
+	Category   string `json:"category"` // earning or deduction, ignored on update
+	Taxable    bool   `json:"taxable"`
+	Active     *bool  `json:"active"` // defaults to true
+	ActorEmail string `json:"actor_email"`
+}
+
+type PayComponentAssignmentRequest struct {
+	EmployeeID    int          `json:"employee_id"`
+	ComponentID   int          `json:"component_id"` // ignored on update
+	Amount        domain.Money `json:"amount"`
+	Taxable       *bool        `json:"taxable"`                           // overrides the component's flag
+	EffectiveFrom string       `json:"effective_from" binding:"required"` // YYYY-MM-DD
+	EffectiveTo   string       `json:"effective_to"`                      // YYYY-MM-DD, empty for open-ended
+	ActorEmail    string       `json:"actor_email"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "payroll-system/internal/domain"
+
+type PayRuleSetRequest struct {
+	Description        string      `json:"description"`
+	BasePayFormula     string      `json:"base_pay_formula" binding:"required"`
+	HourlyDivisor      domain.Rate `json:"hourly_divisor" binding:"required"`
+	OvertimeMultiplier domain.Rate `json:"overtime_multiplier" binding:"required"`
+	// RestDayOvertimeMultiplier is optional and defaults to OvertimeMultiplier.
+	RestDayOvertimeMultiplier domain.Rate `json:"rest_day_overtime_multiplier"`
+	ActorEmail                string      `json:"actor_email"`
+}
+
+type AssignPayRuleSetRequest struct {
+	PeriodID   int    `json:"period_id"`
+	Version    int    `json:"version" binding:"required"`
+	ActorEmail string `json:"actor_email"`
+}
@@ -16,4 +16,27 @@ type PayrollRequest struct {
 	PeriodID   int    `json:"period_id" binding:"required"`
 	ActorEmail string `json:"actor_email"`
 	EmployeeID int    `json:"employee_id"` // Optional, if running for a specific employee
+
+	// EmployeeIDs selects an off-cycle run for a subset of employees; Reason is required with it.
+	EmployeeIDs []int  `json:"employee_ids"`
+	Reason      string `json:"reason"`
+	// IncludeEmployeeIDs adds employees paid off-cycle in the period to a regular run, which
+	// then replaces their off-cycle payslips.
+	IncludeEmployeeIDs []int `json:"include_employee_ids"`
+}
+
+type ReopenPayrollPeriodRequest struct {
+	PeriodID   int    `json:"period_id"`
+	Reason     string `json:"reason" binding:"required"`
+	ActorID    int    `json:"-"`
+	ActorRole  string `json:"-"`
+	ActorEmail string `json:"-"`
+	IPAddress  string `json:"-"`
+}
+
+// PayrollRunDecisionRequest approves or rejects a payroll run waiting for approval.
+type PayrollRunDecisionRequest struct {
+	RunID      int    `json:"run_id"`
+	Comment    string `json:"comment"` // required to reject
+	ActorEmail string `json:"-"`
 }
@@ -0,0 +1,19 @@
+package dto
+
+type PayslipEmailRequest struct {
+	PeriodID          int    `json:"period_id"`
+	ProtectAttachment *bool  `json:"protect_attachment"` // defaults to PAYSLIP_EMAIL_PROTECT
+	ActorEmail        string `json:"-"`
+}
+
+type PayslipEmailResendRequest struct {
+	PeriodID          int    `json:"period_id"`
+	EmployeeID        int    `json:"employee_id"`
+	ProtectAttachment *bool  `json:"protect_attachment"` // defaults to PAYSLIP_EMAIL_PROTECT
+	ActorEmail        string `json:"-"`
+}
+
+type PayslipPasswordRequest struct {
+	Password   string `json:"password" binding:"required"`
+	EmployeeID int    `json:"-"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "payroll-system/internal/domain"
+
+// PayslipTemplateRequest saves a new version of a company's payslip template.
+type PayslipTemplateRequest struct {
+	Company    string `json:"-"`
+	Name       string `json:"name"`
+	Language   string `json:"language"` // defaults to en
+	Body       string `json:"body" binding:"required"`
+	ActorEmail string `json:"-"`
+}
+
+// PayslipTemplatePreviewRequest renders a template without saving it, with the given payslip or
+// a sample one.
+type PayslipTemplatePreviewRequest struct {
+	Body     string          `json:"body" binding:"required"`
+	Language string          `json:"language"`
+	Format   string          `json:"format"` // html or pdf, defaults to html
+	Payslip  *domain.Payslip `json:"payslip"`
+}
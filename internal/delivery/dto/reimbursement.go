@@ -1,9 +1,11 @@
 package dto
 
+import "payroll-system/internal/domain"
+
 type ReimbursementRequest struct {
-	EmployeeID    int     `json:"employee_id"`
-	EmployeeEmail string  `json:"employee_email"`
-	Amount        float64 `json:"amount" binding:"required"`
-	Description   string  `json:"description" binding:"required"`
-	Date          string  `json:"date" binding:"required"`
+	EmployeeID    int          `json:"employee_id"`
+	EmployeeEmail string       `json:"employee_email"`
+	Amount        domain.Money `json:"amount" binding:"required"`
+	Description   string       `json:"description" binding:"required"`
+	Date          string       `json:"date" binding:"required"`
 }
@@ -1,5 +1,7 @@
 package dto
 
+import "payroll-system/internal/domain"
+
 type Response struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
@@ -20,13 +22,35 @@ func NewErrorResponse(message string, err error) *Response {
 }
 
 type EmployeePayrollSummary struct {
-	EmployeeID   int     `json:"employee_id"`
-	EmployeeName string  `json:"employee_name"`
-	TotalSalary  float64 `json:"total_salary"`
+	EmployeeID   int          `json:"employee_id"`
+	EmployeeName string       `json:"employee_name"`
+	TotalSalary  domain.Money `json:"total_salary"`
+	Tax          domain.Money `json:"tax"`
+	NetSalary    domain.Money `json:"net_salary"`
+
+	EmployerContributions domain.Money `json:"employer_contributions"`
 }
 
 type PayrollSummaryResponse struct {
 	PeriodID          int                      `json:"period_id"`
 	EmployeeSummaries []EmployeePayrollSummary `json:"employee_summaries"`
-	TotalSalary       float64                  `json:"total_salary"`
+	TotalSalary       domain.Money             `json:"total_salary"`
+	TotalTax          domain.Money             `json:"total_tax"`
+	TotalNetSalary    domain.Money             `json:"total_net_salary"`
+
+	TotalEmployerContributions     domain.Money            `json:"total_employer_contributions"`
+	EmployerContributionsByProgram map[string]domain.Money `json:"employer_contributions_by_program"`
+}
+
+type PayrollPreviewResponse struct {
+	PeriodID       int              `json:"period_id"`
+	RuleSetVersion int              `json:"rule_set_version"`
+	EmployeeCount  int              `json:"employee_count"`
+	Payslips       []domain.Payslip `json:"payslips"`
+	TotalSalary    domain.Money     `json:"total_salary"`
+	TotalTax       domain.Money     `json:"total_tax"`
+
+	TotalDeductions            domain.Money `json:"total_deductions"`
+	TotalNetSalary             domain.Money `json:"total_net_salary"`
+	TotalEmployerContributions domain.Money `json:"total_employer_contributions"`
 }
@@ -0,0 +1,11 @@
+package dto
+
+import "payroll-system/internal/domain"
+
+type SalaryChangeRequest struct {
+	EmployeeID    int          `json:"employee_id"`
+	Salary        domain.Money `json:"salary"`
+	EffectiveFrom string       `json:"effective_from" binding:"required"` // YYYY-MM-DD
+	Reason        string       `json:"reason"`
+	ActorEmail    string       `json:"actor_email"`
+}
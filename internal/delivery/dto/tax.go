@@ -0,0 +1,9 @@
+package dto
+
+type TaxProfileRequest struct {
+	EmployeeID    int    `json:"employee_id"`
+	NPWP          string `json:"npwp"`
+	MaritalStatus string `json:"marital_status" binding:"required"`
+	Dependents    int    `json:"dependents"`
+	ActorEmail    string `json:"actor_email"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+// TaxStatementRequest generates the 1721-A1 statements of a tax year.
+type TaxStatementRequest struct {
+	TaxYear     int    `json:"tax_year" binding:"required"`
+	EmployeeIDs []int  `json:"employee_ids"` // empty generates a statement for every employee paid in the year
+	ActorEmail  string `json:"actor_email"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+// PayrollVarianceRequest compares a period with a previous one. Thresholds are decimal strings
+// from the query; empty values use the defaults.
+type PayrollVarianceRequest struct {
+	PeriodID                  int
+	PreviousPeriodID          int // 0 compares with the period ending before this one
+	ChangePercent             string
+	ReimbursementSpikePercent string
+	ReimbursementSpikeMinimum string
+}
@@ -0,0 +1,11 @@
+package dto
+
+type YearToDateRebuildRequest struct {
+	TaxYear    int    `json:"tax_year"` // 0 rebuilds every tax year
+	ActorEmail string `json:"actor_email"`
+}
+
+type YearToDateRebuildResponse struct {
+	TaxYear      int `json:"tax_year,omitempty"`
+	Accumulators int `json:"accumulators"` // employee and tax year pairs stored
+}
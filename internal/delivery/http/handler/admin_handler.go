@@ -1,11 +1,17 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"payroll-system/internal/delivery/dto"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
 	admin_service "payroll-system/internal/service/admin"
+	document_service "payroll-system/internal/service/document"
 	employee_service "payroll-system/internal/service/employee"
 	"payroll-system/internal/utils"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -52,7 +58,7 @@ func (h *AdminHandler) AdminCreatePayrollPeriodHandler(c *gin.Context) {
 	payrollPeriodPayload.ActorEmail = claims.Email
 	id, err := h.AdminService.CreatePayrollPeriod(c.Request.Context(), payrollPeriodPayload)
 	if err != nil {
-		c.JSON(500, dto.NewErrorResponse("Failed to create payroll period", err))
+		writeError(c, "Failed to create payroll period", err)
 		return
 	}
 	c.JSON(200, dto.NewSuccessResponse("Payroll period created successfully", dto.PayrollPeriodResponse{
@@ -74,14 +80,471 @@ func (h *AdminHandler) AdminRunPayrollPeriodHandler(c *gin.Context) {
 		return
 	}
 	payrollPeriodRunPayload.ActorEmail = claims.Email
-	err = h.AdminService.RunPayrollPeriod(c.Request.Context(), payrollPeriodRunPayload)
+	job, err := h.AdminService.RunPayrollPeriod(c.Request.Context(), payrollPeriodRunPayload)
 	if err != nil {
-		c.JSON(500, dto.NewErrorResponse("Failed to run payroll period", err))
+		writeError(c, "Failed to run payroll period", err)
 		return
 	}
 
-	c.JSON(200, dto.NewSuccessResponse("Payroll period run initiated successfully", nil))
+	c.JSON(202, dto.NewSuccessResponse("Payroll period run initiated successfully", job))
 }
+func (h *AdminHandler) AdminGetPayrollJobHandler(c *gin.Context) {
+	jobID, err := strconv.Atoi(c.Param("job_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid job ID", err))
+		return
+	}
+	job, err := h.AdminService.GetPayrollJob(c.Request.Context(), jobID)
+	if err != nil {
+		writeError(c, "Failed to retrieve payroll job", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Payroll job retrieved successfully", job))
+}
+func (h *AdminHandler) AdminGetPayrollJobsHandler(c *gin.Context) {
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+		return
+	}
+	jobs, err := h.AdminService.GetPayrollJobs(c.Request.Context(), periodID)
+	if err != nil {
+		writeError(c, "Failed to retrieve payroll jobs", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Payroll jobs retrieved successfully", jobs))
+}
+func (h *AdminHandler) AdminGetPayrollRunsHandler(c *gin.Context) {
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+		return
+	}
+	runs, err := h.AdminService.GetPayrollRuns(c.Request.Context(), periodID)
+	if err != nil {
+		writeError(c, "Failed to retrieve payroll runs", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Payroll runs retrieved successfully", runs))
+}
+func (h *AdminHandler) AdminGetPayrollVarianceHandler(c *gin.Context) {
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+		return
+	}
+	payload := dto.PayrollVarianceRequest{
+		PeriodID:                  periodID,
+		ChangePercent:             c.Query("threshold_percent"),
+		ReimbursementSpikePercent: c.Query("reimbursement_spike_percent"),
+		ReimbursementSpikeMinimum: c.Query("reimbursement_spike_minimum"),
+	}
+	if previousStr := c.Query("previous_period_id"); previousStr != "" {
+		payload.PreviousPeriodID, err = strconv.Atoi(previousStr)
+		if err != nil {
+			c.JSON(400, dto.NewErrorResponse("Invalid previous period ID", err))
+			return
+		}
+	}
+	report, err := h.AdminService.GetPayrollVariance(c.Request.Context(), payload)
+	if err != nil {
+		writeError(c, "Failed to build payroll variance report", err)
+		return
+	}
+	if c.Query("format") == "csv" {
+		csv, err := document_service.RenderPayrollVarianceCSV(report)
+		if err != nil {
+			c.JSON(500, dto.NewErrorResponse("Failed to write payroll variance report", err))
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("variance-%d-%d.csv", report.PreviousPeriodID, report.PeriodID)))
+		c.Data(200, "text/csv", csv)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Payroll variance report retrieved successfully", report))
+}
+
+func (h *AdminHandler) AdminGetPayrollJournalHandler(c *gin.Context) {
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+		return
+	}
+	journal, err := h.AdminService.GetPayrollJournal(c.Request.Context(), periodID)
+	if err != nil {
+		writeError(c, "Failed to build payroll journal", err)
+		return
+	}
+	if c.Query("format") == "csv" {
+		csv, err := document_service.RenderJournalCSV(journal)
+		if err != nil {
+			c.JSON(500, dto.NewErrorResponse("Failed to write payroll journal", err))
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", journal.Reference+".csv"))
+		c.Data(200, "text/csv", csv)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Payroll journal retrieved successfully", journal))
+}
+
+func (h *AdminHandler) AdminGetGLAccountsHandler(c *gin.Context) {
+	accounts, err := h.AdminService.GetGLAccounts(c.Request.Context())
+	if err != nil {
+		writeError(c, "Failed to retrieve GL accounts", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("GL accounts retrieved successfully", accounts))
+}
+
+func (h *AdminHandler) AdminSetGLAccountHandler(c *gin.Context) {
+	var accountPayload dto.GLAccountRequest
+	if err := c.ShouldBindJSON(&accountPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	accountPayload.ActorEmail = claims.Email
+	account, err := h.AdminService.SetGLAccount(c.Request.Context(), accountPayload)
+	if err != nil {
+		writeError(c, "Failed to set GL account", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("GL account set successfully", account))
+}
+
+func (h *AdminHandler) AdminDeleteGLAccountHandler(c *gin.Context) {
+	accountID, err := strconv.Atoi(c.Param("account_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid GL account ID", err))
+		return
+	}
+	if err := h.AdminService.DeleteGLAccount(c.Request.Context(), accountID); err != nil {
+		writeError(c, "Failed to delete GL account", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("GL account deleted successfully", nil))
+}
+
+func (h *AdminHandler) AdminCreateDisbursementHandler(c *gin.Context) {
+	var disbursementPayload dto.DisbursementRequest
+	if err := c.ShouldBindJSON(&disbursementPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	disbursementPayload.PeriodID = periodID
+	disbursementPayload.ActorEmail = claims.Email
+	batch, err := h.AdminService.CreateDisbursement(c.Request.Context(), disbursementPayload)
+	if err != nil {
+		writeError(c, "Failed to create disbursement batch", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Disbursement batch created successfully", batch))
+}
+
+func (h *AdminHandler) AdminGetDisbursementsHandler(c *gin.Context) {
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+		return
+	}
+	batches, err := h.AdminService.GetDisbursements(c.Request.Context(), periodID)
+	if err != nil {
+		writeError(c, "Failed to retrieve disbursement batches", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Disbursement batches retrieved successfully", batches))
+}
+
+func (h *AdminHandler) AdminGetDisbursementHandler(c *gin.Context) {
+	batchID, err := strconv.Atoi(c.Param("batch_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid disbursement batch ID", err))
+		return
+	}
+	batch, err := h.AdminService.GetDisbursement(c.Request.Context(), batchID)
+	if err != nil {
+		writeError(c, "Failed to retrieve disbursement batch", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Disbursement batch retrieved successfully", batch))
+}
+
+func (h *AdminHandler) AdminGetDisbursementFileHandler(c *gin.Context) {
+	batchID, err := strconv.Atoi(c.Param("batch_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid disbursement batch ID", err))
+		return
+	}
+	batch, file, format, err := h.AdminService.GetDisbursementFile(c.Request.Context(), batchID)
+	if err != nil {
+		writeError(c, "Failed to write disbursement file", err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", batch.Reference+"."+format.Extension()))
+	c.Data(200, format.ContentType(), file)
+}
+
+func (h *AdminHandler) AdminCancelDisbursementHandler(c *gin.Context) {
+	var cancelPayload dto.CancelDisbursementRequest
+	if err := c.ShouldBindJSON(&cancelPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	batchID, err := strconv.Atoi(c.Param("batch_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid disbursement batch ID", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	cancelPayload.BatchID = batchID
+	cancelPayload.ActorEmail = claims.Email
+	batch, err := h.AdminService.CancelDisbursement(c.Request.Context(), cancelPayload)
+	if err != nil {
+		writeError(c, "Failed to cancel disbursement batch", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Disbursement batch cancelled successfully", batch))
+}
+
+func (h *AdminHandler) AdminMarkDisbursementSentHandler(c *gin.Context) {
+	batchID, err := strconv.Atoi(c.Param("batch_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid disbursement batch ID", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	batch, err := h.AdminService.MarkDisbursementSent(c.Request.Context(), dto.DisbursementSentRequest{
+		BatchID:    batchID,
+		ActorEmail: claims.Email,
+	})
+	if err != nil {
+		writeError(c, "Failed to mark disbursement batch as sent", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Disbursement batch marked as sent successfully", batch))
+}
+
+func (h *AdminHandler) AdminUpdatePaymentStatusHandler(c *gin.Context) {
+	var statusPayload dto.PaymentStatusRequest
+	if err := c.ShouldBindJSON(&statusPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	batchID, err := strconv.Atoi(c.Param("batch_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid disbursement batch ID", err))
+		return
+	}
+	itemID, err := strconv.Atoi(c.Param("item_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid disbursement item ID", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	statusPayload.BatchID = batchID
+	statusPayload.ItemID = itemID
+	statusPayload.ActorEmail = claims.Email
+	item, err := h.AdminService.UpdatePaymentStatus(c.Request.Context(), statusPayload)
+	if err != nil {
+		writeError(c, "Failed to update payment status", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Payment status updated successfully", item))
+}
+
+func (h *AdminHandler) AdminImportBankStatementHandler(c *gin.Context) {
+	var statementPayload dto.BankStatementImportRequest
+	if err := c.ShouldBindJSON(&statementPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	statementPayload.ActorEmail = claims.Email
+	statement, err := h.AdminService.ImportBankStatement(c.Request.Context(), statementPayload)
+	if err != nil {
+		writeError(c, "Failed to import bank statement", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Bank statement imported successfully", statement))
+}
+
+func (h *AdminHandler) AdminGetReconciliationHandler(c *gin.Context) {
+	periodID := 0
+	if value := c.Query("period_id"); value != "" {
+		var err error
+		periodID, err = strconv.Atoi(value)
+		if err != nil {
+			c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+			return
+		}
+	}
+	report, err := h.AdminService.GetReconciliationReport(c.Request.Context(), periodID)
+	if err != nil {
+		writeError(c, "Failed to retrieve reconciliation report", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Reconciliation report retrieved successfully", report))
+}
+
+func (h *AdminHandler) AdminGetPayrollRunHandler(c *gin.Context) {
+	runID, err := strconv.Atoi(c.Param("run_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid run ID", err))
+		return
+	}
+	run, err := h.AdminService.GetPayrollRun(c.Request.Context(), runID)
+	if err != nil {
+		writeError(c, "Failed to retrieve payroll run", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Payroll run retrieved successfully", run))
+}
+
+func (h *AdminHandler) AdminApprovePayrollRunHandler(c *gin.Context) {
+	payload, ok := bindPayrollRunDecisionRequest(c)
+	if !ok {
+		return
+	}
+	run, err := h.AdminService.ApprovePayrollRun(c.Request.Context(), payload)
+	if err != nil {
+		writeError(c, "Failed to approve payroll run", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Payroll run approved and period locked successfully", run))
+}
+
+func (h *AdminHandler) AdminRejectPayrollRunHandler(c *gin.Context) {
+	payload, ok := bindPayrollRunDecisionRequest(c)
+	if !ok {
+		return
+	}
+	run, err := h.AdminService.RejectPayrollRun(c.Request.Context(), payload)
+	if err != nil {
+		writeError(c, "Failed to reject payroll run", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Payroll run rejected successfully", run))
+}
+
+// bindPayrollRunDecisionRequest reads the optional comment of an approval or rejection.
+func bindPayrollRunDecisionRequest(c *gin.Context) (dto.PayrollRunDecisionRequest, bool) {
+	var payload dto.PayrollRunDecisionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+			return payload, false
+		}
+	}
+	runID, err := strconv.Atoi(c.Param("run_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid run ID", err))
+		return payload, false
+	}
+	payload.RunID = runID
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return payload, false
+	}
+	payload.ActorEmail = claims.Email
+	return payload, true
+}
+
+func (h *AdminHandler) AdminPreviewPayrollPeriodHandler(c *gin.Context) {
+
+	var payrollPreviewPayload dto.PayrollRequest
+	if err := c.ShouldBindJSON(&payrollPreviewPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	payrollPreviewPayload.ActorEmail = claims.Email
+	preview, err := h.AdminService.PreviewPayrollPeriod(c.Request.Context(), payrollPreviewPayload)
+	if err != nil {
+		writeError(c, "Failed to preview payroll period", err)
+		return
+	}
+
+	c.JSON(200, dto.NewSuccessResponse("Payroll period previewed successfully", preview))
+}
+func (h *AdminHandler) AdminReopenPayrollPeriodHandler(c *gin.Context) {
+	var reopenPayload dto.ReopenPayrollPeriodRequest
+	if err := c.ShouldBindJSON(&reopenPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+		return
+	}
+	reopenPayload.PeriodID = periodID
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	reopenPayload.ActorID = claims.UserID
+	reopenPayload.ActorRole = claims.Role
+	reopenPayload.ActorEmail = claims.Email
+	reopenPayload.IPAddress = c.ClientIP()
+	reopen, err := h.AdminService.ReopenPayrollPeriod(c.Request.Context(), reopenPayload)
+	if err != nil {
+		writeError(c, "Failed to reopen payroll period", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Payroll period reopened successfully", reopen))
+}
+
+func (h *AdminHandler) AdminGetPayrollReopensHandler(c *gin.Context) {
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+		return
+	}
+	reopens, err := h.AdminService.GetPayrollReopens(c.Request.Context(), periodID)
+	if err != nil {
+		writeError(c, "Failed to retrieve payroll period reopens", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Payroll period reopens retrieved successfully", reopens))
+}
+
 func (h *AdminHandler) AdminViewPayrollSummaryHandler(c *gin.Context) {
 	var payrollSummaryPayload dto.PayrollRequest
 	periodIdStr := c.Param("period_id")
@@ -105,8 +568,963 @@ func (h *AdminHandler) AdminViewPayrollSummaryHandler(c *gin.Context) {
 	payrollSummaryPayload.ActorEmail = claims.Email
 	summary, err := h.AdminService.ViewPayrollSummary(c.Request.Context(), payrollSummaryPayload.PeriodID)
 	if err != nil {
-		c.JSON(500, dto.NewErrorResponse("Failed to retrieve payroll summary", err))
+		writeError(c, "Failed to retrieve payroll summary", err)
 		return
 	}
 	c.JSON(200, dto.NewSuccessResponse("Payroll summary retrieved successfully", summary))
 }
+
+func (h *AdminHandler) AdminCreatePayRuleSetHandler(c *gin.Context) {
+	var payRuleSetPayload dto.PayRuleSetRequest
+	if err := c.ShouldBindJSON(&payRuleSetPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	payRuleSetPayload.ActorEmail = claims.Email
+	rules, err := h.AdminService.CreatePayRuleSet(c.Request.Context(), payRuleSetPayload)
+	if err != nil {
+		writeError(c, "Failed to create pay rule set", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Pay rule set created successfully", rules))
+}
+func (h *AdminHandler) AdminGetPayRuleSetsHandler(c *gin.Context) {
+	ruleSets, err := h.AdminService.GetPayRuleSets(c.Request.Context())
+	if err != nil {
+		writeError(c, "Failed to retrieve pay rule sets", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Pay rule sets retrieved successfully", ruleSets))
+}
+func (h *AdminHandler) AdminAssignPayRuleSetHandler(c *gin.Context) {
+	var assignPayload dto.AssignPayRuleSetRequest
+	if err := c.ShouldBindJSON(&assignPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+		return
+	}
+	assignPayload.PeriodID = periodID
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	assignPayload.ActorEmail = claims.Email
+	if err := h.AdminService.AssignPayRuleSet(c.Request.Context(), assignPayload); err != nil {
+		writeError(c, "Failed to assign pay rule set", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Pay rule set assigned successfully", nil))
+}
+
+func (h *AdminHandler) AdminUpsertTaxProfileHandler(c *gin.Context) {
+	var taxProfilePayload dto.TaxProfileRequest
+	if err := c.ShouldBindJSON(&taxProfilePayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	employeeID, err := strconv.Atoi(c.Param("employee_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid employee ID", err))
+		return
+	}
+	taxProfilePayload.EmployeeID = employeeID
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	taxProfilePayload.ActorEmail = claims.Email
+	if err := h.AdminService.UpsertTaxProfile(c.Request.Context(), taxProfilePayload); err != nil {
+		writeError(c, "Failed to save tax profile", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Tax profile saved successfully", nil))
+}
+func (h *AdminHandler) AdminGetTaxProfileHandler(c *gin.Context) {
+	employeeID, err := strconv.Atoi(c.Param("employee_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid employee ID", err))
+		return
+	}
+	profile, err := h.AdminService.GetTaxProfile(c.Request.Context(), employeeID)
+	if err != nil {
+		writeError(c, "Failed to retrieve tax profile", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Tax profile retrieved successfully", profile))
+}
+
+func (h *AdminHandler) AdminUpdateEmploymentHandler(c *gin.Context) {
+	var employmentPayload dto.EmploymentRequest
+	if err := c.ShouldBindJSON(&employmentPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	employeeID, err := strconv.Atoi(c.Param("employee_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid employee ID", err))
+		return
+	}
+	employmentPayload.EmployeeID = employeeID
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	employmentPayload.ActorEmail = claims.Email
+	employee, err := h.AdminService.UpdateEmployment(c.Request.Context(), employmentPayload)
+	if err != nil {
+		writeError(c, "Failed to update employment", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Employment updated successfully", employee))
+}
+
+func (h *AdminHandler) AdminCreateSalaryChangeHandler(c *gin.Context) {
+	var salaryPayload dto.SalaryChangeRequest
+	if err := c.ShouldBindJSON(&salaryPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	employeeID, err := strconv.Atoi(c.Param("employee_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid employee ID", err))
+		return
+	}
+	salaryPayload.EmployeeID = employeeID
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	salaryPayload.ActorEmail = claims.Email
+	change, err := h.AdminService.CreateSalaryChange(c.Request.Context(), salaryPayload)
+	if err != nil {
+		writeError(c, "Failed to save salary change", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Salary change saved successfully", change))
+}
+
+func (h *AdminHandler) AdminGetSalaryHistoryHandler(c *gin.Context) {
+	employeeID, err := strconv.Atoi(c.Param("employee_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid employee ID", err))
+		return
+	}
+	history, err := h.AdminService.GetSalaryHistory(c.Request.Context(), employeeID)
+	if err != nil {
+		writeError(c, "Failed to retrieve salary history", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Salary history retrieved successfully", history))
+}
+
+func (h *AdminHandler) AdminSetBankAccountHandler(c *gin.Context) {
+	var accountPayload dto.BankAccountRequest
+	if err := c.ShouldBindJSON(&accountPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	employeeID, err := strconv.Atoi(c.Param("employee_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid employee ID", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	accountPayload.EmployeeID = employeeID
+	accountPayload.ActorEmail = claims.Email
+	account, err := h.AdminService.SetBankAccount(c.Request.Context(), accountPayload)
+	if err != nil {
+		writeError(c, "Failed to save bank account", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Bank account saved successfully", account))
+}
+
+func (h *AdminHandler) AdminGetBankAccountHandler(c *gin.Context) {
+	employeeID, err := strconv.Atoi(c.Param("employee_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid employee ID", err))
+		return
+	}
+	account, err := h.AdminService.GetBankAccount(c.Request.Context(), employeeID)
+	if err != nil {
+		writeError(c, "Failed to retrieve bank account", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Bank account retrieved successfully", account))
+}
+
+func (h *AdminHandler) AdminCreatePayComponentHandler(c *gin.Context) {
+	var componentPayload dto.PayComponentRequest
+	if err := c.ShouldBindJSON(&componentPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	componentPayload.ActorEmail = claims.Email
+	component, err := h.AdminService.CreatePayComponent(c.Request.Context(), componentPayload)
+	if err != nil {
+		writeError(c, "Failed to create pay component", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Pay component created successfully", component))
+}
+
+func (h *AdminHandler) AdminUpdatePayComponentHandler(c *gin.Context) {
+	var componentPayload dto.PayComponentRequest
+	if err := c.ShouldBindJSON(&componentPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	componentID, err := strconv.Atoi(c.Param("component_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid pay component ID", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	componentPayload.ActorEmail = claims.Email
+	component, err := h.AdminService.UpdatePayComponent(c.Request.Context(), componentID, componentPayload)
+	if err != nil {
+		writeError(c, "Failed to update pay component", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Pay component updated successfully", component))
+}
+
+func (h *AdminHandler) AdminGetPayComponentsHandler(c *gin.Context) {
+	components, err := h.AdminService.GetPayComponents(c.Request.Context())
+	if err != nil {
+		writeError(c, "Failed to retrieve pay components", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Pay components retrieved successfully", components))
+}
+
+func (h *AdminHandler) AdminAssignPayComponentHandler(c *gin.Context) {
+	var assignmentPayload dto.PayComponentAssignmentRequest
+	if err := c.ShouldBindJSON(&assignmentPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	employeeID, err := strconv.Atoi(c.Param("employee_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid employee ID", err))
+		return
+	}
+	assignmentPayload.EmployeeID = employeeID
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	assignmentPayload.ActorEmail = claims.Email
+	assignment, err := h.AdminService.AssignPayComponent(c.Request.Context(), assignmentPayload)
+	if err != nil {
+		writeError(c, "Failed to assign pay component", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Pay component assigned successfully", assignment))
+}
+
+func (h *AdminHandler) AdminUpdatePayComponentAssignmentHandler(c *gin.Context) {
+	var assignmentPayload dto.PayComponentAssignmentRequest
+	if err := c.ShouldBindJSON(&assignmentPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	employeeID, err := strconv.Atoi(c.Param("employee_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid employee ID", err))
+		return
+	}
+	assignmentID, err := strconv.Atoi(c.Param("assignment_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid assignment ID", err))
+		return
+	}
+	assignmentPayload.EmployeeID = employeeID
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	assignmentPayload.ActorEmail = claims.Email
+	assignment, err := h.AdminService.UpdatePayComponentAssignment(c.Request.Context(), assignmentID, assignmentPayload)
+	if err != nil {
+		writeError(c, "Failed to update pay component assignment", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Pay component assignment updated successfully", assignment))
+}
+
+func (h *AdminHandler) AdminDeletePayComponentAssignmentHandler(c *gin.Context) {
+	employeeID, err := strconv.Atoi(c.Param("employee_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid employee ID", err))
+		return
+	}
+	assignmentID, err := strconv.Atoi(c.Param("assignment_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid assignment ID", err))
+		return
+	}
+	if err := h.AdminService.DeletePayComponentAssignment(c.Request.Context(), employeeID, assignmentID); err != nil {
+		writeError(c, "Failed to delete pay component assignment", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Pay component assignment deleted successfully", nil))
+}
+
+func (h *AdminHandler) AdminGetEmployeePayComponentsHandler(c *gin.Context) {
+	employeeID, err := strconv.Atoi(c.Param("employee_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid employee ID", err))
+		return
+	}
+	assignments, err := h.AdminService.GetEmployeePayComponents(c.Request.Context(), employeeID)
+	if err != nil {
+		writeError(c, "Failed to retrieve pay components", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Pay components retrieved successfully", assignments))
+}
+
+func (h *AdminHandler) AdminCreateLoanHandler(c *gin.Context) {
+	var loanPayload dto.LoanRequest
+	if err := c.ShouldBindJSON(&loanPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	employeeID, err := strconv.Atoi(c.Param("employee_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid employee ID", err))
+		return
+	}
+	loanPayload.EmployeeID = employeeID
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	loanPayload.ActorEmail = claims.Email
+	loan, err := h.AdminService.CreateLoan(c.Request.Context(), loanPayload)
+	if err != nil {
+		writeError(c, "Failed to create loan", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Loan created successfully", loan))
+}
+
+func (h *AdminHandler) AdminGetEmployeeLoansHandler(c *gin.Context) {
+	employeeID, err := strconv.Atoi(c.Param("employee_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid employee ID", err))
+		return
+	}
+	loans, err := h.AdminService.GetEmployeeLoans(c.Request.Context(), employeeID)
+	if err != nil {
+		writeError(c, "Failed to retrieve loans", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Loans retrieved successfully", loans))
+}
+
+func (h *AdminHandler) AdminGetLoanHandler(c *gin.Context) {
+	loanID, err := strconv.Atoi(c.Param("loan_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid loan ID", err))
+		return
+	}
+	loan, err := h.AdminService.GetLoan(c.Request.Context(), loanID)
+	if err != nil {
+		writeError(c, "Failed to retrieve loan", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Loan retrieved successfully", loan))
+}
+
+func (h *AdminHandler) AdminCancelLoanHandler(c *gin.Context) {
+	loanID, err := strconv.Atoi(c.Param("loan_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid loan ID", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	loan, err := h.AdminService.CancelLoan(c.Request.Context(), loanID, claims.Email)
+	if err != nil {
+		writeError(c, "Failed to cancel loan", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Loan cancelled successfully", loan))
+}
+
+func (h *AdminHandler) bindBonusRunRequest(c *gin.Context) (dto.BonusRunRequest, bool) {
+	var bonusRunPayload dto.BonusRunRequest
+	if err := c.ShouldBindJSON(&bonusRunPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return bonusRunPayload, false
+	}
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+		return bonusRunPayload, false
+	}
+	bonusRunPayload.PeriodID = periodID
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return bonusRunPayload, false
+	}
+	bonusRunPayload.ActorEmail = claims.Email
+	return bonusRunPayload, true
+}
+
+func (h *AdminHandler) AdminRunBonusHandler(c *gin.Context) {
+	bonusRunPayload, ok := h.bindBonusRunRequest(c)
+	if !ok {
+		return
+	}
+	run, err := h.AdminService.RunBonus(c.Request.Context(), bonusRunPayload)
+	if err != nil {
+		writeError(c, "Failed to run bonus", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Bonus run completed successfully", run))
+}
+
+func (h *AdminHandler) AdminPreviewBonusHandler(c *gin.Context) {
+	bonusRunPayload, ok := h.bindBonusRunRequest(c)
+	if !ok {
+		return
+	}
+	run, err := h.AdminService.PreviewBonus(c.Request.Context(), bonusRunPayload)
+	if err != nil {
+		writeError(c, "Failed to preview bonus", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Bonus previewed successfully", run))
+}
+
+func (h *AdminHandler) AdminGetBonusRunsHandler(c *gin.Context) {
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+		return
+	}
+	runs, err := h.AdminService.GetBonusRuns(c.Request.Context(), periodID)
+	if err != nil {
+		writeError(c, "Failed to retrieve bonus runs", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Bonus runs retrieved successfully", runs))
+}
+
+func (h *AdminHandler) AdminGetBonusRunHandler(c *gin.Context) {
+	runID, err := strconv.Atoi(c.Param("run_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid bonus run ID", err))
+		return
+	}
+	run, err := h.AdminService.GetBonusRun(c.Request.Context(), runID)
+	if err != nil {
+		writeError(c, "Failed to retrieve bonus run", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Bonus run retrieved successfully", run))
+}
+
+func (h *AdminHandler) AdminGetYearToDateHandler(c *gin.Context) {
+	taxYear, ok := taxYearQuery(c)
+	if !ok {
+		return
+	}
+	ytd, err := h.AdminService.GetYearToDate(c.Request.Context(), taxYear)
+	if err != nil {
+		writeError(c, "Failed to retrieve year-to-date totals", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Year-to-date totals retrieved successfully", ytd))
+}
+
+func (h *AdminHandler) AdminGetEmployeeYearToDateHandler(c *gin.Context) {
+	employeeID, err := strconv.Atoi(c.Param("employee_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid employee ID", err))
+		return
+	}
+	taxYear, ok := taxYearQuery(c)
+	if !ok {
+		return
+	}
+	ytd, err := h.AdminService.GetEmployeeYearToDate(c.Request.Context(), employeeID, taxYear)
+	if err != nil {
+		writeError(c, "Failed to retrieve year-to-date totals", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Year-to-date totals retrieved successfully", ytd))
+}
+
+func (h *AdminHandler) AdminRebuildYearToDateHandler(c *gin.Context) {
+	var rebuildPayload dto.YearToDateRebuildRequest
+	if err := c.ShouldBindJSON(&rebuildPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	rebuildPayload.ActorEmail = claims.Email
+	rebuild, err := h.AdminService.RebuildYearToDate(c.Request.Context(), rebuildPayload)
+	if err != nil {
+		writeError(c, "Failed to rebuild year-to-date totals", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Year-to-date totals rebuilt successfully", rebuild))
+}
+
+func (h *AdminHandler) AdminGenerateTaxStatementsHandler(c *gin.Context) {
+	var statementPayload dto.TaxStatementRequest
+	if err := c.ShouldBindJSON(&statementPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	statementPayload.ActorEmail = claims.Email
+	statements, err := h.AdminService.GenerateTaxStatements(c.Request.Context(), statementPayload)
+	if err != nil {
+		writeError(c, "Failed to generate tax statements", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Tax statements generated successfully", statements))
+}
+
+func (h *AdminHandler) AdminGetTaxStatementsHandler(c *gin.Context) {
+	taxYear, ok := taxYearQuery(c)
+	if !ok {
+		return
+	}
+	statements, err := h.AdminService.GetTaxStatements(c.Request.Context(), taxYear)
+	if err != nil {
+		writeError(c, "Failed to retrieve tax statements", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Tax statements retrieved successfully", statements))
+}
+
+func (h *AdminHandler) AdminGetTaxStatementHandler(c *gin.Context) {
+	employeeID, err := strconv.Atoi(c.Param("employee_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid employee ID", err))
+		return
+	}
+	taxYear, err := strconv.Atoi(c.Param("tax_year"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid tax year", err))
+		return
+	}
+	statement, err := h.AdminService.GetTaxStatement(c.Request.Context(), employeeID, taxYear)
+	if err != nil {
+		writeError(c, "Failed to retrieve tax statement", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Tax statement retrieved successfully", statement))
+}
+
+func (h *AdminHandler) AdminGetTaxStatementPDFHandler(c *gin.Context) {
+	employeeID, err := strconv.Atoi(c.Param("employee_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid employee ID", err))
+		return
+	}
+	taxYear, err := strconv.Atoi(c.Param("tax_year"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid tax year", err))
+		return
+	}
+	pdf, err := h.AdminService.GetTaxStatementPDF(c.Request.Context(), employeeID, taxYear)
+	if err != nil {
+		writeError(c, "Failed to render tax statement", err)
+		return
+	}
+	writePDF(c, fmt.Sprintf("1721-A1-%d-%d.pdf", taxYear, employeeID), pdf)
+}
+
+func (h *AdminHandler) AdminGetPayslipPDFHandler(c *gin.Context) {
+	employeeID, err := strconv.Atoi(c.Param("employee_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid employee ID", err))
+		return
+	}
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+		return
+	}
+	pdf, err := h.AdminService.GetPayslipPDF(c.Request.Context(), employeeID, periodID)
+	if err != nil {
+		writeError(c, "Failed to render payslip", err)
+		return
+	}
+	writePDF(c, fmt.Sprintf("payslip-%d-%d.pdf", periodID, employeeID), pdf)
+}
+
+func (h *AdminHandler) AdminGetPayslipHTMLHandler(c *gin.Context) {
+	employeeID, err := strconv.Atoi(c.Param("employee_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid employee ID", err))
+		return
+	}
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+		return
+	}
+	page, err := h.AdminService.GetPayslipHTML(c.Request.Context(), employeeID, periodID)
+	if err != nil {
+		writeError(c, "Failed to render payslip", err)
+		return
+	}
+	writeHTML(c, page)
+}
+
+func (h *AdminHandler) AdminSavePayslipTemplateHandler(c *gin.Context) {
+	var templatePayload dto.PayslipTemplateRequest
+	if err := c.ShouldBindJSON(&templatePayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	templatePayload.Company = c.Param("company")
+	templatePayload.ActorEmail = claims.Email
+	template, err := h.AdminService.SavePayslipTemplate(c.Request.Context(), templatePayload)
+	if err != nil {
+		writeError(c, "Failed to save payslip template", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Payslip template saved successfully", template))
+}
+
+func (h *AdminHandler) AdminGetPayslipTemplatesHandler(c *gin.Context) {
+	templates, err := h.AdminService.GetPayslipTemplates(c.Request.Context(), c.Param("company"))
+	if err != nil {
+		writeError(c, "Failed to retrieve payslip templates", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Payslip templates retrieved successfully", templates))
+}
+
+func (h *AdminHandler) AdminGetPayslipTemplateHandler(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid version", err))
+		return
+	}
+	template, err := h.AdminService.GetPayslipTemplate(c.Request.Context(), c.Param("company"), version)
+	if err != nil {
+		writeError(c, "Failed to retrieve payslip template", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Payslip template retrieved successfully", template))
+}
+
+func (h *AdminHandler) AdminPreviewPayslipTemplateHandler(c *gin.Context) {
+	var previewPayload dto.PayslipTemplatePreviewRequest
+	if err := c.ShouldBindJSON(&previewPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	preview, err := h.AdminService.PreviewPayslipTemplate(c.Request.Context(), previewPayload)
+	if err != nil {
+		writeError(c, "Failed to preview payslip template", err)
+		return
+	}
+	if previewPayload.Format == domain.PayslipFormatPDF {
+		writePDF(c, "payslip-preview.pdf", preview)
+		return
+	}
+	writeHTML(c, preview)
+}
+
+func (h *AdminHandler) AdminDistributePayslipEmailsHandler(c *gin.Context) {
+	var emailPayload dto.PayslipEmailRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&emailPayload); err != nil {
+			c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+			return
+		}
+	}
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	emailPayload.PeriodID = periodID
+	emailPayload.ActorEmail = claims.Email
+	distribution, err := h.AdminService.DistributePayslipEmails(c.Request.Context(), emailPayload)
+	if err != nil {
+		writeError(c, "Failed to queue payslip emails", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Payslip emails queued successfully", distribution))
+}
+
+func (h *AdminHandler) AdminResendPayslipEmailHandler(c *gin.Context) {
+	var resendPayload dto.PayslipEmailResendRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&resendPayload); err != nil {
+			c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+			return
+		}
+	}
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+		return
+	}
+	employeeID, err := strconv.Atoi(c.Param("employee_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid employee ID", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	resendPayload.PeriodID = periodID
+	resendPayload.EmployeeID = employeeID
+	resendPayload.ActorEmail = claims.Email
+	email, err := h.AdminService.ResendPayslipEmail(c.Request.Context(), resendPayload)
+	if err != nil {
+		writeError(c, "Failed to resend payslip email", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Payslip email queued successfully", email))
+}
+
+func (h *AdminHandler) AdminGetPayslipEmailsHandler(c *gin.Context) {
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+		return
+	}
+	emails, err := h.AdminService.GetPayslipEmails(c.Request.Context(), periodID, c.Query("status"))
+	if err != nil {
+		writeError(c, "Failed to retrieve payslip emails", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Payslip emails retrieved successfully", emails))
+}
+
+func (h *AdminHandler) AdminCreateBPJSRateHandler(c *gin.Context) {
+	var bpjsRatePayload dto.BPJSRateRequest
+	if err := c.ShouldBindJSON(&bpjsRatePayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	bpjsRatePayload.ActorEmail = claims.Email
+	id, err := h.AdminService.CreateBPJSRate(c.Request.Context(), bpjsRatePayload)
+	if err != nil {
+		writeError(c, "Failed to create BPJS rate", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("BPJS rate created successfully", gin.H{"id": id}))
+}
+func (h *AdminHandler) AdminGetBPJSRatesHandler(c *gin.Context) {
+	rates, err := h.AdminService.GetBPJSRates(c.Request.Context())
+	if err != nil {
+		writeError(c, "Failed to retrieve BPJS rates", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("BPJS rates retrieved successfully", rates))
+}
+
+func (h *AdminHandler) AdminCreateHolidayHandler(c *gin.Context) {
+	var holidayPayload dto.HolidayRequest
+	if err := c.ShouldBindJSON(&holidayPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	holidayPayload.ActorEmail = claims.Email
+	holiday, err := h.AdminService.CreateHoliday(c.Request.Context(), holidayPayload)
+	if err != nil {
+		writeError(c, "Failed to create holiday", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Holiday created successfully", holiday))
+}
+
+func (h *AdminHandler) AdminUpdateHolidayHandler(c *gin.Context) {
+	var holidayPayload dto.HolidayRequest
+	if err := c.ShouldBindJSON(&holidayPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	holidayID, err := strconv.Atoi(c.Param("holiday_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid holiday ID", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	holidayPayload.ActorEmail = claims.Email
+	holiday, err := h.AdminService.UpdateHoliday(c.Request.Context(), holidayID, holidayPayload)
+	if err != nil {
+		writeError(c, "Failed to update holiday", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Holiday updated successfully", holiday))
+}
+
+func (h *AdminHandler) AdminDeleteHolidayHandler(c *gin.Context) {
+	holidayID, err := strconv.Atoi(c.Param("holiday_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid holiday ID", err))
+		return
+	}
+	if err := h.AdminService.DeleteHoliday(c.Request.Context(), holidayID); err != nil {
+		writeError(c, "Failed to delete holiday", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Holiday deleted successfully", nil))
+}
+
+func (h *AdminHandler) AdminGetHolidaysHandler(c *gin.Context) {
+	year := time.Now().Year()
+	if yearStr := c.Query("year"); yearStr != "" {
+		parsed, err := strconv.Atoi(yearStr)
+		if err != nil {
+			c.JSON(400, dto.NewErrorResponse("Invalid year", err))
+			return
+		}
+		year = parsed
+	}
+	holidays, err := h.AdminService.GetHolidays(c.Request.Context(), year)
+	if err != nil {
+		writeError(c, "Failed to retrieve holidays", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Holidays retrieved successfully", holidays))
+}
+
+func (h *AdminHandler) AdminImportHolidaysHandler(c *gin.Context) {
+	var importPayload dto.HolidayImportRequest
+	if err := c.ShouldBindJSON(&importPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	importPayload.ActorEmail = claims.Email
+	holidays, err := h.AdminService.ImportHolidays(c.Request.Context(), importPayload)
+	if err != nil {
+		writeError(c, "Failed to import holidays", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Holidays imported successfully", holidays))
+}
+
+// taxYearQuery reads the tax_year query parameter, defaulting to the current year. It writes
+// the error response and returns false when the parameter is not a number.
+func taxYearQuery(c *gin.Context) (int, bool) {
+	taxYear := time.Now().Year()
+	if taxYearStr := c.Query("tax_year"); taxYearStr != "" {
+		parsed, err := strconv.Atoi(taxYearStr)
+		if err != nil {
+			c.JSON(400, dto.NewErrorResponse("Invalid tax year", err))
+			return 0, false
+		}
+		taxYear = parsed
+	}
+	return taxYear, true
+}
+
+// writeHTML sends a rendered page.
+func writeHTML(c *gin.Context, page []byte) {
+	c.Data(200, "text/html; charset=utf-8", page)
+}
+
+// writePDF sends the document as a download with the file name.
+func writePDF(c *gin.Context, filename string, pdf []byte) {
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(200, "application/pdf", pdf)
+}
+
+// errorStatus is the HTTP status answered for each kind of error a caller can act on.
+var errorStatus = map[error_const.Kind]int{
+	error_const.KindInvalid:      400,
+	error_const.KindUnauthorized: 401,
+	error_const.KindForbidden:    403,
+	error_const.KindNotFound:     404,
+	error_const.KindConflict:     409,
+}
+
+// writeError answers a failed service call. Errors the caller can act on are sent with
+// their own message and the status of their kind; anything else is a 500 with message.
+func writeError(c *gin.Context, message string, err error) {
+	var known *error_const.Error
+	if errors.As(err, &known) {
+		c.JSON(errorStatus[known.Kind], dto.NewErrorResponse(err.Error(), err))
+		return
+	}
+	c.JSON(500, dto.NewErrorResponse(message, err))
+}
@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"payroll-system/internal/error_const"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -19,3 +22,24 @@ func TestAdminLoginHandler_BadRequest(t *testing.T) {
 		t.Errorf("expected 400, got %d", w.Code)
 	}
 }
+
+func TestWriteError_StatusByKind(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{error_const.ErrInvalidTaxYear, 400},
+		{error_const.ErrPayrollRunSelfApproval, 403},
+		{error_const.ErrPayrollRunNotFound, 404},
+		{fmt.Errorf("approve run 3: %w", error_const.ErrPayrollPeriodLocked), 409},
+		{errors.New("connection refused"), 500},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		writeError(c, "Failed to approve payroll run", tt.err)
+		if w.Code != tt.want {
+			t.Errorf("%v: expected %d, got %d", tt.err, tt.want, w.Code)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"fmt"
 	"payroll-system/internal/delivery/dto"
 	employee_service "payroll-system/internal/service/employee"
 	"payroll-system/internal/utils"
@@ -48,7 +49,7 @@ func (h *EmployeeHandler) EmployeeAttendanceHandler(c *gin.Context) {
 	attendancePayload.EmployeeID = claims.UserID
 	attendancePayload.EmployeeEmail = claims.Email
 	if err := h.empService.RecordAttendance(c.Request.Context(), attendancePayload); err != nil {
-		c.JSON(500, dto.NewErrorResponse("Failed to record attendance", err))
+		writeError(c, "Failed to record attendance", err)
 		return
 	}
 	c.JSON(200, dto.NewSuccessResponse("Attendance recorded successfully", nil))
@@ -67,7 +68,7 @@ func (h *EmployeeHandler) EmployeeOvertimeSubmissionHandler(c *gin.Context) {
 	overtimePayload.EmployeeID = claims.UserID
 	overtimePayload.EmployeeEmail = claims.Email
 	if err := h.empService.SubmitOvertime(c.Request.Context(), overtimePayload); err != nil {
-		c.JSON(500, dto.NewErrorResponse("Failed to submit overtime", err))
+		writeError(c, "Failed to submit overtime", err)
 		return
 	}
 	c.JSON(200, dto.NewSuccessResponse("Overtime submitted successfully", nil))
@@ -86,7 +87,7 @@ func (h *EmployeeHandler) EmployeeReimbursementHandler(c *gin.Context) {
 	reimbursementPayload.EmployeeID = claims.UserID
 	reimbursementPayload.EmployeeEmail = claims.Email
 	if err := h.empService.SubmitReimbursement(c.Request.Context(), reimbursementPayload); err != nil {
-		c.JSON(500, dto.NewErrorResponse("Failed to submit reimbursement", err))
+		writeError(c, "Failed to submit reimbursement", err)
 		return
 	}
 	c.JSON(200, dto.NewSuccessResponse("Reimbursement submitted successfully", nil))
@@ -116,9 +117,182 @@ func (h *EmployeeHandler) EmployeePayslipHandler(c *gin.Context) {
 
 	payslip, err := h.empService.GetPayslip(c.Request.Context(), payslipPayload)
 	if err != nil {
-		c.JSON(500, dto.NewErrorResponse("Failed to retrieve payslip", err))
+		writeError(c, "Failed to retrieve payslip", err)
 		return
 	}
 
 	c.JSON(200, dto.NewSuccessResponse("Payslip retrieved successfully", payslip))
 }
+
+func (h *EmployeeHandler) EmployeePayslipPDFHandler(c *gin.Context) {
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil || periodID == 0 {
+		c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	pdf, err := h.empService.GetPayslipPDF(c.Request.Context(), dto.PayrollRequest{
+		EmployeeID: claims.UserID,
+		PeriodID:   periodID,
+		ActorEmail: claims.Email,
+	})
+	if err != nil {
+		writeError(c, "Failed to render payslip", err)
+		return
+	}
+	writePDF(c, fmt.Sprintf("payslip-%d.pdf", periodID), pdf)
+}
+
+func (h *EmployeeHandler) EmployeePayslipHTMLHandler(c *gin.Context) {
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil || periodID == 0 {
+		c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	page, err := h.empService.GetPayslipHTML(c.Request.Context(), dto.PayrollRequest{
+		EmployeeID: claims.UserID,
+		PeriodID:   periodID,
+		ActorEmail: claims.Email,
+	})
+	if err != nil {
+		writeError(c, "Failed to render payslip", err)
+		return
+	}
+	writeHTML(c, page)
+}
+
+func (h *EmployeeHandler) EmployeeBonusPayslipsHandler(c *gin.Context) {
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil || periodID == 0 {
+		c.JSON(400, dto.NewErrorResponse("Invalid period ID", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	payslips, err := h.empService.GetBonusPayslips(c.Request.Context(), claims.UserID, periodID)
+	if err != nil {
+		writeError(c, "Failed to retrieve bonus payslips", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Bonus payslips retrieved successfully", payslips))
+}
+
+func (h *EmployeeHandler) EmployeeYearToDateHandler(c *gin.Context) {
+	taxYear, ok := taxYearQuery(c)
+	if !ok {
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	ytd, err := h.empService.GetYearToDate(c.Request.Context(), claims.UserID, taxYear)
+	if err != nil {
+		writeError(c, "Failed to retrieve year-to-date totals", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Year-to-date totals retrieved successfully", ytd))
+}
+
+func (h *EmployeeHandler) EmployeeTaxStatementHandler(c *gin.Context) {
+	taxYear, err := strconv.Atoi(c.Param("tax_year"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid tax year", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	statement, err := h.empService.GetTaxStatement(c.Request.Context(), claims.UserID, taxYear)
+	if err != nil {
+		writeError(c, "Failed to retrieve tax statement", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Tax statement retrieved successfully", statement))
+}
+
+func (h *EmployeeHandler) EmployeeTaxStatementPDFHandler(c *gin.Context) {
+	taxYear, err := strconv.Atoi(c.Param("tax_year"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid tax year", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	pdf, err := h.empService.GetTaxStatementPDF(c.Request.Context(), claims.UserID, taxYear)
+	if err != nil {
+		writeError(c, "Failed to render tax statement", err)
+		return
+	}
+	writePDF(c, fmt.Sprintf("1721-A1-%d.pdf", taxYear), pdf)
+}
+
+func (h *EmployeeHandler) EmployeeLoansHandler(c *gin.Context) {
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	loans, err := h.empService.GetLoans(c.Request.Context(), claims.UserID)
+	if err != nil {
+		writeError(c, "Failed to retrieve loans", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Loans retrieved successfully", loans))
+}
+
+func (h *EmployeeHandler) EmployeeLoanHandler(c *gin.Context) {
+	loanID, err := strconv.Atoi(c.Param("loan_id"))
+	if err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid loan ID", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	loan, err := h.empService.GetLoan(c.Request.Context(), claims.UserID, loanID)
+	if err != nil {
+		writeError(c, "Failed to retrieve loan", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Loan retrieved successfully", loan))
+}
+
+func (h *EmployeeHandler) EmployeeSetPayslipPasswordHandler(c *gin.Context) {
+	var passwordPayload dto.PayslipPasswordRequest
+	if err := c.ShouldBindJSON(&passwordPayload); err != nil {
+		c.JSON(400, dto.NewErrorResponse("Invalid request", err))
+		return
+	}
+	claims, err := utils.GetClaimsFromJWTUsingContext(c)
+	if err != nil {
+		c.JSON(401, dto.NewErrorResponse("Unauthorized", err))
+		return
+	}
+	passwordPayload.EmployeeID = claims.UserID
+	if err := h.empService.SetPayslipPassword(c.Request.Context(), passwordPayload); err != nil {
+		writeError(c, "Failed to set payslip password", err)
+		return
+	}
+	c.JSON(200, dto.NewSuccessResponse("Payslip password set successfully", nil))
+}
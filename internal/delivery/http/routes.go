@@ -43,7 +43,78 @@ func RegisterAdminRoutes(router *gin.RouterGroup, adminHandler *handler.AdminHan
 	{
 		adminGroup.POST("/payroll-period", adminHandler.AdminCreatePayrollPeriodHandler)
 		adminGroup.POST("/payroll-period/run", adminHandler.AdminRunPayrollPeriodHandler)
+		adminGroup.GET("/payroll-period/:period_id/runs", adminHandler.AdminGetPayrollRunsHandler)
+		adminGroup.GET("/payroll-runs/:run_id", adminHandler.AdminGetPayrollRunHandler)
+		adminGroup.POST("/payroll-runs/:run_id/approve", adminHandler.AdminApprovePayrollRunHandler)
+		adminGroup.POST("/payroll-runs/:run_id/reject", adminHandler.AdminRejectPayrollRunHandler)
+		adminGroup.GET("/payroll-period/:period_id/jobs", adminHandler.AdminGetPayrollJobsHandler)
+		adminGroup.GET("/payroll-jobs/:job_id", adminHandler.AdminGetPayrollJobHandler)
+		adminGroup.POST("/payroll-period/preview", adminHandler.AdminPreviewPayrollPeriodHandler)
+		adminGroup.POST("/payroll-period/:period_id/reopen", adminHandler.AdminReopenPayrollPeriodHandler)
+		adminGroup.GET("/payroll-period/:period_id/reopens", adminHandler.AdminGetPayrollReopensHandler)
 		adminGroup.GET("/payroll-summary/:period_id", adminHandler.AdminViewPayrollSummaryHandler)
+		adminGroup.GET("/payroll-period/:period_id/variance", adminHandler.AdminGetPayrollVarianceHandler)
+		adminGroup.GET("/payroll-period/:period_id/journal", adminHandler.AdminGetPayrollJournalHandler)
+		adminGroup.POST("/payroll-period/:period_id/disbursements", adminHandler.AdminCreateDisbursementHandler)
+		adminGroup.GET("/payroll-period/:period_id/disbursements", adminHandler.AdminGetDisbursementsHandler)
+		adminGroup.POST("/payroll-period/:period_id/payslip-emails", adminHandler.AdminDistributePayslipEmailsHandler)
+		adminGroup.GET("/payroll-period/:period_id/payslip-emails", adminHandler.AdminGetPayslipEmailsHandler)
+		adminGroup.POST("/payroll-period/:period_id/payslip-emails/:employee_id/resend", adminHandler.AdminResendPayslipEmailHandler)
+		adminGroup.GET("/disbursements/:batch_id", adminHandler.AdminGetDisbursementHandler)
+		adminGroup.GET("/disbursements/:batch_id/file", adminHandler.AdminGetDisbursementFileHandler)
+		adminGroup.POST("/disbursements/:batch_id/cancel", adminHandler.AdminCancelDisbursementHandler)
+		adminGroup.POST("/disbursements/:batch_id/sent", adminHandler.AdminMarkDisbursementSentHandler)
+		adminGroup.PUT("/disbursements/:batch_id/items/:item_id/status", adminHandler.AdminUpdatePaymentStatusHandler)
+		adminGroup.POST("/bank-statements/import", adminHandler.AdminImportBankStatementHandler)
+		adminGroup.GET("/reconciliation", adminHandler.AdminGetReconciliationHandler)
+		adminGroup.GET("/pay-rules", adminHandler.AdminGetPayRuleSetsHandler)
+		adminGroup.POST("/pay-rules", adminHandler.AdminCreatePayRuleSetHandler)
+		adminGroup.PUT("/payroll-period/:period_id/pay-rules", adminHandler.AdminAssignPayRuleSetHandler)
+		adminGroup.GET("/employees/:employee_id/tax-profile", adminHandler.AdminGetTaxProfileHandler)
+		adminGroup.PUT("/employees/:employee_id/tax-profile", adminHandler.AdminUpsertTaxProfileHandler)
+		adminGroup.PUT("/employees/:employee_id/employment", adminHandler.AdminUpdateEmploymentHandler)
+		adminGroup.GET("/employees/:employee_id/bank-account", adminHandler.AdminGetBankAccountHandler)
+		adminGroup.PUT("/employees/:employee_id/bank-account", adminHandler.AdminSetBankAccountHandler)
+		adminGroup.GET("/employees/:employee_id/salaries", adminHandler.AdminGetSalaryHistoryHandler)
+		adminGroup.POST("/employees/:employee_id/salaries", adminHandler.AdminCreateSalaryChangeHandler)
+		adminGroup.GET("/pay-components", adminHandler.AdminGetPayComponentsHandler)
+		adminGroup.POST("/pay-components", adminHandler.AdminCreatePayComponentHandler)
+		adminGroup.PUT("/pay-components/:component_id", adminHandler.AdminUpdatePayComponentHandler)
+		adminGroup.GET("/employees/:employee_id/pay-components", adminHandler.AdminGetEmployeePayComponentsHandler)
+		adminGroup.POST("/employees/:employee_id/pay-components", adminHandler.AdminAssignPayComponentHandler)
+		adminGroup.PUT("/employees/:employee_id/pay-components/:assignment_id", adminHandler.AdminUpdatePayComponentAssignmentHandler)
+		adminGroup.DELETE("/employees/:employee_id/pay-components/:assignment_id", adminHandler.AdminDeletePayComponentAssignmentHandler)
+		adminGroup.GET("/gl-accounts", adminHandler.AdminGetGLAccountsHandler)
+		adminGroup.PUT("/gl-accounts", adminHandler.AdminSetGLAccountHandler)
+		adminGroup.DELETE("/gl-accounts/:account_id", adminHandler.AdminDeleteGLAccountHandler)
+		adminGroup.GET("/employees/:employee_id/loans", adminHandler.AdminGetEmployeeLoansHandler)
+		adminGroup.POST("/employees/:employee_id/loans", adminHandler.AdminCreateLoanHandler)
+		adminGroup.GET("/loans/:loan_id", adminHandler.AdminGetLoanHandler)
+		adminGroup.POST("/loans/:loan_id/cancel", adminHandler.AdminCancelLoanHandler)
+		adminGroup.GET("/payroll-period/:period_id/bonus-runs", adminHandler.AdminGetBonusRunsHandler)
+		adminGroup.POST("/payroll-period/:period_id/bonus-runs", adminHandler.AdminRunBonusHandler)
+		adminGroup.POST("/payroll-period/:period_id/bonus-runs/preview", adminHandler.AdminPreviewBonusHandler)
+		adminGroup.GET("/bonus-runs/:run_id", adminHandler.AdminGetBonusRunHandler)
+		adminGroup.GET("/ytd", adminHandler.AdminGetYearToDateHandler)
+		adminGroup.POST("/ytd/rebuild", adminHandler.AdminRebuildYearToDateHandler)
+		adminGroup.GET("/employees/:employee_id/ytd", adminHandler.AdminGetEmployeeYearToDateHandler)
+		adminGroup.GET("/tax-statements", adminHandler.AdminGetTaxStatementsHandler)
+		adminGroup.POST("/tax-statements/generate", adminHandler.AdminGenerateTaxStatementsHandler)
+		adminGroup.GET("/employees/:employee_id/tax-statements/:tax_year", adminHandler.AdminGetTaxStatementHandler)
+		adminGroup.GET("/employees/:employee_id/tax-statements/:tax_year/pdf", adminHandler.AdminGetTaxStatementPDFHandler)
+		adminGroup.GET("/employees/:employee_id/payslip/:period_id/pdf", adminHandler.AdminGetPayslipPDFHandler)
+		adminGroup.GET("/employees/:employee_id/payslip/:period_id/html", adminHandler.AdminGetPayslipHTMLHandler)
+		adminGroup.POST("/payslip-templates/preview", adminHandler.AdminPreviewPayslipTemplateHandler)
+		adminGroup.GET("/payslip-templates/:company", adminHandler.AdminGetPayslipTemplatesHandler)
+		adminGroup.PUT("/payslip-templates/:company", adminHandler.AdminSavePayslipTemplateHandler)
+		adminGroup.GET("/payslip-templates/:company/versions/:version", adminHandler.AdminGetPayslipTemplateHandler)
+		adminGroup.GET("/bpjs-rates", adminHandler.AdminGetBPJSRatesHandler)
+		adminGroup.POST("/bpjs-rates", adminHandler.AdminCreateBPJSRateHandler)
+		adminGroup.GET("/holidays", adminHandler.AdminGetHolidaysHandler)
+		adminGroup.POST("/holidays", adminHandler.AdminCreateHolidayHandler)
+		adminGroup.POST("/holidays/import", adminHandler.AdminImportHolidaysHandler)
+		adminGroup.PUT("/holidays/:holiday_id", adminHandler.AdminUpdateHolidayHandler)
+		adminGroup.DELETE("/holidays/:holiday_id", adminHandler.AdminDeleteHolidayHandler)
 	}
 }
 
@@ -55,5 +126,14 @@ func RegisterEmployeeRoutes(router *gin.RouterGroup, employeeHandler *handler.Em
 		employeeGroup.POST("/overtime", employeeHandler.EmployeeOvertimeSubmissionHandler)
 		employeeGroup.POST("/reimbursement", employeeHandler.EmployeeReimbursementHandler)
 		employeeGroup.GET("/payslip/:period_id", employeeHandler.EmployeePayslipHandler)
+		employeeGroup.GET("/payslip/:period_id/bonus", employeeHandler.EmployeeBonusPayslipsHandler)
+		employeeGroup.GET("/payslip/:period_id/pdf", employeeHandler.EmployeePayslipPDFHandler)
+		employeeGroup.GET("/payslip/:period_id/html", employeeHandler.EmployeePayslipHTMLHandler)
+		employeeGroup.PUT("/payslip-password", employeeHandler.EmployeeSetPayslipPasswordHandler)
+		employeeGroup.GET("/ytd", employeeHandler.EmployeeYearToDateHandler)
+		employeeGroup.GET("/tax-statements/:tax_year", employeeHandler.EmployeeTaxStatementHandler)
+		employeeGroup.GET("/tax-statements/:tax_year/pdf", employeeHandler.EmployeeTaxStatementPDFHandler)
+		employeeGroup.GET("/loans", employeeHandler.EmployeeLoansHandler)
+		employeeGroup.GET("/loans/:loan_id", employeeHandler.EmployeeLoanHandler)
 	}
 }
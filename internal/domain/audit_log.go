@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// AuditLog is a row of audit_logs. Details is stored as JSONB.
+type AuditLog struct {
+	ID        int       `json:"id"`
+	ActorID   int       `json:"actor_id"`
+	ActorRole string    `json:"actor_role"`
+	Action    string    `json:"action"`
+	Details   any       `json:"details"`
+	IPAddress string    `json:"ip_address"`
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `json:"created_by"`
+}
+
+const AuditActionReopenPayrollPeriod = "reopen_payroll_period"
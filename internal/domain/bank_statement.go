@@ -0,0 +1,69 @@
+package domain
+
+import "time"
+
+const (
+	BankStatementFormatCSV   = "csv"
+	BankStatementFormatMT940 = "mt940"
+)
+
+const (
+	StatementDebit  = "debit"  // money leaving the company account, e.g. a salary transfer
+	StatementCredit = "credit" // money coming in, e.g. a returned transfer
+)
+
+// BankStatementImport is a statement of the company account imported to confirm payments.
+type BankStatementImport struct {
+	ID             int                  `json:"id"`
+	Format         string               `json:"format"`
+	AccountNumber  string               `json:"account_number,omitempty"`
+	EntryCount     int                  `json:"entry_count"`
+	DuplicateCount int                  `json:"duplicate_count"` // entries imported before, skipped
+	MatchedCount   int                  `json:"matched_count"`
+	Entries        []BankStatementEntry `json:"entries"`
+	CreatedAt      time.Time            `json:"created_at"`
+	CreatedBy      string               `json:"created_by"`
+}
+
+// BankStatementEntry is one booking on the statement. A matched entry changed the status of
+// the disbursement item it paid or returned.
+type BankStatementEntry struct {
+	ID            int       `json:"id"`
+	ImportID      int       `json:"import_id"`
+	Line          int       `json:"line"`
+	BookingDate   time.Time `json:"booking_date"`
+	Direction     string    `json:"direction"`
+	Amount        Money     `json:"amount"`
+	Reference     string    `json:"reference"`
+	Description   string    `json:"description"`
+	AccountNumber string    `json:"account_number,omitempty"` // counterparty, when the statement has it
+	Fingerprint   string    `json:"-"`
+	Duplicate     bool      `json:"duplicate,omitempty"`
+	MatchedItemID int       `json:"matched_item_id,omitempty"`
+	MatchedStatus string    `json:"matched_status,omitempty"`
+}
+
+// ReconciliationItem is a disbursement item with the batch and period it belongs to.
+type ReconciliationItem struct {
+	DisbursementItem
+	BatchReference string `json:"batch_reference"`
+	PeriodID       int    `json:"period_id"`
+}
+
+// PaymentStatusTotal counts the items in a payment status.
+type PaymentStatusTotal struct {
+	Status    string `json:"status"`
+	ItemCount int    `json:"item_count"`
+	Amount    Money  `json:"amount"`
+}
+
+// ReconciliationReport lists the payments of exported batches that are not confirmed as paid:
+// unconfirmed ones still waiting for the bank statement and unpaid ones to reissue, with the
+// statement entries no payment matched.
+type ReconciliationReport struct {
+	PeriodID         int                  `json:"period_id,omitempty"` // 0 for every period
+	Totals           []PaymentStatusTotal `json:"totals"`
+	Unconfirmed      []ReconciliationItem `json:"unconfirmed"`
+	ToReissue        []ReconciliationItem `json:"to_reissue"`
+	UnmatchedEntries []BankStatementEntry `json:"unmatched_entries"`
+}
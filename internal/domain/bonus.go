@@ -0,0 +1,77 @@
+package domain
+
+import "time"
+
+const (
+	BonusKindTHR   = "thr"   // Tunjangan Hari Raya, the religious holiday allowance
+	BonusKindBonus = "bonus" // ad-hoc bonuses with amounts uploaded by finance
+)
+
+func IsValidBonusKind(kind string) bool {
+	return kind == BonusKindTHR || kind == BonusKindBonus
+}
+
+// BonusRun is a THR or bonus payment to a set of employees. Its payslips are separate from the
+// regular payslips but linked to the same payroll period, so their PPh 21 is withheld together
+// with the period's regular pay.
+type BonusRun struct {
+	ID            int            `json:"id"`
+	PeriodID      int            `json:"period_id"`
+	Kind          string         `json:"kind"`
+	Reason        string         `json:"reason,omitempty"`
+	ReferenceDate *time.Time     `json:"reference_date,omitempty"` // THR only: the date tenure is counted to
+	EmployeeCount int            `json:"employee_count"`
+	TotalGross    Money          `json:"total_gross"`
+	TotalNet      Money          `json:"total_net"`
+	Payslips      []BonusPayslip `json:"payslips,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	CreatedBy     string         `json:"created_by"`
+	UpdatedBy     string         `json:"updated_by"`
+}
+
+// BonusPayslip is the payslip of one employee in a bonus run.
+type BonusPayslip struct {
+	ID         int       `json:"id"`
+	RunID      int       `json:"run_id"`
+	EmployeeID int       `json:"employee_id"`
+	PeriodID   int       `json:"period_id"`
+	Kind       string    `json:"kind"`
+	Payslip    Payslip   `json:"payslip"`
+	CreatedAt  time.Time `json:"created_at"`
+	CreatedBy  string    `json:"created_by"`
+}
+
+// BonusAmount is an uploaded bonus for one employee.
+type BonusAmount struct {
+	EmployeeID  int    `json:"employee_id"`
+	Amount      Money  `json:"amount"`
+	Description string `json:"description"`
+}
+
+// ServiceMonths counts the full months of continuous service from start to on.
+func ServiceMonths(start, on time.Time) int {
+	if on.Before(start) {
+		return 0
+	}
+	months := (on.Year()-start.Year())*12 + int(on.Month()-start.Month())
+	if on.Day() < start.Day() {
+		months--
+	}
+	return months
+}
+
+// THRMonths returns the twelfths of a monthly wage an employee is entitled to as THR under
+// Permenaker 6/2016: none below one month of service, one twelfth per full month below twelve
+// months and the full monthly wage from twelve months. Employees without a start date are
+// taken to have served at least a year.
+func (e Employee) THRMonths(on time.Time) int {
+	if e.StartDate == nil {
+		return 12
+	}
+	months := ServiceMonths(*e.StartDate, on)
+	if months > 12 {
+		return 12
+	}
+	return months
+}
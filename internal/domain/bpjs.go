@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+const (
+	BPJSProgramJHT       = "JHT" // Jaminan Hari Tua
+	BPJSProgramJP        = "JP"  // Jaminan Pensiun
+	BPJSProgramJKK       = "JKK" // Jaminan Kecelakaan Kerja
+	BPJSProgramJKM       = "JKM" // Jaminan Kematian
+	BPJSProgramKesehatan = "KES" // BPJS Kesehatan
+)
+
+// BPJSRate is the contribution rate of a BPJS program from its effective date onwards.
+// A zero WageCap means the whole wage is subject to the contribution.
+type BPJSRate struct {
+	ID            int       `json:"id"`
+	Program       string    `json:"program"`
+	EmployeeRate  Rate      `json:"employee_rate"`
+	EmployerRate  Rate      `json:"employer_rate"`
+	WageCap       Money     `json:"wage_cap"`
+	EffectiveFrom time.Time `json:"effective_from"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	CreatedBy     string    `json:"created_by"`
+	UpdatedBy     string    `json:"updated_by"`
+}
+
+// BPJSContribution is the contribution of one program on a payslip.
+type BPJSContribution struct {
+	Program        string `json:"program"`
+	Wage           Money  `json:"wage"` // wage after applying the cap
+	EmployeeAmount Money  `json:"employee_amount"`
+	EmployerAmount Money  `json:"employer_amount"`
+}
+
+// IsPension reports whether the employee share is deductible for PPh 21 (JHT and JP).
+func (c BPJSContribution) IsPension() bool {
+	return c.Program == BPJSProgramJHT || c.Program == BPJSProgramJP
+}
+
+// IsTaxableBenefit reports whether the employer share is added to the PPh 21 gross (JKK, JKM and Kesehatan).
+func (c BPJSContribution) IsTaxableBenefit() bool {
+	return c.Program == BPJSProgramJKK || c.Program == BPJSProgramJKM || c.Program == BPJSProgramKesehatan
+}
+
+func IsValidBPJSProgram(program string) bool {
+	switch program {
+	case BPJSProgramJHT, BPJSProgramJP, BPJSProgramJKK, BPJSProgramJKM, BPJSProgramKesehatan:
+		return true
+	}
+	return false
+}
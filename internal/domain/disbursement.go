@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// BankAccount is the account an employee's net pay is transferred to.
+type BankAccount struct {
+	EmployeeID    int       `json:"employee_id"`
+	BankCode      string    `json:"bank_code"` // e.g. the 3-digit clearing code or the SWIFT code
+	AccountNumber string    `json:"account_number"`
+	AccountHolder string    `json:"account_holder"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	CreatedBy     string    `json:"created_by"`
+	UpdatedBy     string    `json:"updated_by"`
+}
+
+// DisbursementSource is the company account the transfers are debited from.
+type DisbursementSource struct {
+	Name          string `json:"name"`
+	BankCode      string `json:"bank_code"`
+	AccountNumber string `json:"account_number"`
+}
+
+const (
+	DisbursementBatchExported  = "exported"
+	DisbursementBatchCancelled = "cancelled" // the file was not sent, so the period can be exported again
+)
+
+// Payment statuses of a disbursement item. Items start pending, become sent when the batch is
+// sent to the bank, and paid, failed or returned once the bank statement or an admin confirms
+// the outcome. Failed and returned payments can be reissued in a later batch.
+const (
+	PaymentPending  = "pending"
+	PaymentSent     = "sent"
+	PaymentPaid     = "paid"
+	PaymentFailed   = "failed"   // rejected by the bank, the money never left
+	PaymentReturned = "returned" // paid, then credited back, e.g. to a closed account
+)
+
+func IsValidPaymentStatus(status string) bool {
+	switch status {
+	case PaymentPending, PaymentSent, PaymentPaid, PaymentFailed, PaymentReturned:
+		return true
+	}
+	return false
+}
+
+// CanChangePaymentStatus reports whether a payment can move from one status to the other.
+func CanChangePaymentStatus(from, to string) bool {
+	switch from {
+	case PaymentPending:
+		return to == PaymentSent || to == PaymentPaid || to == PaymentFailed
+	case PaymentSent:
+		return to == PaymentPaid || to == PaymentFailed || to == PaymentReturned
+	case PaymentPaid:
+		return to == PaymentReturned
+	}
+	return false
+}
+
+// IsUnpaid reports whether the payment did not reach the employee and should be reissued.
+func IsUnpaid(status string) bool {
+	return status == PaymentFailed || status == PaymentReturned
+}
+
+// DisbursementBatch is an exported bank transfer file for the net pay of a locked period. The
+// first batch of a period pays each employee's net pay; later ones are supplementary and pay
+// only what the period's payslips add up to beyond what earlier batches transferred, e.g. after
+// an off-cycle correction or a re-run, so employees are not paid twice.
+type DisbursementBatch struct {
+	ID           int                `json:"id"`
+	Reference    string             `json:"reference"`
+	PeriodID     int                `json:"period_id"`
+	Format       string             `json:"format"`
+	Status       string             `json:"status"`
+	ValueDate    time.Time          `json:"value_date"`
+	Source       DisbursementSource `json:"source"`
+	Currency     string             `json:"currency"`
+	ItemCount    int                `json:"item_count"`
+	TotalAmount  Money              `json:"total_amount"`
+	Checksum     string             `json:"checksum"`
+	CancelReason string             `json:"cancel_reason,omitempty"`
+	SentAt       *time.Time         `json:"sent_at,omitempty"`
+	SentBy       string             `json:"sent_by,omitempty"`
+	Items        []DisbursementItem `json:"items,omitempty"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+	CreatedBy    string             `json:"created_by"`
+	UpdatedBy    string             `json:"updated_by"`
+}
+
+// DisbursementItem is the transfer of one employee's net pay, or the reissue of an earlier
+// transfer that failed or was returned.
+type DisbursementItem struct {
+	ID              int        `json:"id"`
+	BatchID         int        `json:"batch_id"`
+	Line            int        `json:"line"`
+	EmployeeID      int        `json:"employee_id"`
+	EmployeeName    string     `json:"employee_name"`
+	BankCode        string     `json:"bank_code"`
+	AccountNumber   string     `json:"account_number"`
+	AccountHolder   string     `json:"account_holder"`
+	Amount          Money      `json:"amount"`
+	Status          string     `json:"status"`
+	StatusNote      string     `json:"status_note,omitempty"`
+	BankReference   string     `json:"bank_reference,omitempty"` // of the matched statement entry
+	StatusUpdatedAt *time.Time `json:"status_updated_at,omitempty"`
+	StatusUpdatedBy string     `json:"status_updated_by,omitempty"`
+	ReissueOfItemID int        `json:"reissue_of_item_id,omitempty"` // the failed or returned item this pays again
+	ReissuedBatchID int        `json:"reissued_batch_id,omitempty"`  // the batch that pays this item again
+}
+
+// BatchReference is the reference printed on the file and the transfers, derived from the ID.
+func (b DisbursementBatch) BatchReference() string {
+	return fmt.Sprintf("DISB%08d", b.ID)
+}
+
+func (b DisbursementBatch) IsExported() bool {
+	return b.Status == DisbursementBatchExported
+}
+
+// DisbursementChecksum is the SHA-256 of the items' bank codes, account numbers and amounts in
+// minor units, one "bank|account|amount" line per item in line order. Banks and the exporter
+// recompute it to detect a file changed after export.
+func DisbursementChecksum(items []DisbursementItem) string {
+	h := sha256.New()
+	for _, item := range items {
+		fmt.Fprintf(h, "%s|%s|%d\n", item.BankCode, item.AccountNumber, item.Amount.MinorUnits())
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// DisbursementOwed is what each employee is still owed for a period: the net pay of their
+// payslips in it, regular and off-cycle corrections together, less what exported batches
+// already transferred for it. Employees owed nothing, or paid more than their payslips now add
+// up to, are left out; an overpayment is recovered through payroll, not by a transfer.
+func DisbursementOwed(payrolls []Payroll, disbursed map[int]Money) map[int]Money {
+	net := make(map[int]Money)
+	for _, payroll := range payrolls {
+		net[payroll.EmployeeID] = net[payroll.EmployeeID].Add(payroll.Payslip.NetSalary)
+	}
+	owed := make(map[int]Money, len(net))
+	for employeeID, amount := range net {
+		if amount = amount.Sub(disbursed[employeeID]); amount.IsPositive() {
+			owed[employeeID] = amount
+		}
+	}
+	return owed
+}
+
+// PaymentStatusesChangingTo lists the statuses a payment can move to the status from.
+func PaymentStatusesChangingTo(to string) []string {
+	var from []string
+	for _, status := range []string{PaymentPending, PaymentSent, PaymentPaid, PaymentFailed, PaymentReturned} {
+		if CanChangePaymentStatus(status, to) {
+			from = append(from, status)
+		}
+	}
+	return from
+}
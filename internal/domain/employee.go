@@ -2,17 +2,74 @@ package domain
 
 import "time"
 
+const (
+	EmploymentStatusActive     = "active"
+	EmploymentStatusInactive   = "inactive"   // suspended, e.g. unpaid leave: not paid and cannot log in
+	EmploymentStatusTerminated = "terminated" // paid and can log in until the end date
+)
+
 type Employee struct {
-	ID            int     `json:"id"`
-	Name          string  `json:"name"`
-	Email         string  `json:"email"`
-	Password_hash string  `json:"password_hash"`
-	Role          string  `json:"role"`
-	Salary        float64 `json:"salary"`
-	Created_at    string  `json:"created_at"`
-	Updated_at    string  `json:"updated_at"`
-	Created_by    string  `json:"created_by"`
-	Updated_by    string  `json:"updated_by"`
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	Password_hash string `json:"password_hash"`
+	Role          string `json:"role"`
+	Salary        Money  `json:"salary"`
+	// StartDate and EndDate bound the employment, both inclusive; nil means open-ended
+	StartDate        *time.Time `json:"start_date,omitempty"`
+	EndDate          *time.Time `json:"end_date,omitempty"`
+	EmploymentStatus string     `json:"employment_status"`
+	CostCenter       string     `json:"cost_center"` // empty when not assigned
+	Company          string     `json:"company"`     // legal entity code, picks the payslip template; empty when not assigned
+	Created_at       string     `json:"created_at"`
+	Updated_at       string     `json:"updated_at"`
+	Created_by       string     `json:"created_by"`
+	Updated_by       string     `json:"updated_by"`
+}
+
+func IsValidEmploymentStatus(status string) bool {
+	switch status {
+	case EmploymentStatusActive, EmploymentStatusInactive, EmploymentStatusTerminated:
+		return true
+	}
+	return false
+}
+
+// IsInactive reports whether the employee is suspended. Employees stored before the status
+// existed have an empty status and count as active.
+func (e Employee) IsInactive() bool {
+	return e.EmploymentStatus == EmploymentStatusInactive
+}
+
+// CanLogin reports whether the employee is not inactive and has not left on the given day.
+func (e Employee) CanLogin(today time.Time) bool {
+	if e.IsInactive() {
+		return false
+	}
+	if e.EndDate == nil {
+		return true
+	}
+	date := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	return !date.After(*e.EndDate)
+}
+
+// EmploymentWithin returns the days from start to end the employee is employed and payable.
+// ok is false when the employee is inactive or the employment does not overlap the range.
+func (e Employee) EmploymentWithin(start, end time.Time) (from, to time.Time, ok bool) {
+	if e.IsInactive() {
+		return time.Time{}, time.Time{}, false
+	}
+	from, to = start, end
+	if e.StartDate != nil && e.StartDate.After(from) {
+		from = *e.StartDate
+	}
+	if e.EndDate != nil && e.EndDate.Before(to) {
+		to = *e.EndDate
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
 }
 
 type Attendance struct {
@@ -40,12 +97,14 @@ type Overtime struct {
 type OvertimeRecap struct {
 	Date   time.Time `json:"date"`
 	Hours  int       `json:"hours"`
-	Amount float64   `json:"amount"` // Total salary for the overtime hours
+	Amount Money     `json:"amount"` // Total salary for the overtime hours
+	// RestDay marks overtime on a weekend or holiday, paid with the rest day multiplier.
+	RestDay bool `json:"rest_day,omitempty"`
 }
 type Reimbursement struct {
 	ID          int       `json:"id"`
 	EmployeeID  int       `json:"employee_id"`
-	Amount      float64   `json:"amount"`
+	Amount      Money     `json:"amount"`
 	Description string    `json:"description"`
 	Date        time.Time `json:"date"`
 	CreatedAt   time.Time `json:"created_at"`
@@ -53,24 +112,42 @@ type Reimbursement struct {
 	CreatedBy   string    `json:"created_by"`
 	UpdatedBy   string    `json:"updated_by"`
 }
+
+// Payslip is the result of a payroll calculation. Lines are the itemised pay; the totals are
+// derived from them, and the fixed amount fields are kept for clients of the earlier format.
 type Payslip struct {
-	ID                        int             `json:"id"`
-	EmployeeID                int             `json:"employee_id"`
-	PeriodID                  int             `json:"period_id"`
-	NumberAttendances         int             `json:"num_attendances"`
-	TotalWorkDays             int             `json:"total_work_days"`
-	SalaryByAttendance        float64         `json:"salary_by_attendance"`
-	OvertimesRecap            []OvertimeRecap `json:"overtimes_recap"`
-	OvetimeTotalSalary        float64         `json:"overtime_total_salary"`
-	Reimbursements            []Reimbursement `json:"reimbursements"`
-	ReimbursementsTotalSalary float64         `json:"reimbursements_total_salary"`
-	TotalSalary               float64         `json:"total_salary"`
-	Description               string          `json:"description"`
+	ID                        int                `json:"id"`
+	EmployeeID                int                `json:"employee_id"`
+	PeriodID                  int                `json:"period_id"`
+	RuleSetVersion            int                `json:"rule_set_version"`
+	Currency                  string             `json:"currency"`
+	NumberAttendances         int                `json:"num_attendances"`
+	TotalWorkDays             int                `json:"total_work_days"`
+	SalaryByAttendance        Money              `json:"salary_by_attendance"`
+	SalarySegments            []SalarySegment    `json:"salary_segments,omitempty"` // base pay per salary in effect during the period
+	OvertimesRecap            []OvertimeRecap    `json:"overtimes_recap"`
+	OvetimeTotalSalary        Money              `json:"overtime_total_salary"`
+	Reimbursements            []Reimbursement    `json:"reimbursements"`
+	ReimbursementsTotalSalary Money              `json:"reimbursements_total_salary"`
+	TotalSalary               Money              `json:"total_salary"` // gross pay
+	TaxableIncome             Money              `json:"taxable_income"`
+	Tax                       *TaxDetail         `json:"tax,omitempty"`
+	BPJS                      []BPJSContribution `json:"bpjs"`
+	EmployerContributions     Money              `json:"employer_contributions"` // employer BPJS shares, not part of the pay
+	Deductions                []Deduction        `json:"deductions"`
+	TotalDeductions           Money              `json:"total_deductions"`
+	NetSalary                 Money              `json:"net_salary"`
+	LoanInstallments          []LoanInstallment  `json:"loan_installments,omitempty"`
+	YearToDate                *TaxYearToDate     `json:"year_to_date,omitempty"` // the tax year up to and including this payslip
+	Lines                     []PayslipLine      `json:"lines"`
+	Description               string             `json:"description"`
 }
 type Payroll struct {
 	ID         int       `json:"id"`
 	EmployeeID int       `json:"employee_id"`
 	PeriodID   int       `json:"period_id"`
+	RunID      int       `json:"run_id,omitempty"`   // 0 for payrolls stored before runs were recorded
+	RunType    string    `json:"run_type,omitempty"` // off-cycle payslips add to the employee's regular one
 	Payslip    Payslip   `json:"payslip"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
@@ -0,0 +1,75 @@
+package domain
+
+import "time"
+
+const (
+	HolidayTypeNational    = "national"     // libur nasional
+	HolidayTypeCutiBersama = "cuti_bersama" // collective leave set by the government
+	HolidayTypeCompany     = "company"      // company specific day off
+)
+
+type Holiday struct {
+	ID        int       `json:"id"`
+	Date      time.Time `json:"date"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedBy string    `json:"created_by"`
+	UpdatedBy string    `json:"updated_by"`
+}
+
+func IsValidHolidayType(holidayType string) bool {
+	switch holidayType {
+	case HolidayTypeNational, HolidayTypeCutiBersama, HolidayTypeCompany:
+		return true
+	}
+	return false
+}
+
+// HolidayCalendar answers whether a date is a workday. The zero value has no holidays,
+// so only weekends are days off.
+type HolidayCalendar struct {
+	holidays map[string]Holiday
+}
+
+const calendarDateLayout = "2006-01-02"
+
+func NewHolidayCalendar(holidays []Holiday) HolidayCalendar {
+	calendar := HolidayCalendar{holidays: make(map[string]Holiday, len(holidays))}
+	for _, h := range holidays {
+		calendar.holidays[h.Date.Format(calendarDateLayout)] = h
+	}
+	return calendar
+}
+
+// Holiday returns the holiday on the date, if any. Only the calendar date is compared.
+func (c HolidayCalendar) Holiday(date time.Time) (Holiday, bool) {
+	h, ok := c.holidays[date.Format(calendarDateLayout)]
+	return h, ok
+}
+
+func (c HolidayCalendar) IsHoliday(date time.Time) bool {
+	_, ok := c.Holiday(date)
+	return ok
+}
+
+func IsWeekend(date time.Time) bool {
+	return date.Weekday() == time.Saturday || date.Weekday() == time.Sunday
+}
+
+// IsWorkday reports whether the date is neither a weekend nor a holiday.
+func (c HolidayCalendar) IsWorkday(date time.Time) bool {
+	return !IsWeekend(date) && !c.IsHoliday(date)
+}
+
+// Workdays counts the workdays from start to end, both inclusive.
+func (c HolidayCalendar) Workdays(start, end time.Time) int {
+	days := 0
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if c.IsWorkday(d) {
+			days++
+		}
+	}
+	return days
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHolidayCalendarWorkdays(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	start, end := date("2025-03-01"), date("2025-03-31")
+
+	var weekendsOnly HolidayCalendar
+	if got := weekendsOnly.Workdays(start, end); got != 21 {
+		t.Errorf("workdays without holidays = %d, want 21", got)
+	}
+
+	calendar := NewHolidayCalendar([]Holiday{
+		{Date: date("2025-03-29"), Name: "Hari Suci Nyepi", Type: HolidayTypeNational}, // Saturday
+		{Date: date("2025-03-31"), Name: "Idul Fitri", Type: HolidayTypeNational},
+		{Date: date("2025-03-28"), Name: "Cuti Bersama Nyepi", Type: HolidayTypeCutiBersama},
+	})
+	if got := calendar.Workdays(start, end); got != 19 {
+		t.Errorf("workdays with holidays = %d, want 19", got)
+	}
+	// times of day do not matter
+	if calendar.IsWorkday(date("2025-03-31").Add(9 * time.Hour)) {
+		t.Error("Idul Fitri should not be a workday")
+	}
+	if !calendar.IsWorkday(date("2025-03-27")) {
+		t.Error("an ordinary Thursday should be a workday")
+	}
+}
@@ -0,0 +1,87 @@
+package domain
+
+import "time"
+
+// Journal components, in the order of the journal lines. Expenses are debited and payables
+// credited; together they balance because the net pay is the earnings less the deductions.
+const (
+	JournalSalaryExpense          = "salary_expense" // base pay and allowances
+	JournalOvertimeExpense        = "overtime_expense"
+	JournalReimbursementExpense   = "reimbursement_expense"
+	JournalBPJSExpense            = "bpjs_expense" // employer BPJS contributions
+	JournalTaxPayable             = "tax_payable"  // PPh 21 withheld
+	JournalBPJSPayable            = "bpjs_payable" // employee and employer BPJS contributions
+	JournalLoanReceivable         = "loan_receivable"
+	JournalOtherDeductionsPayable = "other_deductions_payable" // recurring deductions such as cooperative dues
+	JournalNetSalaryPayable       = "net_salary_payable"
+)
+
+// JournalComponents lists the components in journal order.
+var JournalComponents = []string{
+	JournalSalaryExpense,
+	JournalOvertimeExpense,
+	JournalReimbursementExpense,
+	JournalBPJSExpense,
+	JournalTaxPayable,
+	JournalBPJSPayable,
+	JournalLoanReceivable,
+	JournalOtherDeductionsPayable,
+	JournalNetSalaryPayable,
+}
+
+func IsValidJournalComponent(component string) bool {
+	for _, c := range JournalComponents {
+		if c == component {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDebitJournalComponent reports whether the component is an expense debited by the journal.
+func IsDebitJournalComponent(component string) bool {
+	switch component {
+	case JournalSalaryExpense, JournalOvertimeExpense, JournalReimbursementExpense, JournalBPJSExpense:
+		return true
+	}
+	return false
+}
+
+// GLAccount maps a journal component to a general-ledger account. An account with a cost
+// center applies to the employees of that cost center only and takes precedence over the
+// component's default account, which has an empty cost center.
+type GLAccount struct {
+	ID          int       `json:"id"`
+	Component   string    `json:"component"`
+	CostCenter  string    `json:"cost_center"`
+	AccountCode string    `json:"account_code"`
+	AccountName string    `json:"account_name"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	CreatedBy   string    `json:"created_by"`
+	UpdatedBy   string    `json:"updated_by"`
+}
+
+// Journal is the double-entry journal of a locked payroll period.
+type Journal struct {
+	Reference   string        `json:"reference"`
+	PeriodID    int           `json:"period_id"`
+	EntryDate   string        `json:"entry_date"` // YYYY-MM-DD, the end of the period
+	Currency    string        `json:"currency"`
+	Lines       []JournalLine `json:"lines"`
+	TotalDebit  Money         `json:"total_debit"`
+	TotalCredit Money         `json:"total_credit"`
+}
+
+// JournalLine is the amount of a component for a cost center. Exactly one of Debit and Credit
+// is non-zero.
+type JournalLine struct {
+	Line        int    `json:"line"`
+	AccountCode string `json:"account_code"`
+	AccountName string `json:"account_name"`
+	CostCenter  string `json:"cost_center"`
+	Component   string `json:"component"`
+	Description string `json:"description"`
+	Debit       Money  `json:"debit"`
+	Credit      Money  `json:"credit"`
+}
@@ -0,0 +1,82 @@
+package domain
+
+import "time"
+
+const (
+	LoanTypeLoan    = "loan"
+	LoanTypeAdvance = "salary_advance"
+)
+
+const (
+	LoanStatusActive    = "active"
+	LoanStatusPaidOff   = "paid_off"
+	LoanStatusCancelled = "cancelled"
+)
+
+func IsValidLoanType(loanType string) bool {
+	return loanType == LoanTypeLoan || loanType == LoanTypeAdvance
+}
+
+// Loan is a staff loan or salary advance repaid through payroll. One installment is due in
+// every payroll period from the period containing StartDate until the principal is repaid.
+// Outstanding is the principal less the installments deducted in locked periods.
+type Loan struct {
+	ID                int             `json:"id"`
+	EmployeeID        int             `json:"employee_id"`
+	Type              string          `json:"type"`
+	Principal         Money           `json:"principal"`
+	Installments      int             `json:"installments"`
+	InstallmentAmount Money           `json:"installment_amount"`
+	StartDate         time.Time       `json:"start_date"`
+	Description       string          `json:"description"`
+	Status            string          `json:"status"`
+	Outstanding       Money           `json:"outstanding"`
+	Repayments        []LoanRepayment `json:"repayments,omitempty"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+	CreatedBy         string          `json:"created_by"`
+	UpdatedBy         string          `json:"updated_by"`
+}
+
+// LoanRepayment is the installment deducted from an employee's payslip in a locked period.
+type LoanRepayment struct {
+	LoanID    int       `json:"loan_id"`
+	PeriodID  int       `json:"period_id"`
+	PayrollID int       `json:"payroll_id"`
+	Amount    Money     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LoanInstallment is the installment of a loan deducted on a payslip. Amount is less than Due
+// when the installment was capped to keep the net pay from going negative.
+type LoanInstallment struct {
+	LoanID int   `json:"loan_id"`
+	Due    Money `json:"due"`
+	Amount Money `json:"amount"`
+}
+
+// InstallmentAmountFor splits the principal into equal installments, rounded up to the minor
+// unit so the last installment is the smallest.
+func InstallmentAmountFor(principal Money, installments int) Money {
+	if installments <= 0 {
+		return principal
+	}
+	n := int64(installments)
+	return Money{minor: (principal.minor + n - 1) / n}
+}
+
+// DueInstallment is the installment due in a period: the installment amount, or the
+// outstanding balance when less is left.
+func (l Loan) DueInstallment() Money {
+	if l.Outstanding.LessThan(l.InstallmentAmount) {
+		return l.Outstanding
+	}
+	return l.InstallmentAmount
+}
+
+func (l Loan) Label() string {
+	if l.Type == LoanTypeAdvance {
+		return "Salary advance"
+	}
+	return "Loan"
+}
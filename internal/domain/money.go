@@ -0,0 +1,432 @@
+package domain
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"payroll-system/internal/error_const"
+	"strconv"
+	"strings"
+)
+
+// Money is an exact amount kept in hundredths of the currency unit, matching
+// the NUMERIC(12,2) columns it is stored in. Use NewMoney or ParseMoney to build one.
+type Money struct {
+	minor int64
+}
+
+var ZeroMoney = Money{}
+
+// NewMoney returns an amount of whole currency units.
+func NewMoney(units int64) Money {
+	return Money{minor: units}.Mul(100)
+}
+
+// MoneyFromMinorUnits returns an amount of hundredths of the currency unit.
+func MoneyFromMinorUnits(minor int64) Money {
+	return Money{minor: minor}
+}
+
+// ParseMoney parses a decimal string. Digits beyond two decimal places are rounded half-up.
+// Amounts that do not fit in int64 hundredths are rejected with ErrMoneyOverflow.
+func ParseMoney(s string) (Money, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return Money{}, error_const.ErrInvalidMoney
+	}
+	minor := roundToMinor(r, 2, RoundHalfUp)
+	if !minor.IsInt64() {
+		return Money{}, error_const.ErrMoneyOverflow
+	}
+	return Money{minor: minor.Int64()}, nil
+}
+
+// Add, Sub, Neg and Mul panic with ErrMoneyOverflow instead of wrapping around. Calculations
+// over amounts from user input defer RecoverMoneyOverflow to turn that into an error.
+func (m Money) Add(o Money) Money {
+	sum := m.minor + o.minor
+	if (sum > m.minor) != (o.minor > 0) {
+		panic(error_const.ErrMoneyOverflow)
+	}
+	return Money{minor: sum}
+}
+
+func (m Money) Sub(o Money) Money {
+	diff := m.minor - o.minor
+	if (diff < m.minor) != (o.minor > 0) {
+		panic(error_const.ErrMoneyOverflow)
+	}
+	return Money{minor: diff}
+}
+
+func (m Money) Neg() Money {
+	if m.minor == math.MinInt64 {
+		panic(error_const.ErrMoneyOverflow)
+	}
+	return Money{minor: -m.minor}
+}
+
+func (m Money) Mul(n int64) Money {
+	if m.minor == 0 || n == 0 {
+		return Money{}
+	}
+	product := m.minor * n
+	if product/n != m.minor || (n == -1 && m.minor == math.MinInt64) {
+		panic(error_const.ErrMoneyOverflow)
+	}
+	return Money{minor: product}
+}
+
+// RecoverMoneyOverflow stops the panic of an overflowing Money operation and stores
+// ErrMoneyOverflow in *err. Other panics are passed on. Use it as
+// defer domain.RecoverMoneyOverflow(&err).
+func RecoverMoneyOverflow(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if e, ok := r.(error); ok && errors.Is(e, error_const.ErrMoneyOverflow) {
+		*err = e
+		return
+	}
+	panic(r)
+}
+
+func (m Money) MinorUnits() int64        { return m.minor }
+func (m Money) IsZero() bool             { return m.minor == 0 }
+func (m Money) IsPositive() bool         { return m.minor > 0 }
+func (m Money) IsNegative() bool         { return m.minor < 0 }
+func (m Money) GreaterThan(o Money) bool { return m.minor > o.minor }
+func (m Money) LessThan(o Money) bool    { return m.minor < o.minor }
+
+// Rat returns the amount as an exact rational number of currency units.
+func (m Money) Rat() *big.Rat {
+	return big.NewRat(m.minor, 100)
+}
+
+// String formats the amount with two decimal places, e.g. "5010000.00".
+func (m Money) String() string {
+	sign := ""
+	minor := m.minor
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/100, minor%100)
+}
+
+// MarshalJSON encodes the amount as a JSON number so API and JSONB payslip shapes are unchanged.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a quoted decimal string. Payslips stored before
+// Money existed hold float values, which are rounded half-up to two decimals.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner; pgx hands NUMERIC values over as decimal strings.
+func (m *Money) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*m = Money{}
+		return nil
+	case string:
+		return m.parseInto(v)
+	case []byte:
+		return m.parseInto(string(v))
+	case int64:
+		*m = NewMoney(v)
+		return nil
+	case float64:
+		return m.parseInto(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		return fmt.Errorf("%w: cannot scan %T", error_const.ErrInvalidMoney, src)
+	}
+}
+
+func (m *Money) parseInto(s string) error {
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// SumMoney adds up the given amounts.
+func SumMoney(amounts ...Money) Money {
+	var total Money
+	for _, a := range amounts {
+		total = total.Add(a)
+	}
+	return total
+}
+
+type RoundingMode string
+
+const (
+	RoundHalfUp   RoundingMode = "half_up"   // half away from zero
+	RoundHalfEven RoundingMode = "half_even" // banker's rounding
+	RoundDown     RoundingMode = "down"      // toward zero
+	RoundUp       RoundingMode = "up"        // away from zero
+)
+
+const DefaultCurrency = "IDR"
+
+// RoundingPolicy decides how calculated amounts are rounded for a currency.
+// Scale is the number of decimals kept: 0 rounds to whole units, 2 to cents.
+type RoundingPolicy struct {
+	Currency string       `json:"currency"`
+	Scale    int          `json:"scale"`
+	Mode     RoundingMode `json:"mode"`
+}
+
+// DefaultRoundingPolicy rounds half-up to the whole rupiah.
+var DefaultRoundingPolicy = RoundingPolicy{Currency: DefaultCurrency, Scale: 0, Mode: RoundHalfUp}
+
+func (p RoundingPolicy) Validate() error {
+	if p.Currency == "" || p.Scale < 0 || p.Scale > 2 {
+		return error_const.ErrInvalidRoundingPolicy
+	}
+	switch p.Mode {
+	case RoundHalfUp, RoundHalfEven, RoundDown, RoundUp:
+		return nil
+	default:
+		return error_const.ErrInvalidRoundingPolicy
+	}
+}
+
+// RoundingPolicies are the rounding policies by currency and the currency used when none is
+// given. The zero value has IDR as the default currency, rounded by DefaultRoundingPolicy.
+type RoundingPolicies struct {
+	defaultCurrency string
+	byCurrency      map[string]RoundingPolicy
+}
+
+// NewRoundingPolicies validates the policies. They are added to DefaultRoundingPolicy, which a
+// policy for IDR replaces; an empty default currency means IDR.
+func NewRoundingPolicies(defaultCurrency string, policies ...RoundingPolicy) (RoundingPolicies, error) {
+	p := RoundingPolicies{
+		defaultCurrency: defaultCurrency,
+		byCurrency:      map[string]RoundingPolicy{DefaultCurrency: DefaultRoundingPolicy},
+	}
+	for _, policy := range policies {
+		if err := policy.Validate(); err != nil {
+			return RoundingPolicies{}, err
+		}
+		p.byCurrency[policy.Currency] = policy
+	}
+	return p, nil
+}
+
+// DefaultCurrency is the currency of amounts that do not name one.
+func (p RoundingPolicies) DefaultCurrency() string {
+	if p.defaultCurrency == "" {
+		return DefaultCurrency
+	}
+	return p.defaultCurrency
+}
+
+// For returns the policy for the currency, or for the default currency when it is empty.
+// Currencies without a configured policy are rounded half-up to two decimals.
+func (p RoundingPolicies) For(currency string) RoundingPolicy {
+	if currency == "" {
+		currency = p.DefaultCurrency()
+	}
+	if policy, ok := p.byCurrency[currency]; ok {
+		return policy
+	}
+	if currency == DefaultCurrency {
+		return DefaultRoundingPolicy
+	}
+	return RoundingPolicy{Currency: currency, Scale: 2, Mode: RoundHalfUp}
+}
+
+// Round converts an exact amount of currency units into Money according to the policy.
+func (p RoundingPolicy) Round(r *big.Rat) Money {
+	return moneyFromRat(r, p.Scale, p.Mode)
+}
+
+// RoundMoney re-rounds an existing amount according to the policy.
+func (p RoundingPolicy) RoundMoney(m Money) Money {
+	return p.Round(m.Rat())
+}
+
+// moneyFromRat panics with ErrMoneyOverflow when the rounded amount does not fit in Money.
+func moneyFromRat(r *big.Rat, scale int, mode RoundingMode) Money {
+	minor := roundToMinor(r, scale, mode)
+	if !minor.IsInt64() {
+		panic(error_const.ErrMoneyOverflow)
+	}
+	return Money{minor: minor.Int64()}
+}
+
+func roundToMinor(r *big.Rat, scale int, mode RoundingMode) *big.Int {
+	factor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(factor))
+	rounded := roundToInt(scaled, mode)
+	toMinor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(2-scale)), nil)
+	return rounded.Mul(rounded, toMinor)
+}
+
+func roundToInt(r *big.Rat, mode RoundingMode) *big.Int {
+	num := new(big.Int).Set(r.Num())
+	den := r.Denom()
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 {
+		return q
+	}
+	sign := int64(r.Sign())
+	twiceRem := new(big.Int).Abs(rem)
+	twiceRem.Lsh(twiceRem, 1)
+	cmpHalf := twiceRem.Cmp(den)
+
+	awayFromZero := false
+	switch mode {
+	case RoundDown:
+	case RoundUp:
+		awayFromZero = true
+	case RoundHalfEven:
+		awayFromZero = cmpHalf > 0 || (cmpHalf == 0 && q.Bit(0) == 1)
+	default: // RoundHalfUp
+		awayFromZero = cmpHalf >= 0
+	}
+	if awayFromZero {
+		q.Add(q, big.NewInt(sign))
+	}
+	return q
+}
+
+// Rate is an exact decimal factor such as a multiplier, divisor or percentage rate.
+type Rate struct {
+	rat *big.Rat
+}
+
+// NewRate parses a decimal string such as "1.5" or "0.0024".
+func NewRate(s string) (Rate, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return Rate{}, error_const.ErrInvalidRate
+	}
+	return Rate{rat: r}, nil
+}
+
+// MustRate is NewRate for constants known to be valid; it panics otherwise.
+func MustRate(s string) Rate {
+	r, err := NewRate(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func RateFromInt(n int64) Rate {
+	return Rate{rat: new(big.Rat).SetInt64(n)}
+}
+
+// RateFromRat keeps an exact rational, e.g. a daily rate that is only rounded once multiplied.
+func RateFromRat(r *big.Rat) Rate {
+	return Rate{rat: new(big.Rat).Set(r)}
+}
+
+// Rat returns a copy of the rate as a rational number.
+func (r Rate) Rat() *big.Rat {
+	if r.rat == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Set(r.rat)
+}
+
+func (r Rate) Sign() int {
+	if r.rat == nil {
+		return 0
+	}
+	return r.rat.Sign()
+}
+
+func (r Rate) IsPositive() bool { return r.Sign() > 0 }
+
+func (r Rate) Cmp(o Rate) int { return r.Rat().Cmp(o.Rat()) }
+
+// String formats the rate without trailing zeros, e.g. "2" or "0.0024".
+func (r Rate) String() string {
+	s := r.Rat().FloatString(12)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	parsed, err := NewRate(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+func (r *Rate) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*r = Rate{}
+		return nil
+	case string:
+		return r.parseInto(v)
+	case []byte:
+		return r.parseInto(string(v))
+	case int64:
+		*r = RateFromInt(v)
+		return nil
+	case float64:
+		return r.parseInto(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		return fmt.Errorf("%w: cannot scan %T", error_const.ErrInvalidRate, src)
+	}
+}
+
+func (r *Rate) parseInto(s string) error {
+	parsed, err := NewRate(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
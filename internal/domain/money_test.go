@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"payroll-system/internal/error_const"
+	"testing"
+)
+
+func TestRoundingPolicy_Modes(t *testing.T) {
+	cases := []struct {
+		value string
+		mode  RoundingMode
+		scale int
+		want  string
+	}{
+		{"1234.5", RoundHalfUp, 0, "1235.00"},
+		{"-1234.5", RoundHalfUp, 0, "-1235.00"},
+		{"1234.49", RoundHalfUp, 0, "1234.00"},
+		{"1234.5", RoundHalfEven, 0, "1234.00"},
+		{"1235.5", RoundHalfEven, 0, "1236.00"},
+		{"1234.99", RoundDown, 0, "1234.00"},
+		{"1234.01", RoundUp, 0, "1235.00"},
+		{"10.005", RoundHalfUp, 2, "10.01"},
+		{"10.004", RoundHalfUp, 2, "10.00"},
+	}
+	for _, tc := range cases {
+		r, _ := new(big.Rat).SetString(tc.value)
+		got := RoundingPolicy{Currency: "XXX", Scale: tc.scale, Mode: tc.mode}.Round(r)
+		if got.String() != tc.want {
+			t.Errorf("%s %s/%d: expected %s, got %s", tc.value, tc.mode, tc.scale, tc.want, got)
+		}
+	}
+}
+
+func TestMoney_JSONRoundTripAndLegacyFloats(t *testing.T) {
+	var legacy struct {
+		Total Money `json:"total_salary"`
+	}
+	if err := json.Unmarshal([]byte(`{"total_salary": 4294285.714285714}`), &legacy); err != nil {
+		t.Fatal(err)
+	}
+	if legacy.Total != MoneyFromMinorUnits(429428571) {
+		t.Errorf("expected 4294285.71, got %s", legacy.Total)
+	}
+	data, err := json.Marshal(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"total_salary":4294285.71}` {
+		t.Errorf("unexpected JSON %s", data)
+	}
+}
+
+func TestMoney_Scan(t *testing.T) {
+	var m Money
+	if err := m.Scan("5010000.00"); err != nil {
+		t.Fatal(err)
+	}
+	if m != NewMoney(5010000) {
+		t.Errorf("expected 5010000.00, got %s", m)
+	}
+	if err := m.Scan("not a number"); err == nil {
+		t.Error("expected error for invalid numeric")
+	}
+}
+
+func TestMoney_Overflow(t *testing.T) {
+	if _, err := ParseMoney("92233720368547758.08"); err != error_const.ErrMoneyOverflow {
+		t.Errorf("ParseMoney beyond int64 hundredths: expected ErrMoneyOverflow, got %v", err)
+	}
+	max := MoneyFromMinorUnits(math.MaxInt64)
+	overflows := map[string]func(){
+		"Add": func() { max.Add(MoneyFromMinorUnits(1)) },
+		"Sub": func() { max.Neg().Sub(MoneyFromMinorUnits(2)) },
+		"Mul": func() { NewMoney(100000000000000).Mul(1000) },
+		"Neg": func() { MoneyFromMinorUnits(math.MinInt64).Neg() },
+	}
+	for name, op := range overflows {
+		err := func() (err error) {
+			defer RecoverMoneyOverflow(&err)
+			op()
+			return nil
+		}()
+		if err != error_const.ErrMoneyOverflow {
+			t.Errorf("%s: expected ErrMoneyOverflow, got %v", name, err)
+		}
+	}
+	if got := max.Sub(max).Add(NewMoney(-5)).Mul(-3); got != NewMoney(15) {
+		t.Errorf("expected 15.00, got %s", got)
+	}
+}
+
+func TestRoundingPolicies(t *testing.T) {
+	var zero RoundingPolicies
+	if zero.DefaultCurrency() != "IDR" || zero.For("") != DefaultRoundingPolicy {
+		t.Errorf("zero value: expected IDR rounded to the rupiah, got %s %+v", zero.DefaultCurrency(), zero.For(""))
+	}
+	policies, err := NewRoundingPolicies("USD", RoundingPolicy{Currency: "IDR", Scale: 2, Mode: RoundDown})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policies.For("").Currency != "USD" || policies.For("USD").Scale != 2 || policies.For("IDR").Mode != RoundDown {
+		t.Errorf("unexpected policies %+v %+v", policies.For(""), policies.For("IDR"))
+	}
+	if _, err := NewRoundingPolicies("IDR", RoundingPolicy{Currency: "IDR", Scale: 3, Mode: RoundUp}); err != error_const.ErrInvalidRoundingPolicy {
+		t.Errorf("expected ErrInvalidRoundingPolicy, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// PayComponent is a catalogue entry for a recurring allowance or deduction, e.g. a transport
+// allowance or cooperative dues. Its code is the code of the payslip line.
+type PayComponent struct {
+	ID        int       `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Category  string    `json:"category"` // PayslipLineEarning or PayslipLineDeduction
+	Taxable   bool      `json:"taxable"`  // only earnings are taxable
+	Active    bool      `json:"active"`   // inactive components are no longer paid
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedBy string    `json:"created_by"`
+	UpdatedBy string    `json:"updated_by"`
+}
+
+func IsValidPayComponentCategory(category string) bool {
+	return category == PayslipLineEarning || category == PayslipLineDeduction
+}
+
+// PayComponentAssignment is the monthly amount of a component paid to or deducted from an
+// employee from EffectiveFrom until EffectiveTo, both inclusive; a nil EffectiveTo is open-ended.
+type PayComponentAssignment struct {
+	ID            int          `json:"id"`
+	EmployeeID    int          `json:"employee_id"`
+	ComponentID   int          `json:"component_id"`
+	Component     PayComponent `json:"component"`
+	Amount        Money        `json:"amount"`
+	Taxable       *bool        `json:"taxable,omitempty"` // overrides the component's flag
+	EffectiveFrom time.Time    `json:"effective_from"`
+	EffectiveTo   *time.Time   `json:"effective_to,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+	CreatedBy     string       `json:"created_by"`
+	UpdatedBy     string       `json:"updated_by"`
+}
+
+// IsTaxable reports whether the assigned amount is taxable income. Deductions never are.
+func (a PayComponentAssignment) IsTaxable() bool {
+	if a.Component.Category != PayslipLineEarning {
+		return false
+	}
+	if a.Taxable != nil {
+		return *a.Taxable
+	}
+	return a.Component.Taxable
+}
+
+// EffectiveWithin returns the days from start to end the assignment is effective, or false
+// if there are none.
+func (a PayComponentAssignment) EffectiveWithin(start, end time.Time) (from, to time.Time, ok bool) {
+	from, to = start, end
+	if a.EffectiveFrom.After(from) {
+		from = a.EffectiveFrom
+	}
+	if a.EffectiveTo != nil && a.EffectiveTo.Before(to) {
+		to = *a.EffectiveTo
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
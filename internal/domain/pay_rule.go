@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+const (
+	BasePayFormulaAttendanceRatio = "attendance_ratio" // base salary x attendances / workdays
+	BasePayFormulaFullSalary      = "full_salary"      // base salary regardless of attendance
+)
+
+// PayRuleSet is a versioned set of pay rules used by the payroll calculator.
+// A new version is created on every change so older payslips stay reproducible.
+type PayRuleSet struct {
+	ID                 int    `json:"id"`
+	Version            int    `json:"version"`
+	Description        string `json:"description"`
+	BasePayFormula     string `json:"base_pay_formula"`
+	HourlyDivisor      Rate   `json:"hourly_divisor"`      // base salary is divided by this to get the overtime rate
+	OvertimeMultiplier Rate   `json:"overtime_multiplier"` // overtime rate multiplier
+	// RestDayOvertimeMultiplier applies to overtime on weekends and holidays; zero means OvertimeMultiplier.
+	RestDayOvertimeMultiplier Rate      `json:"rest_day_overtime_multiplier"`
+	CreatedAt                 time.Time `json:"created_at"`
+	UpdatedAt                 time.Time `json:"updated_at"`
+	CreatedBy                 string    `json:"created_by"`
+	UpdatedBy                 string    `json:"updated_by"`
+}
+
+// OvertimeMultiplierFor returns the multiplier for overtime worked on a workday or a rest day.
+func (r PayRuleSet) OvertimeMultiplierFor(restDay bool) Rate {
+	if restDay && r.RestDayOvertimeMultiplier.IsPositive() {
+		return r.RestDayOvertimeMultiplier
+	}
+	return r.OvertimeMultiplier
+}
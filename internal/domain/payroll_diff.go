@@ -0,0 +1,59 @@
+package domain
+
+import "sort"
+
+// DiffPayrolls compares the payslips of two runs of the same period per employee,
+// ordered by employee ID. An employee's payslips on either side are added up, so the
+// regular payslip and any off-cycle ones count as one.
+func DiffPayrolls(voided, rerun []Payroll) []PayslipDiff {
+	oldByEmployee := totalsByEmployee(voided)
+	newByEmployee := totalsByEmployee(rerun)
+
+	employeeIDs := make([]int, 0, len(newByEmployee))
+	for id := range newByEmployee {
+		employeeIDs = append(employeeIDs, id)
+	}
+	for id := range oldByEmployee {
+		if _, ok := newByEmployee[id]; !ok {
+			employeeIDs = append(employeeIDs, id)
+		}
+	}
+	sort.Ints(employeeIDs)
+
+	diffs := make([]PayslipDiff, 0, len(employeeIDs))
+	for _, id := range employeeIDs {
+		diff := PayslipDiff{EmployeeID: id}
+		var before, after PayslipTotals
+		if totals, ok := oldByEmployee[id]; ok {
+			before = totals
+			diff.Old = &totals
+		}
+		if totals, ok := newByEmployee[id]; ok {
+			after = totals
+			diff.New = &totals
+		}
+		switch {
+		case diff.Old == nil:
+			diff.Change = PayslipChangeAdded
+		case diff.New == nil:
+			diff.Change = PayslipChangeRemoved
+		case before == after:
+			diff.Change = PayslipChangeUnchanged
+		default:
+			diff.Change = PayslipChangeChanged
+		}
+		diff.TotalSalaryDelta = after.TotalSalary.Sub(before.TotalSalary)
+		diff.TaxDelta = after.Tax.Sub(before.Tax)
+		diff.NetSalaryDelta = after.NetSalary.Sub(before.NetSalary)
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+func totalsByEmployee(payrolls []Payroll) map[int]PayslipTotals {
+	result := make(map[int]PayslipTotals, len(payrolls))
+	for _, payroll := range payrolls {
+		result[payroll.EmployeeID] = result[payroll.EmployeeID].Add(payroll.Payslip.Totals())
+	}
+	return result
+}
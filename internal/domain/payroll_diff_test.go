@@ -0,0 +1,55 @@
+package domain
+
+import "testing"
+
+func payrollWithNet(employeeID int, gross, net int64) Payroll {
+	return Payroll{
+		EmployeeID: employeeID,
+		Payslip: Payslip{
+			TotalSalary: NewMoney(gross),
+			NetSalary:   NewMoney(net),
+			Tax:         &TaxDetail{Amount: NewMoney(gross - net)},
+		},
+	}
+}
+
+func TestDiffPayrolls(t *testing.T) {
+	old := []Payroll{
+		payrollWithNet(3, 5000000, 4900000),
+		payrollWithNet(1, 6000000, 5800000),
+		payrollWithNet(2, 7000000, 6700000),
+	}
+	rerun := []Payroll{
+		payrollWithNet(1, 6000000, 5800000),
+		payrollWithNet(2, 7500000, 7150000),
+		payrollWithNet(4, 4000000, 4000000),
+		payrollWithNet(4, 500000, 500000), // an off-cycle payslip adds to the regular one
+	}
+
+	diffs := DiffPayrolls(old, rerun)
+	want := []struct {
+		employeeID int
+		change     string
+		netDelta   Money
+	}{
+		{1, PayslipChangeUnchanged, ZeroMoney},
+		{2, PayslipChangeChanged, NewMoney(450000)},
+		{3, PayslipChangeRemoved, NewMoney(-4900000)},
+		{4, PayslipChangeAdded, NewMoney(4500000)},
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("got %d diffs, want %d", len(diffs), len(want))
+	}
+	for i, w := range want {
+		d := diffs[i]
+		if d.EmployeeID != w.employeeID || d.Change != w.change || d.NetSalaryDelta != w.netDelta {
+			t.Errorf("diff %d = {%d %s %s}, want {%d %s %s}", i, d.EmployeeID, d.Change, d.NetSalaryDelta, w.employeeID, w.change, w.netDelta)
+		}
+	}
+	if diffs[1].TaxDelta != NewMoney(50000) {
+		t.Errorf("tax delta = %s, want 50000.00", diffs[1].TaxDelta)
+	}
+	if diffs[2].New != nil || diffs[3].Old != nil {
+		t.Error("removed/added diffs should only carry one side")
+	}
+}
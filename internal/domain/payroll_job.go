@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+const (
+	PayrollJobQueued    = "queued"
+	PayrollJobRunning   = "running"
+	PayrollJobSucceeded = "succeeded"
+	PayrollJobFailed    = "failed"
+)
+
+// PayrollJob is a payroll run executed in the background. Jobs are persisted so
+// queued and interrupted runs are picked up again after a restart.
+type PayrollJob struct {
+	ID                 int               `json:"id"`
+	PeriodID           int               `json:"period_id"`
+	RunType            string            `json:"run_type"`
+	EmployeeIDs        []int             `json:"employee_ids,omitempty"` // paid by an off-cycle run, or paid off-cycle and included in a regular run
+	Reason             string            `json:"reason,omitempty"`
+	RunID              *int              `json:"run_id,omitempty"` // set once the job succeeds
+	Status             string            `json:"status"`
+	TotalEmployees     int               `json:"total_employees"`
+	ProcessedEmployees int               `json:"processed_employees"`
+	Errors             []PayrollJobError `json:"errors"`
+	Attempts           int               `json:"attempts"`
+	StartedAt          *time.Time        `json:"started_at,omitempty"`
+	FinishedAt         *time.Time        `json:"finished_at,omitempty"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+	CreatedBy          string            `json:"created_by"`
+	UpdatedBy          string            `json:"updated_by"`
+}
+
+// PayrollJobError is a failure of the whole job (EmployeeID 0) or of one employee's payslip.
+type PayrollJobError struct {
+	EmployeeID int    `json:"employee_id,omitempty"`
+	Message    string `json:"message"`
+}
+
+func (j PayrollJob) IsFinished() bool {
+	return j.Status == PayrollJobSucceeded || j.Status == PayrollJobFailed
+}
+
+func (j PayrollJob) IsOffCycle() bool {
+	return j.RunType == PayrollRunOffCycle
+}
@@ -3,14 +3,15 @@ package domain
 import "time"
 
 type PayrollPeriod struct {
-	ID        int       `json:"id"`
-	StartDate time.Time `json:"start_date"`
-	EndDate   time.Time `json:"end_date"`
-	Locked    bool      `json:"locked"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	CreatedBy string    `json:"created_by"`
-	UpdatedBy string    `json:"updated_by"`
+	ID             int       `json:"id"`
+	StartDate      time.Time `json:"start_date"`
+	EndDate        time.Time `json:"end_date"`
+	Locked         bool      `json:"locked"`
+	RuleSetVersion int       `json:"rule_set_version"` // 0 until a pay rule set is pinned
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	CreatedBy      string    `json:"created_by"`
+	UpdatedBy      string    `json:"updated_by"`
 }
 
 type PayrollPeriodDate = time.Time
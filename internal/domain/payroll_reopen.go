@@ -0,0 +1,72 @@
+package domain
+
+import "time"
+
+const (
+	PayslipChangeAdded     = "added"   // employee had no payslip before the run
+	PayslipChangeRemoved   = "removed" // employee's old payslip was voided without a replacement
+	PayslipChangeChanged   = "changed"
+	PayslipChangeUnchanged = "unchanged"
+)
+
+// PayrollReopen records a locked period being unlocked for corrections and the job re-running
+// it. Once the re-run is approved, RunID, VoidedPayrolls and Diff record the run, how many
+// payslips it voided and the per-employee diff of the old and new payslips.
+type PayrollReopen struct {
+	ID             int           `json:"id"`
+	PeriodID       int           `json:"period_id"`
+	Reason         string        `json:"reason"`
+	JobID          int           `json:"job_id"`
+	RunID          *int          `json:"run_id,omitempty"`
+	VoidedPayrolls int           `json:"voided_payrolls"`
+	Diff           []PayslipDiff `json:"diff"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+	CreatedBy      string        `json:"created_by"`
+	UpdatedBy      string        `json:"updated_by"`
+}
+
+// PayslipTotals are the headline amounts of a payslip compared by a run's diff.
+type PayslipTotals struct {
+	TotalSalary           Money `json:"total_salary"`
+	Tax                   Money `json:"tax"`
+	TotalDeductions       Money `json:"total_deductions"`
+	NetSalary             Money `json:"net_salary"`
+	EmployerContributions Money `json:"employer_contributions"`
+}
+
+// PayslipDiff compares an employee's payslips before and after a run.
+// Old is nil for added employees and New is nil for removed ones.
+type PayslipDiff struct {
+	EmployeeID       int            `json:"employee_id"`
+	Change           string         `json:"change"`
+	Old              *PayslipTotals `json:"old,omitempty"`
+	New              *PayslipTotals `json:"new,omitempty"`
+	TotalSalaryDelta Money          `json:"total_salary_delta"`
+	TaxDelta         Money          `json:"tax_delta"`
+	NetSalaryDelta   Money          `json:"net_salary_delta"`
+}
+
+// Add sums two sets of totals, e.g. an employee's regular and off-cycle payslips.
+func (t PayslipTotals) Add(other PayslipTotals) PayslipTotals {
+	return PayslipTotals{
+		TotalSalary:           t.TotalSalary.Add(other.TotalSalary),
+		Tax:                   t.Tax.Add(other.Tax),
+		TotalDeductions:       t.TotalDeductions.Add(other.TotalDeductions),
+		NetSalary:             t.NetSalary.Add(other.NetSalary),
+		EmployerContributions: t.EmployerContributions.Add(other.EmployerContributions),
+	}
+}
+
+func (p Payslip) Totals() PayslipTotals {
+	totals := PayslipTotals{
+		TotalSalary:           p.TotalSalary,
+		TotalDeductions:       p.TotalDeductions,
+		NetSalary:             p.NetSalary,
+		EmployerContributions: p.EmployerContributions,
+	}
+	if p.Tax != nil {
+		totals.Tax = p.Tax.Amount
+	}
+	return totals
+}
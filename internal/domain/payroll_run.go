@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+const (
+	PayrollRunRegular  = "regular"   // every employee not paid off-cycle in the period; locks the period once approved
+	PayrollRunOffCycle = "off_cycle" // selected employees only; leaves the period as it is
+)
+
+const (
+	PayrollRunPendingApproval = "pending_approval" // calculated, waiting for a second admin
+	PayrollRunApproved        = "approved"         // payslips stored; regular runs lock the period
+	PayrollRunRejected        = "rejected"         // payslips discarded, the run can be made again
+)
+
+// PayrollRun records one set of payslips stored for a period: a regular run, another one each
+// time the period is reopened, and any number of off-cycle runs for late joiners, corrections
+// and final pay.
+// Every run waits for approval by an admin other than the one who ran it; until then its
+// payslips are kept on the run only.
+type PayrollRun struct {
+	ID            int                    `json:"id"`
+	PeriodID      int                    `json:"period_id"`
+	RunType       string                 `json:"run_type"`
+	Status        string                 `json:"status"`
+	Reason        string                 `json:"reason,omitempty"`
+	EmployeeCount int                    `json:"employee_count"`
+	Diff          []PayslipDiff          `json:"diff"`                  // against the payslips the run replaces or adds to
+	Payrolls      []Payroll              `json:"payrolls,omitempty"`    // payslips waiting for approval
+	Transitions   []PayrollRunTransition `json:"transitions,omitempty"` // oldest first
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+	CreatedBy     string                 `json:"created_by"`
+	UpdatedBy     string                 `json:"updated_by"`
+}
+
+func (r PayrollRun) IsPendingApproval() bool {
+	return r.Status == PayrollRunPendingApproval
+}
+
+func (r PayrollRun) IsOffCycle() bool {
+	return r.RunType == PayrollRunOffCycle
+}
+
+// PayrollRunTransition records a change of a run's status, who made it and why. FromStatus
+// is empty for the transition that created the run.
+type PayrollRunTransition struct {
+	ID         int       `json:"id"`
+	RunID      int       `json:"run_id"`
+	FromStatus string    `json:"from_status,omitempty"`
+	ToStatus   string    `json:"to_status"`
+	Comment    string    `json:"comment,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	CreatedBy  string    `json:"created_by"`
+}
@@ -0,0 +1,62 @@
+package domain
+
+// Components compared by the variance report.
+const (
+	VarianceComponentBase          = "base"
+	VarianceComponentOvertime      = "overtime"
+	VarianceComponentReimbursement = "reimbursement"
+	VarianceComponentTotal         = "total" // gross pay
+)
+
+const (
+	VarianceStatusNew      = "new"     // paid in the period but not in the previous one
+	VarianceStatusMissing  = "missing" // paid in the previous period but not in this one
+	VarianceStatusExisting = "existing"
+)
+
+// Flags raised on the employees of a variance report.
+const (
+	VarianceFlagAboveThreshold     = "above_threshold"
+	VarianceFlagNewEmployee        = "new_employee"
+	VarianceFlagMissingEmployee    = "missing_employee"
+	VarianceFlagReimbursementSpike = "reimbursement_spike"
+)
+
+// VarianceThresholds decide which changes a variance report flags. Percentages are of the
+// previous period's amount.
+type VarianceThresholds struct {
+	ChangePercent             Rate  `json:"change_percent"`              // component changes above this are flagged
+	ReimbursementSpikePercent Rate  `json:"reimbursement_spike_percent"` // reimbursement growth above this is a spike
+	ReimbursementSpikeMinimum Money `json:"reimbursement_spike_minimum"` // smaller reimbursements are never a spike
+}
+
+// ComponentVariance compares one component of two periods. ChangePercent is nil when the
+// previous amount is zero.
+type ComponentVariance struct {
+	Component     string `json:"component"`
+	Previous      Money  `json:"previous"`
+	Current       Money  `json:"current"`
+	Change        Money  `json:"change"`
+	ChangePercent *Rate  `json:"change_percent"`
+	Flagged       bool   `json:"flagged"`
+}
+
+type EmployeeVariance struct {
+	EmployeeID   int                 `json:"employee_id"`
+	EmployeeName string              `json:"employee_name"`
+	Status       string              `json:"status"`
+	Components   []ComponentVariance `json:"components"`
+	Flags        []string            `json:"flags"`
+}
+
+// PayrollVarianceReport compares the payslips of a period with those of a previous period per
+// employee and component.
+type PayrollVarianceReport struct {
+	PeriodID         int                 `json:"period_id"`
+	PreviousPeriodID int                 `json:"previous_period_id"`
+	Pending          bool                `json:"pending"` // the period's payslips are a run waiting for approval
+	Thresholds       VarianceThresholds  `json:"thresholds"`
+	Totals           []ComponentVariance `json:"totals"`
+	Employees        []EmployeeVariance  `json:"employees"`
+	FlaggedEmployees int                 `json:"flagged_employees"`
+}
@@ -0,0 +1,96 @@
+package domain
+
+// PayslipCorrection is the off-cycle payslip that adds to the payslips already paid to an
+// employee in a period, so together they pay what recalculated says. Its lines are the
+// differences per line, leaving out lines that did not change and information lines. Loan
+// installments are left to the regular payslip, so a correction never repays a loan twice.
+func PayslipCorrection(paid []Payslip, recalculated Payslip) Payslip {
+	correction := recalculated
+	correction.SalarySegments = nil
+	correction.OvertimesRecap = nil
+	correction.Reimbursements = nil
+	correction.LoanInstallments = nil
+
+	type lineKey struct {
+		code, category, label string
+		taxable               bool
+	}
+	var keys []lineKey
+	lines := make(map[lineKey]PayslipLine)
+	deltas := make(map[lineKey]Money)
+	add := func(line PayslipLine, amount Money) {
+		if line.Category == PayslipLineInformation || line.Code == PayslipLineCodeLoanInstallment {
+			return
+		}
+		key := lineKey{line.Code, line.Category, line.Label(), line.Taxable}
+		if _, ok := lines[key]; !ok {
+			keys = append(keys, key)
+			lines[key] = line
+		}
+		deltas[key] = deltas[key].Add(amount)
+	}
+	for _, line := range recalculated.Lines {
+		add(line, line.Amount)
+	}
+
+	var bpjsPrograms []string
+	bpjs := make(map[string]BPJSContribution)
+	addBPJS := func(contribution BPJSContribution, sign int64) {
+		current, ok := bpjs[contribution.Program]
+		if !ok {
+			bpjsPrograms = append(bpjsPrograms, contribution.Program)
+			current.Program = contribution.Program
+		}
+		current.Wage = current.Wage.Add(contribution.Wage.Mul(sign))
+		current.EmployeeAmount = current.EmployeeAmount.Add(contribution.EmployeeAmount.Mul(sign))
+		current.EmployerAmount = current.EmployerAmount.Add(contribution.EmployerAmount.Mul(sign))
+		bpjs[contribution.Program] = current
+	}
+	for _, contribution := range recalculated.BPJS {
+		addBPJS(contribution, 1)
+	}
+
+	var tax Money
+	if recalculated.Tax != nil {
+		tax = recalculated.Tax.Amount
+	}
+	for _, payslip := range paid {
+		for _, line := range payslip.Lines {
+			add(line, line.Amount.Neg())
+		}
+		for _, contribution := range payslip.BPJS {
+			addBPJS(contribution, -1)
+		}
+		if payslip.Tax != nil {
+			tax = tax.Sub(payslip.Tax.Amount)
+		}
+		correction.SalaryByAttendance = correction.SalaryByAttendance.Sub(payslip.SalaryByAttendance)
+		correction.OvetimeTotalSalary = correction.OvetimeTotalSalary.Sub(payslip.OvetimeTotalSalary)
+		correction.ReimbursementsTotalSalary = correction.ReimbursementsTotalSalary.Sub(payslip.ReimbursementsTotalSalary)
+		correction.TaxableIncome = correction.TaxableIncome.Sub(payslip.TaxableIncome)
+	}
+
+	correction.Lines = []PayslipLine{}
+	for _, key := range keys {
+		if deltas[key].IsZero() {
+			continue
+		}
+		line := lines[key]
+		delta := NewAmountLine(line.Code, line.Category, line.Description, deltas[key], line.Taxable)
+		delta.StartDate, delta.EndDate, delta.LoanID = line.StartDate, line.EndDate, line.LoanID
+		correction.Lines = append(correction.Lines, delta)
+	}
+	correction.BPJS = nil
+	for _, program := range bpjsPrograms {
+		if contribution := bpjs[program]; !contribution.EmployeeAmount.IsZero() || !contribution.EmployerAmount.IsZero() {
+			correction.BPJS = append(correction.BPJS, contribution)
+		}
+	}
+	if recalculated.Tax != nil {
+		detail := *recalculated.Tax
+		detail.Amount = tax
+		correction.Tax = &detail
+	}
+	correction.SetTotalsFromLines()
+	return correction
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "testing"
+
+func TestPayslipCorrection(t *testing.T) {
+	payslip := func(base, overtime, tax, jht int64) Payslip {
+		p := Payslip{
+			Lines: []PayslipLine{
+				{Code: PayslipLineCodeWorkdays, Category: PayslipLineInformation, Description: "Workdays", Quantity: RateFromInt(21)},
+				NewAmountLine(PayslipLineCodeBasePay, PayslipLineEarning, "Base salary", NewMoney(base), true),
+				NewAmountLine(DeductionCodePPh21, PayslipLineDeduction, "PPh 21", NewMoney(tax), false),
+				NewAmountLine(PayslipLineCodeLoanInstallment, PayslipLineDeduction, "Loan installment", NewMoney(100000), false),
+			},
+			Tax:              &TaxDetail{Amount: NewMoney(tax)},
+			BPJS:             []BPJSContribution{{Program: BPJSProgramJHT, EmployeeAmount: NewMoney(jht)}},
+			TaxableIncome:    NewMoney(base + overtime),
+			LoanInstallments: []LoanInstallment{{LoanID: 1, Amount: NewMoney(100000)}},
+		}
+		if overtime > 0 {
+			p.Lines = append(p.Lines, NewAmountLine(PayslipLineCodeOvertime, PayslipLineEarning, "Overtime", NewMoney(overtime), true))
+		}
+		p.SetTotalsFromLines()
+		return p
+	}
+	paid := payslip(5000000, 0, 50000, 100000)
+	recalculated := payslip(5000000, 300000, 65000, 100000)
+
+	correction := PayslipCorrection([]Payslip{paid}, recalculated)
+	if len(correction.Lines) != 2 {
+		t.Fatalf("correction lines = %+v, want the overtime and the PPh 21 difference", correction.Lines)
+	}
+	if line := correction.Lines[0]; line.Code != DeductionCodePPh21 || line.Amount != NewMoney(15000) {
+		t.Errorf("first line = %s %s, want PPH21 15000.00", line.Code, line.Amount)
+	}
+	if line := correction.Lines[1]; line.Code != PayslipLineCodeOvertime || line.Amount != NewMoney(300000) {
+		t.Errorf("second line = %s %s, want OVERTIME 300000.00", line.Code, line.Amount)
+	}
+	if correction.TotalSalary != NewMoney(300000) || correction.NetSalary != NewMoney(285000) {
+		t.Errorf("totals = %s gross, %s net; want 300000.00 and 285000.00", correction.TotalSalary, correction.NetSalary)
+	}
+	if correction.Tax.Amount != NewMoney(15000) || correction.TaxableIncome != NewMoney(300000) {
+		t.Errorf("tax = %s on %s, want 15000.00 on 300000.00", correction.Tax.Amount, correction.TaxableIncome)
+	}
+	if len(correction.BPJS) != 0 || correction.LoanInstallments != nil {
+		t.Errorf("unchanged BPJS and loan installments must be left out, got %+v and %+v", correction.BPJS, correction.LoanInstallments)
+	}
+
+	// together the payslips pay what the recalculation says
+	if total := paid.Totals().Add(correction.Totals()); total.TotalSalary != recalculated.TotalSalary || total.Tax != recalculated.Tax.Amount {
+		t.Errorf("paid + correction = %+v, want the recalculated totals", total)
+	}
+}
@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+const (
+	PayslipEmailQueued  = "queued"
+	PayslipEmailSending = "sending"
+	PayslipEmailSent    = "sent"
+	PayslipEmailFailed  = "failed" // gave up; an admin can resend it
+)
+
+// PayslipEmail is the delivery of one employee's payslip of a period. Emails are persisted so
+// queued and retried deliveries survive a restart.
+type PayslipEmail struct {
+	ID            int        `json:"id"`
+	PeriodID      int        `json:"period_id"`
+	EmployeeID    int        `json:"employee_id"`
+	Recipient     string     `json:"recipient"`
+	Protected     bool       `json:"protected"` // the attachment is opened with the employee's payslip password
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	CreatedBy     string     `json:"created_by"`
+	UpdatedBy     string     `json:"updated_by"`
+}
+
+func IsValidPayslipEmailStatus(status string) bool {
+	switch status {
+	case PayslipEmailQueued, PayslipEmailSending, PayslipEmailSent, PayslipEmailFailed:
+		return true
+	}
+	return false
+}
+
+// PayslipEmailDistribution is the outcome of queueing a period's payslips for email.
+type PayslipEmailDistribution struct {
+	PeriodID      int   `json:"period_id"`
+	Queued        int   `json:"queued"`
+	AlreadyQueued int   `json:"already_queued"`          // queued or sent by an earlier distribution
+	WithoutEmail  []int `json:"without_email,omitempty"` // employee IDs that were skipped
+}
+
+// PayslipEmailSettings controls the automatic distribution when a payroll run is approved.
+type PayslipEmailSettings struct {
+	SendOnLock        bool
+	ProtectAttachment bool // default for distributions that do not choose
+}
+
+// PayslipPassword is what is kept of the password an employee sets for their protected
+// payslips. The password itself is not stored: User and Key are derived from it with random
+// salts the way PDF encryption needs them, so payslips can be encrypted for it without HR
+// knowing it. Key opens every payslip encrypted for the password and is stored encrypted with
+// a server secret.
+type PayslipPassword struct {
+	EmployeeID int       `json:"employee_id"`
+	User       []byte    `json:"-"`
+	Key        []byte    `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,212 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	PayslipLineEarning              = "earning"
+	PayslipLineDeduction            = "deduction"
+	PayslipLineEmployerContribution = "employer_contribution" // paid by the employer on top of the pay
+	PayslipLineInformation          = "information"           // shown on the payslip but not paid, e.g. workdays
+)
+
+// Codes of the lines produced by the calculator. PPh 21 uses DeductionCodePPh21 and BPJS
+// lines use "BPJS_" followed by the program, for both the employee and the employer share.
+const (
+	PayslipLineCodeWorkdays        = "WORKDAYS"
+	PayslipLineCodeAttendance      = "ATTENDANCE"
+	PayslipLineCodeBasePay         = "BASE_PAY"
+	PayslipLineCodeOvertime        = "OVERTIME"
+	PayslipLineCodeRestDayOvertime = "OVERTIME_REST_DAY"
+	PayslipLineCodeReimbursement   = "REIMBURSEMENT"
+	PayslipLineCodeLoanInstallment = "LOAN_INSTALLMENT"
+	PayslipLineCodeTHR             = "THR"
+	PayslipLineCodeBonus           = "BONUS"
+)
+
+// IsReservedPayslipLineCode reports whether the code belongs to a line the calculator produces,
+// so pay components cannot use it.
+func IsReservedPayslipLineCode(code string) bool {
+	switch code {
+	case PayslipLineCodeWorkdays, PayslipLineCodeAttendance, PayslipLineCodeBasePay, PayslipLineCodeOvertime,
+		PayslipLineCodeRestDayOvertime, PayslipLineCodeReimbursement, PayslipLineCodeLoanInstallment,
+		PayslipLineCodeTHR, PayslipLineCodeBonus, DeductionCodePPh21:
+		return true
+	}
+	return strings.HasPrefix(code, "BPJS_")
+}
+
+// Units of a line's quantity.
+const (
+	PayslipLineUnitDays   = "days"
+	PayslipLineUnitHours  = "hours"
+	PayslipLineUnitMonths = "months"
+	PayslipLineUnitWage   = "wage" // the contribution base a BPJS rate applies to
+)
+
+// PayslipLine is one typed line of a payslip. Amount is Quantity x Rate rounded with the
+// currency's rounding policy; information lines have a quantity but no amount. Description
+// is a plain label: what the line was calculated from is in the other fields.
+type PayslipLine struct {
+	Code        string     `json:"code"`
+	Category    string     `json:"category"`
+	Description string     `json:"description"`
+	Quantity    Rate       `json:"quantity"`
+	Unit        string     `json:"unit,omitempty"`
+	Rate        Rate       `json:"rate"`
+	Amount      Money      `json:"amount"`
+	Taxable     bool       `json:"taxable"`
+	Formula     string     `json:"formula,omitempty"`    // the base pay formula of BASE_PAY lines
+	StartDate   *time.Time `json:"start_date,omitempty"` // the days the line covers, e.g. a salary segment
+	EndDate     *time.Time `json:"end_date,omitempty"`
+	LoanID      int        `json:"loan_id,omitempty"`
+}
+
+// Label is the description with the dates or loan the line is for, as printed on payslips.
+func (l PayslipLine) Label() string {
+	label := l.Description
+	switch {
+	case l.StartDate != nil && l.EndDate != nil && !l.StartDate.Equal(*l.EndDate):
+		label += " " + l.StartDate.Format("2006-01-02") + " to " + l.EndDate.Format("2006-01-02")
+	case l.StartDate != nil:
+		label += " " + l.StartDate.Format("2006-01-02")
+	}
+	if l.LoanID != 0 {
+		label += fmt.Sprintf(" #%d", l.LoanID)
+	}
+	return label
+}
+
+// PayslipLines are the lines of a payslip in display order.
+type PayslipLines []PayslipLine
+
+// Total sums the amounts of the lines in a category.
+func (lines PayslipLines) Total(category string) Money {
+	var total Money
+	for _, line := range lines {
+		if line.Category == category {
+			total = total.Add(line.Amount)
+		}
+	}
+	return total
+}
+
+// TotalOf sums the amounts of the lines with one of the codes.
+func (lines PayslipLines) TotalOf(codes ...string) Money {
+	var total Money
+	for _, line := range lines {
+		for _, code := range codes {
+			if line.Code == code {
+				total = total.Add(line.Amount)
+			}
+		}
+	}
+	return total
+}
+
+// TaxableIncome sums the taxable earnings and the taxable employer contributions, which are
+// a benefit in kind for PPh 21.
+func (lines PayslipLines) TaxableIncome() Money {
+	var total Money
+	for _, line := range lines {
+		if line.Taxable && (line.Category == PayslipLineEarning || line.Category == PayslipLineEmployerContribution) {
+			total = total.Add(line.Amount)
+		}
+	}
+	return total
+}
+
+// SetTotalsFromLines derives the gross, deductions, net and employer contributions from the
+// lines, and lists the deduction lines under Deductions for clients of the earlier format.
+func (p *Payslip) SetTotalsFromLines() {
+	lines := PayslipLines(p.Lines)
+	p.TotalSalary = lines.Total(PayslipLineEarning)
+	p.TotalDeductions = lines.Total(PayslipLineDeduction)
+	p.NetSalary = p.TotalSalary.Sub(p.TotalDeductions)
+	p.EmployerContributions = lines.Total(PayslipLineEmployerContribution)
+	p.Deductions = nil
+	for _, line := range lines {
+		if line.Category == PayslipLineDeduction {
+			p.Deductions = append(p.Deductions, Deduction{Code: line.Code, Description: line.Description, Amount: line.Amount})
+		}
+	}
+}
+
+// UnmarshalJSON reads payslips stored before they had lines by rebuilding the lines from the
+// fixed fields. The stored totals are kept as they are.
+func (p *Payslip) UnmarshalJSON(data []byte) error {
+	type storedPayslip Payslip
+	var stored storedPayslip
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+	*p = Payslip(stored)
+	if p.Lines == nil {
+		p.Lines = p.legacyLines()
+	}
+	return nil
+}
+
+func (p Payslip) legacyLines() []PayslipLine {
+	lines := []PayslipLine{
+		{Code: PayslipLineCodeWorkdays, Category: PayslipLineInformation, Description: "Workdays", Quantity: RateFromInt(int64(p.TotalWorkDays)), Unit: PayslipLineUnitDays},
+		{Code: PayslipLineCodeAttendance, Category: PayslipLineInformation, Description: "Attendance", Quantity: RateFromInt(int64(p.NumberAttendances)), Unit: PayslipLineUnitDays},
+	}
+	if len(p.SalarySegments) == 0 {
+		lines = append(lines, NewAmountLine(PayslipLineCodeBasePay, PayslipLineEarning, "Base salary", p.SalaryByAttendance, true))
+	}
+	for _, s := range p.SalarySegments {
+		line := NewAmountLine(PayslipLineCodeBasePay, PayslipLineEarning, "Base salary", s.Amount, true)
+		line.StartDate, line.EndDate = dateRef(s.StartDate), dateRef(s.EndDate)
+		lines = append(lines, line)
+	}
+	for _, o := range p.OvertimesRecap {
+		code, description := PayslipLineCodeOvertime, "Overtime"
+		if o.RestDay {
+			code, description = PayslipLineCodeRestDayOvertime, "Rest day overtime"
+		}
+		line := NewAmountLine(code, PayslipLineEarning, description, o.Amount, true)
+		line.StartDate, line.EndDate = dateRef(o.Date), dateRef(o.Date)
+		if o.Hours > 0 {
+			line.Quantity = RateFromInt(int64(o.Hours))
+			line.Unit = PayslipLineUnitHours
+			line.Rate = RateFromRat(new(big.Rat).Quo(o.Amount.Rat(), big.NewRat(int64(o.Hours), 1)))
+		}
+		lines = append(lines, line)
+	}
+	for _, r := range p.Reimbursements {
+		lines = append(lines, NewAmountLine(PayslipLineCodeReimbursement, PayslipLineEarning, r.Description, r.Amount, false))
+	}
+	for _, d := range p.Deductions {
+		lines = append(lines, NewAmountLine(d.Code, PayslipLineDeduction, d.Description, d.Amount, false))
+	}
+	for _, c := range p.BPJS {
+		if !c.EmployerAmount.IsZero() {
+			lines = append(lines, NewAmountLine("BPJS_"+c.Program, PayslipLineEmployerContribution,
+				"BPJS "+c.Program+" employer contribution", c.EmployerAmount, c.IsTaxableBenefit()))
+		}
+	}
+	return lines
+}
+
+func dateRef(t time.Time) *time.Time {
+	return &t
+}
+
+// NewAmountLine is a line with a quantity of one, e.g. a reimbursement or a withheld tax.
+func NewAmountLine(code, category, description string, amount Money, taxable bool) PayslipLine {
+	return PayslipLine{
+		Code:        code,
+		Category:    category,
+		Description: description,
+		Quantity:    RateFromInt(1),
+		Rate:        RateFromRat(amount.Rat()),
+		Amount:      amount,
+		Taxable:     taxable,
+	}
+}
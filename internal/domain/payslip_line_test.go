@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// A payslip as stored before payslips had lines.
+const legacyPayslipJSON = `{
+	"employee_id": 3, "period_id": 1, "num_attendances": 18, "total_work_days": 21,
+	"salary_by_attendance": 4294286,
+	"overtimes_recap": [{"date": "2025-06-10T00:00:00Z", "hours": 2, "amount": 1002000}],
+	"overtime_total_salary": 1002000,
+	"reimbursements": [{"amount": 150000, "description": "Taxi"}],
+	"reimbursements_total_salary": 150000,
+	"total_salary": 5446286,
+	"bpjs": [{"program": "JKK", "wage": 5010000, "employee_amount": 0, "employer_amount": 12024}],
+	"employer_contributions": 12024,
+	"deductions": [{"code": "PPH21", "description": "PPh 21 income tax", "amount": 101989}],
+	"total_deductions": 101989,
+	"net_salary": 5344297
+}`
+
+func TestPayslipUnmarshal_LegacyPayslip(t *testing.T) {
+	var payslip Payslip
+	if err := json.Unmarshal([]byte(legacyPayslipJSON), &payslip); err != nil {
+		t.Fatal(err)
+	}
+	lines := PayslipLines(payslip.Lines)
+	if got := lines.Total(PayslipLineEarning); got != payslip.TotalSalary {
+		t.Errorf("earning lines = %s, want the stored gross %s", got, payslip.TotalSalary)
+	}
+	if got := lines.Total(PayslipLineDeduction); got != payslip.TotalDeductions {
+		t.Errorf("deduction lines = %s, want the stored deductions %s", got, payslip.TotalDeductions)
+	}
+	if got := lines.Total(PayslipLineEmployerContribution); got != payslip.EmployerContributions {
+		t.Errorf("employer lines = %s, want the stored contributions %s", got, payslip.EmployerContributions)
+	}
+	// salary, overtime and the JKK premium are taxable, the reimbursement is not
+	if got := lines.TaxableIncome(); got != NewMoney(5308310) {
+		t.Errorf("taxable lines = %s, want 5308310.00", got)
+	}
+	if overtime := payslip.Lines[3]; overtime.Code != PayslipLineCodeOvertime || overtime.Quantity.Cmp(RateFromInt(2)) != 0 || overtime.Rate.Cmp(RateFromInt(501000)) != 0 {
+		t.Errorf("overtime line = %+v, want 2 x 501000", overtime)
+	}
+
+	// payslips with lines are read as stored
+	data, err := json.Marshal(payslip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reread Payslip
+	if err := json.Unmarshal(data, &reread); err != nil {
+		t.Fatal(err)
+	}
+	if len(reread.Lines) != len(payslip.Lines) || reread.NetSalary != payslip.NetSalary {
+		t.Errorf("re-read payslip has %d lines and net %s, want %d and %s", len(reread.Lines), reread.NetSalary, len(payslip.Lines), payslip.NetSalary)
+	}
+}
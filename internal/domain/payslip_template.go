@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+)
+
+// Formats payslips are rendered in.
+const (
+	PayslipFormatHTML = "html"
+	PayslipFormatPDF  = "pdf"
+)
+
+var companyCodePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,30}$`)
+
+// IsValidCompanyCode reports whether the code can name a legal entity: letters, digits, dashes
+// and underscores, up to 30 characters.
+func IsValidCompanyCode(code string) bool {
+	return companyCodePattern.MatchString(code)
+}
+
+// PayslipTemplate is a version of a company's payslip layout, written in Go html/template.
+// Saving a template adds a version; payslips are rendered with the company's latest version.
+type PayslipTemplate struct {
+	ID        int       `json:"id"`
+	Company   string    `json:"company"`
+	Version   int       `json:"version"`
+	Name      string    `json:"name"`
+	Language  string    `json:"language"` // e.g. en or id, set as the lang of the HTML page
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `json:"created_by"`
+}
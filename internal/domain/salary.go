@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// SalaryChange is an entry of an employee's salary history. The salary applies from
+// EffectiveFrom until the next change. Entries are never updated, so payslips of earlier
+// periods can be recalculated from the history.
+type SalaryChange struct {
+	ID            int       `json:"id"`
+	EmployeeID    int       `json:"employee_id"`
+	Salary        Money     `json:"salary"`
+	EffectiveFrom time.Time `json:"effective_from"`
+	Reason        string    `json:"reason"`
+	CreatedAt     time.Time `json:"created_at"`
+	CreatedBy     string    `json:"created_by"`
+}
+
+// SalarySegment is the part of a payroll period paid at one monthly salary.
+type SalarySegment struct {
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	Salary    Money     `json:"salary"`
+	Workdays  int       `json:"workdays"`
+	Amount    Money     `json:"amount"` // base pay earned in the segment
+}
+
+// SalarySegments splits the days from start to end, both inclusive, by the salary changes
+// effective in them. Days before the first change have no salary and get no segment.
+func SalarySegments(history []SalaryChange, start, end time.Time) []SalarySegment {
+	changes := make([]SalaryChange, len(history))
+	copy(changes, history)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].EffectiveFrom.Before(changes[j].EffectiveFrom)
+	})
+
+	var segments []SalarySegment
+	for i, change := range changes {
+		segmentStart := start
+		if change.EffectiveFrom.After(start) {
+			segmentStart = change.EffectiveFrom
+		}
+		segmentEnd := end
+		if i+1 < len(changes) {
+			if dayBefore := changes[i+1].EffectiveFrom.AddDate(0, 0, -1); dayBefore.Before(end) {
+				segmentEnd = dayBefore
+			}
+		}
+		if segmentStart.After(segmentEnd) {
+			continue
+		}
+		segments = append(segments, SalarySegment{
+			StartDate: segmentStart,
+			EndDate:   segmentEnd,
+			Salary:    change.Salary,
+		})
+	}
+	return segments
+}
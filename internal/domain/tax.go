@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	MaritalStatusSingle  = "TK" // tidak kawin
+	MaritalStatusMarried = "K"  // kawin
+)
+
+const (
+	TaxMethodTER    = "ter"    // monthly withholding with the average effective rate tables
+	TaxMethodAnnual = "annual" // December true-up with the annual progressive rates
+)
+
+// TaxProfile holds the PPh 21 details of an employee.
+type TaxProfile struct {
+	EmployeeID    int       `json:"employee_id"`
+	NPWP          string    `json:"npwp"`
+	MaritalStatus string    `json:"marital_status"` // TK or K
+	Dependents    int       `json:"dependents"`     // 0-3, counted for PTKP
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	CreatedBy     string    `json:"created_by"`
+	UpdatedBy     string    `json:"updated_by"`
+}
+
+// DefaultTaxProfile is used for employees without a stored profile.
+func DefaultTaxProfile(employeeID int) TaxProfile {
+	return TaxProfile{EmployeeID: employeeID, MaritalStatus: MaritalStatusSingle}
+}
+
+// PTKPStatus returns the PTKP status code, e.g. "TK/0" or "K/2".
+func (p TaxProfile) PTKPStatus() string {
+	dependents := p.Dependents
+	if dependents > 3 {
+		dependents = 3
+	}
+	status := p.MaritalStatus
+	if status == "" {
+		status = MaritalStatusSingle
+	}
+	return fmt.Sprintf("%s/%d", status, dependents)
+}
+
+func (p TaxProfile) HasNPWP() bool {
+	return p.NPWP != ""
+}
+
+// TaxYearToDate is what has already been paid and withheld for an employee in a tax year.
+type TaxYearToDate struct {
+	EmployeeID    int   `json:"employee_id"`
+	TaxYear       int   `json:"tax_year,omitempty"`
+	Months        int   `json:"months"` // regular payslips; THR and bonus payslips do not count as a month
+	Gross         Money `json:"gross"`
+	TaxableIncome Money `json:"taxable_income"`
+	TaxWithheld   Money `json:"tax_withheld"`
+	BPJSEmployee  Money `json:"bpjs_employee"`
+	BPJSEmployer  Money `json:"bpjs_employer"`
+	Pension       Money `json:"pension"` // employee JHT and JP contributions
+	Net           Money `json:"net"`
+}
+
+// Add returns the year to date with the payslip included.
+func (y TaxYearToDate) Add(p Payslip, regular bool) TaxYearToDate {
+	if regular {
+		y.Months++
+	}
+	y.Gross = y.Gross.Add(p.TotalSalary)
+	y.TaxableIncome = y.TaxableIncome.Add(p.TaxableIncome)
+	if p.Tax != nil {
+		y.TaxWithheld = y.TaxWithheld.Add(p.Tax.Amount)
+	}
+	for _, contribution := range p.BPJS {
+		y.BPJSEmployee = y.BPJSEmployee.Add(contribution.EmployeeAmount)
+		y.BPJSEmployer = y.BPJSEmployer.Add(contribution.EmployerAmount)
+		if contribution.IsPension() {
+			y.Pension = y.Pension.Add(contribution.EmployeeAmount)
+		}
+	}
+	y.Net = y.Net.Add(p.NetSalary)
+	return y
+}
+
+// Plus returns the sum of two year-to-date totals of the same employee and tax year.
+func (y TaxYearToDate) Plus(other TaxYearToDate) TaxYearToDate {
+	y.Months += other.Months
+	y.Gross = y.Gross.Add(other.Gross)
+	y.TaxableIncome = y.TaxableIncome.Add(other.TaxableIncome)
+	y.TaxWithheld = y.TaxWithheld.Add(other.TaxWithheld)
+	y.BPJSEmployee = y.BPJSEmployee.Add(other.BPJSEmployee)
+	y.BPJSEmployer = y.BPJSEmployer.Add(other.BPJSEmployer)
+	y.Pension = y.Pension.Add(other.Pension)
+	y.Net = y.Net.Add(other.Net)
+	return y
+}
+
+// TaxDetail explains how the PPh 21 on a payslip was calculated.
+type TaxDetail struct {
+	Method        string `json:"method"`
+	PTKPStatus    string `json:"ptkp_status"`
+	HasNPWP       bool   `json:"has_npwp"`
+	TERCategory   string `json:"ter_category,omitempty"`
+	TERRate       Rate   `json:"ter_rate"`
+	AnnualTaxable Money  `json:"annual_taxable_income"` // PKP, only for the annual method
+	AnnualTax     Money  `json:"annual_tax"`
+	WithheldYTD   Money  `json:"withheld_ytd"`
+	Amount        Money  `json:"amount"`
+}
+
+const DeductionCodePPh21 = "PPH21"
+
+// Deduction is an amount withheld from the gross pay.
+type Deduction struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+	Amount      Money  `json:"amount"`
+}
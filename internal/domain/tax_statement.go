@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// TaxWithholder is the employer that withholds PPh 21 and signs the 1721-A1 statements.
+type TaxWithholder struct {
+	Name string `json:"name"`
+	NPWP string `json:"npwp"`
+}
+
+// TaxYearPayslip is a payslip of a locked period, as counted in the tax year of the period.
+type TaxYearPayslip struct {
+	PeriodID  int
+	PeriodEnd time.Time
+	Regular   bool // false for THR and bonus payslips
+	Payslip   Payslip
+}
+
+// TaxStatement is the 1721-A1 statement of the PPh 21 withheld from an employee in a tax
+// year. The numbered fields follow the items of section B of the form.
+type TaxStatement struct {
+	ID           int           `json:"id"`
+	Number       string        `json:"number"`
+	Sequence     int           `json:"sequence"`
+	TaxYear      int           `json:"tax_year"`
+	Withholder   TaxWithholder `json:"withholder"`
+	EmployeeID   int           `json:"employee_id"`
+	EmployeeName string        `json:"employee_name"`
+	NPWP         string        `json:"npwp"`
+	PTKPStatus   string        `json:"ptkp_status"`
+	FirstMonth   int           `json:"first_month"` // masa perolehan penghasilan
+	LastMonth    int           `json:"last_month"`
+	Currency     string        `json:"currency"`
+
+	Salary            Money `json:"salary"`             // 1. gaji
+	TaxAllowance      Money `json:"tax_allowance"`      // 2. tunjangan PPh
+	OtherAllowances   Money `json:"other_allowances"`   // 3. tunjangan lainnya, uang lembur
+	Honorarium        Money `json:"honorarium"`         // 4. honorarium
+	InsurancePremiums Money `json:"insurance_premiums"` // 5. premi asuransi dibayar pemberi kerja
+	BenefitsInKind    Money `json:"benefits_in_kind"`   // 6. natura
+	BonusAndTHR       Money `json:"bonus_and_thr"`      // 7. tantiem, bonus, THR
+	GrossIncome       Money `json:"gross_income"`       // 8. 1 to 7
+
+	OccupationalCost     Money `json:"occupational_cost"`     // 9. biaya jabatan
+	PensionContributions Money `json:"pension_contributions"` // 10. iuran JHT dan JP
+	Zakat                Money `json:"zakat"`                 // 11.
+	TotalDeductions      Money `json:"total_deductions"`      // 12. 9 to 11
+
+	NetIncome        Money `json:"net_income"`         // 13. 8 - 12
+	PriorNetIncome   Money `json:"prior_net_income"`   // 14. from earlier employers in the year
+	AnnualNetIncome  Money `json:"annual_net_income"`  // 15. 13 + 14
+	PTKP             Money `json:"ptkp"`               // 16.
+	TaxableIncome    Money `json:"taxable_income"`     // 17. PKP, 15 - 16
+	AnnualTax        Money `json:"annual_tax"`         // 18. PPh 21 on PKP
+	PriorTaxWithheld Money `json:"prior_tax_withheld"` // 19. withheld by earlier employers
+	TaxDue           Money `json:"tax_due"`            // 20. PPh 21 terutang
+	TaxWithheld      Money `json:"tax_withheld"`       // 21. withheld on the payslips of the year
+
+	GeneratedAt time.Time `json:"generated_at"`
+	GeneratedBy string    `json:"generated_by"`
+}
+
+// FormNumber is the statement number printed on the form: the form code 1.1, the last month
+// and year of the statement and its sequence among the statements of the tax year.
+func (s TaxStatement) FormNumber() string {
+	return fmt.Sprintf("1.1-%02d.%02d-%07d", s.LastMonth, s.TaxYear%100, s.Sequence)
+}
@@ -1,6 +1,4 @@
 package error_const
 
-import "errors"
-
-var ErrAttendanceAlreadyExists = errors.New("attendance already exists for the given date and employee")
-var ErrAttendanceOnWeekend = errors.New("attendance cannot be recorded on weekends")
+var ErrAttendanceAlreadyExists = Conflict("attendance already exists for the given date and employee")
+var ErrAttendanceOnWeekend = Invalid("attendance cannot be recorded on weekends")
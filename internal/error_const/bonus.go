@@ -0,0 +1,11 @@
+package error_const
+
+var ErrBonusRunNotFound = NotFound("bonus run not found")
+var ErrInvalidBonusKind = Invalid("invalid bonus kind, expected thr or bonus")
+var ErrInvalidBonusAmount = Invalid("bonus amount must be greater than zero")
+var ErrBonusAmountsRequired = Invalid("bonus amounts are required")
+var ErrDuplicateBonusEmployee = Invalid("employee is listed more than once")
+var ErrNoTHREntitlement = Invalid("employee has less than one month of service and no THR entitlement")
+var ErrTHRAlreadyPaid = Conflict("THR has already been paid to the employee this year")
+var ErrPayrollPeriodNotRun = Conflict("payroll period must be run and locked before paying THR or bonuses")
+var ErrNoEligibleEmployees = Invalid("no employees are eligible for THR")
@@ -0,0 +1,4 @@
+package error_const
+
+var ErrInvalidBPJSProgram = Invalid("BPJS program must be one of JHT, JP, JKK, JKM or KES")
+var ErrInvalidBPJSRate = Invalid("BPJS rates and wage cap cannot be negative")
@@ -0,0 +1,25 @@
+package error_const
+
+var ErrBankAccountNotFound = NotFound("bank account not found for the employee")
+var ErrInvalidBankCode = Invalid("bank code must be 3 to 11 upper case letters or digits")
+var ErrInvalidBankAccountNumber = Invalid("bank account number must be 5 to 20 digits")
+var ErrBankAccountHolderRequired = Invalid("bank account holder name is required")
+var ErrBankAccountMissing = Invalid("employees paid in the period have no bank account")
+var ErrDisbursementPeriodNotLocked = Conflict("payroll period must be locked before its disbursement is exported")
+var ErrPeriodAlreadyDisbursed = Conflict("the net pay of the payroll period was already disbursed")
+var ErrMixedDisbursementCurrencies = Conflict("payslips of the payroll period are in more than one currency")
+var ErrDisbursementOutdated = Conflict("the payslips or disbursements of the payroll period changed while the batch was prepared, try again")
+var ErrUnknownDisbursementFormat = Invalid("unknown disbursement file format")
+var ErrDisbursementBatchNotFound = NotFound("disbursement batch not found")
+var ErrDisbursementBatchNotExported = Conflict("disbursement batch is not exported")
+var ErrCancelReasonRequired = Invalid("a reason is required to cancel a disbursement batch")
+var ErrNoNetPayToDisburse = Invalid("no employee has net pay to disburse in this period")
+var ErrDisbursementBatchSent = Conflict("disbursement batch was already sent to the bank")
+var ErrDisbursementItemNotFound = NotFound("disbursement item not found")
+var ErrInvalidPaymentStatus = Invalid("payment status must be pending, sent, paid, failed or returned")
+var ErrPaymentStatusChange = Conflict("the payment cannot change to this status")
+var ErrDisbursementItemReissued = Conflict("the payment was already reissued in another batch")
+var ErrUnknownStatementFormat = Invalid("bank statement format must be csv or mt940")
+var ErrInvalidStatement = Invalid("bank statement cannot be read")
+var ErrEmptyStatement = Invalid("bank statement has no entries")
+var ErrStatementImportConflict = Conflict("the bank statement is being imported by another request, try again")
@@ -0,0 +1,7 @@
+package error_const
+
+var ErrInvalidEmploymentStatus = Invalid("employment status must be active, inactive or terminated")
+var ErrEmploymentEndBeforeStart = Invalid("employment end date cannot be before the start date")
+var ErrTerminationDateRequired = Invalid("an end date is required to terminate an employee")
+var ErrEmployeeNotEmployed = Invalid("employee is not employed in the payroll period")
+var ErrEmployeeInactive = Invalid("employee is no longer active")
@@ -0,0 +1,58 @@
+package error_const
+
+// Kind tells what went wrong with a request, so the HTTP layer can answer with a
+// matching status without knowing every error by name.
+type Kind int
+
+const (
+	// KindInvalid is a request the client has to change before it can succeed.
+	KindInvalid Kind = iota
+	// KindNotFound is a request for something that does not exist.
+	KindNotFound
+	// KindConflict is a request the current state of the data does not allow.
+	KindConflict
+	// KindUnauthorized is a request without valid credentials.
+	KindUnauthorized
+	// KindForbidden is a request the caller is not allowed to make.
+	KindForbidden
+)
+
+// Error is an error the caller can act on. Errors that are not an *Error are
+// failures of the system itself.
+type Error struct {
+	Kind    Kind
+	message string
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+func newError(kind Kind, message string) error {
+	return &Error{Kind: kind, message: message}
+}
+
+// Invalid returns an error for a request the client has to change.
+func Invalid(message string) error {
+	return newError(KindInvalid, message)
+}
+
+// NotFound returns an error for a request for something that does not exist.
+func NotFound(message string) error {
+	return newError(KindNotFound, message)
+}
+
+// Conflict returns an error for a request the current state does not allow.
+func Conflict(message string) error {
+	return newError(KindConflict, message)
+}
+
+// Unauthorized returns an error for a request without valid credentials.
+func Unauthorized(message string) error {
+	return newError(KindUnauthorized, message)
+}
+
+// Forbidden returns an error for a request the caller is not allowed to make.
+func Forbidden(message string) error {
+	return newError(KindForbidden, message)
+}
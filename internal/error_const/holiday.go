@@ -0,0 +1,8 @@
+package error_const
+
+var ErrHolidayNotFound = NotFound("holiday not found")
+var ErrHolidayAlreadyExists = Conflict("a holiday already exists on this date")
+var ErrInvalidHolidayType = Invalid("invalid holiday type, use national, cuti_bersama or company")
+var ErrHolidayNameRequired = Invalid("holiday name is required")
+var ErrHolidayOutsideYear = Invalid("holiday date is outside the imported year")
+var ErrAttendanceOnHoliday = Invalid("attendance cannot be recorded on a holiday")
@@ -0,0 +1,10 @@
+package error_const
+
+import "errors"
+
+var ErrJournalPeriodNotLocked = Conflict("payroll period must be locked before exporting its journal")
+var ErrGLAccountNotConfigured = Invalid("no GL account is configured for the journal component")
+var ErrGLAccountNotFound = NotFound("GL account not found")
+var ErrInvalidJournalComponent = Invalid("journal component is not valid")
+var ErrGLAccountCodeRequired = Invalid("GL account code is required")
+var ErrUnbalancedJournal = errors.New("journal debits and credits do not balance")
@@ -0,0 +1,7 @@
+package error_const
+
+var ErrLoanNotFound = NotFound("loan not found")
+var ErrInvalidLoanType = Invalid("invalid loan type, expected loan or salary_advance")
+var ErrInvalidLoanPrincipal = Invalid("loan principal must be greater than zero")
+var ErrInvalidLoanInstallments = Invalid("number of installments must be between 1 and 120")
+var ErrLoanNotActive = Conflict("loan is not active")
@@ -0,0 +1,6 @@
+package error_const
+
+var ErrInvalidMoney = Invalid("invalid money amount")
+var ErrInvalidRate = Invalid("invalid rate")
+var ErrInvalidRoundingPolicy = Invalid("invalid rounding policy, scale must be 0-2 and mode one of half_up, half_even, down, up")
+var ErrMoneyOverflow = Invalid("money amount out of range")
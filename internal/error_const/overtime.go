@@ -1,7 +1,5 @@
 package error_const
 
-import "errors"
-
-var ErrInvalidOvertimeHours = errors.New("overtime hours must be a positive number")
-var ErrOvertimeHoursExceeded = errors.New("overtime hours cannot exceed 3 hours per day")
-var ErrOvertimeAlreadyExists = errors.New("overtime record already exists for the given date")
+var ErrInvalidOvertimeHours = Invalid("overtime hours must be a positive number")
+var ErrOvertimeHoursExceeded = Invalid("overtime hours cannot exceed 3 hours per day")
+var ErrOvertimeAlreadyExists = Conflict("overtime record already exists for the given date")
@@ -0,0 +1,12 @@
+package error_const
+
+var ErrPayComponentNotFound = NotFound("pay component not found")
+var ErrPayComponentAlreadyExists = Conflict("a pay component with this code already exists")
+var ErrInvalidPayComponentCode = Invalid("pay component code must contain only upper case letters, digits and underscores")
+var ErrReservedPayComponentCode = Invalid("pay component code is reserved for a calculated payslip line")
+var ErrInvalidPayComponentCategory = Invalid("pay component category must be earning or deduction")
+var ErrPayComponentNameRequired = Invalid("pay component name is required")
+var ErrPayComponentInactive = Invalid("pay component is inactive")
+var ErrPayComponentAssignmentNotFound = NotFound("pay component assignment not found")
+var ErrPayComponentAssignmentOverlap = Conflict("the employee already has this pay component in the given dates")
+var ErrInvalidPayComponentAmount = Invalid("pay component amount must be greater than zero")
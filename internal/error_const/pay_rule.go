@@ -0,0 +1,6 @@
+package error_const
+
+var ErrPayRuleSetNotFound = NotFound("pay rule set not found")
+var ErrInvalidPayRuleSet = Invalid("pay rule set is invalid, divisor and multiplier must be positive")
+var ErrUnknownBasePayFormula = Invalid("unknown base pay formula")
+var ErrNoWorkdaysInPeriod = Invalid("payroll period has no workdays")
@@ -0,0 +1,12 @@
+package error_const
+
+var ErrPayrollJobNotFound = NotFound("payroll job not found")
+var ErrPayrollJobAlreadyActive = Conflict("a payroll job is already queued or running for this period")
+var ErrPayrollCalculationFailed = Invalid("payroll calculation failed")
+var ErrOffCycleReasonRequired = Invalid("a reason is required for an off-cycle payroll run")
+var ErrEmployeeNotFound = NotFound("employee not found")
+var ErrPayrollRunNotFound = NotFound("payroll run not found")
+var ErrPayrollRunPendingApproval = Conflict("a payroll run of this period is waiting for approval")
+var ErrPayrollRunNotPending = Conflict("payroll run is not waiting for approval")
+var ErrPayrollRunSelfApproval = Forbidden("a payroll run must be approved or rejected by another admin than the one who ran it")
+var ErrRejectionCommentRequired = Invalid("a comment is required to reject a payroll run")
@@ -1,13 +1,15 @@
 package error_const
 
-import "errors"
-
-var ErrEmptyPayrollPeriod = errors.New("payroll period cannot be empty")
-var ErrStartDateAfterEndDate = errors.New("start date cannot be after end date")
-var ErrPayrollPeriodNotFound = errors.New("payroll period not found")
-var ErrPayrollPeriodLocked = errors.New("payroll period is locked, cannot modify")
-var ErrPayrollPeriodAlreadyExists = errors.New("payroll period already exists for the given date range")
-var ErrPayrollPeriodInvalid = errors.New("payroll period is invalid, check start and end dates")
-var ErrNoEmployeesFound = errors.New("no employees found for payroll period")
-var ErrPayslipNotFound = errors.New("payslip not found for the given employee and period")
-var ErrNoPayrollsFound = errors.New("no payrolls found for this period")
+var ErrEmptyPayrollPeriod = Invalid("payroll period cannot be empty")
+var ErrStartDateAfterEndDate = Invalid("start date cannot be after end date")
+var ErrPayrollPeriodNotFound = NotFound("payroll period not found")
+var ErrPayrollPeriodLocked = Conflict("payroll period is locked, cannot modify")
+var ErrPayrollPeriodAlreadyExists = Conflict("payroll period already exists for the given date range")
+var ErrPayrollPeriodInvalid = Invalid("payroll period is invalid, check start and end dates")
+var ErrNoEmployeesFound = NotFound("no employees found for payroll period")
+var ErrPayslipNotFound = NotFound("payslip not found for the given employee and period")
+var ErrNoPayrollsFound = NotFound("no payrolls found for this period")
+var ErrPayrollPeriodNotLocked = Conflict("payroll period is not locked, run it instead of reopening")
+var ErrReopenReasonRequired = Invalid("a reason is required to reopen a payroll period")
+var ErrPreviousPayrollPeriodNotFound = NotFound("no payroll period ends before this period to compare with")
+var ErrInvalidVarianceThreshold = Invalid("variance thresholds must be non-negative numbers")
@@ -0,0 +1,13 @@
+package error_const
+
+import "errors"
+
+var ErrMailNotConfigured = Invalid("outgoing mail is not configured, set SMTP_HOST")
+var ErrPayslipEmailPeriodNotLocked = Conflict("payroll period must be locked before its payslips are emailed")
+var ErrPayslipEmailSending = Conflict("the payslip email is being sent, try again shortly")
+var ErrInvalidPayslipEmailStatus = Invalid("payslip email status must be queued, sending, sent or failed")
+var ErrEmployeeEmailMissing = Invalid("employee has no email address")
+var ErrNoPayslipPassword = Invalid("employee has not set a payslip password")
+var ErrInvalidSMTPConfig = errors.New("SMTP_PORT must be a port number, SMTP_FROM an email address and SMTP_TLS starttls, implicit or none")
+var ErrInvalidPayslipKeySecret = errors.New("PAYSLIP_KEY_SECRET must have at least 32 characters")
+var ErrPayslipPasswordTooShort = Invalid("payslip password must have at least 8 characters")
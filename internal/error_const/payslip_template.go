@@ -0,0 +1,7 @@
+package error_const
+
+var ErrInvalidCompanyCode = Invalid("company code must be letters, digits, dashes or underscores, up to 30 characters")
+var ErrPayslipTemplateNotFound = NotFound("payslip template not found")
+var ErrInvalidPayslipTemplate = Invalid("invalid payslip template")
+var ErrPayslipTemplateConflict = Conflict("another version of the payslip template was saved at the same time, try again")
+var ErrUnknownPayslipFormat = Invalid("payslip format must be html or pdf")
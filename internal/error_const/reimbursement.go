@@ -1,5 +1,3 @@
 package error_const
 
-import "errors"
-
-var ErrInvalidReimbursementAmount = errors.New("reimbursement amount must be a positive number")
+var ErrInvalidReimbursementAmount = Invalid("reimbursement amount must be a positive number")
@@ -0,0 +1,6 @@
+package error_const
+
+var ErrInvalidSalary = Invalid("salary must be greater than zero")
+var ErrSalaryChangeAlreadyExists = Conflict("a salary change is already effective on this date")
+var ErrSalaryChangeInLockedPeriod = Conflict("salary change falls in or before a locked payroll period, reopen the period first")
+var ErrNoSalaryInPeriod = Invalid("no salary is effective in the payroll period")
@@ -0,0 +1,9 @@
+package error_const
+
+var ErrInvalidMaritalStatus = Invalid("marital status must be TK or K")
+var ErrInvalidDependents = Invalid("number of dependents must be between 0 and 3")
+var ErrInvalidNPWP = Invalid("NPWP must contain 15 or 16 digits")
+var ErrTaxProfileNotFound = NotFound("tax profile not found for the given employee")
+var ErrInvalidTaxYear = Invalid("tax year must be between 2000 and 2100")
+var ErrTaxStatementNotFound = NotFound("tax statement not found for the given employee and tax year")
+var ErrNoPayslipsInTaxYear = Invalid("no payslips in locked payroll periods of the tax year")
@@ -5,9 +5,14 @@ package mocks
 
 import (
 	"context"
+	"sort"
 	"time"
 	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"payroll-system/internal/utils"
+	"net/mail"
 	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
 )
 
 type MockPayrollRepository struct {
@@ -16,6 +21,11 @@ type MockPayrollRepository struct {
 	Err      error
 	PayrollPeriod domain.PayrollPeriod
 	Payslip domain.Payroll
+	YearToDate []domain.TaxYearToDate
+	Runs []domain.PayrollRun
+	PreviousPayrollPeriod domain.PayrollPeriod
+	Reopens []domain.PayrollReopen
+	ReopenJobs []domain.PayrollJob // the re-run jobs queued by reopens
 }
 
 func NewMockPayrollRepository(ctrl *gomock.Controller) *MockPayrollRepository {
@@ -23,15 +33,24 @@ func NewMockPayrollRepository(ctrl *gomock.Controller) *MockPayrollRepository {
 }
 
 func (m *MockPayrollRepository) GetPayrollsByPeriodID(ctx context.Context, periodID int) ([]domain.Payroll, error) {
-	return m.Payrolls, m.Err
+	payrolls := []domain.Payroll{}
+	for _, payroll := range m.Payrolls {
+		if payroll.PeriodID == periodID {
+			payrolls = append(payrolls, payroll)
+		}
+	}
+	return payrolls, m.Err
 }
 
-func (m *MockPayrollRepository) BulkInsertPayrolls(ctx context.Context, payrolls []domain.Payroll) error {
-	return m.Err
+func (m *MockPayrollRepository) GetPayrollPeriod(ctx context.Context, period domain.PayrollPeriod) (domain.PayrollPeriod, error) {
+	return m.PayrollPeriod, m.Err
 }
 
-func (m *MockPayrollRepository) GetPayrollPeriod(ctx context.Context, period domain.PayrollPeriod) (domain.PayrollPeriod, error) {
-	return domain.PayrollPeriod{}, m.Err
+func (m *MockPayrollRepository) GetPreviousPayrollPeriod(ctx context.Context, period domain.PayrollPeriod) (domain.PayrollPeriod, error) {
+	if m.PreviousPayrollPeriod.ID == 0 {
+		return domain.PayrollPeriod{}, pgx.ErrNoRows
+	}
+	return m.PreviousPayrollPeriod, m.Err
 }
 
 func (m *MockPayrollRepository) GetPayrollPeriodFromDateRange(ctx context.Context, period domain.PayrollPeriod) (domain.PayrollPeriod, error) {
@@ -47,9 +66,142 @@ func (m *MockPayrollRepository) GetPayrollPeriodFromDate(ctx context.Context, da
 func (m *MockPayrollRepository) GetEmployeePayslipByPeriod(ctx context.Context, payroll domain.Payroll) (domain.Payroll, error) {
 	return m.Payslip, m.Err
 }
-func (m *MockPayrollRepository) LockPayrollPeriod(ctx context.Context, periodID int) error {
+func (m *MockPayrollRepository) SetPayrollPeriodRuleSetVersion(ctx context.Context, period domain.PayrollPeriod) error {
 	return m.Err
 }
+func (m *MockPayrollRepository) GetTaxYearToDateGroupedByEmployeeID(ctx context.Context, taxYear int, before time.Time) (map[int]domain.TaxYearToDate, error) {
+	return map[int]domain.TaxYearToDate{}, m.Err
+}
+func (m *MockPayrollRepository) GetYearToDate(ctx context.Context, employeeID, taxYear int) (domain.TaxYearToDate, error) {
+	for _, ytd := range m.YearToDate {
+		if ytd.EmployeeID == employeeID && ytd.TaxYear == taxYear {
+			return ytd, m.Err
+		}
+	}
+	if m.Err != nil {
+		return domain.TaxYearToDate{}, m.Err
+	}
+	return domain.TaxYearToDate{}, pgx.ErrNoRows
+}
+func (m *MockPayrollRepository) GetYearToDateByTaxYear(ctx context.Context, taxYear int) ([]domain.TaxYearToDate, error) {
+	var result []domain.TaxYearToDate
+	for _, ytd := range m.YearToDate {
+		if ytd.TaxYear == taxYear {
+			result = append(result, ytd)
+		}
+	}
+	return result, m.Err
+}
+func (m *MockPayrollRepository) RebuildYearToDate(ctx context.Context, taxYear int) (int, error) {
+	count := 0
+	for _, ytd := range m.YearToDate {
+		if taxYear == 0 || ytd.TaxYear == taxYear {
+			count++
+		}
+	}
+	return count, m.Err
+}
+func (m *MockPayrollRepository) ReopenPayrollPeriod(ctx context.Context, reopen domain.PayrollReopen, job domain.PayrollJob, audit domain.AuditLog) (domain.PayrollReopen, error) {
+	if m.Err != nil {
+		return domain.PayrollReopen{}, m.Err
+	}
+	m.PayrollPeriod.Locked = false
+	m.ReopenJobs = append(m.ReopenJobs, job)
+	reopen.ID = len(m.Reopens) + 1
+	reopen.JobID = len(m.ReopenJobs)
+	reopen.Diff = []domain.PayslipDiff{}
+	m.Reopens = append(m.Reopens, reopen)
+	return reopen, nil
+}
+func (m *MockPayrollRepository) GetPayrollReopensByPeriodID(ctx context.Context, periodID int) ([]domain.PayrollReopen, error) {
+	reopens := []domain.PayrollReopen{}
+	for _, reopen := range m.Reopens {
+		if reopen.PeriodID == periodID {
+			reopens = append(reopens, reopen)
+		}
+	}
+	return reopens, m.Err
+}
+func (m *MockPayrollRepository) GetPayrollRunsByPeriodID(ctx context.Context, periodID int) ([]domain.PayrollRun, error) {
+	runs := []domain.PayrollRun{}
+	for _, run := range m.Runs {
+		if run.PeriodID == periodID {
+			runs = append(runs, run)
+		}
+	}
+	return runs, m.Err
+}
+func (m *MockPayrollRepository) GetPayrollRun(ctx context.Context, runID int) (domain.PayrollRun, error) {
+	for _, run := range m.Runs {
+		if run.ID == runID {
+			return run, m.Err
+		}
+	}
+	return domain.PayrollRun{}, pgx.ErrNoRows
+}
+func (m *MockPayrollRepository) ApprovePayrollRun(ctx context.Context, runID int, transition domain.PayrollRunTransition) (domain.PayrollRun, error) {
+	transition.ToStatus = domain.PayrollRunApproved
+	run, err := m.decide(runID, transition)
+	if err == nil {
+		if !run.IsOffCycle() {
+			paid := make(map[int]bool)
+			for _, payroll := range run.Payrolls {
+				paid[payroll.EmployeeID] = true
+			}
+			kept := m.Payrolls[:0]
+			for _, payroll := range m.Payrolls {
+				if payroll.PeriodID != run.PeriodID || (payroll.RunType == domain.PayrollRunOffCycle && !paid[payroll.EmployeeID]) {
+					kept = append(kept, payroll)
+				}
+			}
+			voided := len(m.Payrolls) - len(kept)
+			m.Payrolls = kept
+			for i := len(m.Reopens) - 1; i >= 0; i-- {
+				if m.Reopens[i].PeriodID == run.PeriodID {
+					runID := run.ID
+					m.Reopens[i].RunID, m.Reopens[i].VoidedPayrolls, m.Reopens[i].Diff = &runID, voided, run.Diff
+					break
+				}
+			}
+		}
+		for _, payroll := range run.Payrolls {
+			payroll.RunID = run.ID
+			payroll.RunType = run.RunType
+			m.Payrolls = append(m.Payrolls, payroll)
+		}
+		if !run.IsOffCycle() {
+			m.PayrollPeriod.Locked = true
+		}
+	}
+	return run, err
+}
+func (m *MockPayrollRepository) RejectPayrollRun(ctx context.Context, runID int, transition domain.PayrollRunTransition) (domain.PayrollRun, error) {
+	transition.ToStatus = domain.PayrollRunRejected
+	return m.decide(runID, transition)
+}
+func (m *MockPayrollRepository) decide(runID int, transition domain.PayrollRunTransition) (domain.PayrollRun, error) {
+	if m.Err != nil {
+		return domain.PayrollRun{}, m.Err
+	}
+	for i, run := range m.Runs {
+		if run.ID != runID {
+			continue
+		}
+		if !run.IsPendingApproval() {
+			return domain.PayrollRun{}, error_const.ErrPayrollRunNotPending
+		}
+		transition.RunID = runID
+		transition.FromStatus = run.Status
+		m.Runs[i].Status = transition.ToStatus
+		m.Runs[i].Transitions = append(m.Runs[i].Transitions, transition)
+		decided := run
+		m.Runs[i].Payrolls = nil
+		decided.Status = transition.ToStatus
+		decided.Transitions = m.Runs[i].Transitions
+		return decided, nil
+	}
+	return domain.PayrollRun{}, pgx.ErrNoRows
+}
 
 type MockEmployeeRepository struct {
 	ctrl      *gomock.Controller
@@ -71,11 +223,25 @@ func (m *MockEmployeeRepository) GetEmployeeByID(ctx context.Context, employeeID
 }
 
 func (m *MockEmployeeRepository) GetAllEmployees(ctx context.Context) ([]domain.Employee, error) {
-	return []domain.Employee{}, m.Err
+	employees := []domain.Employee{}
+	for _, e := range m.Employees {
+		employees = append(employees, *e)
+	}
+	sort.Slice(employees, func(i, j int) bool { return employees[i].ID < employees[j].ID })
+	return employees, m.Err
 }
 func (m *MockEmployeeRepository) GetEmployee(ctx context.Context, credential domain.Employee) (domain.Employee, error) {
 	return m.Employee, m.Err
 }
+func (m *MockEmployeeRepository) UpdateEmployment(ctx context.Context, employee domain.Employee) (*domain.Employee, error) {
+	e, ok := m.Employees[employee.ID]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	e.StartDate, e.EndDate, e.EmploymentStatus = employee.StartDate, employee.EndDate, employee.EmploymentStatus
+	e.CostCenter, e.Company = employee.CostCenter, employee.Company
+	return e, nil
+}
 
 type MockAttendanceRepository struct {
 	ctrl       *gomock.Controller
@@ -141,3 +307,950 @@ func NewMockAdminRepository(ctrl *gomock.Controller) *MockAdminRepository {
 func (m *MockAdminRepository) GetAdmin(ctx context.Context, credential domain.Admin) (domain.Admin, error) {
 	return m.Admin, m.Err
 }
+
+type MockPayRuleRepository struct {
+	ctrl  *gomock.Controller
+	Rules domain.PayRuleSet
+	Err   error
+}
+
+func NewMockPayRuleRepository(ctrl *gomock.Controller) *MockPayRuleRepository {
+	return &MockPayRuleRepository{ctrl: ctrl}
+}
+
+func (m *MockPayRuleRepository) CreatePayRuleSet(ctx context.Context, rules domain.PayRuleSet) (domain.PayRuleSet, error) {
+	return rules, m.Err
+}
+func (m *MockPayRuleRepository) GetPayRuleSetByVersion(ctx context.Context, version int) (domain.PayRuleSet, error) {
+	return m.Rules, m.Err
+}
+func (m *MockPayRuleRepository) GetLatestPayRuleSet(ctx context.Context) (domain.PayRuleSet, error) {
+	return m.Rules, m.Err
+}
+func (m *MockPayRuleRepository) GetAllPayRuleSets(ctx context.Context) ([]domain.PayRuleSet, error) {
+	return []domain.PayRuleSet{m.Rules}, m.Err
+}
+
+type MockTaxProfileRepository struct {
+	ctrl     *gomock.Controller
+	Profiles map[int]domain.TaxProfile
+	Err      error
+}
+
+func NewMockTaxProfileRepository(ctrl *gomock.Controller) *MockTaxProfileRepository {
+	return &MockTaxProfileRepository{ctrl: ctrl, Profiles: make(map[int]domain.TaxProfile)}
+}
+
+func (m *MockTaxProfileRepository) UpsertTaxProfile(ctx context.Context, profile domain.TaxProfile) error {
+	return m.Err
+}
+func (m *MockTaxProfileRepository) GetTaxProfile(ctx context.Context, employeeID int) (domain.TaxProfile, error) {
+	return m.Profiles[employeeID], m.Err
+}
+func (m *MockTaxProfileRepository) GetTaxProfilesGroupedByEmployeeID(ctx context.Context) (map[int]domain.TaxProfile, error) {
+	return m.Profiles, m.Err
+}
+
+type MockBPJSRepository struct {
+	ctrl  *gomock.Controller
+	Rates []domain.BPJSRate
+	Err   error
+}
+
+func NewMockBPJSRepository(ctrl *gomock.Controller) *MockBPJSRepository {
+	return &MockBPJSRepository{ctrl: ctrl}
+}
+
+func (m *MockBPJSRepository) CreateBPJSRate(ctx context.Context, rate domain.BPJSRate) (int, error) {
+	return 0, m.Err
+}
+func (m *MockBPJSRepository) GetAllBPJSRates(ctx context.Context) ([]domain.BPJSRate, error) {
+	return m.Rates, m.Err
+}
+func (m *MockBPJSRepository) GetBPJSRatesEffectiveOn(ctx context.Context, date time.Time) ([]domain.BPJSRate, error) {
+	return m.Rates, m.Err
+}
+
+// MockPayrollJobRepository keeps jobs in memory and hands out queued jobs in order.
+type MockPayrollJobRepository struct {
+	ctrl      *gomock.Controller
+	Jobs      []domain.PayrollJob
+	Completed []domain.Payroll
+	Runs      []domain.PayrollRun
+	Err       error
+}
+
+func NewMockPayrollJobRepository(ctrl *gomock.Controller) *MockPayrollJobRepository {
+	return &MockPayrollJobRepository{ctrl: ctrl}
+}
+
+func (m *MockPayrollJobRepository) CreatePayrollJob(ctx context.Context, job domain.PayrollJob) (domain.PayrollJob, error) {
+	if m.Err != nil {
+		return domain.PayrollJob{}, m.Err
+	}
+	job.ID = len(m.Jobs) + 1
+	job.Status = domain.PayrollJobQueued
+	m.Jobs = append(m.Jobs, job)
+	return job, nil
+}
+func (m *MockPayrollJobRepository) GetPayrollJob(ctx context.Context, jobID int) (domain.PayrollJob, error) {
+	for _, job := range m.Jobs {
+		if job.ID == jobID {
+			return job, m.Err
+		}
+	}
+	return domain.PayrollJob{}, pgx.ErrNoRows
+}
+func (m *MockPayrollJobRepository) GetPayrollJobsByPeriodID(ctx context.Context, periodID int) ([]domain.PayrollJob, error) {
+	return m.Jobs, m.Err
+}
+func (m *MockPayrollJobRepository) ClaimNextPayrollJob(ctx context.Context) (domain.PayrollJob, error) {
+	for i, job := range m.Jobs {
+		if job.Status == domain.PayrollJobQueued {
+			m.Jobs[i].Status = domain.PayrollJobRunning
+			m.Jobs[i].Attempts++
+			return m.Jobs[i], nil
+		}
+	}
+	return domain.PayrollJob{}, pgx.ErrNoRows
+}
+func (m *MockPayrollJobRepository) RequeueStalePayrollJobs(ctx context.Context, staleAfter time.Duration) (int, error) {
+	return 0, m.Err
+}
+func (m *MockPayrollJobRepository) UpdatePayrollJobProgress(ctx context.Context, job domain.PayrollJob) error {
+	return m.store(job, domain.PayrollJobRunning)
+}
+func (m *MockPayrollJobRepository) FailPayrollJob(ctx context.Context, job domain.PayrollJob) error {
+	return m.store(job, domain.PayrollJobFailed)
+}
+func (m *MockPayrollJobRepository) CompletePayrollJob(ctx context.Context, job domain.PayrollJob, run domain.PayrollRun, payrolls []domain.Payroll) (domain.PayrollRun, error) {
+	run.Status = domain.PayrollRunPendingApproval
+	m.Completed = append(m.Completed, payrolls...)
+	m.Runs = append(m.Runs, run)
+	return run, m.store(job, domain.PayrollJobSucceeded)
+}
+func (m *MockPayrollJobRepository) store(job domain.PayrollJob, status string) error {
+	for i := range m.Jobs {
+		if m.Jobs[i].ID == job.ID {
+			job.Status = status
+			m.Jobs[i] = job
+		}
+	}
+	return m.Err
+}
+
+type MockHolidayRepository struct {
+	ctrl     *gomock.Controller
+	Holidays []domain.Holiday
+	Err      error
+}
+
+func NewMockHolidayRepository(ctrl *gomock.Controller) *MockHolidayRepository {
+	return &MockHolidayRepository{ctrl: ctrl}
+}
+
+func (m *MockHolidayRepository) CreateHoliday(ctx context.Context, holiday domain.Holiday) (domain.Holiday, error) {
+	return holiday, m.Err
+}
+func (m *MockHolidayRepository) UpdateHoliday(ctx context.Context, holiday domain.Holiday) (domain.Holiday, error) {
+	return holiday, m.Err
+}
+func (m *MockHolidayRepository) DeleteHoliday(ctx context.Context, holidayID int) error {
+	return m.Err
+}
+func (m *MockHolidayRepository) GetHolidaysBetween(ctx context.Context, start, end time.Time) ([]domain.Holiday, error) {
+	var holidays []domain.Holiday
+	for _, h := range m.Holidays {
+		if !h.Date.Before(start) && !h.Date.After(end) {
+			holidays = append(holidays, h)
+		}
+	}
+	return holidays, m.Err
+}
+func (m *MockHolidayRepository) ImportHolidays(ctx context.Context, year int, holidays []domain.Holiday, replace bool) ([]domain.Holiday, error) {
+	return holidays, m.Err
+}
+
+type MockSalaryRepository struct {
+	ctrl      *gomock.Controller
+	Histories map[int][]domain.SalaryChange
+	Err       error
+}
+
+func NewMockSalaryRepository(ctrl *gomock.Controller) *MockSalaryRepository {
+	return &MockSalaryRepository{ctrl: ctrl, Histories: make(map[int][]domain.SalaryChange)}
+}
+
+func (m *MockSalaryRepository) CreateSalaryChange(ctx context.Context, change domain.SalaryChange) (domain.SalaryChange, error) {
+	if m.Err != nil {
+		return domain.SalaryChange{}, m.Err
+	}
+	m.Histories[change.EmployeeID] = append(m.Histories[change.EmployeeID], change)
+	return change, nil
+}
+func (m *MockSalaryRepository) GetSalaryHistory(ctx context.Context, employeeID int) ([]domain.SalaryChange, error) {
+	return m.Histories[employeeID], m.Err
+}
+func (m *MockSalaryRepository) GetSalaryHistoriesGroupedByEmployeeID(ctx context.Context, until time.Time) (map[int][]domain.SalaryChange, error) {
+	result := make(map[int][]domain.SalaryChange)
+	for employeeID, history := range m.Histories {
+		for _, change := range history {
+			if !change.EffectiveFrom.After(until) {
+				result[employeeID] = append(result[employeeID], change)
+			}
+		}
+	}
+	return result, m.Err
+}
+
+type MockPayComponentRepository struct {
+	ctrl        *gomock.Controller
+	Components  map[int]domain.PayComponent
+	Assignments map[int][]domain.PayComponentAssignment
+	Err         error
+}
+
+func NewMockPayComponentRepository(ctrl *gomock.Controller) *MockPayComponentRepository {
+	return &MockPayComponentRepository{
+		ctrl:        ctrl,
+		Components:  make(map[int]domain.PayComponent),
+		Assignments: make(map[int][]domain.PayComponentAssignment),
+	}
+}
+
+func (m *MockPayComponentRepository) CreatePayComponent(ctx context.Context, component domain.PayComponent) (domain.PayComponent, error) {
+	if m.Err != nil {
+		return domain.PayComponent{}, m.Err
+	}
+	component.ID = len(m.Components) + 1
+	m.Components[component.ID] = component
+	return component, nil
+}
+func (m *MockPayComponentRepository) UpdatePayComponent(ctx context.Context, component domain.PayComponent) (domain.PayComponent, error) {
+	if m.Err != nil {
+		return domain.PayComponent{}, m.Err
+	}
+	if _, ok := m.Components[component.ID]; !ok {
+		return domain.PayComponent{}, pgx.ErrNoRows
+	}
+	m.Components[component.ID] = component
+	return component, nil
+}
+func (m *MockPayComponentRepository) GetPayComponent(ctx context.Context, componentID int) (domain.PayComponent, error) {
+	if m.Err != nil {
+		return domain.PayComponent{}, m.Err
+	}
+	component, ok := m.Components[componentID]
+	if !ok {
+		return domain.PayComponent{}, pgx.ErrNoRows
+	}
+	return component, nil
+}
+func (m *MockPayComponentRepository) GetAllPayComponents(ctx context.Context) ([]domain.PayComponent, error) {
+	var components []domain.PayComponent
+	for _, component := range m.Components {
+		components = append(components, component)
+	}
+	return components, m.Err
+}
+func (m *MockPayComponentRepository) CreatePayComponentAssignment(ctx context.Context, assignment domain.PayComponentAssignment) (domain.PayComponentAssignment, error) {
+	if m.Err != nil {
+		return domain.PayComponentAssignment{}, m.Err
+	}
+	assignment.Component = m.Components[assignment.ComponentID]
+	m.Assignments[assignment.EmployeeID] = append(m.Assignments[assignment.EmployeeID], assignment)
+	return assignment, nil
+}
+func (m *MockPayComponentRepository) UpdatePayComponentAssignment(ctx context.Context, assignment domain.PayComponentAssignment) (domain.PayComponentAssignment, error) {
+	if m.Err != nil {
+		return domain.PayComponentAssignment{}, m.Err
+	}
+	for i, current := range m.Assignments[assignment.EmployeeID] {
+		if current.ID == assignment.ID {
+			assignment.ComponentID = current.ComponentID
+			assignment.Component = current.Component
+			m.Assignments[assignment.EmployeeID][i] = assignment
+			return assignment, nil
+		}
+	}
+	return domain.PayComponentAssignment{}, pgx.ErrNoRows
+}
+func (m *MockPayComponentRepository) DeletePayComponentAssignment(ctx context.Context, employeeID, assignmentID int) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	for i, current := range m.Assignments[employeeID] {
+		if current.ID == assignmentID {
+			m.Assignments[employeeID] = append(m.Assignments[employeeID][:i], m.Assignments[employeeID][i+1:]...)
+			return nil
+		}
+	}
+	return pgx.ErrNoRows
+}
+func (m *MockPayComponentRepository) GetEmployeePayComponentAssignments(ctx context.Context, employeeID int) ([]domain.PayComponentAssignment, error) {
+	return m.Assignments[employeeID], m.Err
+}
+func (m *MockPayComponentRepository) GetPayComponentAssignmentsGroupedByEmployeeID(ctx context.Context, start, end time.Time) (map[int][]domain.PayComponentAssignment, error) {
+	result := make(map[int][]domain.PayComponentAssignment)
+	for employeeID, assignments := range m.Assignments {
+		for _, assignment := range assignments {
+			if _, _, ok := assignment.EffectiveWithin(start, end); ok && assignment.Component.Active {
+				result[employeeID] = append(result[employeeID], assignment)
+			}
+		}
+	}
+	return result, m.Err
+}
+
+type MockLoanRepository struct {
+	ctrl  *gomock.Controller
+	Loans map[int]domain.Loan
+	Err   error
+}
+
+func NewMockLoanRepository(ctrl *gomock.Controller) *MockLoanRepository {
+	return &MockLoanRepository{ctrl: ctrl, Loans: make(map[int]domain.Loan)}
+}
+
+func (m *MockLoanRepository) CreateLoan(ctx context.Context, loan domain.Loan) (domain.Loan, error) {
+	if m.Err != nil {
+		return domain.Loan{}, m.Err
+	}
+	loan.ID = len(m.Loans) + 1
+	loan.Status = domain.LoanStatusActive
+	loan.Outstanding = loan.Principal
+	m.Loans[loan.ID] = loan
+	return loan, nil
+}
+func (m *MockLoanRepository) GetLoan(ctx context.Context, loanID int) (domain.Loan, error) {
+	if m.Err != nil {
+		return domain.Loan{}, m.Err
+	}
+	loan, ok := m.Loans[loanID]
+	if !ok {
+		return domain.Loan{}, pgx.ErrNoRows
+	}
+	return loan, nil
+}
+func (m *MockLoanRepository) GetEmployeeLoans(ctx context.Context, employeeID int) ([]domain.Loan, error) {
+	var loans []domain.Loan
+	for _, loan := range m.Loans {
+		if loan.EmployeeID == employeeID {
+			loans = append(loans, loan)
+		}
+	}
+	sort.Slice(loans, func(i, j int) bool { return loans[i].ID < loans[j].ID })
+	return loans, m.Err
+}
+func (m *MockLoanRepository) CancelLoan(ctx context.Context, loan domain.Loan) (domain.Loan, error) {
+	if m.Err != nil {
+		return domain.Loan{}, m.Err
+	}
+	current, ok := m.Loans[loan.ID]
+	if !ok || current.Status != domain.LoanStatusActive {
+		return domain.Loan{}, pgx.ErrNoRows
+	}
+	current.Status = domain.LoanStatusCancelled
+	m.Loans[loan.ID] = current
+	return current, nil
+}
+func (m *MockLoanRepository) GetLoansDueGroupedByEmployeeID(ctx context.Context, period domain.PayrollPeriod) (map[int][]domain.Loan, error) {
+	result := make(map[int][]domain.Loan)
+	for id := 1; id <= len(m.Loans); id++ {
+		loan, ok := m.Loans[id]
+		if !ok || loan.Status == domain.LoanStatusCancelled || loan.StartDate.After(period.EndDate) || !loan.Outstanding.IsPositive() {
+			continue
+		}
+		result[loan.EmployeeID] = append(result[loan.EmployeeID], loan)
+	}
+	return result, m.Err
+}
+
+type MockBonusRepository struct {
+	ctrl     *gomock.Controller
+	Runs     map[int]domain.BonusRun
+	Payslips []domain.BonusPayslip
+	Err      error
+}
+
+func NewMockBonusRepository(ctrl *gomock.Controller) *MockBonusRepository {
+	return &MockBonusRepository{ctrl: ctrl, Runs: make(map[int]domain.BonusRun)}
+}
+
+func (m *MockBonusRepository) CreateBonusRun(ctx context.Context, run domain.BonusRun, payslips []domain.BonusPayslip) (domain.BonusRun, error) {
+	if m.Err != nil {
+		return domain.BonusRun{}, m.Err
+	}
+	run.ID = len(m.Runs) + 1
+	run.EmployeeCount = len(payslips)
+	for _, payslip := range payslips {
+		payslip.RunID = run.ID
+		payslip.ID = len(m.Payslips) + 1
+		m.Payslips = append(m.Payslips, payslip)
+	}
+	m.Runs[run.ID] = run
+	return run, nil
+}
+func (m *MockBonusRepository) GetBonusRun(ctx context.Context, runID int) (domain.BonusRun, error) {
+	if m.Err != nil {
+		return domain.BonusRun{}, m.Err
+	}
+	run, ok := m.Runs[runID]
+	if !ok {
+		return domain.BonusRun{}, pgx.ErrNoRows
+	}
+	for _, payslip := range m.Payslips {
+		if payslip.RunID == runID {
+			run.Payslips = append(run.Payslips, payslip)
+		}
+	}
+	return run, nil
+}
+func (m *MockBonusRepository) GetBonusRunsByPeriodID(ctx context.Context, periodID int) ([]domain.BonusRun, error) {
+	var runs []domain.BonusRun
+	for id := 1; id <= len(m.Runs); id++ {
+		if run, ok := m.Runs[id]; ok && run.PeriodID == periodID {
+			runs = append(runs, run)
+		}
+	}
+	return runs, m.Err
+}
+func (m *MockBonusRepository) GetBonusPayslipsByPeriodID(ctx context.Context, periodID int) ([]domain.BonusPayslip, error) {
+	var payslips []domain.BonusPayslip
+	for _, payslip := range m.Payslips {
+		if payslip.PeriodID == periodID {
+			payslips = append(payslips, payslip)
+		}
+	}
+	return payslips, m.Err
+}
+func (m *MockBonusRepository) GetEmployeeBonusPayslipsByPeriod(ctx context.Context, employeeID, periodID int) ([]domain.BonusPayslip, error) {
+	var payslips []domain.BonusPayslip
+	for _, payslip := range m.Payslips {
+		if payslip.EmployeeID == employeeID && payslip.PeriodID == periodID {
+			payslips = append(payslips, payslip)
+		}
+	}
+	return payslips, m.Err
+}
+
+type MockTaxStatementRepository struct {
+	ctrl       *gomock.Controller
+	Payslips   map[int][]domain.TaxYearPayslip
+	Statements []domain.TaxStatement
+	Err        error
+}
+
+func NewMockTaxStatementRepository(ctrl *gomock.Controller) *MockTaxStatementRepository {
+	return &MockTaxStatementRepository{ctrl: ctrl, Payslips: make(map[int][]domain.TaxYearPayslip)}
+}
+
+func (m *MockTaxStatementRepository) GetTaxYearPayslipsGroupedByEmployeeID(ctx context.Context, taxYear int) (map[int][]domain.TaxYearPayslip, error) {
+	result := make(map[int][]domain.TaxYearPayslip)
+	for employeeID, payslips := range m.Payslips {
+		for _, payslip := range payslips {
+			if payslip.PeriodEnd.Year() == taxYear {
+				result[employeeID] = append(result[employeeID], payslip)
+			}
+		}
+	}
+	return result, m.Err
+}
+func (m *MockTaxStatementRepository) SaveTaxStatements(ctx context.Context, statements []domain.TaxStatement) ([]domain.TaxStatement, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var saved []domain.TaxStatement
+	for _, statement := range statements {
+		statement.Sequence = 0
+		last := 0
+		for i, stored := range m.Statements {
+			if stored.TaxYear != statement.TaxYear {
+				continue
+			}
+			if stored.Sequence > last {
+				last = stored.Sequence
+			}
+			if stored.EmployeeID == statement.EmployeeID {
+				statement.ID, statement.Sequence = stored.ID, stored.Sequence
+				m.Statements = append(m.Statements[:i], m.Statements[i+1:]...)
+				break
+			}
+		}
+		if statement.Sequence == 0 {
+			statement.ID, statement.Sequence = len(m.Statements)+len(saved)+1, last+1
+		}
+		statement.Number = statement.FormNumber()
+		m.Statements = append(m.Statements, statement)
+		saved = append(saved, statement)
+	}
+	return saved, nil
+}
+func (m *MockTaxStatementRepository) GetTaxStatement(ctx context.Context, employeeID, taxYear int) (domain.TaxStatement, error) {
+	if m.Err != nil {
+		return domain.TaxStatement{}, m.Err
+	}
+	for _, statement := range m.Statements {
+		if statement.EmployeeID == employeeID && statement.TaxYear == taxYear {
+			return statement, nil
+		}
+	}
+	return domain.TaxStatement{}, pgx.ErrNoRows
+}
+func (m *MockTaxStatementRepository) GetTaxStatementsByTaxYear(ctx context.Context, taxYear int) ([]domain.TaxStatement, error) {
+	var statements []domain.TaxStatement
+	for _, statement := range m.Statements {
+		if statement.TaxYear == taxYear {
+			statements = append(statements, statement)
+		}
+	}
+	return statements, m.Err
+}
+
+type MockGLAccountRepository struct {
+	ctrl     *gomock.Controller
+	Accounts []domain.GLAccount
+	Err      error
+}
+
+func NewMockGLAccountRepository(ctrl *gomock.Controller) *MockGLAccountRepository {
+	return &MockGLAccountRepository{ctrl: ctrl}
+}
+
+func (m *MockGLAccountRepository) UpsertGLAccount(ctx context.Context, account domain.GLAccount) (domain.GLAccount, error) {
+	if m.Err != nil {
+		return domain.GLAccount{}, m.Err
+	}
+	for i, stored := range m.Accounts {
+		if stored.Component == account.Component && stored.CostCenter == account.CostCenter {
+			account.ID = stored.ID
+			m.Accounts[i] = account
+			return account, nil
+		}
+	}
+	account.ID = len(m.Accounts) + 1
+	m.Accounts = append(m.Accounts, account)
+	return account, nil
+}
+func (m *MockGLAccountRepository) DeleteGLAccount(ctx context.Context, accountID int) error {
+	for i, stored := range m.Accounts {
+		if stored.ID == accountID {
+			m.Accounts = append(m.Accounts[:i], m.Accounts[i+1:]...)
+			return m.Err
+		}
+	}
+	return pgx.ErrNoRows
+}
+func (m *MockGLAccountRepository) GetAllGLAccounts(ctx context.Context) ([]domain.GLAccount, error) {
+	return m.Accounts, m.Err
+}
+
+type MockBankAccountRepository struct {
+	ctrl     *gomock.Controller
+	Accounts map[int]domain.BankAccount
+	Err      error
+}
+
+func NewMockBankAccountRepository(ctrl *gomock.Controller) *MockBankAccountRepository {
+	return &MockBankAccountRepository{ctrl: ctrl, Accounts: make(map[int]domain.BankAccount)}
+}
+
+func (m *MockBankAccountRepository) UpsertBankAccount(ctx context.Context, account domain.BankAccount) (domain.BankAccount, error) {
+	if m.Err != nil {
+		return domain.BankAccount{}, m.Err
+	}
+	m.Accounts[account.EmployeeID] = account
+	return account, nil
+}
+func (m *MockBankAccountRepository) GetBankAccount(ctx context.Context, employeeID int) (domain.BankAccount, error) {
+	account, ok := m.Accounts[employeeID]
+	if !ok {
+		return domain.BankAccount{}, pgx.ErrNoRows
+	}
+	return account, m.Err
+}
+func (m *MockBankAccountRepository) GetBankAccountsByEmployeeID(ctx context.Context) (map[int]domain.BankAccount, error) {
+	return m.Accounts, m.Err
+}
+
+type MockDisbursementRepository struct {
+	ctrl    *gomock.Controller
+	Batches []domain.DisbursementBatch
+	Imports []domain.BankStatementImport
+	Err     error
+}
+
+func NewMockDisbursementRepository(ctrl *gomock.Controller) *MockDisbursementRepository {
+	return &MockDisbursementRepository{ctrl: ctrl}
+}
+
+func (m *MockDisbursementRepository) CreateDisbursementBatch(ctx context.Context, batch domain.DisbursementBatch) (domain.DisbursementBatch, error) {
+	if m.Err != nil {
+		return domain.DisbursementBatch{}, m.Err
+	}
+	batch.ID = len(m.Batches) + 1
+	batch.Status = domain.DisbursementBatchExported
+	batch.Reference = batch.BatchReference()
+	itemID := 0
+	for _, stored := range m.Batches {
+		itemID += len(stored.Items)
+	}
+	for i := range batch.Items {
+		itemID++
+		batch.Items[i].ID = itemID
+		batch.Items[i].BatchID = batch.ID
+		batch.Items[i].Status = domain.PaymentPending
+		if reissued := m.findItem(batch.Items[i].ReissueOfItemID); reissued != nil {
+			if reissued.ReissuedBatchID != 0 {
+				return domain.DisbursementBatch{}, error_const.ErrDisbursementItemReissued
+			}
+			reissued.ReissuedBatchID = batch.ID
+		}
+	}
+	m.Batches = append(m.Batches, batch)
+	return batch, nil
+}
+func (m *MockDisbursementRepository) findItem(itemID int) *domain.DisbursementItem {
+	for i := range m.Batches {
+		for j := range m.Batches[i].Items {
+			if m.Batches[i].Items[j].ID == itemID {
+				return &m.Batches[i].Items[j]
+			}
+		}
+	}
+	return nil
+}
+func (m *MockDisbursementRepository) GetDisbursementBatch(ctx context.Context, batchID int) (domain.DisbursementBatch, error) {
+	for _, batch := range m.Batches {
+		if batch.ID == batchID {
+			return batch, m.Err
+		}
+	}
+	return domain.DisbursementBatch{}, pgx.ErrNoRows
+}
+func (m *MockDisbursementRepository) GetDisbursementBatchesByPeriodID(ctx context.Context, periodID int) ([]domain.DisbursementBatch, error) {
+	batches := []domain.DisbursementBatch{}
+	for _, batch := range m.Batches {
+		if batch.PeriodID == periodID {
+			batches = append(batches, batch)
+		}
+	}
+	return batches, m.Err
+}
+func (m *MockDisbursementRepository) GetDisbursedAmounts(ctx context.Context, periodID int) (map[int]domain.Money, error) {
+	amounts := make(map[int]domain.Money)
+	for _, batch := range m.Batches {
+		if batch.PeriodID != periodID || !batch.IsExported() {
+			continue
+		}
+		for _, item := range batch.Items {
+			if item.ReissueOfItemID == 0 {
+				amounts[item.EmployeeID] = amounts[item.EmployeeID].Add(item.Amount)
+			}
+		}
+	}
+	return amounts, m.Err
+}
+func (m *MockDisbursementRepository) CancelDisbursementBatch(ctx context.Context, batch domain.DisbursementBatch) (domain.DisbursementBatch, error) {
+	for i, stored := range m.Batches {
+		if stored.ID != batch.ID {
+			continue
+		}
+		if !stored.IsExported() {
+			return domain.DisbursementBatch{}, error_const.ErrDisbursementBatchNotExported
+		}
+		if stored.SentAt != nil {
+			return domain.DisbursementBatch{}, error_const.ErrDisbursementBatchSent
+		}
+		stored.Status, stored.CancelReason, stored.UpdatedBy = domain.DisbursementBatchCancelled, batch.CancelReason, batch.UpdatedBy
+		m.Batches[i] = stored
+		return stored, m.Err
+	}
+	return domain.DisbursementBatch{}, pgx.ErrNoRows
+}
+func (m *MockDisbursementRepository) MarkDisbursementBatchSent(ctx context.Context, batch domain.DisbursementBatch) (domain.DisbursementBatch, error) {
+	for i, stored := range m.Batches {
+		if stored.ID != batch.ID {
+			continue
+		}
+		if !stored.IsExported() {
+			return domain.DisbursementBatch{}, error_const.ErrDisbursementBatchNotExported
+		}
+		if stored.SentAt != nil {
+			return domain.DisbursementBatch{}, error_const.ErrDisbursementBatchSent
+		}
+		now := time.Now()
+		stored.SentAt, stored.SentBy = &now, batch.UpdatedBy
+		for j := range stored.Items {
+			if stored.Items[j].Status == domain.PaymentPending {
+				stored.Items[j].Status = domain.PaymentSent
+			}
+		}
+		m.Batches[i] = stored
+		return stored, m.Err
+	}
+	return domain.DisbursementBatch{}, pgx.ErrNoRows
+}
+func (m *MockDisbursementRepository) UpdateDisbursementItemStatus(ctx context.Context, item domain.DisbursementItem) (domain.DisbursementItem, error) {
+	stored := m.findItem(item.ID)
+	if stored == nil || stored.BatchID != item.BatchID {
+		return domain.DisbursementItem{}, pgx.ErrNoRows
+	}
+	if !domain.CanChangePaymentStatus(stored.Status, item.Status) {
+		return domain.DisbursementItem{}, error_const.ErrPaymentStatusChange
+	}
+	now := time.Now()
+	stored.Status, stored.StatusNote, stored.StatusUpdatedBy, stored.StatusUpdatedAt = item.Status, item.StatusNote, item.StatusUpdatedBy, &now
+	return *stored, m.Err
+}
+func (m *MockDisbursementRepository) GetDisbursementItems(ctx context.Context, periodID int, statuses []string) ([]domain.ReconciliationItem, error) {
+	items := []domain.ReconciliationItem{}
+	for _, batch := range m.Batches {
+		if !batch.IsExported() || (periodID != 0 && batch.PeriodID != periodID) {
+			continue
+		}
+		for _, item := range batch.Items {
+			for _, status := range statuses {
+				if item.Status == status {
+					items = append(items, domain.ReconciliationItem{DisbursementItem: item, BatchReference: batch.Reference, PeriodID: batch.PeriodID})
+				}
+			}
+		}
+	}
+	return items, m.Err
+}
+func (m *MockDisbursementRepository) GetImportedStatementFingerprints(ctx context.Context, fingerprints []string) (map[string]bool, error) {
+	imported := make(map[string]bool)
+	for _, statement := range m.Imports {
+		for _, entry := range statement.Entries {
+			imported[entry.Fingerprint] = true
+		}
+	}
+	return imported, m.Err
+}
+func (m *MockDisbursementRepository) SaveBankStatementImport(ctx context.Context, statement domain.BankStatementImport) (domain.BankStatementImport, error) {
+	if m.Err != nil {
+		return domain.BankStatementImport{}, m.Err
+	}
+	statement.ID = len(m.Imports) + 1
+	statement.EntryCount = len(statement.Entries)
+	for i := range statement.Entries {
+		entry := &statement.Entries[i]
+		entry.ImportID = statement.ID
+		if entry.Duplicate {
+			statement.DuplicateCount++
+			continue
+		}
+		if item := m.findItem(entry.MatchedItemID); item != nil {
+			item.Status, item.BankReference, item.StatusUpdatedBy = entry.MatchedStatus, entry.Reference, statement.CreatedBy
+			statement.MatchedCount++
+		}
+	}
+	m.Imports = append(m.Imports, statement)
+	return statement, nil
+}
+func (m *MockDisbursementRepository) GetUnmatchedBankStatementEntries(ctx context.Context) ([]domain.BankStatementEntry, error) {
+	entries := []domain.BankStatementEntry{}
+	for _, statement := range m.Imports {
+		for _, entry := range statement.Entries {
+			if entry.MatchedItemID == 0 && !entry.Duplicate {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries, m.Err
+}
+
+type MockPayslipTemplateRepository struct {
+	ctrl      *gomock.Controller
+	Templates []domain.PayslipTemplate
+	Err       error
+}
+
+func NewMockPayslipTemplateRepository(ctrl *gomock.Controller) *MockPayslipTemplateRepository {
+	return &MockPayslipTemplateRepository{ctrl: ctrl}
+}
+
+func (m *MockPayslipTemplateRepository) CreatePayslipTemplate(ctx context.Context, template domain.PayslipTemplate) (domain.PayslipTemplate, error) {
+	if m.Err != nil {
+		return domain.PayslipTemplate{}, m.Err
+	}
+	template.Version = 1
+	for _, stored := range m.Templates {
+		if stored.Company == template.Company && stored.Version >= template.Version {
+			template.Version = stored.Version + 1
+		}
+	}
+	template.ID = len(m.Templates) + 1
+	template.CreatedAt = time.Now()
+	m.Templates = append(m.Templates, template)
+	return template, nil
+}
+func (m *MockPayslipTemplateRepository) GetPayslipTemplates(ctx context.Context, company string) ([]domain.PayslipTemplate, error) {
+	templates := []domain.PayslipTemplate{}
+	for i := len(m.Templates) - 1; i >= 0; i-- {
+		if m.Templates[i].Company == company {
+			templates = append(templates, m.Templates[i])
+		}
+	}
+	return templates, m.Err
+}
+func (m *MockPayslipTemplateRepository) GetPayslipTemplate(ctx context.Context, company string, version int) (domain.PayslipTemplate, error) {
+	for _, template := range m.Templates {
+		if template.Company == company && template.Version == version {
+			return template, m.Err
+		}
+	}
+	return domain.PayslipTemplate{}, pgx.ErrNoRows
+}
+func (m *MockPayslipTemplateRepository) GetLatestPayslipTemplate(ctx context.Context, company string) (domain.PayslipTemplate, error) {
+	templates, err := m.GetPayslipTemplates(ctx, company)
+	if err != nil {
+		return domain.PayslipTemplate{}, err
+	}
+	if len(templates) == 0 {
+		return domain.PayslipTemplate{}, pgx.ErrNoRows
+	}
+	return templates[0], nil
+}
+
+// MockPayslipEmailRepository keeps emails in memory and hands out queued emails that are due.
+type MockPayslipEmailRepository struct {
+	ctrl   *gomock.Controller
+	Emails []domain.PayslipEmail
+	Err    error
+}
+
+func NewMockPayslipEmailRepository(ctrl *gomock.Controller) *MockPayslipEmailRepository {
+	return &MockPayslipEmailRepository{ctrl: ctrl}
+}
+
+func (m *MockPayslipEmailRepository) EnqueuePayslipEmails(ctx context.Context, emails []domain.PayslipEmail) (int, error) {
+	if m.Err != nil {
+		return 0, m.Err
+	}
+	queued := 0
+	for _, email := range emails {
+		if m.find(email.PeriodID, email.EmployeeID) >= 0 {
+			continue
+		}
+		email.ID = len(m.Emails) + 1
+		email.Status = domain.PayslipEmailQueued
+		email.NextAttemptAt = time.Now()
+		m.Emails = append(m.Emails, email)
+		queued++
+	}
+	return queued, nil
+}
+func (m *MockPayslipEmailRepository) ResendPayslipEmail(ctx context.Context, email domain.PayslipEmail) (domain.PayslipEmail, error) {
+	if m.Err != nil {
+		return domain.PayslipEmail{}, m.Err
+	}
+	i := m.find(email.PeriodID, email.EmployeeID)
+	if i < 0 {
+		email.ID = len(m.Emails) + 1
+		m.Emails = append(m.Emails, email)
+		i = len(m.Emails) - 1
+	} else if m.Emails[i].Status == domain.PayslipEmailSending {
+		return domain.PayslipEmail{}, pgx.ErrNoRows
+	}
+	m.Emails[i].Recipient = email.Recipient
+	m.Emails[i].Protected = email.Protected
+	m.Emails[i].Status = domain.PayslipEmailQueued
+	m.Emails[i].Attempts = 0
+	m.Emails[i].LastError = ""
+	m.Emails[i].NextAttemptAt = time.Now()
+	m.Emails[i].SentAt = nil
+	m.Emails[i].UpdatedBy = email.UpdatedBy
+	return m.Emails[i], nil
+}
+func (m *MockPayslipEmailRepository) GetPayslipEmailsByPeriodID(ctx context.Context, periodID int, status string) ([]domain.PayslipEmail, error) {
+	emails := []domain.PayslipEmail{}
+	for _, email := range m.Emails {
+		if email.PeriodID == periodID && (status == "" || email.Status == status) {
+			emails = append(emails, email)
+		}
+	}
+	return emails, m.Err
+}
+func (m *MockPayslipEmailRepository) ClaimDuePayslipEmails(ctx context.Context, limit int) ([]domain.PayslipEmail, error) {
+	var emails []domain.PayslipEmail
+	for i, email := range m.Emails {
+		if len(emails) < limit && email.Status == domain.PayslipEmailQueued && !email.NextAttemptAt.After(time.Now()) {
+			m.Emails[i].Status = domain.PayslipEmailSending
+			m.Emails[i].Attempts++
+			emails = append(emails, m.Emails[i])
+		}
+	}
+	return emails, m.Err
+}
+func (m *MockPayslipEmailRepository) RequeueStalePayslipEmails(ctx context.Context, staleAfter time.Duration) (int, error) {
+	return 0, m.Err
+}
+func (m *MockPayslipEmailRepository) UpdatePayslipEmailDelivery(ctx context.Context, email domain.PayslipEmail) error {
+	if i := m.find(email.PeriodID, email.EmployeeID); i >= 0 && m.Emails[i].Status == domain.PayslipEmailSending {
+		m.Emails[i] = email
+	}
+	return m.Err
+}
+func (m *MockPayslipEmailRepository) find(periodID, employeeID int) int {
+	for i, email := range m.Emails {
+		if email.PeriodID == periodID && email.EmployeeID == employeeID {
+			return i
+		}
+	}
+	return -1
+}
+
+// MockMailer records the messages it is asked to send. Errors fails every message to the
+// address with the error.
+type MockMailer struct {
+	Sent   []utils.MailMessage
+	Errors map[string]error
+}
+
+func NewMockMailer() *MockMailer {
+	return &MockMailer{Errors: make(map[string]error)}
+}
+
+func (m *MockMailer) Send(ctx context.Context, message utils.MailMessage) error {
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return err
+	}
+	if err := m.Errors[to.Address]; err != nil {
+		return err
+	}
+	m.Sent = append(m.Sent, message)
+	return nil
+}
+
+// MockPayslipPasswordRepository keeps payslip passwords by employee ID.
+type MockPayslipPasswordRepository struct {
+	ctrl      *gomock.Controller
+	Passwords map[int]domain.PayslipPassword
+	Err       error
+}
+
+func NewMockPayslipPasswordRepository(ctrl *gomock.Controller) *MockPayslipPasswordRepository {
+	return &MockPayslipPasswordRepository{ctrl: ctrl, Passwords: make(map[int]domain.PayslipPassword)}
+}
+
+func (m *MockPayslipPasswordRepository) SetPayslipPassword(ctx context.Context, password domain.PayslipPassword) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Passwords[password.EmployeeID] = password
+	return nil
+}
+
+func (m *MockPayslipPasswordRepository) GetPayslipPassword(ctx context.Context, employeeID int) (domain.PayslipPassword, error) {
+	if m.Err != nil {
+		return domain.PayslipPassword{}, m.Err
+	}
+	password, ok := m.Passwords[employeeID]
+	if !ok {
+		return domain.PayslipPassword{}, pgx.ErrNoRows
+	}
+	return password, nil
+}
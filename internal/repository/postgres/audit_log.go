@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"payroll-system/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// insertAuditLog writes the entry inside the caller's transaction, so the audit
+// trail is only kept when the audited change is committed.
+func insertAuditLog(ctx context.Context, tx pgx.Tx, audit domain.AuditLog) error {
+	details, err := json.Marshal(audit.Details)
+	if err != nil {
+		return err
+	}
+	createdBy := audit.CreatedBy
+	if createdBy == "" {
+		createdBy = "system"
+	}
+	var ipAddress *string
+	if audit.IPAddress != "" {
+		ipAddress = &audit.IPAddress
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO audit_logs (actor_id, actor_role, action, details, ip_address, created_at, updated_at, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW(), $6, $6)`,
+		audit.ActorID, audit.ActorRole, audit.Action, details, ipAddress, createdBy)
+	return err
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type BankAccountRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewBankAccountRepository(pool *pgxpool.Pool) *BankAccountRepository {
+	return &BankAccountRepository{
+		pool: pool,
+	}
+}
+
+const bankAccountColumns = `employee_id, bank_code, account_number, account_holder, created_at, updated_at, created_by, updated_by`
+
+func scanBankAccount(row pgx.Row) (domain.BankAccount, error) {
+	var a domain.BankAccount
+	err := row.Scan(&a.EmployeeID, &a.BankCode, &a.AccountNumber, &a.AccountHolder, &a.CreatedAt, &a.UpdatedAt, &a.CreatedBy, &a.UpdatedBy)
+	if err != nil {
+		return domain.BankAccount{}, err
+	}
+	return a, nil
+}
+
+func (r *BankAccountRepository) UpsertBankAccount(ctx context.Context, account domain.BankAccount) (domain.BankAccount, error) {
+	if account.EmployeeID == 0 {
+		return domain.BankAccount{}, error_const.ErrInvalidID
+	}
+	if account.CreatedBy == "" || account.UpdatedBy == "" {
+		return domain.BankAccount{}, error_const.ErrInvalidUser
+	}
+	return scanBankAccount(r.pool.QueryRow(ctx, `
+		INSERT INTO employee_bank_accounts (employee_id, bank_code, account_number, account_holder, created_at, updated_at, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, NOW(), NOW(), $5, $6)
+		ON CONFLICT (employee_id) DO UPDATE
+		SET bank_code = EXCLUDED.bank_code, account_number = EXCLUDED.account_number, account_holder = EXCLUDED.account_holder,
+			updated_at = NOW(), updated_by = EXCLUDED.updated_by
+		RETURNING `+bankAccountColumns,
+		account.EmployeeID, account.BankCode, account.AccountNumber, account.AccountHolder, account.CreatedBy, account.UpdatedBy))
+}
+
+func (r *BankAccountRepository) GetBankAccount(ctx context.Context, employeeID int) (domain.BankAccount, error) {
+	return scanBankAccount(r.pool.QueryRow(ctx, `
+		SELECT `+bankAccountColumns+`
+		FROM employee_bank_accounts
+		WHERE employee_id = $1`, employeeID))
+}
+
+func (r *BankAccountRepository) GetBankAccountsByEmployeeID(ctx context.Context) (map[int]domain.BankAccount, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+bankAccountColumns+`
+		FROM employee_bank_accounts
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := make(map[int]domain.BankAccount)
+	for rows.Next() {
+		account, err := scanBankAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts[account.EmployeeID] = account
+	}
+	return accounts, rows.Err()
+}
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const bankStatementEntryColumns = `id, import_id, line, booking_date, direction, amount, reference, description, account_number,
+	fingerprint, COALESCE(matched_item_id, 0), matched_status`
+
+func scanBankStatementEntry(row pgx.Row) (domain.BankStatementEntry, error) {
+	var e domain.BankStatementEntry
+	err := row.Scan(&e.ID, &e.ImportID, &e.Line, &e.BookingDate, &e.Direction, &e.Amount, &e.Reference, &e.Description,
+		&e.AccountNumber, &e.Fingerprint, &e.MatchedItemID, &e.MatchedStatus)
+	if err != nil {
+		return domain.BankStatementEntry{}, err
+	}
+	return e, nil
+}
+
+// GetImportedStatementFingerprints returns which of the fingerprints belong to entries that
+// were already imported.
+func (r *DisbursementRepository) GetImportedStatementFingerprints(ctx context.Context, fingerprints []string) (map[string]bool, error) {
+	rows, err := r.pool.Query(ctx, `SELECT fingerprint FROM bank_statement_entries WHERE fingerprint = ANY($1)`, fingerprints)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	imported := make(map[string]bool)
+	for rows.Next() {
+		var fingerprint string
+		if err := rows.Scan(&fingerprint); err != nil {
+			return nil, err
+		}
+		imported[fingerprint] = true
+	}
+	return imported, rows.Err()
+}
+
+// SaveBankStatementImport stores the statement's new entries and applies their matches in one
+// transaction. A match whose payment changed status meanwhile is dropped, leaving the entry
+// unmatched. When another import stored one of the entries meanwhile nothing is saved and
+// ErrStatementImportConflict is returned, so the statement can be imported again.
+func (r *DisbursementRepository) SaveBankStatementImport(ctx context.Context, statement domain.BankStatementImport) (domain.BankStatementImport, error) {
+	if statement.CreatedBy == "" {
+		return domain.BankStatementImport{}, error_const.ErrInvalidUser
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.BankStatementImport{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO bank_statement_imports (format, account_number, entry_count, created_at, created_by)
+		VALUES ($1, $2, $3, NOW(), $4)
+		RETURNING id, created_at`,
+		statement.Format, statement.AccountNumber, len(statement.Entries), statement.CreatedBy,
+	).Scan(&statement.ID, &statement.CreatedAt)
+	if err != nil {
+		return domain.BankStatementImport{}, err
+	}
+	statement.EntryCount = len(statement.Entries)
+	statement.DuplicateCount, statement.MatchedCount = 0, 0
+	for i := range statement.Entries {
+		entry := &statement.Entries[i]
+		entry.ImportID = statement.ID
+		if entry.Duplicate {
+			statement.DuplicateCount++
+			continue
+		}
+		if entry.MatchedItemID != 0 {
+			tag, err := tx.Exec(ctx, `
+				UPDATE disbursement_items
+				SET status = $2, bank_reference = $3, status_note = $4, status_updated_at = NOW(), status_updated_by = $5
+				WHERE id = $1 AND status = ANY($6)
+			`, entry.MatchedItemID, entry.MatchedStatus, entry.Reference, "matched to the bank statement",
+				statement.CreatedBy, domain.PaymentStatusesChangingTo(entry.MatchedStatus))
+			if err != nil {
+				return domain.BankStatementImport{}, err
+			}
+			if tag.RowsAffected() == 0 {
+				entry.MatchedItemID, entry.MatchedStatus = 0, ""
+			}
+		}
+		var matchedItemID *int
+		if entry.MatchedItemID != 0 {
+			matchedItemID = &entry.MatchedItemID
+		}
+		err := tx.QueryRow(ctx, `
+			INSERT INTO bank_statement_entries (import_id, line, booking_date, direction, amount, reference, description,
+				account_number, fingerprint, matched_item_id, matched_status)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			ON CONFLICT (fingerprint) DO NOTHING
+			RETURNING id`,
+			statement.ID, entry.Line, entry.BookingDate, entry.Direction, entry.Amount, entry.Reference, entry.Description,
+			entry.AccountNumber, entry.Fingerprint, matchedItemID, entry.MatchedStatus,
+		).Scan(&entry.ID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.BankStatementImport{}, error_const.ErrStatementImportConflict
+		}
+		if err != nil {
+			return domain.BankStatementImport{}, err
+		}
+		if entry.MatchedItemID != 0 {
+			statement.MatchedCount++
+		}
+	}
+	_, err = tx.Exec(ctx, `
+		UPDATE bank_statement_imports SET duplicate_count = $2, matched_count = $3 WHERE id = $1
+	`, statement.ID, statement.DuplicateCount, statement.MatchedCount)
+	if err != nil {
+		return domain.BankStatementImport{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return domain.BankStatementImport{}, err
+	}
+	return statement, nil
+}
+
+// GetUnmatchedBankStatementEntries returns the imported entries no payment matched, oldest first.
+func (r *DisbursementRepository) GetUnmatchedBankStatementEntries(ctx context.Context) ([]domain.BankStatementEntry, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+bankStatementEntryColumns+`
+		FROM bank_statement_entries
+		WHERE matched_item_id IS NULL
+		ORDER BY booking_date, id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []domain.BankStatementEntry{}
+	for rows.Next() {
+		entry, err := scanBankStatementEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type BonusRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewBonusRepository(pool *pgxpool.Pool) *BonusRepository {
+	return &BonusRepository{
+		pool: pool,
+	}
+}
+
+const bonusRunColumns = `id, period_id, kind, reason, reference_date, employee_count, total_gross, total_net,
+	created_at, updated_at, created_by, updated_by`
+
+func scanBonusRun(row pgx.Row) (domain.BonusRun, error) {
+	var run domain.BonusRun
+	err := row.Scan(&run.ID, &run.PeriodID, &run.Kind, &run.Reason, &run.ReferenceDate, &run.EmployeeCount, &run.TotalGross, &run.TotalNet,
+		&run.CreatedAt, &run.UpdatedAt, &run.CreatedBy, &run.UpdatedBy)
+	if err != nil {
+		return domain.BonusRun{}, err
+	}
+	return run, nil
+}
+
+const bonusPayslipColumns = `id, run_id, employee_id, period_id, kind, payslip, created_at, created_by`
+
+func scanBonusPayslips(rows pgx.Rows) ([]domain.BonusPayslip, error) {
+	payslips := []domain.BonusPayslip{}
+	for rows.Next() {
+		var p domain.BonusPayslip
+		var payslipData []byte
+		if err := rows.Scan(&p.ID, &p.RunID, &p.EmployeeID, &p.PeriodID, &p.Kind, &payslipData, &p.CreatedAt, &p.CreatedBy); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(payslipData, &p.Payslip); err != nil {
+			return nil, err
+		}
+		payslips = append(payslips, p)
+	}
+	return payslips, rows.Err()
+}
+
+// CreateBonusRun stores a bonus run and its payslips in one transaction. The period must be
+// locked, since the payslips are taxed together with the period's regular payslips.
+func (r *BonusRepository) CreateBonusRun(ctx context.Context, run domain.BonusRun, payslips []domain.BonusPayslip) (domain.BonusRun, error) {
+	if run.PeriodID == 0 || len(payslips) == 0 {
+		return domain.BonusRun{}, error_const.ErrInvalidInput
+	}
+	if run.CreatedBy == "" || run.UpdatedBy == "" {
+		return domain.BonusRun{}, error_const.ErrInvalidUser
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.BonusRun{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	// the row lock keeps the period locked and orders bonus runs of the same period
+	var locked bool
+	var taxYear int
+	err = tx.QueryRow(ctx, `
+		SELECT locked, EXTRACT(YEAR FROM end_date)::int FROM payroll_periods WHERE id = $1 FOR UPDATE
+	`, run.PeriodID).Scan(&locked, &taxYear)
+	if err != nil {
+		return domain.BonusRun{}, err
+	}
+	if !locked {
+		return domain.BonusRun{}, error_const.ErrPayrollPeriodNotRun
+	}
+
+	created, err := scanBonusRun(tx.QueryRow(ctx, `
+		INSERT INTO bonus_runs (period_id, kind, reason, reference_date, employee_count, total_gross, total_net,
+			created_at, updated_at, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW(), $8, $9)
+		RETURNING `+bonusRunColumns,
+		run.PeriodID, run.Kind, run.Reason, run.ReferenceDate, len(payslips), run.TotalGross, run.TotalNet, run.CreatedBy, run.UpdatedBy))
+	if err != nil {
+		return domain.BonusRun{}, err
+	}
+
+	rows := make([][]interface{}, 0, len(payslips))
+	for _, payslip := range payslips {
+		if payslip.EmployeeID == 0 {
+			return domain.BonusRun{}, error_const.ErrInvalidUser
+		}
+		rows = append(rows, []interface{}{created.ID, payslip.EmployeeID, run.PeriodID, run.Kind, taxYear, payslip.Payslip, run.CreatedBy})
+	}
+	_, err = tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"bonus_payslips"},
+		[]string{"run_id", "employee_id", "period_id", "kind", "tax_year", "payslip", "created_by"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return domain.BonusRun{}, err
+	}
+	if err := syncYearToDate(ctx, tx, run.PeriodID); err != nil {
+		return domain.BonusRun{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return domain.BonusRun{}, err
+	}
+	return created, nil
+}
+
+// GetBonusRun returns the run with its payslips.
+func (r *BonusRepository) GetBonusRun(ctx context.Context, runID int) (domain.BonusRun, error) {
+	run, err := scanBonusRun(r.pool.QueryRow(ctx, `SELECT `+bonusRunColumns+` FROM bonus_runs WHERE id = $1`, runID))
+	if err != nil {
+		return domain.BonusRun{}, err
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+bonusPayslipColumns+`
+		FROM bonus_payslips
+		WHERE run_id = $1
+		ORDER BY employee_id
+	`, runID)
+	if err != nil {
+		return domain.BonusRun{}, err
+	}
+	defer rows.Close()
+	run.Payslips, err = scanBonusPayslips(rows)
+	if err != nil {
+		return domain.BonusRun{}, err
+	}
+	return run, nil
+}
+
+func (r *BonusRepository) GetBonusRunsByPeriodID(ctx context.Context, periodID int) ([]domain.BonusRun, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+bonusRunColumns+`
+		FROM bonus_runs
+		WHERE period_id = $1
+		ORDER BY id
+	`, periodID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []domain.BonusRun{}
+	for rows.Next() {
+		run, err := scanBonusRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+func (r *BonusRepository) GetBonusPayslipsByPeriodID(ctx context.Context, periodID int) ([]domain.BonusPayslip, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+bonusPayslipColumns+`
+		FROM bonus_payslips
+		WHERE period_id = $1
+		ORDER BY id
+	`, periodID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanBonusPayslips(rows)
+}
+
+func (r *BonusRepository) GetEmployeeBonusPayslipsByPeriod(ctx context.Context, employeeID, periodID int) ([]domain.BonusPayslip, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+bonusPayslipColumns+`
+		FROM bonus_payslips
+		WHERE employee_id = $1 AND period_id = $2
+		ORDER BY id
+	`, employeeID, periodID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanBonusPayslips(rows)
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type BPJSRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewBPJSRepository(pool *pgxpool.Pool) *BPJSRepository {
+	return &BPJSRepository{
+		pool: pool,
+	}
+}
+
+func (r *BPJSRepository) CreateBPJSRate(ctx context.Context, rate domain.BPJSRate) (int, error) {
+	var id int
+	if rate.EffectiveFrom.IsZero() {
+		return 0, error_const.ErrInvalidDateFormat
+	}
+	if rate.CreatedBy == "" || rate.UpdatedBy == "" {
+		return 0, error_const.ErrInvalidUser
+	}
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO bpjs_rates (program, employee_rate, employer_rate, wage_cap, effective_from, created_at, updated_at, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW(), $6, $7)
+		RETURNING id`, rate.Program, rate.EmployeeRate, rate.EmployerRate, rate.WageCap, rate.EffectiveFrom,
+		rate.CreatedBy, rate.UpdatedBy).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *BPJSRepository) GetAllBPJSRates(ctx context.Context) ([]domain.BPJSRate, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, program, employee_rate, employer_rate, wage_cap, effective_from, created_at, updated_at, created_by, updated_by
+		FROM bpjs_rates
+		ORDER BY program, effective_from
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanBPJSRates(rows)
+}
+
+// GetBPJSRatesEffectiveOn returns, per program, the latest rate effective on the given date.
+func (r *BPJSRepository) GetBPJSRatesEffectiveOn(ctx context.Context, date time.Time) ([]domain.BPJSRate, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT DISTINCT ON (program)
+			id, program, employee_rate, employer_rate, wage_cap, effective_from, created_at, updated_at, created_by, updated_by
+		FROM bpjs_rates
+		WHERE effective_from <= $1
+		ORDER BY program, effective_from DESC
+	`, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanBPJSRates(rows)
+}
+
+func scanBPJSRates(rows interface {
+	Next() bool
+	Scan(dest ...any) error
+}) ([]domain.BPJSRate, error) {
+	var rates []domain.BPJSRate
+	for rows.Next() {
+		var rate domain.BPJSRate
+		err := rows.Scan(&rate.ID, &rate.Program, &rate.EmployeeRate, &rate.EmployerRate, &rate.WageCap,
+			&rate.EffectiveFrom, &rate.CreatedAt, &rate.UpdatedAt, &rate.CreatedBy, &rate.UpdatedBy)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
@@ -0,0 +1,419 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DisbursementRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewDisbursementRepository(pool *pgxpool.Pool) *DisbursementRepository {
+	return &DisbursementRepository{
+		pool: pool,
+	}
+}
+
+const disbursementBatchColumns = `id, period_id, format, status, value_date, source, currency, item_count, total_amount, checksum,
+	cancel_reason, sent_at, sent_by, created_at, updated_at, created_by, updated_by`
+
+func scanDisbursementBatch(row pgx.Row) (domain.DisbursementBatch, error) {
+	var b domain.DisbursementBatch
+	var source []byte
+	err := row.Scan(&b.ID, &b.PeriodID, &b.Format, &b.Status, &b.ValueDate, &source, &b.Currency, &b.ItemCount, &b.TotalAmount,
+		&b.Checksum, &b.CancelReason, &b.SentAt, &b.SentBy, &b.CreatedAt, &b.UpdatedAt, &b.CreatedBy, &b.UpdatedBy)
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	if err := json.Unmarshal(source, &b.Source); err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	b.Reference = b.BatchReference()
+	return b, nil
+}
+
+const disbursementItemColumns = `i.id, i.batch_id, i.line, i.employee_id, i.employee_name, i.bank_code, i.account_number, i.account_holder,
+	i.amount, i.status, i.status_note, i.bank_reference, i.status_updated_at, i.status_updated_by,
+	COALESCE(i.reissue_of_item_id, 0), COALESCE(i.reissued_batch_id, 0)`
+
+func scanDisbursementItem(row pgx.Row, extra ...any) (domain.DisbursementItem, error) {
+	var item domain.DisbursementItem
+	dest := []any{&item.ID, &item.BatchID, &item.Line, &item.EmployeeID, &item.EmployeeName, &item.BankCode, &item.AccountNumber,
+		&item.AccountHolder, &item.Amount, &item.Status, &item.StatusNote, &item.BankReference, &item.StatusUpdatedAt,
+		&item.StatusUpdatedBy, &item.ReissueOfItemID, &item.ReissuedBatchID}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return domain.DisbursementItem{}, err
+	}
+	return item, nil
+}
+
+// CreateDisbursementBatch stores the batch and its items if the period is locked and the items
+// still pay what the period owes, see checkDisbursementOwed. The period row is locked so two
+// exports of a period, or an export and an approval into the period, cannot both pay the same
+// amounts. Items reissuing an earlier payment mark it as reissued, which fails if another
+// batch did.
+func (r *DisbursementRepository) CreateDisbursementBatch(ctx context.Context, batch domain.DisbursementBatch) (domain.DisbursementBatch, error) {
+	if batch.CreatedBy == "" || batch.UpdatedBy == "" {
+		return domain.DisbursementBatch{}, error_const.ErrInvalidUser
+	}
+	if len(batch.Items) == 0 {
+		return domain.DisbursementBatch{}, error_const.ErrInvalidInput
+	}
+	source, err := json.Marshal(batch.Source)
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	err = tx.QueryRow(ctx, `SELECT locked FROM payroll_periods WHERE id = $1 FOR UPDATE`, batch.PeriodID).Scan(&locked)
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	if !locked {
+		return domain.DisbursementBatch{}, error_const.ErrDisbursementPeriodNotLocked
+	}
+	if err := checkDisbursementOwed(ctx, tx, batch); err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+
+	items := batch.Items
+	batch, err = scanDisbursementBatch(tx.QueryRow(ctx, `
+		INSERT INTO disbursement_batches (period_id, format, status, value_date, source, currency, item_count, total_amount, checksum,
+			created_at, updated_at, created_by, updated_by)
+		VALUES ($1, $2, 'exported', $3, $4, $5, $6, $7, $8, NOW(), NOW(), $9, $10)
+		RETURNING `+disbursementBatchColumns,
+		batch.PeriodID, batch.Format, batch.ValueDate, source, batch.Currency, batch.ItemCount, batch.TotalAmount, batch.Checksum,
+		batch.CreatedBy, batch.UpdatedBy))
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	rows := make([][]interface{}, 0, len(items))
+	var reissued []int
+	for i := range items {
+		items[i].BatchID = batch.ID
+		items[i].Status = domain.PaymentPending
+		var reissueOf *int
+		if items[i].ReissueOfItemID != 0 {
+			reissueOf = &items[i].ReissueOfItemID
+			reissued = append(reissued, items[i].ReissueOfItemID)
+		}
+		rows = append(rows, []interface{}{
+			batch.ID, items[i].Line, items[i].EmployeeID, items[i].EmployeeName,
+			items[i].BankCode, items[i].AccountNumber, items[i].AccountHolder, items[i].Amount, items[i].Status, reissueOf,
+		})
+	}
+	_, err = tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"disbursement_items"},
+		[]string{"batch_id", "line", "employee_id", "employee_name", "bank_code", "account_number", "account_holder", "amount",
+			"status", "reissue_of_item_id"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	if len(reissued) > 0 {
+		tag, err := tx.Exec(ctx, `
+			UPDATE disbursement_items
+			SET reissued_batch_id = $2
+			WHERE id = ANY($1) AND reissued_batch_id IS NULL AND status IN ('failed', 'returned')
+		`, reissued, batch.ID)
+		if err != nil {
+			return domain.DisbursementBatch{}, err
+		}
+		if int(tag.RowsAffected()) != len(reissued) {
+			return domain.DisbursementBatch{}, error_const.ErrDisbursementItemReissued
+		}
+	}
+	batch.Items = items
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	return batch, nil
+}
+
+// checkDisbursementOwed fails with ErrDisbursementOutdated unless the batch's items, leaving
+// reissues aside, pay exactly what each employee is owed for the period as of tx, so a batch
+// prepared before a concurrent approval or export does not pay twice or miss a correction.
+func checkDisbursementOwed(ctx context.Context, tx pgx.Tx, batch domain.DisbursementBatch) error {
+	rows, err := tx.Query(ctx, `
+		SELECT `+activePayrollColumns+`
+		FROM payrolls
+		WHERE period_id = $1 AND voided_at IS NULL
+	`, batch.PeriodID)
+	if err != nil {
+		return err
+	}
+	payrolls, err := scanActivePayrolls(rows)
+	rows.Close()
+	if err != nil {
+		return err
+	}
+	disbursed, err := disbursedAmounts(ctx, tx, batch.PeriodID)
+	if err != nil {
+		return err
+	}
+	owed := domain.DisbursementOwed(payrolls, disbursed)
+	paid := 0
+	for _, item := range batch.Items {
+		if item.ReissueOfItemID != 0 {
+			continue
+		}
+		if amount, ok := owed[item.EmployeeID]; !ok || amount != item.Amount {
+			return error_const.ErrDisbursementOutdated
+		}
+		paid++
+	}
+	if paid != len(owed) {
+		return error_const.ErrDisbursementOutdated
+	}
+	return nil
+}
+
+// GetDisbursedAmounts returns per employee what the exported batches of the period transferred
+// for it. Reissues pay an earlier transfer again and are not counted.
+func (r *DisbursementRepository) GetDisbursedAmounts(ctx context.Context, periodID int) (map[int]domain.Money, error) {
+	return disbursedAmounts(ctx, r.pool, periodID)
+}
+
+func disbursedAmounts(ctx context.Context, db interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}, periodID int) (map[int]domain.Money, error) {
+	rows, err := db.Query(ctx, `
+		SELECT i.employee_id, SUM(i.amount)
+		FROM disbursement_items i
+		JOIN disbursement_batches b ON b.id = i.batch_id
+		WHERE b.period_id = $1 AND b.status = 'exported' AND i.reissue_of_item_id IS NULL
+		GROUP BY i.employee_id
+	`, periodID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	amounts := make(map[int]domain.Money)
+	for rows.Next() {
+		var employeeID int
+		var amount domain.Money
+		if err := rows.Scan(&employeeID, &amount); err != nil {
+			return nil, err
+		}
+		amounts[employeeID] = amount
+	}
+	return amounts, rows.Err()
+}
+
+// GetDisbursementBatch returns the batch with its items in line order.
+func (r *DisbursementRepository) GetDisbursementBatch(ctx context.Context, batchID int) (domain.DisbursementBatch, error) {
+	batch, err := scanDisbursementBatch(r.pool.QueryRow(ctx, `
+		SELECT `+disbursementBatchColumns+`
+		FROM disbursement_batches
+		WHERE id = $1`, batchID))
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+disbursementItemColumns+`
+		FROM disbursement_items i
+		WHERE i.batch_id = $1
+		ORDER BY i.line
+	`, batchID)
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item, err := scanDisbursementItem(rows)
+		if err != nil {
+			return domain.DisbursementBatch{}, err
+		}
+		batch.Items = append(batch.Items, item)
+	}
+	return batch, rows.Err()
+}
+
+func (r *DisbursementRepository) GetDisbursementBatchesByPeriodID(ctx context.Context, periodID int) ([]domain.DisbursementBatch, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+disbursementBatchColumns+`
+		FROM disbursement_batches
+		WHERE period_id = $1
+		ORDER BY id DESC`, periodID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	batches := []domain.DisbursementBatch{}
+	for rows.Next() {
+		batch, err := scanDisbursementBatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+	return batches, rows.Err()
+}
+
+// CancelDisbursementBatch marks an exported batch that was not sent as cancelled, so the next
+// export of the period pays its amounts instead and the payments it reissued can be reissued
+// again.
+func (r *DisbursementRepository) CancelDisbursementBatch(ctx context.Context, batch domain.DisbursementBatch) (domain.DisbursementBatch, error) {
+	if batch.UpdatedBy == "" {
+		return domain.DisbursementBatch{}, error_const.ErrInvalidUser
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := scanDisbursementBatch(tx.QueryRow(ctx, `
+		SELECT `+disbursementBatchColumns+`
+		FROM disbursement_batches
+		WHERE id = $1
+		FOR UPDATE`, batch.ID))
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	if !current.IsExported() {
+		return domain.DisbursementBatch{}, error_const.ErrDisbursementBatchNotExported
+	}
+	if current.SentAt != nil {
+		return domain.DisbursementBatch{}, error_const.ErrDisbursementBatchSent
+	}
+	cancelled, err := scanDisbursementBatch(tx.QueryRow(ctx, `
+		UPDATE disbursement_batches
+		SET status = 'cancelled', cancel_reason = $2, updated_at = NOW(), updated_by = $3
+		WHERE id = $1
+		RETURNING `+disbursementBatchColumns, batch.ID, batch.CancelReason, batch.UpdatedBy))
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	_, err = tx.Exec(ctx, `UPDATE disbursement_items SET reissued_batch_id = NULL WHERE reissued_batch_id = $1`, batch.ID)
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	return cancelled, nil
+}
+
+// MarkDisbursementBatchSent records that an exported batch was sent to the bank and moves its
+// pending payments to sent.
+func (r *DisbursementRepository) MarkDisbursementBatchSent(ctx context.Context, batch domain.DisbursementBatch) (domain.DisbursementBatch, error) {
+	if batch.UpdatedBy == "" {
+		return domain.DisbursementBatch{}, error_const.ErrInvalidUser
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := scanDisbursementBatch(tx.QueryRow(ctx, `
+		SELECT `+disbursementBatchColumns+`
+		FROM disbursement_batches
+		WHERE id = $1
+		FOR UPDATE`, batch.ID))
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	if !current.IsExported() {
+		return domain.DisbursementBatch{}, error_const.ErrDisbursementBatchNotExported
+	}
+	if current.SentAt != nil {
+		return domain.DisbursementBatch{}, error_const.ErrDisbursementBatchSent
+	}
+	sent, err := scanDisbursementBatch(tx.QueryRow(ctx, `
+		UPDATE disbursement_batches
+		SET sent_at = NOW(), sent_by = $2, updated_at = NOW(), updated_by = $2
+		WHERE id = $1
+		RETURNING `+disbursementBatchColumns, batch.ID, batch.UpdatedBy))
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	_, err = tx.Exec(ctx, `
+		UPDATE disbursement_items
+		SET status = 'sent', status_updated_at = NOW(), status_updated_by = $2
+		WHERE batch_id = $1 AND status = 'pending'
+	`, batch.ID, batch.UpdatedBy)
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	return sent, nil
+}
+
+// UpdateDisbursementItemStatus changes the status of a payment of an exported batch if it is
+// still in one of the statuses that can change to the new one. Returns pgx.ErrNoRows when the
+// batch has no such item.
+func (r *DisbursementRepository) UpdateDisbursementItemStatus(ctx context.Context, item domain.DisbursementItem) (domain.DisbursementItem, error) {
+	if item.StatusUpdatedBy == "" {
+		return domain.DisbursementItem{}, error_const.ErrInvalidUser
+	}
+	updated, err := scanDisbursementItem(r.pool.QueryRow(ctx, `
+		UPDATE disbursement_items i
+		SET status = $3, status_note = $4, status_updated_at = NOW(), status_updated_by = $5
+		FROM disbursement_batches b
+		WHERE i.id = $1 AND i.batch_id = $2 AND b.id = i.batch_id AND b.status = 'exported' AND i.status = ANY($6)
+		RETURNING `+disbursementItemColumns,
+		item.ID, item.BatchID, item.Status, item.StatusNote, item.StatusUpdatedBy, domain.PaymentStatusesChangingTo(item.Status)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		var exists bool
+		if err := r.pool.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM disbursement_items WHERE id = $1 AND batch_id = $2)
+		`, item.ID, item.BatchID).Scan(&exists); err != nil {
+			return domain.DisbursementItem{}, err
+		}
+		if exists {
+			return domain.DisbursementItem{}, error_const.ErrPaymentStatusChange
+		}
+		return domain.DisbursementItem{}, pgx.ErrNoRows
+	}
+	return updated, err
+}
+
+// GetDisbursementItems returns the payments of exported batches in the statuses, of one period
+// or of every period when periodID is 0, in batch and line order.
+func (r *DisbursementRepository) GetDisbursementItems(ctx context.Context, periodID int, statuses []string) ([]domain.ReconciliationItem, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+disbursementItemColumns+`, b.period_id
+		FROM disbursement_items i
+		JOIN disbursement_batches b ON b.id = i.batch_id
+		WHERE b.status = 'exported' AND ($1 = 0 OR b.period_id = $1) AND i.status = ANY($2)
+		ORDER BY i.batch_id, i.line
+	`, periodID, statuses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []domain.ReconciliationItem{}
+	for rows.Next() {
+		var item domain.ReconciliationItem
+		item.DisbursementItem, err = scanDisbursementItem(rows, &item.PeriodID)
+		if err != nil {
+			return nil, err
+		}
+		item.BatchReference = domain.DisbursementBatch{ID: item.BatchID}.BatchReference()
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
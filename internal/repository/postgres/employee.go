@@ -5,6 +5,7 @@ import (
 	"payroll-system/internal/domain"
 	"payroll-system/internal/error_const"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -25,8 +26,9 @@ func (r *EmployeeRepository) GetEmployee(ctx context.Context, credential domain.
 	}
 
 	err := r.pool.
-		QueryRow(ctx, "SELECT id, name, email, password_hash, role, salary FROM employees WHERE email = $1", credential.Email).
-		Scan(&employee.ID, &employee.Name, &employee.Email, &employee.Password_hash, &employee.Role, &employee.Salary)
+		QueryRow(ctx, "SELECT id, name, email, password_hash, role, salary, start_date, end_date, employment_status, cost_center, company FROM employees WHERE email = $1", credential.Email).
+		Scan(&employee.ID, &employee.Name, &employee.Email, &employee.Password_hash, &employee.Role, &employee.Salary,
+			&employee.StartDate, &employee.EndDate, &employee.EmploymentStatus, &employee.CostCenter, &employee.Company)
 
 	if err != nil {
 		return domain.Employee{}, err
@@ -37,7 +39,7 @@ func (r *EmployeeRepository) GetEmployee(ctx context.Context, credential domain.
 	return employee, nil
 }
 func (r *EmployeeRepository) GetAllEmployees(ctx context.Context) ([]domain.Employee, error) {
-	rows, err := r.pool.Query(ctx, "SELECT id, name, email, password_hash, role, salary FROM employees")
+	rows, err := r.pool.Query(ctx, "SELECT id, name, email, password_hash, role, salary, start_date, end_date, employment_status, cost_center, company FROM employees ORDER BY id")
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +48,8 @@ func (r *EmployeeRepository) GetAllEmployees(ctx context.Context) ([]domain.Empl
 	var employees []domain.Employee
 	for rows.Next() {
 		var employee domain.Employee
-		err := rows.Scan(&employee.ID, &employee.Name, &employee.Email, &employee.Password_hash, &employee.Role, &employee.Salary)
+		err := rows.Scan(&employee.ID, &employee.Name, &employee.Email, &employee.Password_hash, &employee.Role, &employee.Salary,
+			&employee.StartDate, &employee.EndDate, &employee.EmploymentStatus, &employee.CostCenter, &employee.Company)
 		if err != nil {
 			return nil, err
 		}
@@ -66,14 +69,33 @@ func (r *EmployeeRepository) GetPayslip(employeeID string, period string) (inter
 
 func (r *EmployeeRepository) GetEmployeeByID(ctx context.Context, employeeID int) (*domain.Employee, error) {
 	row := r.pool.QueryRow(ctx, `
-		SELECT id, name, email, password_hash, role, salary
+		SELECT id, name, email, password_hash, role, salary, start_date, end_date, employment_status, cost_center, company
 		FROM employees
 		WHERE id = $1
 	`, employeeID)
 
 	var e domain.Employee
-	if err := row.Scan(&e.ID, &e.Name, &e.Email, &e.Password_hash, &e.Role, &e.Salary); err != nil {
+	if err := row.Scan(&e.ID, &e.Name, &e.Email, &e.Password_hash, &e.Role, &e.Salary, &e.StartDate, &e.EndDate, &e.EmploymentStatus, &e.CostCenter, &e.Company); err != nil {
 		return nil, err
 	}
 	return &e, nil
 }
+
+// UpdateEmployment stores the employment dates, status, cost center and company of an employee.
+func (r *EmployeeRepository) UpdateEmployment(ctx context.Context, employee domain.Employee) (*domain.Employee, error) {
+	if employee.ID == 0 || employee.Updated_by == "" {
+		return nil, error_const.ErrInvalidUser
+	}
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE employees
+		SET start_date = $2, end_date = $3, employment_status = $4, cost_center = $5, company = $6, updated_at = NOW(), updated_by = $7
+		WHERE id = $1
+	`, employee.ID, employee.StartDate, employee.EndDate, employee.EmploymentStatus, employee.CostCenter, employee.Company, employee.Updated_by)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return r.GetEmployeeByID(ctx, employee.ID)
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type GLAccountRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewGLAccountRepository(pool *pgxpool.Pool) *GLAccountRepository {
+	return &GLAccountRepository{
+		pool: pool,
+	}
+}
+
+const glAccountColumns = `id, component, cost_center, account_code, account_name, created_at, updated_at, created_by, updated_by`
+
+func scanGLAccount(row pgx.Row) (domain.GLAccount, error) {
+	var a domain.GLAccount
+	err := row.Scan(&a.ID, &a.Component, &a.CostCenter, &a.AccountCode, &a.AccountName, &a.CreatedAt, &a.UpdatedAt, &a.CreatedBy, &a.UpdatedBy)
+	if err != nil {
+		return domain.GLAccount{}, err
+	}
+	return a, nil
+}
+
+// UpsertGLAccount sets the account of a component and cost center, replacing the account
+// configured before.
+func (r *GLAccountRepository) UpsertGLAccount(ctx context.Context, account domain.GLAccount) (domain.GLAccount, error) {
+	if account.CreatedBy == "" || account.UpdatedBy == "" {
+		return domain.GLAccount{}, error_const.ErrInvalidUser
+	}
+	return scanGLAccount(r.pool.QueryRow(ctx, `
+		INSERT INTO gl_accounts (component, cost_center, account_code, account_name, created_at, updated_at, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, NOW(), NOW(), $5, $6)
+		ON CONFLICT (component, cost_center) DO UPDATE
+		SET account_code = EXCLUDED.account_code, account_name = EXCLUDED.account_name,
+			updated_at = NOW(), updated_by = EXCLUDED.updated_by
+		RETURNING `+glAccountColumns,
+		account.Component, account.CostCenter, account.AccountCode, account.AccountName, account.CreatedBy, account.UpdatedBy))
+}
+
+func (r *GLAccountRepository) DeleteGLAccount(ctx context.Context, accountID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM gl_accounts WHERE id = $1`, accountID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *GLAccountRepository) GetAllGLAccounts(ctx context.Context) ([]domain.GLAccount, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+glAccountColumns+`
+		FROM gl_accounts
+		ORDER BY component, cost_center
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []domain.GLAccount{}
+	for rows.Next() {
+		account, err := scanGLAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type HolidayRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewHolidayRepository(pool *pgxpool.Pool) *HolidayRepository {
+	return &HolidayRepository{
+		pool: pool,
+	}
+}
+
+const holidayColumns = `id, date, name, type, created_at, updated_at, created_by, updated_by`
+
+func scanHolidays(rows pgx.Rows) ([]domain.Holiday, error) {
+	holidays := []domain.Holiday{}
+	for rows.Next() {
+		holiday, err := scanHoliday(rows)
+		if err != nil {
+			return nil, err
+		}
+		holidays = append(holidays, holiday)
+	}
+	return holidays, rows.Err()
+}
+
+func scanHoliday(row pgx.Row) (domain.Holiday, error) {
+	var h domain.Holiday
+	err := row.Scan(&h.ID, &h.Date, &h.Name, &h.Type, &h.CreatedAt, &h.UpdatedAt, &h.CreatedBy, &h.UpdatedBy)
+	if err != nil {
+		return domain.Holiday{}, err
+	}
+	return h, nil
+}
+
+func (r *HolidayRepository) CreateHoliday(ctx context.Context, holiday domain.Holiday) (domain.Holiday, error) {
+	if holiday.Date.IsZero() {
+		return domain.Holiday{}, error_const.ErrInvalidDateFormat
+	}
+	if holiday.CreatedBy == "" || holiday.UpdatedBy == "" {
+		return domain.Holiday{}, error_const.ErrInvalidUser
+	}
+	return scanHoliday(r.pool.QueryRow(ctx, `
+		INSERT INTO holidays (date, name, type, created_at, updated_at, created_by, updated_by)
+		VALUES ($1, $2, $3, NOW(), NOW(), $4, $5)
+		RETURNING `+holidayColumns,
+		holiday.Date, holiday.Name, holiday.Type, holiday.CreatedBy, holiday.UpdatedBy))
+}
+
+func (r *HolidayRepository) UpdateHoliday(ctx context.Context, holiday domain.Holiday) (domain.Holiday, error) {
+	if holiday.ID == 0 {
+		return domain.Holiday{}, error_const.ErrInvalidID
+	}
+	if holiday.UpdatedBy == "" {
+		return domain.Holiday{}, error_const.ErrInvalidUser
+	}
+	return scanHoliday(r.pool.QueryRow(ctx, `
+		UPDATE holidays
+		SET date = $2, name = $3, type = $4, updated_at = NOW(), updated_by = $5
+		WHERE id = $1
+		RETURNING `+holidayColumns,
+		holiday.ID, holiday.Date, holiday.Name, holiday.Type, holiday.UpdatedBy))
+}
+
+func (r *HolidayRepository) DeleteHoliday(ctx context.Context, holidayID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM holidays WHERE id = $1`, holidayID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *HolidayRepository) GetHoliday(ctx context.Context, holidayID int) (domain.Holiday, error) {
+	return scanHoliday(r.pool.QueryRow(ctx, `
+		SELECT `+holidayColumns+`
+		FROM holidays
+		WHERE id = $1`, holidayID))
+}
+
+// GetHolidaysBetween returns the holidays from start to end, both inclusive.
+func (r *HolidayRepository) GetHolidaysBetween(ctx context.Context, start, end time.Time) ([]domain.Holiday, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+holidayColumns+`
+		FROM holidays
+		WHERE date BETWEEN $1 AND $2
+		ORDER BY date
+	`, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanHolidays(rows)
+}
+
+// ImportHolidays upserts a year's holiday list by date. With replace, holidays of that year
+// missing from the list are removed, so the stored calendar matches the list exactly.
+func (r *HolidayRepository) ImportHolidays(ctx context.Context, year int, holidays []domain.Holiday, replace bool) ([]domain.Holiday, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	dates := make([]time.Time, 0, len(holidays))
+	for _, h := range holidays {
+		if h.CreatedBy == "" || h.UpdatedBy == "" {
+			return nil, error_const.ErrInvalidUser
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO holidays (date, name, type, created_at, updated_at, created_by, updated_by)
+			VALUES ($1, $2, $3, NOW(), NOW(), $4, $5)
+			ON CONFLICT (date) DO UPDATE
+			SET name = EXCLUDED.name, type = EXCLUDED.type, updated_at = NOW(), updated_by = EXCLUDED.updated_by
+		`, h.Date, h.Name, h.Type, h.CreatedBy, h.UpdatedBy)
+		if err != nil {
+			return nil, err
+		}
+		dates = append(dates, h.Date)
+	}
+	if replace {
+		_, err := tx.Exec(ctx, `
+			DELETE FROM holidays
+			WHERE EXTRACT(YEAR FROM date) = $1 AND NOT (date = ANY($2))
+		`, year, dates)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT `+holidayColumns+`
+		FROM holidays
+		WHERE EXTRACT(YEAR FROM date) = $1
+		ORDER BY date
+	`, year)
+	if err != nil {
+		return nil, err
+	}
+	imported, err := scanHolidays(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	return imported, tx.Commit(ctx)
+}
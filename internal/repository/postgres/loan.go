@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LoanRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewLoanRepository(pool *pgxpool.Pool) *LoanRepository {
+	return &LoanRepository{
+		pool: pool,
+	}
+}
+
+const loanColumns = `id, employee_id, type, principal, installments, installment_amount, start_date, description,
+	status, outstanding, created_at, updated_at, created_by, updated_by`
+
+func scanLoan(row pgx.Row) (domain.Loan, error) {
+	var l domain.Loan
+	err := row.Scan(&l.ID, &l.EmployeeID, &l.Type, &l.Principal, &l.Installments, &l.InstallmentAmount, &l.StartDate, &l.Description,
+		&l.Status, &l.Outstanding, &l.CreatedAt, &l.UpdatedAt, &l.CreatedBy, &l.UpdatedBy)
+	if err != nil {
+		return domain.Loan{}, err
+	}
+	return l, nil
+}
+
+func scanLoans(rows pgx.Rows) ([]domain.Loan, error) {
+	loans := []domain.Loan{}
+	for rows.Next() {
+		loan, err := scanLoan(rows)
+		if err != nil {
+			return nil, err
+		}
+		loans = append(loans, loan)
+	}
+	return loans, rows.Err()
+}
+
+func (r *LoanRepository) CreateLoan(ctx context.Context, loan domain.Loan) (domain.Loan, error) {
+	if loan.EmployeeID == 0 || loan.CreatedBy == "" || loan.UpdatedBy == "" {
+		return domain.Loan{}, error_const.ErrInvalidUser
+	}
+	return scanLoan(r.pool.QueryRow(ctx, `
+		INSERT INTO loans (employee_id, type, principal, installments, installment_amount, start_date, description,
+			status, outstanding, created_at, updated_at, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, 'active', $3, NOW(), NOW(), $8, $9)
+		RETURNING `+loanColumns,
+		loan.EmployeeID, loan.Type, loan.Principal, loan.Installments, loan.InstallmentAmount, loan.StartDate, loan.Description,
+		loan.CreatedBy, loan.UpdatedBy))
+}
+
+// GetLoan returns the loan with its repayments.
+func (r *LoanRepository) GetLoan(ctx context.Context, loanID int) (domain.Loan, error) {
+	loan, err := scanLoan(r.pool.QueryRow(ctx, `SELECT `+loanColumns+` FROM loans WHERE id = $1`, loanID))
+	if err != nil {
+		return domain.Loan{}, err
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT r.loan_id, r.period_id, r.payroll_id, r.amount, r.created_at
+		FROM loan_repayments r
+		JOIN payroll_periods pp ON pp.id = r.period_id
+		WHERE r.loan_id = $1
+		ORDER BY pp.start_date
+	`, loanID)
+	if err != nil {
+		return domain.Loan{}, err
+	}
+	defer rows.Close()
+
+	loan.Repayments = []domain.LoanRepayment{}
+	for rows.Next() {
+		var repayment domain.LoanRepayment
+		if err := rows.Scan(&repayment.LoanID, &repayment.PeriodID, &repayment.PayrollID, &repayment.Amount, &repayment.CreatedAt); err != nil {
+			return domain.Loan{}, err
+		}
+		loan.Repayments = append(loan.Repayments, repayment)
+	}
+	return loan, rows.Err()
+}
+
+func (r *LoanRepository) GetEmployeeLoans(ctx context.Context, employeeID int) ([]domain.Loan, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+loanColumns+`
+		FROM loans
+		WHERE employee_id = $1
+		ORDER BY start_date, id
+	`, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanLoans(rows)
+}
+
+// CancelLoan stops the deductions of an active loan. Installments already deducted stay.
+// Returns pgx.ErrNoRows when the loan is not active.
+func (r *LoanRepository) CancelLoan(ctx context.Context, loan domain.Loan) (domain.Loan, error) {
+	if loan.UpdatedBy == "" {
+		return domain.Loan{}, error_const.ErrInvalidUser
+	}
+	return scanLoan(r.pool.QueryRow(ctx, `
+		UPDATE loans
+		SET status = 'cancelled', updated_at = NOW(), updated_by = $2
+		WHERE id = $1 AND status = 'active'
+		RETURNING `+loanColumns,
+		loan.ID, loan.UpdatedBy))
+}
+
+// GetLoansDueGroupedByEmployeeID returns the loans with an installment due in the period,
+// oldest first. Outstanding is the balance left by the other periods, so a period that is run
+// again deducts the same installment instead of the next one.
+func (r *LoanRepository) GetLoansDueGroupedByEmployeeID(ctx context.Context, period domain.PayrollPeriod) (map[int][]domain.Loan, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+loanColumns+`
+		FROM (
+			SELECT l.id, l.employee_id, l.type, l.principal, l.installments, l.installment_amount, l.start_date,
+				l.description, l.status,
+				l.principal - COALESCE((
+					SELECT SUM(r.amount) FROM loan_repayments r WHERE r.loan_id = l.id AND r.period_id <> $1
+				), 0) AS outstanding,
+				l.created_at, l.updated_at, l.created_by, l.updated_by
+			FROM loans l
+			WHERE l.status <> 'cancelled' AND l.start_date <= $2
+		) due
+		WHERE outstanding > 0
+		ORDER BY employee_id, start_date, id
+	`, period.ID, period.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	loans, err := scanLoans(rows)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int][]domain.Loan)
+	for _, loan := range loans {
+		result[loan.EmployeeID] = append(result[loan.EmployeeID], loan)
+	}
+	return result, nil
+}
+
+// syncLoanRepayments rebuilds the repayments of a locked period from the loan installments on
+// its active payslips and refreshes the outstanding balance and status of the loans involved.
+// It runs in the transaction that stores payslips into a locked period, so voided payslips
+// drop their repayments.
+func syncLoanRepayments(ctx context.Context, tx pgx.Tx, periodID int) error {
+	rows, err := tx.Query(ctx, `DELETE FROM loan_repayments WHERE period_id = $1 RETURNING loan_id`, periodID)
+	if err != nil {
+		return err
+	}
+	var loanIDs []int
+	for rows.Next() {
+		var loanID int
+		if err := rows.Scan(&loanID); err != nil {
+			rows.Close()
+			return err
+		}
+		loanIDs = append(loanIDs, loanID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO loan_repayments (loan_id, period_id, payroll_id, amount, created_at)
+		SELECT (i->>'loan_id')::int, p.period_id, p.id, (i->>'amount')::numeric, NOW()
+		FROM payrolls p
+		CROSS JOIN jsonb_array_elements(COALESCE(p.payslip->'loan_installments', '[]'::jsonb)) i
+		WHERE p.period_id = $1 AND p.voided_at IS NULL AND (i->>'amount')::numeric > 0
+	`, periodID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE loans l
+		SET outstanding = GREATEST(l.principal - COALESCE((SELECT SUM(r.amount) FROM loan_repayments r WHERE r.loan_id = l.id), 0), 0),
+			updated_at = NOW()
+		WHERE l.id = ANY($2) OR l.id IN (SELECT loan_id FROM loan_repayments WHERE period_id = $1)
+	`, periodID, loanIDs)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `
+		UPDATE loans
+		SET status = CASE WHEN outstanding = 0 THEN 'paid_off' ELSE 'active' END
+		WHERE status <> 'cancelled' AND (id = ANY($2) OR id IN (SELECT loan_id FROM loan_repayments WHERE period_id = $1))
+	`, periodID, loanIDs)
+	return err
+}
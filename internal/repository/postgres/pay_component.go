@@ -0,0 +1,274 @@
+package postgres
+
+import (
+	"context"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PayComponentRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPayComponentRepository(pool *pgxpool.Pool) *PayComponentRepository {
+	return &PayComponentRepository{
+		pool: pool,
+	}
+}
+
+const payComponentColumns = `id, code, name, category, taxable, active, created_at, updated_at, created_by, updated_by`
+
+func scanPayComponent(row pgx.Row) (domain.PayComponent, error) {
+	var c domain.PayComponent
+	err := row.Scan(&c.ID, &c.Code, &c.Name, &c.Category, &c.Taxable, &c.Active, &c.CreatedAt, &c.UpdatedAt, &c.CreatedBy, &c.UpdatedBy)
+	if err != nil {
+		return domain.PayComponent{}, err
+	}
+	return c, nil
+}
+
+func (r *PayComponentRepository) CreatePayComponent(ctx context.Context, component domain.PayComponent) (domain.PayComponent, error) {
+	if component.CreatedBy == "" || component.UpdatedBy == "" {
+		return domain.PayComponent{}, error_const.ErrInvalidUser
+	}
+	return scanPayComponent(r.pool.QueryRow(ctx, `
+		INSERT INTO pay_components (code, name, category, taxable, active, created_at, updated_at, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW(), $6, $7)
+		RETURNING `+payComponentColumns,
+		component.Code, component.Name, component.Category, component.Taxable, component.Active, component.CreatedBy, component.UpdatedBy))
+}
+
+// UpdatePayComponent changes the name, taxable flag and active flag of a component. The code
+// and category are fixed once created, since stored payslips refer to them.
+func (r *PayComponentRepository) UpdatePayComponent(ctx context.Context, component domain.PayComponent) (domain.PayComponent, error) {
+	if component.ID == 0 {
+		return domain.PayComponent{}, error_const.ErrInvalidID
+	}
+	if component.UpdatedBy == "" {
+		return domain.PayComponent{}, error_const.ErrInvalidUser
+	}
+	return scanPayComponent(r.pool.QueryRow(ctx, `
+		UPDATE pay_components
+		SET name = $2, taxable = $3, active = $4, updated_at = NOW(), updated_by = $5
+		WHERE id = $1
+		RETURNING `+payComponentColumns,
+		component.ID, component.Name, component.Taxable, component.Active, component.UpdatedBy))
+}
+
+func (r *PayComponentRepository) GetPayComponent(ctx context.Context, componentID int) (domain.PayComponent, error) {
+	return scanPayComponent(r.pool.QueryRow(ctx, `
+		SELECT `+payComponentColumns+`
+		FROM pay_components
+		WHERE id = $1`, componentID))
+}
+
+func (r *PayComponentRepository) GetAllPayComponents(ctx context.Context) ([]domain.PayComponent, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+payComponentColumns+`
+		FROM pay_components
+		ORDER BY code
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	components := []domain.PayComponent{}
+	for rows.Next() {
+		component, err := scanPayComponent(rows)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, component)
+	}
+	return components, rows.Err()
+}
+
+const payComponentAssignmentColumns = `a.id, a.employee_id, a.component_id, a.amount, a.taxable, a.effective_from, a.effective_to,
+	a.created_at, a.updated_at, a.created_by, a.updated_by,
+	c.id, c.code, c.name, c.category, c.taxable, c.active, c.created_at, c.updated_at, c.created_by, c.updated_by`
+
+func scanPayComponentAssignment(row pgx.Row) (domain.PayComponentAssignment, error) {
+	var a domain.PayComponentAssignment
+	c := &a.Component
+	err := row.Scan(&a.ID, &a.EmployeeID, &a.ComponentID, &a.Amount, &a.Taxable, &a.EffectiveFrom, &a.EffectiveTo,
+		&a.CreatedAt, &a.UpdatedAt, &a.CreatedBy, &a.UpdatedBy,
+		&c.ID, &c.Code, &c.Name, &c.Category, &c.Taxable, &c.Active, &c.CreatedAt, &c.UpdatedAt, &c.CreatedBy, &c.UpdatedBy)
+	if err != nil {
+		return domain.PayComponentAssignment{}, err
+	}
+	return a, nil
+}
+
+func scanPayComponentAssignments(rows pgx.Rows) ([]domain.PayComponentAssignment, error) {
+	assignments := []domain.PayComponentAssignment{}
+	for rows.Next() {
+		assignment, err := scanPayComponentAssignment(rows)
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, assignment)
+	}
+	return assignments, rows.Err()
+}
+
+// checkAssignmentOverlap rejects a second assignment of the same component to an employee
+// for overlapping dates, which would pay the component twice.
+// The employee row is locked so concurrent assignments are checked one after the other.
+func checkAssignmentOverlap(ctx context.Context, tx pgx.Tx, assignment domain.PayComponentAssignment) error {
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM employees WHERE id = $1 FOR UPDATE`, assignment.EmployeeID); err != nil {
+		return err
+	}
+	var overlaps bool
+	err := tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM employee_pay_components
+			WHERE employee_id = $1 AND component_id = $2 AND id <> $3
+				AND effective_from <= COALESCE($5::date, 'infinity'::date)
+				AND COALESCE(effective_to, 'infinity'::date) >= $4
+		)
+	`, assignment.EmployeeID, assignment.ComponentID, assignment.ID, assignment.EffectiveFrom, assignment.EffectiveTo).Scan(&overlaps)
+	if err != nil {
+		return err
+	}
+	if overlaps {
+		return error_const.ErrPayComponentAssignmentOverlap
+	}
+	return nil
+}
+
+func getPayComponentAssignment(ctx context.Context, tx pgx.Tx, assignmentID int) (domain.PayComponentAssignment, error) {
+	return scanPayComponentAssignment(tx.QueryRow(ctx, `
+		SELECT `+payComponentAssignmentColumns+`
+		FROM employee_pay_components a
+		JOIN pay_components c ON c.id = a.component_id
+		WHERE a.id = $1`, assignmentID))
+}
+
+func (r *PayComponentRepository) CreatePayComponentAssignment(ctx context.Context, assignment domain.PayComponentAssignment) (domain.PayComponentAssignment, error) {
+	if assignment.EmployeeID == 0 || assignment.CreatedBy == "" || assignment.UpdatedBy == "" {
+		return domain.PayComponentAssignment{}, error_const.ErrInvalidUser
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.PayComponentAssignment{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := checkAssignmentOverlap(ctx, tx, assignment); err != nil {
+		return domain.PayComponentAssignment{}, err
+	}
+	var id int
+	err = tx.QueryRow(ctx, `
+		INSERT INTO employee_pay_components (employee_id, component_id, amount, taxable, effective_from, effective_to,
+			created_at, updated_at, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW(), $7, $8)
+		RETURNING id
+	`, assignment.EmployeeID, assignment.ComponentID, assignment.Amount, assignment.Taxable, assignment.EffectiveFrom,
+		assignment.EffectiveTo, assignment.CreatedBy, assignment.UpdatedBy).Scan(&id)
+	if err != nil {
+		return domain.PayComponentAssignment{}, err
+	}
+	created, err := getPayComponentAssignment(ctx, tx, id)
+	if err != nil {
+		return domain.PayComponentAssignment{}, err
+	}
+	return created, tx.Commit(ctx)
+}
+
+// UpdatePayComponentAssignment changes the amount, taxable override and dates of an employee's
+// assignment. The employee and component stay the same.
+func (r *PayComponentRepository) UpdatePayComponentAssignment(ctx context.Context, assignment domain.PayComponentAssignment) (domain.PayComponentAssignment, error) {
+	if assignment.ID == 0 {
+		return domain.PayComponentAssignment{}, error_const.ErrInvalidID
+	}
+	if assignment.UpdatedBy == "" {
+		return domain.PayComponentAssignment{}, error_const.ErrInvalidUser
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.PayComponentAssignment{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := getPayComponentAssignment(ctx, tx, assignment.ID)
+	if err != nil {
+		return domain.PayComponentAssignment{}, err
+	}
+	if current.EmployeeID != assignment.EmployeeID {
+		return domain.PayComponentAssignment{}, pgx.ErrNoRows
+	}
+	assignment.ComponentID = current.ComponentID
+	if err := checkAssignmentOverlap(ctx, tx, assignment); err != nil {
+		return domain.PayComponentAssignment{}, err
+	}
+	_, err = tx.Exec(ctx, `
+		UPDATE employee_pay_components
+		SET amount = $2, taxable = $3, effective_from = $4, effective_to = $5, updated_at = NOW(), updated_by = $6
+		WHERE id = $1
+	`, assignment.ID, assignment.Amount, assignment.Taxable, assignment.EffectiveFrom, assignment.EffectiveTo, assignment.UpdatedBy)
+	if err != nil {
+		return domain.PayComponentAssignment{}, err
+	}
+	updated, err := getPayComponentAssignment(ctx, tx, assignment.ID)
+	if err != nil {
+		return domain.PayComponentAssignment{}, err
+	}
+	return updated, tx.Commit(ctx)
+}
+
+func (r *PayComponentRepository) DeletePayComponentAssignment(ctx context.Context, employeeID, assignmentID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM employee_pay_components WHERE id = $1 AND employee_id = $2`, assignmentID, employeeID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *PayComponentRepository) GetEmployeePayComponentAssignments(ctx context.Context, employeeID int) ([]domain.PayComponentAssignment, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+payComponentAssignmentColumns+`
+		FROM employee_pay_components a
+		JOIN pay_components c ON c.id = a.component_id
+		WHERE a.employee_id = $1
+		ORDER BY c.code, a.effective_from
+	`, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanPayComponentAssignments(rows)
+}
+
+// GetPayComponentAssignmentsGroupedByEmployeeID returns the assignments of active components
+// effective on at least one day from start to end.
+func (r *PayComponentRepository) GetPayComponentAssignmentsGroupedByEmployeeID(ctx context.Context, start, end time.Time) (map[int][]domain.PayComponentAssignment, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+payComponentAssignmentColumns+`
+		FROM employee_pay_components a
+		JOIN pay_components c ON c.id = a.component_id
+		WHERE c.active AND a.effective_from <= $2 AND (a.effective_to IS NULL OR a.effective_to >= $1)
+		ORDER BY a.employee_id, c.code, a.effective_from
+	`, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assignments, err := scanPayComponentAssignments(rows)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int][]domain.PayComponentAssignment)
+	for _, assignment := range assignments {
+		result[assignment.EmployeeID] = append(result[assignment.EmployeeID], assignment)
+	}
+	return result, nil
+}
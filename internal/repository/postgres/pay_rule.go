@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PayRuleRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPayRuleRepository(pool *pgxpool.Pool) *PayRuleRepository {
+	return &PayRuleRepository{
+		pool: pool,
+	}
+}
+
+const payRuleSetColumns = `id, version, description, base_pay_formula, hourly_divisor, overtime_multiplier,
+		rest_day_overtime_multiplier, created_at, updated_at, created_by, updated_by`
+
+func scanPayRuleSet(row interface{ Scan(dest ...any) error }) (domain.PayRuleSet, error) {
+	var rules domain.PayRuleSet
+	err := row.Scan(&rules.ID, &rules.Version, &rules.Description, &rules.BasePayFormula,
+		&rules.HourlyDivisor, &rules.OvertimeMultiplier, &rules.RestDayOvertimeMultiplier,
+		&rules.CreatedAt, &rules.UpdatedAt, &rules.CreatedBy, &rules.UpdatedBy)
+	if err != nil {
+		return domain.PayRuleSet{}, err
+	}
+	return rules, nil
+}
+
+// CreatePayRuleSet stores the rules as the next version and returns it.
+func (r *PayRuleRepository) CreatePayRuleSet(ctx context.Context, rules domain.PayRuleSet) (domain.PayRuleSet, error) {
+	if rules.CreatedBy == "" || rules.UpdatedBy == "" {
+		return domain.PayRuleSet{}, error_const.ErrInvalidUser
+	}
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO pay_rule_sets (version, description, base_pay_formula, hourly_divisor, overtime_multiplier,
+			rest_day_overtime_multiplier, created_at, updated_at, created_by, updated_by)
+		SELECT COALESCE(MAX(version), 0) + 1, $1, $2, $3, $4, $5, NOW(), NOW(), $6, $7
+		FROM pay_rule_sets
+		RETURNING `+payRuleSetColumns,
+		rules.Description, rules.BasePayFormula, rules.HourlyDivisor, rules.OvertimeMultiplier,
+		rules.OvertimeMultiplierFor(true), rules.CreatedBy, rules.UpdatedBy)
+	return scanPayRuleSet(row)
+}
+
+func (r *PayRuleRepository) GetPayRuleSetByVersion(ctx context.Context, version int) (domain.PayRuleSet, error) {
+	if version == 0 {
+		return domain.PayRuleSet{}, error_const.ErrInvalidID
+	}
+	row := r.pool.QueryRow(ctx, `
+		SELECT `+payRuleSetColumns+`
+		FROM pay_rule_sets
+		WHERE version = $1
+	`, version)
+	return scanPayRuleSet(row)
+}
+
+func (r *PayRuleRepository) GetLatestPayRuleSet(ctx context.Context) (domain.PayRuleSet, error) {
+	row := r.pool.QueryRow(ctx, `
+		SELECT `+payRuleSetColumns+`
+		FROM pay_rule_sets
+		ORDER BY version DESC
+		LIMIT 1
+	`)
+	return scanPayRuleSet(row)
+}
+
+func (r *PayRuleRepository) GetAllPayRuleSets(ctx context.Context) ([]domain.PayRuleSet, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+payRuleSetColumns+`
+		FROM pay_rule_sets
+		ORDER BY version
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ruleSets []domain.PayRuleSet
+	for rows.Next() {
+		rules, err := scanPayRuleSet(rows)
+		if err != nil {
+			return nil, err
+		}
+		ruleSets = append(ruleSets, rules)
+	}
+	return ruleSets, nil
+}
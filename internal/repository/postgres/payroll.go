@@ -20,36 +20,15 @@ func NewPayrollRepository(pool *pgxpool.Pool) *PayrollRepository {
 	}
 }
 
-func (r *PayrollRepository) CreatePayroll(ctx context.Context, payroll domain.Payroll) error {
-	if payroll.EmployeeID == 0 || payroll.PeriodID == 0 {
-		return error_const.ErrInvalidUser
-	}
-	if payroll.CreatedBy == "" || payroll.UpdatedBy == "" {
-		return error_const.ErrInvalidUser
-	}
-	_, err := r.pool.Exec(ctx, `
-		INSERT INTO payrolls (employee_id, period_id, payslip, created_at, updated_at, created_by, updated_by)
-		VALUES ($1, $2, $3, NOW(), NOW(), $4, $5)
-		RETURNING id`, payroll.EmployeeID, payroll.PeriodID, payroll.Payslip, payroll.CreatedBy, payroll.UpdatedBy)
-
-	if err != nil {
-		return err
+func employeeIDsOf(payrolls []domain.Payroll) []int {
+	ids := make([]int, 0, len(payrolls))
+	for _, payroll := range payrolls {
+		ids = append(ids, payroll.EmployeeID)
 	}
-	return nil
+	return ids
 }
 
-// bulkInsert payrolls
-func (r *PayrollRepository) BulkInsertPayrolls(ctx context.Context, payrolls []domain.Payroll) error {
-	if len(payrolls) == 0 {
-		return error_const.ErrInvalidInput
-	}
-
-	tx, err := r.pool.Begin(ctx)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback(ctx)
-
+func copyPayrolls(ctx context.Context, tx pgx.Tx, payrolls []domain.Payroll) error {
 	// Prepare data for COPY FROM
 	rows := make([][]interface{}, 0, len(payrolls))
 	for _, payroll := range payrolls {
@@ -59,26 +38,32 @@ func (r *PayrollRepository) BulkInsertPayrolls(ctx context.Context, payrolls []d
 		if payroll.CreatedBy == "" || payroll.UpdatedBy == "" {
 			return error_const.ErrInvalidUser
 		}
+		var runID *int
+		if payroll.RunID != 0 {
+			runID = &payroll.RunID
+		}
+		runType := payroll.RunType
+		if runType == "" {
+			runType = domain.PayrollRunRegular
+		}
 		rows = append(rows, []interface{}{
 			payroll.EmployeeID,
 			payroll.PeriodID,
+			runID,
+			runType,
 			payroll.Payslip,
 			payroll.CreatedBy,
 			payroll.UpdatedBy,
 		})
 	}
 
-	_, err = tx.CopyFrom(
+	_, err := tx.CopyFrom(
 		ctx,
 		pgx.Identifier{"payrolls"},
-		[]string{"employee_id", "period_id", "payslip", "created_by", "updated_by"},
+		[]string{"employee_id", "period_id", "run_id", "run_type", "payslip", "created_by", "updated_by"},
 		pgx.CopyFromRows(rows),
 	)
-	if err != nil {
-		return err
-	}
-
-	return tx.Commit(ctx)
+	return err
 }
 
 func (r *PayrollRepository) GetPayrollPeriod(ctx context.Context, period domain.PayrollPeriod) (domain.PayrollPeriod, error) {
@@ -88,7 +73,7 @@ func (r *PayrollRepository) GetPayrollPeriod(ctx context.Context, period domain.
 		return domain.PayrollPeriod{}, error_const.ErrInvalidID
 	}
 	query := `
-		SELECT id, start_date, end_date, locked, created_at, updated_at, created_by, updated_by
+		SELECT id, start_date, end_date, locked, COALESCE(rule_set_version, 0), created_at, updated_at, created_by, updated_by
 		FROM payroll_periods
 		WHERE id = $1
 		LIMIT 1
@@ -99,6 +84,7 @@ func (r *PayrollRepository) GetPayrollPeriod(ctx context.Context, period domain.
 		&result.StartDate,
 		&result.EndDate,
 		&result.Locked,
+		&result.RuleSetVersion,
 		&result.CreatedAt,
 		&result.UpdatedAt,
 		&result.CreatedBy,
@@ -109,6 +95,33 @@ func (r *PayrollRepository) GetPayrollPeriod(ctx context.Context, period domain.
 	}
 	return result, nil
 }
+
+// GetPreviousPayrollPeriod returns the latest period ending before the period starts.
+func (r *PayrollRepository) GetPreviousPayrollPeriod(ctx context.Context, period domain.PayrollPeriod) (domain.PayrollPeriod, error) {
+	var result domain.PayrollPeriod
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, start_date, end_date, locked, COALESCE(rule_set_version, 0), created_at, updated_at, created_by, updated_by
+		FROM payroll_periods
+		WHERE end_date < $1
+		ORDER BY end_date DESC
+		LIMIT 1
+	`, period.StartDate).Scan(
+		&result.ID,
+		&result.StartDate,
+		&result.EndDate,
+		&result.Locked,
+		&result.RuleSetVersion,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+		&result.CreatedBy,
+		&result.UpdatedBy,
+	)
+	if err != nil {
+		return domain.PayrollPeriod{}, err
+	}
+	return result, nil
+}
+
 func (r *PayrollRepository) GetPayrollPeriodFromDateRange(ctx context.Context, period domain.PayrollPeriod) (domain.PayrollPeriod, error) {
 	var result domain.PayrollPeriod
 
@@ -116,7 +129,7 @@ func (r *PayrollRepository) GetPayrollPeriodFromDateRange(ctx context.Context, p
 		return domain.PayrollPeriod{}, error_const.ErrInvalidDateFormat
 	}
 	query := `
-		SELECT id, start_date, end_date, locked, created_at, updated_at, created_by, updated_by
+		SELECT id, start_date, end_date, locked, COALESCE(rule_set_version, 0), created_at, updated_at, created_by, updated_by
 		FROM payroll_periods
 		WHERE start_date <= $1 AND end_date >= $2
 		LIMIT 1
@@ -127,6 +140,7 @@ func (r *PayrollRepository) GetPayrollPeriodFromDateRange(ctx context.Context, p
 		&result.StartDate,
 		&result.EndDate,
 		&result.Locked,
+		&result.RuleSetVersion,
 		&result.CreatedAt,
 		&result.UpdatedAt,
 		&result.CreatedBy,
@@ -144,7 +158,7 @@ func (r *PayrollRepository) GetPayrollPeriodFromDate(ctx context.Context, date d
 		return domain.PayrollPeriod{}, error_const.ErrInvalidDateFormat
 	}
 	query := `
-		SELECT id, start_date, end_date, locked, created_at, updated_at, created_by, updated_by
+		SELECT id, start_date, end_date, locked, COALESCE(rule_set_version, 0), created_at, updated_at, created_by, updated_by
 		FROM payroll_periods
 		WHERE start_date <= $1 AND end_date >= $1
 		LIMIT 1
@@ -155,6 +169,7 @@ func (r *PayrollRepository) GetPayrollPeriodFromDate(ctx context.Context, date d
 		&result.StartDate,
 		&result.EndDate,
 		&result.Locked,
+		&result.RuleSetVersion,
 		&result.CreatedAt,
 		&result.UpdatedAt,
 		&result.CreatedBy,
@@ -174,10 +189,14 @@ func (r *PayrollRepository) CreatePayrollPeriod(ctx context.Context, payroll dom
 	if payroll.CreatedBy == "" || payroll.UpdatedBy == "" {
 		return "", error_const.ErrInvalidUser
 	}
+	var ruleSetVersion *int
+	if payroll.RuleSetVersion != 0 {
+		ruleSetVersion = &payroll.RuleSetVersion
+	}
 	err := r.pool.QueryRow(ctx, `
-		INSERT INTO payroll_periods (start_date, end_date, locked, created_at, updated_at, created_by, updated_by)
-		VALUES ($1, $2, false, NOW(), NOW(), $3, $4)
-		RETURNING id`, payroll.StartDate, payroll.EndDate, payroll.CreatedBy, payroll.UpdatedBy).Scan(&payrollID)
+		INSERT INTO payroll_periods (start_date, end_date, locked, rule_set_version, created_at, updated_at, created_by, updated_by)
+		VALUES ($1, $2, false, $3, NOW(), NOW(), $4, $5)
+		RETURNING id`, payroll.StartDate, payroll.EndDate, ruleSetVersion, payroll.CreatedBy, payroll.UpdatedBy).Scan(&payrollID)
 
 	if err != nil {
 		return "", err
@@ -194,10 +213,11 @@ func (r *PayrollRepository) GetEmployeePayslipByPeriod(ctx context.Context, _pay
 	query := `
 		SELECT id, employee_id, period_id, payslip, created_at, updated_at, created_by, updated_by
 		FROM payrolls
-		WHERE employee_id = $1 AND period_id = $2
+		WHERE employee_id = $1 AND period_id = $2 AND voided_at IS NULL
+		ORDER BY run_type = 'off_cycle', id
 		LIMIT 1
 	`
-	row := r.pool.QueryRow(ctx, query, _payroll.EmployeeID, _payroll.EmployeeID)
+	row := r.pool.QueryRow(ctx, query, _payroll.EmployeeID, _payroll.PeriodID)
 	err := row.Scan(
 		&payroll.ID,
 		&payroll.EmployeeID,
@@ -214,22 +234,63 @@ func (r *PayrollRepository) GetEmployeePayslipByPeriod(ctx context.Context, _pay
 	return payroll, nil
 }
 
+const activePayrollColumns = `id, employee_id, period_id, COALESCE(run_id, 0), run_type, payslip, created_by, updated_by`
+
+// GetPayrollsByPeriodID returns the active payrolls of the period by employee, each
+// employee's regular payslip before their off-cycle ones.
 func (r *PayrollRepository) GetPayrollsByPeriodID(ctx context.Context, periodID int) ([]domain.Payroll, error) {
 	rows, err := r.pool.Query(ctx, `
-		SELECT id, employee_id, period_id, payslip, created_by, updated_by
+		SELECT `+activePayrollColumns+`
 		FROM payrolls
-		WHERE period_id = $1
+		WHERE period_id = $1 AND voided_at IS NULL
+		ORDER BY employee_id, run_type = 'off_cycle', id
 	`, periodID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
+	return scanActivePayrolls(rows)
+}
+
+// activePayrolls returns the payrolls of the employees in the period inside tx, so callers
+// holding the period lock see the payslips a concurrent approval may have just stored.
+func activePayrolls(ctx context.Context, tx pgx.Tx, periodID int, employeeIDs []int) ([]domain.Payroll, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT `+activePayrollColumns+`
+		FROM payrolls
+		WHERE period_id = $1 AND employee_id = ANY($2) AND voided_at IS NULL
+	`, periodID, employeeIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanActivePayrolls(rows)
+}
+
+// replacedByRegularRun selects the payrolls a regular run paying the employees $2 replaces in
+// period $1: every regular payslip, and the off-cycle payslips of the employees it includes.
+const replacedByRegularRun = `period_id = $1 AND voided_at IS NULL AND (run_type = 'regular' OR employee_id = ANY($2))`
+
+// payrollsReplacedByRegularRun returns the payrolls a regular run paying the employees would
+// replace, see replacedByRegularRun.
+func payrollsReplacedByRegularRun(ctx context.Context, tx pgx.Tx, periodID int, employeeIDs []int) ([]domain.Payroll, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT `+activePayrollColumns+`
+		FROM payrolls
+		WHERE `+replacedByRegularRun, periodID, employeeIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanActivePayrolls(rows)
+}
 
+func scanActivePayrolls(rows pgx.Rows) ([]domain.Payroll, error) {
 	var payrolls []domain.Payroll
 	for rows.Next() {
 		var p domain.Payroll
 		var payslipData []byte
-		if err := rows.Scan(&p.ID, &p.EmployeeID, &p.PeriodID, &payslipData, &p.CreatedBy, &p.UpdatedBy); err != nil {
+		if err := rows.Scan(&p.ID, &p.EmployeeID, &p.PeriodID, &p.RunID, &p.RunType, &payslipData, &p.CreatedBy, &p.UpdatedBy); err != nil {
 			return nil, err
 		}
 		if err := json.Unmarshal(payslipData, &p.Payslip); err != nil {
@@ -237,21 +298,117 @@ func (r *PayrollRepository) GetPayrollsByPeriodID(ctx context.Context, periodID
 		}
 		payrolls = append(payrolls, p)
 	}
-	return payrolls, nil
+	return payrolls, rows.Err()
 }
 
-func (r *PayrollRepository) LockPayrollPeriod(ctx context.Context, periodID int) error {
-	if periodID == 0 {
+func (r *PayrollRepository) SetPayrollPeriodRuleSetVersion(ctx context.Context, period domain.PayrollPeriod) error {
+	if period.ID == 0 || period.RuleSetVersion == 0 {
 		return error_const.ErrInvalidID
 	}
 
 	_, err := r.pool.Exec(ctx, `
 		UPDATE payroll_periods
-		SET locked = true, updated_at = NOW()
-		WHERE id = $1
-	`, periodID)
+		SET rule_set_version = $2, updated_at = NOW(), updated_by = $3
+		WHERE id = $1 AND locked = false
+	`, period.ID, period.RuleSetVersion, period.UpdatedBy)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// ReopenPayrollPeriod unlocks a locked period, queues the job re-running it and records the
+// reopen and its audit log in one transaction, so a reopened period always has its re-run. The
+// stored payrolls stay active until the re-run is approved, which voids them and records the
+// diff on the reopen, see ApprovePayrollRun; the period's year-to-date totals are withdrawn
+// until then.
+func (r *PayrollRepository) ReopenPayrollPeriod(ctx context.Context, reopen domain.PayrollReopen, job domain.PayrollJob, audit domain.AuditLog) (domain.PayrollReopen, error) {
+	if reopen.PeriodID == 0 || job.PeriodID != reopen.PeriodID {
+		return domain.PayrollReopen{}, error_const.ErrInvalidID
+	}
+	if reopen.CreatedBy == "" || reopen.UpdatedBy == "" || job.CreatedBy == "" || job.UpdatedBy == "" {
+		return domain.PayrollReopen{}, error_const.ErrInvalidUser
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.PayrollReopen{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	// the row lock serialises concurrent reopens and runs of the same period
+	var locked bool
+	err = tx.QueryRow(ctx, `SELECT locked FROM payroll_periods WHERE id = $1 FOR UPDATE`, reopen.PeriodID).Scan(&locked)
+	if err != nil {
+		return domain.PayrollReopen{}, err
+	}
+	if !locked {
+		return domain.PayrollReopen{}, error_const.ErrPayrollPeriodNotLocked
+	}
+
+	job, err = insertPayrollJob(ctx, tx, job)
+	if err != nil {
+		return domain.PayrollReopen{}, err
+	}
+	reopen.JobID = job.ID
+	reopen.Diff = []domain.PayslipDiff{}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO payroll_period_reopens (period_id, reason, job_id, created_at, updated_at, created_by, updated_by)
+		VALUES ($1, $2, $3, NOW(), NOW(), $4, $5)
+		RETURNING id, created_at, updated_at`,
+		reopen.PeriodID, reopen.Reason, reopen.JobID, reopen.CreatedBy, reopen.UpdatedBy,
+	).Scan(&reopen.ID, &reopen.CreatedAt, &reopen.UpdatedAt)
+	if err != nil {
+		return domain.PayrollReopen{}, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE payroll_periods
+		SET locked = false, updated_at = NOW(), updated_by = $2
+		WHERE id = $1
+	`, reopen.PeriodID, reopen.UpdatedBy)
+	if err != nil {
+		return domain.PayrollReopen{}, err
+	}
+	if err := syncYearToDate(ctx, tx, reopen.PeriodID); err != nil {
+		return domain.PayrollReopen{}, err
+	}
+
+	audit.Details = reopen // the reopen id, reason and re-run job
+	if err := insertAuditLog(ctx, tx, audit); err != nil {
+		return domain.PayrollReopen{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.PayrollReopen{}, err
+	}
+	return reopen, nil
+}
+
+func (r *PayrollRepository) GetPayrollReopensByPeriodID(ctx context.Context, periodID int) ([]domain.PayrollReopen, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, period_id, reason, COALESCE(job_id, 0), run_id, voided_payrolls, diff, created_at, updated_at, created_by, updated_by
+		FROM payroll_period_reopens
+		WHERE period_id = $1
+		ORDER BY id
+	`, periodID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reopens := []domain.PayrollReopen{}
+	for rows.Next() {
+		var reopen domain.PayrollReopen
+		var diff []byte
+		if err := rows.Scan(&reopen.ID, &reopen.PeriodID, &reopen.Reason, &reopen.JobID, &reopen.RunID, &reopen.VoidedPayrolls, &diff,
+			&reopen.CreatedAt, &reopen.UpdatedAt, &reopen.CreatedBy, &reopen.UpdatedBy); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(diff, &reopen.Diff); err != nil {
+			return nil, err
+		}
+		reopens = append(reopens, reopen)
+	}
+	return reopens, rows.Err()
+}
@@ -0,0 +1,265 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PayrollJobRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPayrollJobRepository(pool *pgxpool.Pool) *PayrollJobRepository {
+	return &PayrollJobRepository{
+		pool: pool,
+	}
+}
+
+const payrollJobColumns = `id, period_id, run_type, employee_ids, reason, run_id, status, total_employees, processed_employees, errors, attempts,
+	started_at, finished_at, created_at, updated_at, created_by, updated_by`
+
+func scanPayrollJob(row pgx.Row) (domain.PayrollJob, error) {
+	var job domain.PayrollJob
+	var jobErrors []byte
+	err := row.Scan(
+		&job.ID,
+		&job.PeriodID,
+		&job.RunType,
+		&job.EmployeeIDs,
+		&job.Reason,
+		&job.RunID,
+		&job.Status,
+		&job.TotalEmployees,
+		&job.ProcessedEmployees,
+		&jobErrors,
+		&job.Attempts,
+		&job.StartedAt,
+		&job.FinishedAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.CreatedBy,
+		&job.UpdatedBy,
+	)
+	if err != nil {
+		return domain.PayrollJob{}, err
+	}
+	if err := json.Unmarshal(jobErrors, &job.Errors); err != nil {
+		return domain.PayrollJob{}, err
+	}
+	return job, nil
+}
+
+func (r *PayrollJobRepository) CreatePayrollJob(ctx context.Context, job domain.PayrollJob) (domain.PayrollJob, error) {
+	if job.PeriodID == 0 {
+		return domain.PayrollJob{}, error_const.ErrInvalidID
+	}
+	if job.CreatedBy == "" || job.UpdatedBy == "" {
+		return domain.PayrollJob{}, error_const.ErrInvalidUser
+	}
+	return insertPayrollJob(ctx, r.pool, job)
+}
+
+func insertPayrollJob(ctx context.Context, db interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}, job domain.PayrollJob) (domain.PayrollJob, error) {
+	runType := job.RunType
+	if runType == "" {
+		runType = domain.PayrollRunRegular
+	}
+	employeeIDs := job.EmployeeIDs
+	if employeeIDs == nil {
+		employeeIDs = []int{}
+	}
+	return scanPayrollJob(db.QueryRow(ctx, `
+		INSERT INTO payroll_jobs (period_id, run_type, employee_ids, reason, status, created_at, updated_at, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, 'queued', NOW(), NOW(), $5, $6)
+		RETURNING `+payrollJobColumns, job.PeriodID, runType, employeeIDs, job.Reason, job.CreatedBy, job.UpdatedBy))
+}
+
+func (r *PayrollJobRepository) GetPayrollJob(ctx context.Context, jobID int) (domain.PayrollJob, error) {
+	return scanPayrollJob(r.pool.QueryRow(ctx, `
+		SELECT `+payrollJobColumns+`
+		FROM payroll_jobs
+		WHERE id = $1`, jobID))
+}
+
+func (r *PayrollJobRepository) GetPayrollJobsByPeriodID(ctx context.Context, periodID int) ([]domain.PayrollJob, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+payrollJobColumns+`
+		FROM payroll_jobs
+		WHERE period_id = $1
+		ORDER BY id DESC`, periodID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []domain.PayrollJob{}
+	for rows.Next() {
+		job, err := scanPayrollJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// ClaimNextPayrollJob marks the oldest queued job as running and returns it.
+// SKIP LOCKED lets several server instances poll the same table. Returns pgx.ErrNoRows when the queue is empty.
+func (r *PayrollJobRepository) ClaimNextPayrollJob(ctx context.Context) (domain.PayrollJob, error) {
+	return scanPayrollJob(r.pool.QueryRow(ctx, `
+		UPDATE payroll_jobs
+		SET status = 'running', attempts = attempts + 1, started_at = NOW(), updated_at = NOW(),
+			processed_employees = 0, errors = '[]'::jsonb
+		WHERE id = (
+			SELECT id FROM payroll_jobs
+			WHERE status = 'queued'
+			ORDER BY id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING `+payrollJobColumns))
+}
+
+// RequeueStalePayrollJobs puts running jobs that have not reported progress for staleAfter
+// back in the queue. These are jobs whose server stopped while running them.
+func (r *PayrollJobRepository) RequeueStalePayrollJobs(ctx context.Context, staleAfter time.Duration) (int, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE payroll_jobs
+		SET status = 'queued', updated_at = NOW()
+		WHERE status = 'running' AND updated_at < NOW() - make_interval(secs => $1)
+	`, staleAfter.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// UpdatePayrollJobProgress stores the progress counts, which also acts as the job's heartbeat.
+func (r *PayrollJobRepository) UpdatePayrollJobProgress(ctx context.Context, job domain.PayrollJob) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE payroll_jobs
+		SET total_employees = $2, processed_employees = $3, updated_at = NOW()
+		WHERE id = $1 AND status = 'running'
+	`, job.ID, job.TotalEmployees, job.ProcessedEmployees)
+	return err
+}
+
+func (r *PayrollJobRepository) FailPayrollJob(ctx context.Context, job domain.PayrollJob) error {
+	jobErrors, err := json.Marshal(job.Errors)
+	if err != nil {
+		return err
+	}
+	_, err = r.pool.Exec(ctx, `
+		UPDATE payroll_jobs
+		SET status = 'failed', total_employees = $2, processed_employees = $3, errors = $4,
+			finished_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'running'
+	`, job.ID, job.TotalEmployees, job.ProcessedEmployees, jobErrors)
+	return err
+}
+
+// CompletePayrollJob records the payroll run and marks the job as succeeded in one transaction,
+// so a job interrupted at any point can safely run again. The run keeps its payrolls until
+// another admin approves it, see ApprovePayrollRun. Regular runs cannot complete once the
+// period is locked; off-cycle runs may. Under the period lock, runs record the diff against
+// the payslips they replace or add to, and off-cycle runs turn the payslips of employees
+// already paid in the period into corrections that add to what was paid.
+func (r *PayrollJobRepository) CompletePayrollJob(ctx context.Context, job domain.PayrollJob, run domain.PayrollRun, payrolls []domain.Payroll) (domain.PayrollRun, error) {
+	if len(payrolls) == 0 {
+		return domain.PayrollRun{}, error_const.ErrInvalidInput
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.PayrollRun{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	err = tx.QueryRow(ctx, `SELECT locked FROM payroll_periods WHERE id = $1 FOR UPDATE`, job.PeriodID).Scan(&locked)
+	if err != nil {
+		return domain.PayrollRun{}, err
+	}
+	if locked && !job.IsOffCycle() {
+		return domain.PayrollRun{}, error_const.ErrPayrollPeriodLocked
+	}
+
+	if !job.IsOffCycle() {
+		replaced, err := payrollsReplacedByRegularRun(ctx, tx, job.PeriodID, employeeIDsOf(payrolls))
+		if err != nil {
+			return domain.PayrollRun{}, err
+		}
+		if len(replaced) > 0 {
+			run.Diff = domain.DiffPayrolls(replaced, payrolls)
+		}
+	} else {
+		current, err := activePayrolls(ctx, tx, job.PeriodID, job.EmployeeIDs)
+		if err != nil {
+			return domain.PayrollRun{}, err
+		}
+		run.Diff = domain.DiffPayrolls(current, payrolls)
+		paid := make(map[int][]domain.Payslip)
+		for _, payroll := range current {
+			paid[payroll.EmployeeID] = append(paid[payroll.EmployeeID], payroll.Payslip)
+		}
+		for i, payroll := range payrolls {
+			if payslips, ok := paid[payroll.EmployeeID]; ok {
+				payrolls[i].Payslip = domain.PayslipCorrection(payslips, payroll.Payslip)
+			}
+		}
+	}
+
+	run.Status = domain.PayrollRunPendingApproval
+	diff, err := json.Marshal(run.Diff)
+	if err != nil {
+		return domain.PayrollRun{}, err
+	}
+	pendingPayrolls, err := json.Marshal(payrolls)
+	if err != nil {
+		return domain.PayrollRun{}, err
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO payroll_runs (period_id, run_type, status, reason, employee_count, diff, pending_payrolls, created_at, updated_at, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW(), $8, $9)
+		RETURNING id, created_at, updated_at`,
+		job.PeriodID, run.RunType, run.Status, run.Reason, len(payrolls), diff, pendingPayrolls, run.CreatedBy, run.UpdatedBy,
+	).Scan(&run.ID, &run.CreatedAt, &run.UpdatedAt)
+	if err != nil {
+		return domain.PayrollRun{}, err
+	}
+	run.PeriodID = job.PeriodID
+	run.EmployeeCount = len(payrolls)
+	transition, err := insertPayrollRunTransition(ctx, tx, domain.PayrollRunTransition{
+		RunID:     run.ID,
+		ToStatus:  run.Status,
+		Comment:   run.Reason,
+		CreatedBy: run.CreatedBy,
+	})
+	if err != nil {
+		return domain.PayrollRun{}, err
+	}
+	run.Transitions = []domain.PayrollRunTransition{transition}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE payroll_jobs
+		SET status = 'succeeded', run_id = $2, total_employees = $3, processed_employees = $4, errors = '[]'::jsonb,
+			finished_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`, job.ID, run.ID, job.TotalEmployees, job.ProcessedEmployees)
+	if err != nil {
+		return domain.PayrollRun{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.PayrollRun{}, err
+	}
+	return run, nil
+}
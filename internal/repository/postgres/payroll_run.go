@@ -0,0 +1,258 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const payrollRunColumns = `id, period_id, run_type, status, reason, employee_count, diff, created_at, updated_at, created_by, updated_by`
+
+// scanPayrollRunWithPayrolls reads payrollRunColumns followed by pending_payrolls.
+func scanPayrollRunWithPayrolls(row pgx.Row) (domain.PayrollRun, error) {
+	var pending []byte
+	run, err := scanPayrollRun(row, &pending)
+	if err != nil {
+		return domain.PayrollRun{}, err
+	}
+	if err := json.Unmarshal(pending, &run.Payrolls); err != nil {
+		return domain.PayrollRun{}, err
+	}
+	return run, nil
+}
+
+// scanPayrollRun reads payrollRunColumns, followed by the columns scanned into extra.
+func scanPayrollRun(row pgx.Row, extra ...any) (domain.PayrollRun, error) {
+	var run domain.PayrollRun
+	var diff []byte
+	dest := []any{
+		&run.ID,
+		&run.PeriodID,
+		&run.RunType,
+		&run.Status,
+		&run.Reason,
+		&run.EmployeeCount,
+		&diff,
+		&run.CreatedAt,
+		&run.UpdatedAt,
+		&run.CreatedBy,
+		&run.UpdatedBy,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return domain.PayrollRun{}, err
+	}
+	if err := json.Unmarshal(diff, &run.Diff); err != nil {
+		return domain.PayrollRun{}, err
+	}
+	return run, nil
+}
+
+func insertPayrollRunTransition(ctx context.Context, tx pgx.Tx, transition domain.PayrollRunTransition) (domain.PayrollRunTransition, error) {
+	err := tx.QueryRow(ctx, `
+		INSERT INTO payroll_run_transitions (run_id, from_status, to_status, comment, created_at, created_by)
+		VALUES ($1, $2, $3, $4, NOW(), $5)
+		RETURNING id, created_at`,
+		transition.RunID, transition.FromStatus, transition.ToStatus, transition.Comment, transition.CreatedBy,
+	).Scan(&transition.ID, &transition.CreatedAt)
+	return transition, err
+}
+
+func (r *PayrollRepository) GetPayrollRunsByPeriodID(ctx context.Context, periodID int) ([]domain.PayrollRun, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+payrollRunColumns+`
+		FROM payroll_runs
+		WHERE period_id = $1
+		ORDER BY id
+	`, periodID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []domain.PayrollRun{}
+	for rows.Next() {
+		run, err := scanPayrollRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// GetPayrollRun returns the run with the payrolls waiting for its approval and its transitions,
+// read in one read-only transaction so they match even while the run is being decided.
+func (r *PayrollRepository) GetPayrollRun(ctx context.Context, runID int) (domain.PayrollRun, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return domain.PayrollRun{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	run, err := scanPayrollRunWithPayrolls(tx.QueryRow(ctx, `
+		SELECT `+payrollRunColumns+`, pending_payrolls
+		FROM payroll_runs
+		WHERE id = $1`, runID))
+	if err != nil {
+		return domain.PayrollRun{}, err
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, run_id, from_status, to_status, comment, created_at, created_by
+		FROM payroll_run_transitions
+		WHERE run_id = $1
+		ORDER BY id
+	`, runID)
+	if err != nil {
+		return domain.PayrollRun{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var transition domain.PayrollRunTransition
+		if err := rows.Scan(&transition.ID, &transition.RunID, &transition.FromStatus, &transition.ToStatus,
+			&transition.Comment, &transition.CreatedAt, &transition.CreatedBy); err != nil {
+			return domain.PayrollRun{}, err
+		}
+		run.Transitions = append(run.Transitions, transition)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.PayrollRun{}, err
+	}
+	return run, tx.Commit(ctx)
+}
+
+// ApprovePayrollRun stores the payrolls of a run waiting for approval and records the loan
+// repayments and year-to-date totals, all in one transaction. Regular runs lock their period
+// and void the payslips they replace when the period was reopened, linking them to the latest
+// reopen, which records the run, how many payslips it voided and its per-employee diff.
+// Off-cycle runs leave the period as it is: their payslips add to the employees' regular
+// ones, and repayments and totals are recorded at once when it is already locked.
+func (r *PayrollRepository) ApprovePayrollRun(ctx context.Context, runID int, transition domain.PayrollRunTransition) (domain.PayrollRun, error) {
+	transition.ToStatus = domain.PayrollRunApproved
+	return r.decidePayrollRun(ctx, runID, transition, func(tx pgx.Tx, run domain.PayrollRun, payrolls []domain.Payroll) error {
+		var locked bool
+		err := tx.QueryRow(ctx, `SELECT locked FROM payroll_periods WHERE id = $1 FOR UPDATE`, run.PeriodID).Scan(&locked)
+		if err != nil {
+			return err
+		}
+		if locked && !run.IsOffCycle() {
+			return error_const.ErrPayrollPeriodLocked
+		}
+		if !run.IsOffCycle() {
+			var reopenID *int
+			err = tx.QueryRow(ctx, `SELECT MAX(id) FROM payroll_period_reopens WHERE period_id = $1`, run.PeriodID).Scan(&reopenID)
+			if err != nil {
+				return err
+			}
+			tag, err := tx.Exec(ctx, `
+				UPDATE payrolls
+				SET voided_at = NOW(), voided_by = $3, reopen_id = $4, updated_at = NOW(), updated_by = $3
+				WHERE `+replacedByRegularRun, run.PeriodID, employeeIDsOf(payrolls), transition.CreatedBy, reopenID)
+			if err != nil {
+				return err
+			}
+			if reopenID != nil {
+				diff, err := json.Marshal(run.Diff)
+				if err != nil {
+					return err
+				}
+				_, err = tx.Exec(ctx, `
+					UPDATE payroll_period_reopens
+					SET voided_payrolls = $2, run_id = $3, diff = $4, updated_at = NOW(), updated_by = $5
+					WHERE id = $1
+				`, *reopenID, tag.RowsAffected(), run.ID, diff, transition.CreatedBy)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		for i := range payrolls {
+			payrolls[i].RunID = run.ID
+			payrolls[i].RunType = run.RunType
+		}
+		if err := copyPayrolls(ctx, tx, payrolls); err != nil {
+			return err
+		}
+		if run.IsOffCycle() {
+			if !locked {
+				return nil
+			}
+		} else {
+			_, err = tx.Exec(ctx, `
+				UPDATE payroll_periods
+				SET locked = true, updated_at = NOW(), updated_by = $2
+				WHERE id = $1
+			`, run.PeriodID, transition.CreatedBy)
+			if err != nil {
+				return err
+			}
+		}
+		if err := syncLoanRepayments(ctx, tx, run.PeriodID); err != nil {
+			return err
+		}
+		return syncYearToDate(ctx, tx, run.PeriodID)
+	})
+}
+
+// RejectPayrollRun discards the payrolls of a run waiting for approval; the period stays open.
+func (r *PayrollRepository) RejectPayrollRun(ctx context.Context, runID int, transition domain.PayrollRunTransition) (domain.PayrollRun, error) {
+	transition.ToStatus = domain.PayrollRunRejected
+	return r.decidePayrollRun(ctx, runID, transition, func(tx pgx.Tx, run domain.PayrollRun, payrolls []domain.Payroll) error {
+		return nil
+	})
+}
+
+// decidePayrollRun moves a run waiting for approval to the transition's status after apply,
+// clears its pending payrolls and records the transition.
+func (r *PayrollRepository) decidePayrollRun(ctx context.Context, runID int, transition domain.PayrollRunTransition,
+	apply func(tx pgx.Tx, run domain.PayrollRun, payrolls []domain.Payroll) error) (domain.PayrollRun, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.PayrollRun{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	run, err := scanPayrollRunWithPayrolls(tx.QueryRow(ctx, `
+		SELECT `+payrollRunColumns+`, pending_payrolls
+		FROM payroll_runs
+		WHERE id = $1
+		FOR UPDATE`, runID))
+	if err != nil {
+		return domain.PayrollRun{}, err
+	}
+	if !run.IsPendingApproval() {
+		return domain.PayrollRun{}, error_const.ErrPayrollRunNotPending
+	}
+	payrolls := run.Payrolls
+	run.Payrolls = nil
+	if err := apply(tx, run, payrolls); err != nil {
+		return domain.PayrollRun{}, err
+	}
+
+	err = tx.QueryRow(ctx, `
+		UPDATE payroll_runs
+		SET status = $2, pending_payrolls = '[]'::jsonb, updated_at = NOW(), updated_by = $3
+		WHERE id = $1
+		RETURNING updated_at`, runID, transition.ToStatus, transition.CreatedBy,
+	).Scan(&run.UpdatedAt)
+	if err != nil {
+		return domain.PayrollRun{}, err
+	}
+	transition.RunID = runID
+	transition.FromStatus = run.Status
+	transition, err = insertPayrollRunTransition(ctx, tx, transition)
+	if err != nil {
+		return domain.PayrollRun{}, err
+	}
+	run.Status = transition.ToStatus
+	run.UpdatedBy = transition.CreatedBy
+	run.Transitions = []domain.PayrollRunTransition{transition}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.PayrollRun{}, err
+	}
+	return run, nil
+}
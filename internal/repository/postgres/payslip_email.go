@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PayslipEmailRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPayslipEmailRepository(pool *pgxpool.Pool) *PayslipEmailRepository {
+	return &PayslipEmailRepository{
+		pool: pool,
+	}
+}
+
+const payslipEmailColumns = `id, period_id, employee_id, recipient, protected, status, attempts, last_error, next_attempt_at, sent_at,
+	created_at, updated_at, created_by, updated_by`
+
+func scanPayslipEmail(row pgx.Row) (domain.PayslipEmail, error) {
+	var email domain.PayslipEmail
+	err := row.Scan(
+		&email.ID,
+		&email.PeriodID,
+		&email.EmployeeID,
+		&email.Recipient,
+		&email.Protected,
+		&email.Status,
+		&email.Attempts,
+		&email.LastError,
+		&email.NextAttemptAt,
+		&email.SentAt,
+		&email.CreatedAt,
+		&email.UpdatedAt,
+		&email.CreatedBy,
+		&email.UpdatedBy,
+	)
+	return email, err
+}
+
+func scanPayslipEmails(rows pgx.Rows) ([]domain.PayslipEmail, error) {
+	defer rows.Close()
+	emails := []domain.PayslipEmail{}
+	for rows.Next() {
+		email, err := scanPayslipEmail(rows)
+		if err != nil {
+			return nil, err
+		}
+		emails = append(emails, email)
+	}
+	return emails, rows.Err()
+}
+
+// EnqueuePayslipEmails queues the emails in one transaction and returns how many were added.
+// Employees that already have an email for the period are left alone, so distributing a
+// period twice does not send anyone their payslip twice.
+func (r *PayslipEmailRepository) EnqueuePayslipEmails(ctx context.Context, emails []domain.PayslipEmail) (int, error) {
+	if len(emails) == 0 {
+		return 0, nil
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	queued := 0
+	for _, email := range emails {
+		if email.CreatedBy == "" {
+			return 0, error_const.ErrInvalidUser
+		}
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO payslip_emails (period_id, employee_id, recipient, protected, status, next_attempt_at,
+				created_at, updated_at, created_by, updated_by)
+			VALUES ($1, $2, $3, $4, 'queued', NOW(), NOW(), NOW(), $5, $5)
+			ON CONFLICT (period_id, employee_id) DO NOTHING
+		`, email.PeriodID, email.EmployeeID, email.Recipient, email.Protected, email.CreatedBy)
+		if err != nil {
+			return 0, err
+		}
+		queued += int(tag.RowsAffected())
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return queued, nil
+}
+
+// ResendPayslipEmail queues the employee's payslip again with a fresh set of attempts, whatever
+// happened to the earlier delivery. Returns pgx.ErrNoRows while the email is being sent.
+func (r *PayslipEmailRepository) ResendPayslipEmail(ctx context.Context, email domain.PayslipEmail) (domain.PayslipEmail, error) {
+	if email.UpdatedBy == "" {
+		return domain.PayslipEmail{}, error_const.ErrInvalidUser
+	}
+	return scanPayslipEmail(r.pool.QueryRow(ctx, `
+		INSERT INTO payslip_emails (period_id, employee_id, recipient, protected, status, next_attempt_at,
+			created_at, updated_at, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, 'queued', NOW(), NOW(), NOW(), $5, $5)
+		ON CONFLICT (period_id, employee_id) DO UPDATE
+		SET recipient = EXCLUDED.recipient, protected = EXCLUDED.protected, status = 'queued', attempts = 0,
+			last_error = '', next_attempt_at = NOW(), sent_at = NULL, updated_at = NOW(), updated_by = EXCLUDED.updated_by
+		WHERE payslip_emails.status <> 'sending'
+		RETURNING `+payslipEmailColumns, email.PeriodID, email.EmployeeID, email.Recipient, email.Protected, email.UpdatedBy))
+}
+
+// GetPayslipEmailsByPeriodID lists the period's emails by employee, only those with the status
+// when it is not empty.
+func (r *PayslipEmailRepository) GetPayslipEmailsByPeriodID(ctx context.Context, periodID int, status string) ([]domain.PayslipEmail, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+payslipEmailColumns+`
+		FROM payslip_emails
+		WHERE period_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY employee_id`, periodID, status)
+	if err != nil {
+		return nil, err
+	}
+	return scanPayslipEmails(rows)
+}
+
+// ClaimDuePayslipEmails marks up to limit queued emails whose next attempt is due as sending
+// and returns them. SKIP LOCKED lets several server instances poll the same table.
+func (r *PayslipEmailRepository) ClaimDuePayslipEmails(ctx context.Context, limit int) ([]domain.PayslipEmail, error) {
+	rows, err := r.pool.Query(ctx, `
+		UPDATE payslip_emails
+		SET status = 'sending', attempts = attempts + 1, updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM payslip_emails
+			WHERE status = 'queued' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at, id
+			FOR UPDATE SKIP LOCKED
+			LIMIT $1
+		)
+		RETURNING `+payslipEmailColumns, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanPayslipEmails(rows)
+}
+
+// RequeueStalePayslipEmails puts emails that have been sending for staleAfter back in the
+// queue. Their server stopped mid-send, so the employee may receive the payslip twice.
+func (r *PayslipEmailRepository) RequeueStalePayslipEmails(ctx context.Context, staleAfter time.Duration) (int, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE payslip_emails
+		SET status = 'queued', next_attempt_at = NOW(), updated_at = NOW()
+		WHERE status = 'sending' AND updated_at < NOW() - make_interval(secs => $1)
+	`, staleAfter.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// UpdatePayslipEmailDelivery records the outcome of sending a claimed email.
+func (r *PayslipEmailRepository) UpdatePayslipEmailDelivery(ctx context.Context, email domain.PayslipEmail) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE payslip_emails
+		SET status = $2, last_error = $3, next_attempt_at = $4, sent_at = $5, updated_at = NOW()
+		WHERE id = $1 AND status = 'sending'
+	`, email.ID, email.Status, email.LastError, email.NextAttemptAt, email.SentAt)
+	return err
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"payroll-system/internal/utils"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PayslipPasswordRepository keeps the U entry of each payslip password as it is and the key
+// sealed with the server secret: the key opens every payslip encrypted for the password, so
+// the database alone must not give it away.
+type PayslipPasswordRepository struct {
+	pool   *pgxpool.Pool
+	sealer *utils.KeySealer
+}
+
+func NewPayslipPasswordRepository(pool *pgxpool.Pool, sealer *utils.KeySealer) *PayslipPasswordRepository {
+	return &PayslipPasswordRepository{
+		pool:   pool,
+		sealer: sealer,
+	}
+}
+
+// payslipKeyContext binds a sealed key to its employee, so it cannot be copied to another.
+func payslipKeyContext(employeeID int) []byte {
+	return []byte("payslip_password:" + strconv.Itoa(employeeID))
+}
+
+func (r *PayslipPasswordRepository) SetPayslipPassword(ctx context.Context, password domain.PayslipPassword) error {
+	if password.EmployeeID == 0 {
+		return error_const.ErrInvalidUser
+	}
+	sealedKey, err := r.sealer.Seal(password.Key, payslipKeyContext(password.EmployeeID))
+	if err != nil {
+		return err
+	}
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO payslip_passwords (employee_id, user_entry, sealed_key, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (employee_id) DO UPDATE
+		SET user_entry = EXCLUDED.user_entry, sealed_key = EXCLUDED.sealed_key, updated_at = NOW()
+	`, password.EmployeeID, password.User, sealedKey)
+	return err
+}
+
+func (r *PayslipPasswordRepository) GetPayslipPassword(ctx context.Context, employeeID int) (domain.PayslipPassword, error) {
+	if employeeID == 0 {
+		return domain.PayslipPassword{}, error_const.ErrInvalidUser
+	}
+	var password domain.PayslipPassword
+	var sealedKey []byte
+	err := r.pool.QueryRow(ctx, `
+		SELECT employee_id, user_entry, sealed_key, created_at, updated_at
+		FROM payslip_passwords
+		WHERE employee_id = $1
+	`, employeeID).Scan(&password.EmployeeID, &password.User, &sealedKey, &password.CreatedAt, &password.UpdatedAt)
+	if err != nil {
+		return domain.PayslipPassword{}, err
+	}
+	if password.Key, err = r.sealer.Open(sealedKey, payslipKeyContext(employeeID)); err != nil {
+		return domain.PayslipPassword{}, err
+	}
+	return password, nil
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PayslipTemplateRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPayslipTemplateRepository(pool *pgxpool.Pool) *PayslipTemplateRepository {
+	return &PayslipTemplateRepository{
+		pool: pool,
+	}
+}
+
+const payslipTemplateColumns = `id, company, version, name, language, body, created_at, created_by`
+
+func scanPayslipTemplate(row pgx.Row) (domain.PayslipTemplate, error) {
+	var t domain.PayslipTemplate
+	err := row.Scan(&t.ID, &t.Company, &t.Version, &t.Name, &t.Language, &t.Body, &t.CreatedAt, &t.CreatedBy)
+	if err != nil {
+		return domain.PayslipTemplate{}, err
+	}
+	return t, nil
+}
+
+// CreatePayslipTemplate stores the template as the company's next version. When another
+// version is saved at the same time ErrPayslipTemplateConflict is returned.
+func (r *PayslipTemplateRepository) CreatePayslipTemplate(ctx context.Context, template domain.PayslipTemplate) (domain.PayslipTemplate, error) {
+	if template.CreatedBy == "" {
+		return domain.PayslipTemplate{}, error_const.ErrInvalidUser
+	}
+	created, err := scanPayslipTemplate(r.pool.QueryRow(ctx, `
+		INSERT INTO payslip_templates (company, version, name, language, body, created_at, created_by)
+		SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3, $4, NOW(), $5
+		FROM payslip_templates
+		WHERE company = $1
+		RETURNING `+payslipTemplateColumns,
+		template.Company, template.Name, template.Language, template.Body, template.CreatedBy))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return domain.PayslipTemplate{}, error_const.ErrPayslipTemplateConflict
+	}
+	return created, err
+}
+
+// GetPayslipTemplates lists the versions of a company's template, newest first.
+func (r *PayslipTemplateRepository) GetPayslipTemplates(ctx context.Context, company string) ([]domain.PayslipTemplate, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+payslipTemplateColumns+`
+		FROM payslip_templates
+		WHERE company = $1
+		ORDER BY version DESC
+	`, company)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []domain.PayslipTemplate{}
+	for rows.Next() {
+		template, err := scanPayslipTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	return templates, rows.Err()
+}
+
+func (r *PayslipTemplateRepository) GetPayslipTemplate(ctx context.Context, company string, version int) (domain.PayslipTemplate, error) {
+	return scanPayslipTemplate(r.pool.QueryRow(ctx, `
+		SELECT `+payslipTemplateColumns+`
+		FROM payslip_templates
+		WHERE company = $1 AND version = $2`, company, version))
+}
+
+// GetLatestPayslipTemplate returns the version payslips of the company are rendered with, or
+// pgx.ErrNoRows when the company has no template.
+func (r *PayslipTemplateRepository) GetLatestPayslipTemplate(ctx context.Context, company string) (domain.PayslipTemplate, error) {
+	return scanPayslipTemplate(r.pool.QueryRow(ctx, `
+		SELECT `+payslipTemplateColumns+`
+		FROM payslip_templates
+		WHERE company = $1
+		ORDER BY version DESC
+		LIMIT 1`, company))
+}
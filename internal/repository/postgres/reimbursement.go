@@ -20,7 +20,7 @@ func NewReimbursementRepository(pool *pgxpool.Pool) *ReimbursementRepository {
 }
 
 func (r *ReimbursementRepository) SubmitReimbursement(ctx context.Context, payload domain.Reimbursement) error {
-	if payload.EmployeeID == 0 || payload.Amount <= 0 {
+	if payload.EmployeeID == 0 || !payload.Amount.IsPositive() {
 		return error_const.ErrInvalidUser // Return an error if employee ID or amount is invalid
 	}
 
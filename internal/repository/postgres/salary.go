@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SalaryRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewSalaryRepository(pool *pgxpool.Pool) *SalaryRepository {
+	return &SalaryRepository{
+		pool: pool,
+	}
+}
+
+const salaryChangeColumns = `id, employee_id, salary, effective_from, reason, created_at, created_by`
+
+func scanSalaryChange(row pgx.Row) (domain.SalaryChange, error) {
+	var c domain.SalaryChange
+	err := row.Scan(&c.ID, &c.EmployeeID, &c.Salary, &c.EffectiveFrom, &c.Reason, &c.CreatedAt, &c.CreatedBy)
+	if err != nil {
+		return domain.SalaryChange{}, err
+	}
+	return c, nil
+}
+
+// CreateSalaryChange appends a salary to the employee's history and refreshes employees.salary
+// with the salary effective today. Changes effective in or before a locked payroll period are
+// rejected, because they would alter payslips that were already paid. An employee without a
+// history first gets an opening entry with the current salary, so the earlier periods keep it.
+func (r *SalaryRepository) CreateSalaryChange(ctx context.Context, change domain.SalaryChange) (domain.SalaryChange, error) {
+	if change.EmployeeID == 0 || change.CreatedBy == "" {
+		return domain.SalaryChange{}, error_const.ErrInvalidUser
+	}
+	if change.EffectiveFrom.IsZero() {
+		return domain.SalaryChange{}, error_const.ErrInvalidDateFormat
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.SalaryChange{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	// FOR SHARE keeps the periods from being locked until this change is committed
+	rows, err := tx.Query(ctx, `
+		SELECT locked FROM payroll_periods WHERE end_date >= $1 FOR SHARE
+	`, change.EffectiveFrom)
+	if err != nil {
+		return domain.SalaryChange{}, err
+	}
+	lockedPeriod := false
+	for rows.Next() {
+		var locked bool
+		if err := rows.Scan(&locked); err != nil {
+			rows.Close()
+			return domain.SalaryChange{}, err
+		}
+		lockedPeriod = lockedPeriod || locked
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return domain.SalaryChange{}, err
+	}
+	if lockedPeriod {
+		return domain.SalaryChange{}, error_const.ErrSalaryChangeInLockedPeriod
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO employee_salaries (employee_id, salary, effective_from, reason, created_at, created_by)
+		SELECT id, salary, DATE '1900-01-01', 'opening salary', NOW(), $2
+		FROM employees
+		WHERE id = $1 AND salary > 0 AND NOT EXISTS (SELECT 1 FROM employee_salaries WHERE employee_id = $1)
+	`, change.EmployeeID, change.CreatedBy)
+	if err != nil {
+		return domain.SalaryChange{}, err
+	}
+	created, err := scanSalaryChange(tx.QueryRow(ctx, `
+		INSERT INTO employee_salaries (employee_id, salary, effective_from, reason, created_at, created_by)
+		VALUES ($1, $2, $3, $4, NOW(), $5)
+		RETURNING `+salaryChangeColumns,
+		change.EmployeeID, change.Salary, change.EffectiveFrom, change.Reason, change.CreatedBy))
+	if err != nil {
+		return domain.SalaryChange{}, err
+	}
+	_, err = tx.Exec(ctx, `
+		UPDATE employees
+		SET salary = current.salary, updated_at = NOW(), updated_by = $2
+		FROM (
+			SELECT salary FROM employee_salaries
+			WHERE employee_id = $1 AND effective_from <= CURRENT_DATE
+			ORDER BY effective_from DESC
+			LIMIT 1
+		) current
+		WHERE employees.id = $1
+	`, change.EmployeeID, change.CreatedBy)
+	if err != nil {
+		return domain.SalaryChange{}, err
+	}
+	return created, tx.Commit(ctx)
+}
+
+func (r *SalaryRepository) GetSalaryHistory(ctx context.Context, employeeID int) ([]domain.SalaryChange, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+salaryChangeColumns+`
+		FROM employee_salaries
+		WHERE employee_id = $1
+		ORDER BY effective_from
+	`, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []domain.SalaryChange{}
+	for rows.Next() {
+		change, err := scanSalaryChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, change)
+	}
+	return history, rows.Err()
+}
+
+// GetSalaryHistoriesGroupedByEmployeeID returns the salary changes effective on or before the date,
+// ordered by effective date.
+func (r *SalaryRepository) GetSalaryHistoriesGroupedByEmployeeID(ctx context.Context, until time.Time) (map[int][]domain.SalaryChange, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+salaryChangeColumns+`
+		FROM employee_salaries
+		WHERE effective_from <= $1
+		ORDER BY employee_id, effective_from
+	`, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[int][]domain.SalaryChange)
+	for rows.Next() {
+		change, err := scanSalaryChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		result[change.EmployeeID] = append(result[change.EmployeeID], change)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TaxProfileRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewTaxProfileRepository(pool *pgxpool.Pool) *TaxProfileRepository {
+	return &TaxProfileRepository{
+		pool: pool,
+	}
+}
+
+func (r *TaxProfileRepository) UpsertTaxProfile(ctx context.Context, profile domain.TaxProfile) error {
+	if profile.EmployeeID == 0 {
+		return error_const.ErrInvalidUser
+	}
+	if profile.CreatedBy == "" || profile.UpdatedBy == "" {
+		return error_const.ErrInvalidUser
+	}
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO employee_tax_profiles (employee_id, npwp, marital_status, dependents, created_at, updated_at, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, NOW(), NOW(), $5, $6)
+		ON CONFLICT (employee_id) DO UPDATE
+		SET npwp = EXCLUDED.npwp, marital_status = EXCLUDED.marital_status, dependents = EXCLUDED.dependents,
+			updated_at = NOW(), updated_by = EXCLUDED.updated_by
+	`, profile.EmployeeID, profile.NPWP, profile.MaritalStatus, profile.Dependents, profile.CreatedBy, profile.UpdatedBy)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *TaxProfileRepository) GetTaxProfile(ctx context.Context, employeeID int) (domain.TaxProfile, error) {
+	if employeeID == 0 {
+		return domain.TaxProfile{}, error_const.ErrInvalidUser
+	}
+	var profile domain.TaxProfile
+	err := r.pool.QueryRow(ctx, `
+		SELECT employee_id, npwp, marital_status, dependents, created_at, updated_at, created_by, updated_by
+		FROM employee_tax_profiles
+		WHERE employee_id = $1
+	`, employeeID).Scan(&profile.EmployeeID, &profile.NPWP, &profile.MaritalStatus, &profile.Dependents,
+		&profile.CreatedAt, &profile.UpdatedAt, &profile.CreatedBy, &profile.UpdatedBy)
+	if err != nil {
+		return domain.TaxProfile{}, err
+	}
+	return profile, nil
+}
+
+func (r *TaxProfileRepository) GetTaxProfilesGroupedByEmployeeID(ctx context.Context) (map[int]domain.TaxProfile, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT employee_id, npwp, marital_status, dependents, created_at, updated_at, created_by, updated_by
+		FROM employee_tax_profiles
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[int]domain.TaxProfile)
+	for rows.Next() {
+		var profile domain.TaxProfile
+		err := rows.Scan(&profile.EmployeeID, &profile.NPWP, &profile.MaritalStatus, &profile.Dependents,
+			&profile.CreatedAt, &profile.UpdatedAt, &profile.CreatedBy, &profile.UpdatedBy)
+		if err != nil {
+			return nil, err
+		}
+		result[profile.EmployeeID] = profile
+	}
+	return result, nil
+}
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TaxStatementRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewTaxStatementRepository(pool *pgxpool.Pool) *TaxStatementRepository {
+	return &TaxStatementRepository{
+		pool: pool,
+	}
+}
+
+const taxStatementColumns = `id, sequence, statement, generated_at, generated_by`
+
+// scanTaxStatement reads the stored statement and sets the columns kept outside it.
+func scanTaxStatement(row pgx.Row) (domain.TaxStatement, error) {
+	var id, sequence int
+	var data []byte
+	var generatedAt time.Time
+	var generatedBy string
+	if err := row.Scan(&id, &sequence, &data, &generatedAt, &generatedBy); err != nil {
+		return domain.TaxStatement{}, err
+	}
+	var statement domain.TaxStatement
+	if err := json.Unmarshal(data, &statement); err != nil {
+		return domain.TaxStatement{}, err
+	}
+	statement.ID, statement.Sequence = id, sequence
+	statement.Number = statement.FormNumber()
+	statement.GeneratedAt, statement.GeneratedBy = generatedAt, generatedBy
+	return statement, nil
+}
+
+// GetTaxYearPayslipsGroupedByEmployeeID returns the active regular payslips and the bonus
+// payslips of the locked periods ending in the tax year, oldest period first.
+func (r *TaxStatementRepository) GetTaxYearPayslipsGroupedByEmployeeID(ctx context.Context, taxYear int) (map[int][]domain.TaxYearPayslip, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT ytd.employee_id, ytd.period_id, pp.end_date, ytd.regular, ytd.payslip
+		FROM (
+			SELECT p.employee_id, p.period_id, p.payslip, TRUE AS regular, p.id
+			FROM payrolls p
+			WHERE p.voided_at IS NULL
+			UNION ALL
+			SELECT bp.employee_id, bp.period_id, bp.payslip, FALSE, bp.id
+			FROM bonus_payslips bp
+		) ytd
+		JOIN payroll_periods pp ON pp.id = ytd.period_id
+		WHERE pp.locked AND EXTRACT(YEAR FROM pp.end_date) = $1
+		ORDER BY ytd.employee_id, pp.end_date, ytd.regular DESC, ytd.id
+	`, taxYear)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[int][]domain.TaxYearPayslip)
+	for rows.Next() {
+		var employeeID int
+		var p domain.TaxYearPayslip
+		var payslipData []byte
+		if err := rows.Scan(&employeeID, &p.PeriodID, &p.PeriodEnd, &p.Regular, &payslipData); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(payslipData, &p.Payslip); err != nil {
+			return nil, err
+		}
+		result[employeeID] = append(result[employeeID], p)
+	}
+	return result, rows.Err()
+}
+
+// SaveTaxStatements stores the statements in one transaction. A statement replaces the one of
+// the same employee and tax year and keeps its sequence; new statements are numbered after
+// the last statement of the tax year.
+func (r *TaxStatementRepository) SaveTaxStatements(ctx context.Context, statements []domain.TaxStatement) ([]domain.TaxStatement, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// numbering reads the last sequence of the year, so concurrent generations take turns
+	if _, err := tx.Exec(ctx, `LOCK TABLE tax_statements IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return nil, err
+	}
+	saved := make([]domain.TaxStatement, 0, len(statements))
+	for _, statement := range statements {
+		if statement.EmployeeID == 0 || statement.GeneratedBy == "" {
+			return nil, error_const.ErrInvalidUser
+		}
+		data, err := json.Marshal(statement)
+		if err != nil {
+			return nil, err
+		}
+		stored, err := scanTaxStatement(tx.QueryRow(ctx, `
+			INSERT INTO tax_statements (employee_id, tax_year, sequence, statement, generated_at, generated_by)
+			VALUES ($1, $2, (SELECT COALESCE(MAX(sequence), 0) + 1 FROM tax_statements WHERE tax_year = $2), $3, NOW(), $4)
+			ON CONFLICT (employee_id, tax_year) DO UPDATE
+			SET statement = EXCLUDED.statement, generated_at = NOW(), generated_by = EXCLUDED.generated_by
+			RETURNING `+taxStatementColumns,
+			statement.EmployeeID, statement.TaxYear, data, statement.GeneratedBy))
+		if err != nil {
+			return nil, err
+		}
+		saved = append(saved, stored)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+func (r *TaxStatementRepository) GetTaxStatement(ctx context.Context, employeeID, taxYear int) (domain.TaxStatement, error) {
+	return scanTaxStatement(r.pool.QueryRow(ctx, `
+		SELECT `+taxStatementColumns+`
+		FROM tax_statements
+		WHERE employee_id = $1 AND tax_year = $2
+	`, employeeID, taxYear))
+}
+
+func (r *TaxStatementRepository) GetTaxStatementsByTaxYear(ctx context.Context, taxYear int) ([]domain.TaxStatement, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+taxStatementColumns+`
+		FROM tax_statements
+		WHERE tax_year = $1
+		ORDER BY sequence
+	`, taxYear)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	statements := []domain.TaxStatement{}
+	for rows.Next() {
+		statement, err := scanTaxStatement(rows)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, statement)
+	}
+	return statements, rows.Err()
+}
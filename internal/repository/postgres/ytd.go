@@ -0,0 +1,243 @@
+package postgres
+
+import (
+	"context"
+	"payroll-system/internal/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// yearToDateFrom joins the active regular payslips and the bonus payslips, aliased ytd, to
+// their period (pp) and the totals of their BPJS contributions (b).
+const yearToDateFrom = `
+	FROM (
+		SELECT p.employee_id, p.period_id, p.payslip, TRUE AS regular
+		FROM payrolls p
+		WHERE p.voided_at IS NULL
+		UNION ALL
+		SELECT bp.employee_id, bp.period_id, bp.payslip, FALSE
+		FROM bonus_payslips bp
+	) ytd
+	JOIN payroll_periods pp ON pp.id = ytd.period_id
+	CROSS JOIN LATERAL (
+		SELECT SUM((c->>'employee_amount')::numeric) AS employee,
+			SUM((c->>'employer_amount')::numeric) AS employer,
+			SUM((c->>'employee_amount')::numeric) FILTER (WHERE c->>'program' IN ('JHT', 'JP')) AS pension
+		FROM jsonb_array_elements(CASE WHEN jsonb_typeof(ytd.payslip->'bpjs') = 'array' THEN ytd.payslip->'bpjs' ELSE '[]'::jsonb END) c
+	) b`
+
+// yearToDateSums are the year-to-date columns after the employee and tax year, in the order
+// scanTaxYearToDate reads them. THR and bonus payslips add to the totals but not to the months.
+const yearToDateSums = `
+	COUNT(DISTINCT ytd.period_id) FILTER (WHERE ytd.regular),
+	COALESCE(SUM((ytd.payslip->>'total_salary')::numeric), 0),
+	COALESCE(SUM((ytd.payslip->>'taxable_income')::numeric), 0),
+	COALESCE(SUM((ytd.payslip->'tax'->>'amount')::numeric), 0),
+	COALESCE(SUM(b.employee), 0),
+	COALESCE(SUM(b.employer), 0),
+	COALESCE(SUM(b.pension), 0),
+	COALESCE(SUM((ytd.payslip->>'net_salary')::numeric), 0)`
+
+func scanTaxYearToDate(row pgx.Row) (domain.TaxYearToDate, error) {
+	var ytd domain.TaxYearToDate
+	err := row.Scan(&ytd.EmployeeID, &ytd.TaxYear, &ytd.Months, &ytd.Gross, &ytd.TaxableIncome, &ytd.TaxWithheld,
+		&ytd.BPJSEmployee, &ytd.BPJSEmployer, &ytd.Pension, &ytd.Net)
+	if err != nil {
+		return domain.TaxYearToDate{}, err
+	}
+	return ytd, nil
+}
+
+func scanTaxYearToDates(rows pgx.Rows) ([]domain.TaxYearToDate, error) {
+	result := []domain.TaxYearToDate{}
+	for rows.Next() {
+		ytd, err := scanTaxYearToDate(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ytd)
+	}
+	return result, rows.Err()
+}
+
+// GetTaxYearToDateGroupedByEmployeeID sums the payslips in periods that end in the tax year
+// before the given date. Unlike the stored accumulators it also counts periods that are not
+// locked yet and leaves out later periods, so a reopened period is taxed on what came before it.
+func (r *PayrollRepository) GetTaxYearToDateGroupedByEmployeeID(ctx context.Context, taxYear int, before time.Time) (map[int]domain.TaxYearToDate, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT ytd.employee_id, $1::int, `+yearToDateSums+`
+		`+yearToDateFrom+`
+		WHERE EXTRACT(YEAR FROM pp.end_date) = $1 AND pp.end_date < $2
+		GROUP BY ytd.employee_id
+	`, taxYear, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals, err := scanTaxYearToDates(rows)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int]domain.TaxYearToDate, len(totals))
+	for _, ytd := range totals {
+		result[ytd.EmployeeID] = ytd
+	}
+	return result, nil
+}
+
+const payrollYTDColumns = `employee_id, tax_year, months, gross, taxable_income, tax_withheld,
+	bpjs_employee, bpjs_employer, pension, net`
+
+// GetYearToDate returns the accumulators of an employee for a tax year.
+func (r *PayrollRepository) GetYearToDate(ctx context.Context, employeeID, taxYear int) (domain.TaxYearToDate, error) {
+	return scanTaxYearToDate(r.pool.QueryRow(ctx, `
+		SELECT `+payrollYTDColumns+`
+		FROM payroll_ytd
+		WHERE employee_id = $1 AND tax_year = $2
+	`, employeeID, taxYear))
+}
+
+// GetYearToDateByTaxYear returns the accumulators of every employee paid in the tax year.
+func (r *PayrollRepository) GetYearToDateByTaxYear(ctx context.Context, taxYear int) ([]domain.TaxYearToDate, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+payrollYTDColumns+`
+		FROM payroll_ytd
+		WHERE tax_year = $1
+		ORDER BY employee_id
+	`, taxYear)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanTaxYearToDates(rows)
+}
+
+// RebuildYearToDate recalculates the accumulators of a tax year, or of every tax year when
+// taxYear is 0, from the stored payslips and returns how many it stored.
+func (r *PayrollRepository) RebuildYearToDate(ctx context.Context, taxYear int) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	taxYears := []int{taxYear}
+	if taxYear == 0 {
+		rows, err := tx.Query(ctx, `
+			SELECT EXTRACT(YEAR FROM end_date)::int FROM payroll_periods
+			UNION
+			SELECT tax_year FROM payroll_ytd
+			ORDER BY 1
+		`)
+		if err != nil {
+			return 0, err
+		}
+		taxYears, err = scanInts(rows)
+		if err != nil {
+			return 0, err
+		}
+	}
+	count := 0
+	for _, year := range taxYears {
+		stored, err := rebuildYearToDate(ctx, tx, year, nil)
+		if err != nil {
+			return 0, err
+		}
+		count += stored
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// syncYearToDate rebuilds the accumulators of the employees with payslips in a period for its
+// tax year. It runs in the transactions that change the payslips of a locked period or its lock,
+// so the accumulators always match the payslips of the locked periods.
+func syncYearToDate(ctx context.Context, tx pgx.Tx, periodID int) error {
+	var taxYear int
+	err := tx.QueryRow(ctx, `SELECT EXTRACT(YEAR FROM end_date)::int FROM payroll_periods WHERE id = $1`, periodID).Scan(&taxYear)
+	if err != nil {
+		return err
+	}
+	// voided payslips count too, since the accumulators must drop them
+	rows, err := tx.Query(ctx, `
+		SELECT employee_id FROM payrolls WHERE period_id = $1
+		UNION
+		SELECT employee_id FROM bonus_payslips WHERE period_id = $1
+		ORDER BY 1
+	`, periodID)
+	if err != nil {
+		return err
+	}
+	employeeIDs, err := scanInts(rows)
+	if err != nil {
+		return err
+	}
+	if len(employeeIDs) == 0 {
+		return nil
+	}
+	_, err = rebuildYearToDate(ctx, tx, taxYear, employeeIDs)
+	return err
+}
+
+// rebuildYearToDate recalculates the accumulators of the given employees for a tax year, or of
+// every employee when employeeIDs is nil. Transaction advisory locks serialise rebuilds of the
+// same accumulators, which would otherwise insert the same keys concurrently, without blocking
+// rebuilds of other years or employees: a whole year takes its year exclusively, employees
+// take the year shared and themselves exclusively, in ascending order so rebuilds cannot
+// deadlock. The locks use the two-key form, namespaced by hashtext, so they cannot collide with
+// other advisory locks in the database.
+func rebuildYearToDate(ctx context.Context, tx pgx.Tx, taxYear int, employeeIDs []int) (int, error) {
+	if employeeIDs == nil {
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('payroll_ytd'), $1)`, taxYear); err != nil {
+			return 0, err
+		}
+	} else {
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock_shared(hashtext('payroll_ytd'), $1)`, taxYear); err != nil {
+			return 0, err
+		}
+		_, err := tx.Exec(ctx, `
+			SELECT pg_advisory_xact_lock(hashtext('payroll_ytd_employee:' || $1::int), e.id)
+			FROM (SELECT DISTINCT id FROM unnest($2::int[]) AS id ORDER BY id) e
+		`, taxYear, employeeIDs)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	_, err := tx.Exec(ctx, `
+		DELETE FROM payroll_ytd
+		WHERE tax_year = $1 AND ($2::int[] IS NULL OR employee_id = ANY($2))
+	`, taxYear, employeeIDs)
+	if err != nil {
+		return 0, err
+	}
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO payroll_ytd (`+payrollYTDColumns+`, updated_at)
+		SELECT ytd.employee_id, $1::int, `+yearToDateSums+`, NOW()
+		`+yearToDateFrom+`
+		WHERE pp.locked AND EXTRACT(YEAR FROM pp.end_date) = $1
+			AND ($2::int[] IS NULL OR ytd.employee_id = ANY($2))
+		GROUP BY ytd.employee_id
+	`, taxYear, employeeIDs)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func scanInts(rows pgx.Rows) ([]int, error) {
+	defer rows.Close()
+	var result []int
+	for rows.Next() {
+		var n int
+		if err := rows.Scan(&n); err != nil {
+			return nil, err
+		}
+		result = append(result, n)
+	}
+	return result, rows.Err()
+}
@@ -7,10 +7,13 @@ import (
 	"payroll-system/internal/delivery/dto"
 	domain "payroll-system/internal/domain"
 	"payroll-system/internal/error_const"
+	payroll_service "payroll-system/internal/service/payroll"
 	"payroll-system/internal/utils"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type AdminRepository interface {
@@ -20,15 +23,46 @@ type AdminRepository interface {
 type EmployeeRepository interface {
 	GetEmployeeByID(ctx context.Context, employeeID int) (*domain.Employee, error)
 	GetAllEmployees(ctx context.Context) ([]domain.Employee, error)
+	UpdateEmployment(ctx context.Context, employee domain.Employee) (*domain.Employee, error)
 }
 
 type PayrollRepository interface {
-	BulkInsertPayrolls(ctx context.Context, payrolls []domain.Payroll) error
 	GetPayrollsByPeriodID(ctx context.Context, periodID int) ([]domain.Payroll, error)
+	GetEmployeePayslipByPeriod(ctx context.Context, payroll domain.Payroll) (domain.Payroll, error)
 	GetPayrollPeriod(ctx context.Context, period domain.PayrollPeriod) (domain.PayrollPeriod, error)
 	GetPayrollPeriodFromDateRange(ctx context.Context, period domain.PayrollPeriod) (domain.PayrollPeriod, error)
+	GetPreviousPayrollPeriod(ctx context.Context, period domain.PayrollPeriod) (domain.PayrollPeriod, error)
 	CreatePayrollPeriod(ctx context.Context, payroll domain.PayrollPeriod) (string, error)
-	LockPayrollPeriod(ctx context.Context, periodID int) error
+	SetPayrollPeriodRuleSetVersion(ctx context.Context, period domain.PayrollPeriod) error
+	GetTaxYearToDateGroupedByEmployeeID(ctx context.Context, taxYear int, before time.Time) (map[int]domain.TaxYearToDate, error)
+	GetYearToDate(ctx context.Context, employeeID, taxYear int) (domain.TaxYearToDate, error)
+	GetYearToDateByTaxYear(ctx context.Context, taxYear int) ([]domain.TaxYearToDate, error)
+	RebuildYearToDate(ctx context.Context, taxYear int) (int, error)
+	ReopenPayrollPeriod(ctx context.Context, reopen domain.PayrollReopen, job domain.PayrollJob, audit domain.AuditLog) (domain.PayrollReopen, error)
+	GetPayrollReopensByPeriodID(ctx context.Context, periodID int) ([]domain.PayrollReopen, error)
+	GetPayrollRunsByPeriodID(ctx context.Context, periodID int) ([]domain.PayrollRun, error)
+	GetPayrollRun(ctx context.Context, runID int) (domain.PayrollRun, error)
+	ApprovePayrollRun(ctx context.Context, runID int, transition domain.PayrollRunTransition) (domain.PayrollRun, error)
+	RejectPayrollRun(ctx context.Context, runID int, transition domain.PayrollRunTransition) (domain.PayrollRun, error)
+}
+
+type PayRuleRepository interface {
+	CreatePayRuleSet(ctx context.Context, rules domain.PayRuleSet) (domain.PayRuleSet, error)
+	GetPayRuleSetByVersion(ctx context.Context, version int) (domain.PayRuleSet, error)
+	GetLatestPayRuleSet(ctx context.Context) (domain.PayRuleSet, error)
+	GetAllPayRuleSets(ctx context.Context) ([]domain.PayRuleSet, error)
+}
+
+type TaxProfileRepository interface {
+	UpsertTaxProfile(ctx context.Context, profile domain.TaxProfile) error
+	GetTaxProfile(ctx context.Context, employeeID int) (domain.TaxProfile, error)
+	GetTaxProfilesGroupedByEmployeeID(ctx context.Context) (map[int]domain.TaxProfile, error)
+}
+
+type BPJSRepository interface {
+	CreateBPJSRate(ctx context.Context, rate domain.BPJSRate) (int, error)
+	GetAllBPJSRates(ctx context.Context) ([]domain.BPJSRate, error)
+	GetBPJSRatesEffectiveOn(ctx context.Context, date time.Time) ([]domain.BPJSRate, error)
 }
 
 type AttendanceRepository interface {
@@ -42,24 +76,98 @@ type ReimbursementRepository interface {
 }
 
 type AdminService struct {
-	adminRepository         AdminRepository
-	employeeRepository      EmployeeRepository
-	payrollRepository       PayrollRepository
-	attendanceRepository    AttendanceRepository
-	overtimeRepository      OvertimeRepository
-	reimbursementRepository ReimbursementRepository
+	adminRepository           AdminRepository
+	employeeRepository        EmployeeRepository
+	payrollRepository         PayrollRepository
+	attendanceRepository      AttendanceRepository
+	overtimeRepository        OvertimeRepository
+	reimbursementRepository   ReimbursementRepository
+	payRuleRepository         PayRuleRepository
+	taxProfileRepository      TaxProfileRepository
+	bpjsRepository            BPJSRepository
+	payrollJobRepository      PayrollJobRepository
+	holidayRepository         HolidayRepository
+	salaryRepository          SalaryRepository
+	payComponentRepository    PayComponentRepository
+	loanRepository            LoanRepository
+	bonusRepository           BonusRepository
+	taxStatementRepository    TaxStatementRepository
+	glAccountRepository       GLAccountRepository
+	bankAccountRepository     BankAccountRepository
+	disbursementRepository    DisbursementRepository
+	templateRepository        PayslipTemplateRepository
+	payslipEmailRepository    PayslipEmailRepository
+	payslipPasswordRepository PayslipPasswordRepository
+	mailer                    Mailer // nil when outgoing mail is not configured
+	payslipEmailSettings      domain.PayslipEmailSettings
+	withholder                domain.TaxWithholder
+	rounding                  domain.RoundingPolicies
+	disbursementSource        domain.DisbursementSource
+	calculator                payroll_service.PayrollCalculator
 }
 
-func NewAdminService(adminRepo AdminRepository, empRepo EmployeeRepository,
-	payrollRepo PayrollRepository, attendanceRepo AttendanceRepository,
-	overtimeRepo OvertimeRepository, reimbursementRepo ReimbursementRepository) *AdminService {
+// AdminDependencies holds what NewAdminService wires into the service. Fields left
+// nil are only dereferenced by the features that need them.
+type AdminDependencies struct {
+	AdminRepository           AdminRepository
+	EmployeeRepository        EmployeeRepository
+	PayrollRepository         PayrollRepository
+	AttendanceRepository      AttendanceRepository
+	OvertimeRepository        OvertimeRepository
+	ReimbursementRepository   ReimbursementRepository
+	PayRuleRepository         PayRuleRepository
+	TaxProfileRepository      TaxProfileRepository
+	BPJSRepository            BPJSRepository
+	PayrollJobRepository      PayrollJobRepository
+	HolidayRepository         HolidayRepository
+	SalaryRepository          SalaryRepository
+	PayComponentRepository    PayComponentRepository
+	LoanRepository            LoanRepository
+	BonusRepository           BonusRepository
+	TaxStatementRepository    TaxStatementRepository
+	GLAccountRepository       GLAccountRepository
+	BankAccountRepository     BankAccountRepository
+	DisbursementRepository    DisbursementRepository
+	PayslipTemplateRepository PayslipTemplateRepository
+	PayslipEmailRepository    PayslipEmailRepository
+	PayslipPasswordRepository PayslipPasswordRepository
+	Mailer                    Mailer                      // nil when outgoing mail is not configured
+	PayslipEmailSettings      domain.PayslipEmailSettings // automatic distribution on approval
+	TaxWithholder             domain.TaxWithholder        // the employer named on payslips and 1721-A1 statements
+	DisbursementSource        domain.DisbursementSource   // the account salaries are transferred from
+	RoundingPolicies          domain.RoundingPolicies     // the zero value rounds IDR to the whole rupiah
+}
+
+func NewAdminService(deps AdminDependencies) *AdminService {
 	return &AdminService{
-		adminRepository:         adminRepo,
-		employeeRepository:      empRepo,
-		payrollRepository:       payrollRepo,
-		attendanceRepository:    attendanceRepo,
-		overtimeRepository:      overtimeRepo,
-		reimbursementRepository: reimbursementRepo,
+		adminRepository:           deps.AdminRepository,
+		employeeRepository:        deps.EmployeeRepository,
+		payrollRepository:         deps.PayrollRepository,
+		attendanceRepository:      deps.AttendanceRepository,
+		overtimeRepository:        deps.OvertimeRepository,
+		reimbursementRepository:   deps.ReimbursementRepository,
+		payRuleRepository:         deps.PayRuleRepository,
+		taxProfileRepository:      deps.TaxProfileRepository,
+		bpjsRepository:            deps.BPJSRepository,
+		payrollJobRepository:      deps.PayrollJobRepository,
+		holidayRepository:         deps.HolidayRepository,
+		salaryRepository:          deps.SalaryRepository,
+		payComponentRepository:    deps.PayComponentRepository,
+		loanRepository:            deps.LoanRepository,
+		bonusRepository:           deps.BonusRepository,
+		taxStatementRepository:    deps.TaxStatementRepository,
+		glAccountRepository:       deps.GLAccountRepository,
+		bankAccountRepository:     deps.BankAccountRepository,
+		disbursementRepository:    deps.DisbursementRepository,
+		templateRepository:        deps.PayslipTemplateRepository,
+		payslipEmailRepository:    deps.PayslipEmailRepository,
+		payslipPasswordRepository: deps.PayslipPasswordRepository,
+		mailer:                    deps.Mailer,
+		payslipEmailSettings:      deps.PayslipEmailSettings,
+		withholder:                deps.TaxWithholder,
+		rounding:                  deps.RoundingPolicies,
+		disbursementSource:        deps.DisbursementSource,
+		calculator:                payroll_service.NewRuleBasedCalculator(deps.RoundingPolicies),
 	}
 }
 
@@ -109,126 +217,533 @@ func (s *AdminService) CreatePayrollPeriod(ctx context.Context, payrollPeriodPay
 		return "", error_const.ErrPayrollPeriodAlreadyExists
 	}
 
+	// new periods are pinned to the latest pay rules at creation time
+	rules, err := s.payRuleRepository.GetLatestPayRuleSet(ctx)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return "", err
+	}
+
 	Id, err := s.payrollRepository.CreatePayrollPeriod(ctx, domain.PayrollPeriod{
-		StartDate: start_date,
-		EndDate:   end_date,
-		CreatedBy: payrollPeriodPayload.ActorEmail,
-		UpdatedBy: payrollPeriodPayload.ActorEmail,
+		StartDate:      start_date,
+		EndDate:        end_date,
+		RuleSetVersion: rules.Version,
+		CreatedBy:      payrollPeriodPayload.ActorEmail,
+		UpdatedBy:      payrollPeriodPayload.ActorEmail,
 	})
 	if err != nil {
 		return "", err
 	}
 	return Id, nil
 }
-func (s *AdminService) RunPayrollPeriod(ctx context.Context, payrollPayload dto.PayrollRequest) error {
-	payrollPeriod, err := s.payrollRepository.GetPayrollPeriod(context.Background(), domain.PayrollPeriod{
-		ID: payrollPayload.PeriodID,
+
+// RunPayrollPeriod queues a background job that calculates the payslips. The run then waits
+// for another admin to approve it, which stores the payslips; approving a regular run locks the
+// period. Requests naming employees are off-cycle runs for those employees only.
+// Progress is followed through GetPayrollJob.
+func (s *AdminService) RunPayrollPeriod(ctx context.Context, payrollPayload dto.PayrollRequest) (domain.PayrollJob, error) {
+	job := newPayrollJob(payrollPayload)
+	if job.IsOffCycle() && job.Reason == "" {
+		return domain.PayrollJob{}, error_const.ErrOffCycleReasonRequired
+	}
+	period, err := s.getRunnablePayrollPeriod(ctx, job)
+	if err != nil {
+		return domain.PayrollJob{}, err
+	}
+	if err := s.checkNoPendingPayrollRun(ctx, period.ID); err != nil {
+		return domain.PayrollJob{}, err
+	}
+	// fail fast on unknown employees instead of in the background
+	if _, err := s.selectPayrollEmployees(ctx, period, job); err != nil {
+		return domain.PayrollJob{}, err
+	}
+
+	job, err = s.payrollJobRepository.CreatePayrollJob(ctx, job)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+			return domain.PayrollJob{}, error_const.ErrPayrollJobAlreadyActive
+		}
+		return domain.PayrollJob{}, err
+	}
+	return job, nil
+}
+
+// newPayrollJob builds the job a request runs: off-cycle when it names employees, merging the
+// single and multiple employee selections, and regular otherwise.
+func newPayrollJob(payload dto.PayrollRequest) domain.PayrollJob {
+	job := domain.PayrollJob{
+		PeriodID:    payload.PeriodID,
+		RunType:     domain.PayrollRunRegular,
+		EmployeeIDs: uniqueIDs(append([]int{payload.EmployeeID}, payload.EmployeeIDs...)),
+		Reason:      strings.TrimSpace(payload.Reason),
+		CreatedBy:   payload.ActorEmail,
+		UpdatedBy:   payload.ActorEmail,
+	}
+	if len(job.EmployeeIDs) > 0 {
+		job.RunType = domain.PayrollRunOffCycle
+	} else {
+		job.EmployeeIDs = uniqueIDs(payload.IncludeEmployeeIDs)
+	}
+	return job
+}
+
+func uniqueIDs(ids []int) []int {
+	seen := make(map[int]bool)
+	var unique []int
+	for _, id := range ids {
+		if id != 0 && !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// PreviewPayrollPeriod runs the same calculation as RunPayrollPeriod, regular or off-cycle,
+// but returns the payslips instead of storing them, and leaves the period unlocked.
+func (s *AdminService) PreviewPayrollPeriod(ctx context.Context, payrollPayload dto.PayrollRequest) (*dto.PayrollPreviewResponse, error) {
+	job := newPayrollJob(payrollPayload)
+	period, err := s.getRunnablePayrollPeriod(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	employees, err := s.selectPayrollEmployees(ctx, period, job)
+	if err != nil {
+		return nil, err
+	}
+	run, err := s.calculatePayrollPeriod(ctx, period, employees, payrollPayload.ActorEmail, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &dto.PayrollPreviewResponse{
+		PeriodID:       run.period.ID,
+		RuleSetVersion: run.rules.Version,
+		EmployeeCount:  len(run.payrolls),
+		Payslips:       make([]domain.Payslip, 0, len(run.payrolls)),
+	}
+	for _, payroll := range run.payrolls {
+		payslip := payroll.Payslip
+		preview.Payslips = append(preview.Payslips, payslip)
+		preview.TotalSalary = preview.TotalSalary.Add(payslip.TotalSalary)
+		preview.TotalDeductions = preview.TotalDeductions.Add(payslip.TotalDeductions)
+		preview.TotalNetSalary = preview.TotalNetSalary.Add(payslip.NetSalary)
+		preview.TotalEmployerContributions = preview.TotalEmployerContributions.Add(payslip.EmployerContributions)
+		if payslip.Tax != nil {
+			preview.TotalTax = preview.TotalTax.Add(payslip.Tax.Amount)
+		}
+	}
+	return preview, nil
+}
+
+func (s *AdminService) getPayrollPeriod(ctx context.Context, periodID int) (domain.PayrollPeriod, error) {
+	period, err := s.payrollRepository.GetPayrollPeriod(ctx, domain.PayrollPeriod{
+		ID: periodID,
 	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return error_const.ErrPayrollPeriodNotFound
+			return domain.PayrollPeriod{}, error_const.ErrPayrollPeriodNotFound
 		}
+		return domain.PayrollPeriod{}, err
 	}
-	if payrollPeriod.Locked {
-		return error_const.ErrPayrollPeriodLocked
+	return period, nil
+}
+
+// getOpenPayrollPeriod returns the period only while it can still be run.
+func (s *AdminService) getOpenPayrollPeriod(ctx context.Context, periodID int) (domain.PayrollPeriod, error) {
+	period, err := s.getPayrollPeriod(ctx, periodID)
+	if err != nil {
+		return domain.PayrollPeriod{}, err
+	}
+	if period.Locked {
+		return domain.PayrollPeriod{}, error_const.ErrPayrollPeriodLocked
+	}
+	return period, nil
+}
+
+// ReopenPayrollPeriod unlocks a locked period so it can be corrected and queues the job
+// re-running it, recording the reason and an audit log. The stored payslips stay until another
+// admin approves the re-run, which voids them, keeping them for history, and records the
+// per-employee diff of old and new payslips on the reopen. Progress is followed through
+// GetPayrollJob and the reopen through GetPayrollReopens.
+func (s *AdminService) ReopenPayrollPeriod(ctx context.Context, payload dto.ReopenPayrollPeriodRequest) (domain.PayrollReopen, error) {
+	reason := strings.TrimSpace(payload.Reason)
+	if reason == "" {
+		return domain.PayrollReopen{}, error_const.ErrReopenReasonRequired
 	}
+	period, err := s.getPayrollPeriod(ctx, payload.PeriodID)
+	if err != nil {
+		return domain.PayrollReopen{}, err
+	}
+	if !period.Locked {
+		return domain.PayrollReopen{}, error_const.ErrPayrollPeriodNotLocked
+	}
+	if err := s.checkNoPendingPayrollRun(ctx, period.ID); err != nil {
+		return domain.PayrollReopen{}, err
+	}
+
+	reopen, err := s.payrollRepository.ReopenPayrollPeriod(ctx, domain.PayrollReopen{
+		PeriodID:  period.ID,
+		Reason:    reason,
+		CreatedBy: payload.ActorEmail,
+		UpdatedBy: payload.ActorEmail,
+	}, domain.PayrollJob{
+		PeriodID:  period.ID,
+		RunType:   domain.PayrollRunRegular,
+		CreatedBy: payload.ActorEmail,
+		UpdatedBy: payload.ActorEmail,
+	}, domain.AuditLog{
+		ActorID:   payload.ActorID,
+		ActorRole: payload.ActorRole,
+		Action:    domain.AuditActionReopenPayrollPeriod,
+		IPAddress: payload.IPAddress,
+		CreatedBy: payload.ActorEmail,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+			return domain.PayrollReopen{}, error_const.ErrPayrollJobAlreadyActive
+		}
+		return domain.PayrollReopen{}, err
+	}
+	return reopen, nil
+}
+
+func (s *AdminService) GetPayrollReopens(ctx context.Context, periodID int) ([]domain.PayrollReopen, error) {
+	if _, err := s.getPayrollPeriod(ctx, periodID); err != nil {
+		return nil, err
+	}
+	return s.payrollRepository.GetPayrollReopensByPeriodID(ctx, periodID)
+}
+
+// getRunnablePayrollPeriod returns the job's period if it can be run: regular runs need an open
+// period, while off-cycle runs may also pay into a locked one.
+func (s *AdminService) getRunnablePayrollPeriod(ctx context.Context, job domain.PayrollJob) (domain.PayrollPeriod, error) {
+	if job.IsOffCycle() {
+		return s.getPayrollPeriod(ctx, job.PeriodID)
+	}
+	return s.getOpenPayrollPeriod(ctx, job.PeriodID)
+}
+
+// selectPayrollEmployees returns the employees a job pays: the given employees for an off-cycle
+// run, or for a regular run every employee employed during the period, leaving out those paid
+// off-cycle in the period unless the job includes them. Inactive employees are never paid.
+func (s *AdminService) selectPayrollEmployees(ctx context.Context, period domain.PayrollPeriod, job domain.PayrollJob) ([]domain.Employee, error) {
 	employees, err := s.employeeRepository.GetAllEmployees(ctx)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	byID := make(map[int]domain.Employee, len(employees))
+	for _, employee := range employees {
+		byID[employee.ID] = employee
+	}
+
+	var given []domain.Employee
+	included := make(map[int]bool, len(job.EmployeeIDs))
+	for _, id := range job.EmployeeIDs {
+		employee, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", error_const.ErrEmployeeNotFound, id)
+		}
+		if _, _, employed := employee.EmploymentWithin(period.StartDate, period.EndDate); !employed {
+			return nil, fmt.Errorf("%w: %d", error_const.ErrEmployeeNotEmployed, id)
+		}
+		included[id] = true
+		given = append(given, employee)
+	}
+	if job.IsOffCycle() {
+		return given, nil
+	}
+
+	paid, err := s.payrollRepository.GetPayrollsByPeriodID(ctx, period.ID)
+	if err != nil {
+		return nil, err
 	}
+	paidOffCycle := make(map[int]bool, len(paid))
+	for _, payroll := range paid {
+		if payroll.RunType == domain.PayrollRunOffCycle {
+			paidOffCycle[payroll.EmployeeID] = true
+		}
+	}
+	var selected []domain.Employee
+	for _, employee := range employedDuring(employees, period) {
+		if !paidOffCycle[employee.ID] || included[employee.ID] {
+			selected = append(selected, employee)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, error_const.ErrNoEmployeesFound
+	}
+	return selected, nil
+}
+
+// employedDuring returns the employees who are payable for at least one day of the period.
+func employedDuring(employees []domain.Employee, period domain.PayrollPeriod) []domain.Employee {
+	var employed []domain.Employee
+	for _, employee := range employees {
+		if _, _, ok := employee.EmploymentWithin(period.StartDate, period.EndDate); ok {
+			employed = append(employed, employee)
+		}
+	}
+	return employed
+}
+
+// payrollRun is the result of calculating a payroll period before anything is stored.
+type payrollRun struct {
+	period   domain.PayrollPeriod
+	rules    domain.PayRuleSet
+	payrolls []domain.Payroll
+	failures []domain.PayrollJobError
+}
+
+// calculatePayrollPeriod loads everything a payroll period depends on and calculates the
+// employees' payslips. It must not write anything, since previews share it with real runs.
+// Callers decide whether the period may be calculated in its current lock state.
+// Every employee is calculated even if some fail; the failures are kept on the returned run.
+// progress, when set, is called with the number of employees processed so far.
+func (s *AdminService) calculatePayrollPeriod(ctx context.Context, payrollPeriod domain.PayrollPeriod, employees []domain.Employee, actorEmail string, progress func(processed, total int)) (*payrollRun, error) {
 	attendance, err := s.attendanceRepository.GetTotalAttendanceByDateRangeGroupedByEmployee(ctx, payrollPeriod.StartDate, payrollPeriod.EndDate)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	overtime, err := s.overtimeRepository.GetOvertimesGroupedByEmployeeID(ctx, payrollPeriod.StartDate, payrollPeriod.EndDate)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 	reimbursement, err := s.reimbursementRepository.GetReimbursementsGroupedByEmployeeID(ctx, payrollPeriod.StartDate, payrollPeriod.EndDate)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	allPayrolls := make([]domain.Payroll, 0, len(employees))
-	if len(employees) == 0 {
-		return error_const.ErrNoEmployeesFound
+	rules, err := s.getPeriodRuleSet(ctx, payrollPeriod)
+	if err != nil {
+		return nil, err
 	}
-	for _, employee := range employees {
+	taxProfiles, err := s.taxProfileRepository.GetTaxProfilesGroupedByEmployeeID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	taxYearToDate, err := s.payrollRepository.GetTaxYearToDateGroupedByEmployeeID(ctx, payrollPeriod.EndDate.Year(), payrollPeriod.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	bpjsRates, err := s.bpjsRepository.GetBPJSRatesEffectiveOn(ctx, payrollPeriod.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	holidays, err := s.holidayRepository.GetHolidaysBetween(ctx, payrollPeriod.StartDate, payrollPeriod.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	calendar := domain.NewHolidayCalendar(holidays)
+	salaryHistories, err := s.salaryRepository.GetSalaryHistoriesGroupedByEmployeeID(ctx, payrollPeriod.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	payComponents, err := s.payComponentRepository.GetPayComponentAssignmentsGroupedByEmployeeID(ctx, payrollPeriod.StartDate, payrollPeriod.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	loans, err := s.loanRepository.GetLoansDueGroupedByEmployeeID(ctx, payrollPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	allPayrolls := make([]domain.Payroll, 0, len(employees))
+	var failures []domain.PayrollJobError
+	totalWorkDay := calendar.Workdays(payrollPeriod.StartDate, payrollPeriod.EndDate)
+	for i, employee := range employees {
+		if progress != nil {
+			progress(i, len(employees))
+		}
+		payslip, err := s.calculator.Calculate(payroll_service.CalculationInput{
+			Employee:       employee,
+			Period:         payrollPeriod,
+			Rules:          rules,
+			Attendances:    attendance[employee.ID],
+			TotalWorkDays:  totalWorkDay,
+			Overtimes:      overtime[employee.ID],
+			Reimbursements: reimbursement[employee.ID],
+			TaxProfile:     taxProfileOrDefault(taxProfiles, employee.ID),
+			TaxYearToDate:  taxYearToDate[employee.ID],
+			BPJSRates:      bpjsRates,
+			Holidays:       calendar,
+			SalaryHistory:  salaryHistories[employee.ID],
+			PayComponents:  payComponents[employee.ID],
+			Loans:          loans[employee.ID],
+		})
+		if err != nil {
+			failures = append(failures, domain.PayrollJobError{EmployeeID: employee.ID, Message: err.Error()})
+			continue
+		}
 		var payroll domain.Payroll
 		payroll.EmployeeID = employee.ID
 		payroll.PeriodID = payrollPeriod.ID
-		var payslip domain.Payslip
-		payslip.EmployeeID = employee.ID
-		payslip.PeriodID = payrollPeriod.ID
-
-		totalWorkDay := utils.GetTotalWorkdays(payrollPeriod.StartDate, payrollPeriod.EndDate)
-		payslip.NumberAttendances = attendance[employee.ID]
-		payslip.TotalWorkDays = totalWorkDay
-		attendanceSalary := employee.Salary * (float64(payslip.NumberAttendances) / float64(totalWorkDay))
-		salaryPerHours := employee.Salary / 20 // Assuming 20 working days in a month
-		payslip.SalaryByAttendance = attendanceSalary
-
-		var overtimeRecaps []domain.OvertimeRecap
-		var overtimeSalary float64
-		var overtimeTotalHours int
-
-		overtimeTotalHours = 0
-		if len(overtime[employee.ID]) > 0 {
-			recapOvertime := overtime[employee.ID]
-			for _, o := range recapOvertime {
-				amount := float64(o.Hours) * salaryPerHours * 2 // Assuming overtime is paid at double rate
-				overtimeRecap := domain.OvertimeRecap{
-					Date:   o.Date,
-					Hours:  o.Hours,
-					Amount: amount,
-				}
-				overtimeSalary += amount
-				overtimeTotalHours += o.Hours
-				overtimeRecaps = append(overtimeRecaps, overtimeRecap)
+		payroll.Payslip = payslip
+		payroll.CreatedBy = actorEmail
+		payroll.UpdatedBy = actorEmail
+
+		allPayrolls = append(allPayrolls, payroll)
+	}
+	if progress != nil {
+		progress(len(employees), len(employees))
+	}
+
+	run := &payrollRun{
+		period:   payrollPeriod,
+		rules:    rules,
+		payrolls: allPayrolls,
+		failures: failures,
+	}
+	if len(failures) > 0 {
+		return run, fmt.Errorf("%w: employee %d: %s", error_const.ErrPayrollCalculationFailed, failures[0].EmployeeID, failures[0].Message)
+	}
+	return run, nil
+}
+
+// getPeriodRuleSet returns the pay rules pinned to the period, or the latest rules for
+// periods created before rule sets existed.
+func (s *AdminService) getPeriodRuleSet(ctx context.Context, period domain.PayrollPeriod) (domain.PayRuleSet, error) {
+	if period.RuleSetVersion != 0 {
+		rules, err := s.payRuleRepository.GetPayRuleSetByVersion(ctx, period.RuleSetVersion)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.PayRuleSet{}, error_const.ErrPayRuleSetNotFound
 			}
+			return domain.PayRuleSet{}, err
 		}
-		payslip.OvertimesRecap = overtimeRecaps
-		payslip.OvetimeTotalSalary = overtimeSalary
-
-		payslip.Reimbursements = reimbursement[employee.ID]
-		totalReimbursement := 0.0
-		for _, r := range payslip.Reimbursements {
-			totalReimbursement += r.Amount
+		return rules, nil
+	}
+	rules, err := s.payRuleRepository.GetLatestPayRuleSet(ctx)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return payroll_service.DefaultPayRuleSet, nil
 		}
-		payslip.ReimbursementsTotalSalary = totalReimbursement
-
-		payslip.TotalSalary = attendanceSalary + overtimeSalary + totalReimbursement
-		payslip.Description = fmt.Sprintf(
-			"Total Salary: %.2f\n"+
-				"Attendance Salary: %.2f (Base Salary: %.2f x Attendance: %d / Workdays: %d)\n"+
-				"Overtime Salary: %.2f (Overtime Hours: %d x Salary/Day: %.2f x 2)\n"+
-				"Total Reimbursement: %.2f",
-			payslip.TotalSalary,
-			attendanceSalary, employee.Salary, payslip.NumberAttendances, totalWorkDay,
-			overtimeSalary, overtimeTotalHours, salaryPerHours,
-			totalReimbursement,
-		)
-		payroll.Payslip = payslip
-		payroll.CreatedBy = payrollPayload.ActorEmail
-		payroll.UpdatedBy = payrollPayload.ActorEmail
+		return domain.PayRuleSet{}, err
+	}
+	return rules, nil
+}
 
-		allPayrolls = append(allPayrolls, payroll)
+func (s *AdminService) CreatePayRuleSet(ctx context.Context, payload dto.PayRuleSetRequest) (domain.PayRuleSet, error) {
+	rules := domain.PayRuleSet{
+		Description:               payload.Description,
+		BasePayFormula:            payload.BasePayFormula,
+		HourlyDivisor:             payload.HourlyDivisor,
+		OvertimeMultiplier:        payload.OvertimeMultiplier,
+		RestDayOvertimeMultiplier: payload.RestDayOvertimeMultiplier,
+		CreatedBy:                 payload.ActorEmail,
+		UpdatedBy:                 payload.ActorEmail,
 	}
+	if err := s.calculator.ValidateRuleSet(rules); err != nil {
+		return domain.PayRuleSet{}, err
+	}
+	return s.payRuleRepository.CreatePayRuleSet(ctx, rules)
+}
 
-	err = s.payrollRepository.BulkInsertPayrolls(ctx, allPayrolls)
+func (s *AdminService) GetPayRuleSets(ctx context.Context) ([]domain.PayRuleSet, error) {
+	return s.payRuleRepository.GetAllPayRuleSets(ctx)
+}
 
+// AssignPayRuleSet pins a rule set version to a payroll period that has not been locked yet.
+func (s *AdminService) AssignPayRuleSet(ctx context.Context, payload dto.AssignPayRuleSetRequest) error {
+	period, err := s.payrollRepository.GetPayrollPeriod(ctx, domain.PayrollPeriod{ID: payload.PeriodID})
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return error_const.ErrPayrollPeriodNotFound
+		}
 		return err
 	}
-	err = s.payrollRepository.LockPayrollPeriod(ctx, payrollPayload.PeriodID)
+	if period.Locked {
+		return error_const.ErrPayrollPeriodLocked
+	}
+	if _, err := s.payRuleRepository.GetPayRuleSetByVersion(ctx, payload.Version); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return error_const.ErrPayRuleSetNotFound
+		}
+		return err
+	}
+	period.RuleSetVersion = payload.Version
+	period.UpdatedBy = payload.ActorEmail
+	return s.payrollRepository.SetPayrollPeriodRuleSetVersion(ctx, period)
+}
+
+func taxProfileOrDefault(profiles map[int]domain.TaxProfile, employeeID int) domain.TaxProfile {
+	if profile, ok := profiles[employeeID]; ok {
+		return profile
+	}
+	return domain.DefaultTaxProfile(employeeID)
+}
 
+func (s *AdminService) UpsertTaxProfile(ctx context.Context, payload dto.TaxProfileRequest) error {
+	npwp := strings.NewReplacer(".", "", "-", "", " ", "").Replace(payload.NPWP)
+	if npwp != "" {
+		if len(npwp) != 15 && len(npwp) != 16 {
+			return error_const.ErrInvalidNPWP
+		}
+		for _, r := range npwp {
+			if r < '0' || r > '9' {
+				return error_const.ErrInvalidNPWP
+			}
+		}
+	}
+	if payload.MaritalStatus != domain.MaritalStatusSingle && payload.MaritalStatus != domain.MaritalStatusMarried {
+		return error_const.ErrInvalidMaritalStatus
+	}
+	if payload.Dependents < 0 || payload.Dependents > 3 {
+		return error_const.ErrInvalidDependents
+	}
+	if _, err := s.employeeRepository.GetEmployeeByID(ctx, payload.EmployeeID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return error_const.ErrUserNotFound
+		}
+		return err
+	}
+	return s.taxProfileRepository.UpsertTaxProfile(ctx, domain.TaxProfile{
+		EmployeeID:    payload.EmployeeID,
+		NPWP:          npwp,
+		MaritalStatus: payload.MaritalStatus,
+		Dependents:    payload.Dependents,
+		CreatedBy:     payload.ActorEmail,
+		UpdatedBy:     payload.ActorEmail,
+	})
+}
+
+func (s *AdminService) GetTaxProfile(ctx context.Context, employeeID int) (domain.TaxProfile, error) {
+	profile, err := s.taxProfileRepository.GetTaxProfile(ctx, employeeID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return error_const.ErrPayrollPeriodNotFound
+			return domain.TaxProfile{}, error_const.ErrTaxProfileNotFound
 		}
+		return domain.TaxProfile{}, err
 	}
+	return profile, nil
+}
+
+func (s *AdminService) CreateBPJSRate(ctx context.Context, payload dto.BPJSRateRequest) (int, error) {
+	program := strings.ToUpper(payload.Program)
+	if !domain.IsValidBPJSProgram(program) {
+		return 0, error_const.ErrInvalidBPJSProgram
+	}
+	if payload.EmployeeRate.Sign() < 0 || payload.EmployerRate.Sign() < 0 || payload.WageCap.IsNegative() {
+		return 0, error_const.ErrInvalidBPJSRate
+	}
+	effectiveFrom, err := time.Parse("2006-01-02", payload.EffectiveFrom)
+	if err != nil {
+		return 0, error_const.ErrInvalidDateFormat
+	}
+	return s.bpjsRepository.CreateBPJSRate(ctx, domain.BPJSRate{
+		Program:       program,
+		EmployeeRate:  payload.EmployeeRate,
+		EmployerRate:  payload.EmployerRate,
+		WageCap:       payload.WageCap,
+		EffectiveFrom: effectiveFrom,
+		CreatedBy:     payload.ActorEmail,
+		UpdatedBy:     payload.ActorEmail,
+	})
+}
 
-	return nil
+func (s *AdminService) GetBPJSRates(ctx context.Context) ([]domain.BPJSRate, error) {
+	return s.bpjsRepository.GetAllBPJSRates(ctx)
 }
 
 func (s *AdminService) ViewPayrollSummary(ctx context.Context, periodID int) (*dto.PayrollSummaryResponse, error) {
@@ -244,7 +759,8 @@ func (s *AdminService) ViewPayrollSummary(ctx context.Context, periodID int) (*d
 		return nil, error_const.ErrNoPayrollsFound
 	}
 
-	var totalSalary float64
+	var totalSalary, totalTax, totalNetSalary, totalEmployerContributions domain.Money
+	employerContributionsByProgram := make(map[string]domain.Money)
 	employeeSummaries := make([]dto.EmployeePayrollSummary, 0, len(payrolls))
 
 	for _, payroll := range payrolls {
@@ -256,15 +772,32 @@ func (s *AdminService) ViewPayrollSummary(ctx context.Context, periodID int) (*d
 			EmployeeID:   employee.ID,
 			EmployeeName: employee.Name,
 			TotalSalary:  payroll.Payslip.TotalSalary,
+			NetSalary:    payroll.Payslip.NetSalary,
+
+			EmployerContributions: payroll.Payslip.EmployerContributions,
+		}
+		if payroll.Payslip.Tax != nil {
+			summary.Tax = payroll.Payslip.Tax.Amount
 		}
 		employeeSummaries = append(employeeSummaries, summary)
-		totalSalary += payroll.Payslip.TotalSalary
+		totalSalary = totalSalary.Add(payroll.Payslip.TotalSalary)
+		totalTax = totalTax.Add(summary.Tax)
+		totalNetSalary = totalNetSalary.Add(payroll.Payslip.NetSalary)
+		totalEmployerContributions = totalEmployerContributions.Add(payroll.Payslip.EmployerContributions)
+		for _, contribution := range payroll.Payslip.BPJS {
+			employerContributionsByProgram[contribution.Program] = employerContributionsByProgram[contribution.Program].Add(contribution.EmployerAmount)
+		}
 	}
 
 	response := &dto.PayrollSummaryResponse{
 		PeriodID:          periodID,
 		EmployeeSummaries: employeeSummaries,
 		TotalSalary:       totalSalary,
+		TotalTax:          totalTax,
+		TotalNetSalary:    totalNetSalary,
+
+		TotalEmployerContributions:     totalEmployerContributions,
+		EmployerContributionsByProgram: employerContributionsByProgram,
 	}
 
 	return response, nil
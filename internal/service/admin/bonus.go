@@ -0,0 +1,251 @@
+package admin_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"payroll-system/internal/delivery/dto"
+	domain "payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	payroll_service "payroll-system/internal/service/payroll"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type BonusRepository interface {
+	CreateBonusRun(ctx context.Context, run domain.BonusRun, payslips []domain.BonusPayslip) (domain.BonusRun, error)
+	GetBonusRun(ctx context.Context, runID int) (domain.BonusRun, error)
+	GetBonusRunsByPeriodID(ctx context.Context, periodID int) ([]domain.BonusRun, error)
+	GetBonusPayslipsByPeriodID(ctx context.Context, periodID int) ([]domain.BonusPayslip, error)
+}
+
+// RunBonus calculates and stores THR or bonus payslips for a locked payroll period.
+func (s *AdminService) RunBonus(ctx context.Context, payload dto.BonusRunRequest) (domain.BonusRun, error) {
+	run, err := s.calculateBonusRun(ctx, payload)
+	if err != nil {
+		return domain.BonusRun{}, err
+	}
+	payslips := run.Payslips
+	created, err := s.bonusRepository.CreateBonusRun(ctx, run, payslips)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+			return domain.BonusRun{}, error_const.ErrTHRAlreadyPaid
+		}
+		return domain.BonusRun{}, err
+	}
+	for i := range payslips {
+		payslips[i].RunID = created.ID
+	}
+	created.Payslips = payslips
+	return created, nil
+}
+
+// PreviewBonus returns the payslips RunBonus would store.
+func (s *AdminService) PreviewBonus(ctx context.Context, payload dto.BonusRunRequest) (domain.BonusRun, error) {
+	return s.calculateBonusRun(ctx, payload)
+}
+
+func (s *AdminService) GetBonusRuns(ctx context.Context, periodID int) ([]domain.BonusRun, error) {
+	if _, err := s.getPayrollPeriod(ctx, periodID); err != nil {
+		return nil, err
+	}
+	return s.bonusRepository.GetBonusRunsByPeriodID(ctx, periodID)
+}
+
+func (s *AdminService) GetBonusRun(ctx context.Context, runID int) (domain.BonusRun, error) {
+	run, err := s.bonusRepository.GetBonusRun(ctx, runID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.BonusRun{}, error_const.ErrBonusRunNotFound
+		}
+		return domain.BonusRun{}, err
+	}
+	return run, nil
+}
+
+// calculateBonusRun calculates the payslips of a bonus run without storing anything. The
+// period must be locked, so the irregular income is taxed on top of the final regular pay.
+func (s *AdminService) calculateBonusRun(ctx context.Context, payload dto.BonusRunRequest) (domain.BonusRun, error) {
+	if !domain.IsValidBonusKind(payload.Kind) {
+		return domain.BonusRun{}, error_const.ErrInvalidBonusKind
+	}
+	period, err := s.getPayrollPeriod(ctx, payload.PeriodID)
+	if err != nil {
+		return domain.BonusRun{}, err
+	}
+	if !period.Locked {
+		return domain.BonusRun{}, error_const.ErrPayrollPeriodNotRun
+	}
+	employees, err := s.employeeRepository.GetAllEmployees(ctx)
+	if err != nil {
+		return domain.BonusRun{}, err
+	}
+	run := domain.BonusRun{
+		PeriodID:  period.ID,
+		Kind:      payload.Kind,
+		Reason:    strings.TrimSpace(payload.Reason),
+		CreatedBy: payload.ActorEmail,
+		UpdatedBy: payload.ActorEmail,
+	}
+
+	var inputs []payroll_service.BonusInput
+	switch payload.Kind {
+	case domain.BonusKindTHR:
+		referenceDate := period.EndDate
+		if payload.ReferenceDate != "" {
+			if referenceDate, err = time.Parse("2006-01-02", payload.ReferenceDate); err != nil {
+				return domain.BonusRun{}, error_const.ErrInvalidDateFormat
+			}
+		}
+		run.ReferenceDate = &referenceDate
+		inputs, err = s.thrInputs(ctx, employees, payload.EmployeeIDs, referenceDate)
+	case domain.BonusKindBonus:
+		inputs, err = bonusInputs(employees, payload.Amounts)
+	}
+	if err != nil {
+		return domain.BonusRun{}, err
+	}
+
+	periodToDate, err := s.bonusPeriodToDate(ctx, period.ID)
+	if err != nil {
+		return domain.BonusRun{}, err
+	}
+	taxProfiles, err := s.taxProfileRepository.GetTaxProfilesGroupedByEmployeeID(ctx)
+	if err != nil {
+		return domain.BonusRun{}, err
+	}
+	taxYearToDate, err := s.payrollRepository.GetTaxYearToDateGroupedByEmployeeID(ctx, period.EndDate.Year(), period.StartDate)
+	if err != nil {
+		return domain.BonusRun{}, err
+	}
+	for _, input := range inputs {
+		employeeID := input.Employee.ID
+		input.Rounding = s.rounding
+		input.Period = period
+		input.Kind = payload.Kind
+		input.TaxProfile = taxProfileOrDefault(taxProfiles, employeeID)
+		input.TaxYearToDate = taxYearToDate[employeeID]
+		input.PeriodToDate = periodToDate[employeeID]
+		payslip, err := payroll_service.CalculateBonusPayslip(input)
+		if err != nil {
+			return domain.BonusRun{}, fmt.Errorf("employee %d: %w", employeeID, err)
+		}
+		run.Payslips = append(run.Payslips, domain.BonusPayslip{
+			EmployeeID: employeeID,
+			PeriodID:   period.ID,
+			Kind:       payload.Kind,
+			Payslip:    payslip,
+			CreatedBy:  payload.ActorEmail,
+		})
+		run.TotalGross = run.TotalGross.Add(payslip.TotalSalary)
+		run.TotalNet = run.TotalNet.Add(payslip.NetSalary)
+	}
+	run.EmployeeCount = len(run.Payslips)
+	return run, nil
+}
+
+// thrInputs selects the employees employed on the reference date with at least a month of
+// service and sets their monthly wage: the salary in effect plus their recurring allowances.
+func (s *AdminService) thrInputs(ctx context.Context, employees []domain.Employee, employeeIDs []int, referenceDate time.Time) ([]payroll_service.BonusInput, error) {
+	selected := make(map[int]bool, len(employeeIDs))
+	for _, id := range employeeIDs {
+		selected[id] = true
+	}
+	salaryHistories, err := s.salaryRepository.GetSalaryHistoriesGroupedByEmployeeID(ctx, referenceDate)
+	if err != nil {
+		return nil, err
+	}
+	payComponents, err := s.payComponentRepository.GetPayComponentAssignmentsGroupedByEmployeeID(ctx, referenceDate, referenceDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var inputs []payroll_service.BonusInput
+	for _, employee := range employees {
+		if len(selected) > 0 && !selected[employee.ID] {
+			continue
+		}
+		if _, _, ok := employee.EmploymentWithin(referenceDate, referenceDate); !ok || employee.THRMonths(referenceDate) <= 0 {
+			continue
+		}
+		wage := employee.Salary
+		if history := salaryHistories[employee.ID]; len(history) > 0 {
+			segments := domain.SalarySegments(history, referenceDate, referenceDate)
+			if len(segments) == 0 {
+				continue
+			}
+			wage = segments[0].Salary
+		}
+		for _, assignment := range payComponents[employee.ID] {
+			if assignment.Component.Category == domain.PayslipLineEarning {
+				wage = wage.Add(assignment.Amount)
+			}
+		}
+		inputs = append(inputs, payroll_service.BonusInput{
+			Employee:      employee,
+			ReferenceDate: referenceDate,
+			MonthlyWage:   wage,
+		})
+	}
+	if len(inputs) == 0 {
+		return nil, error_const.ErrNoEligibleEmployees
+	}
+	return inputs, nil
+}
+
+// bonusInputs groups the uploaded bonus amounts by employee. Every employee may appear once.
+func bonusInputs(employees []domain.Employee, amounts []domain.BonusAmount) ([]payroll_service.BonusInput, error) {
+	if len(amounts) == 0 {
+		return nil, error_const.ErrBonusAmountsRequired
+	}
+	byID := make(map[int]domain.Employee, len(employees))
+	for _, employee := range employees {
+		byID[employee.ID] = employee
+	}
+	seen := make(map[int]bool, len(amounts))
+	inputs := make([]payroll_service.BonusInput, 0, len(amounts))
+	for _, amount := range amounts {
+		employee, ok := byID[amount.EmployeeID]
+		if !ok {
+			return nil, fmt.Errorf("employee %d: %w", amount.EmployeeID, error_const.ErrUserNotFound)
+		}
+		if seen[amount.EmployeeID] {
+			return nil, fmt.Errorf("employee %d: %w", amount.EmployeeID, error_const.ErrDuplicateBonusEmployee)
+		}
+		if !amount.Amount.IsPositive() {
+			return nil, fmt.Errorf("employee %d: %w", amount.EmployeeID, error_const.ErrInvalidBonusAmount)
+		}
+		seen[amount.EmployeeID] = true
+		inputs = append(inputs, payroll_service.BonusInput{
+			Employee: employee,
+			Amounts:  []domain.BonusAmount{amount},
+		})
+	}
+	return inputs, nil
+}
+
+// bonusPeriodToDate sums the regular and earlier bonus payslips of the period per employee.
+func (s *AdminService) bonusPeriodToDate(ctx context.Context, periodID int) (map[int]domain.TaxYearToDate, error) {
+	payrolls, err := s.payrollRepository.GetPayrollsByPeriodID(ctx, periodID)
+	if err != nil {
+		return nil, err
+	}
+	bonusPayslips, err := s.bonusRepository.GetBonusPayslipsByPeriodID(ctx, periodID)
+	if err != nil {
+		return nil, err
+	}
+	totals := make(map[int]domain.TaxYearToDate)
+	for _, payroll := range payrolls {
+		// off-cycle payslips add to the regular one without adding a month
+		_, counted := totals[payroll.EmployeeID]
+		totals[payroll.EmployeeID] = totals[payroll.EmployeeID].Add(payroll.Payslip, !counted)
+	}
+	for _, bonusPayslip := range bonusPayslips {
+		totals[bonusPayslip.EmployeeID] = totals[bonusPayslip.EmployeeID].Add(bonusPayslip.Payslip, false)
+	}
+	return totals, nil
+}
@@ -0,0 +1,273 @@
+package admin_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"payroll-system/internal/delivery/dto"
+	domain "payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	document_service "payroll-system/internal/service/document"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type BankAccountRepository interface {
+	UpsertBankAccount(ctx context.Context, account domain.BankAccount) (domain.BankAccount, error)
+	GetBankAccount(ctx context.Context, employeeID int) (domain.BankAccount, error)
+	GetBankAccountsByEmployeeID(ctx context.Context) (map[int]domain.BankAccount, error)
+}
+
+type DisbursementRepository interface {
+	CreateDisbursementBatch(ctx context.Context, batch domain.DisbursementBatch) (domain.DisbursementBatch, error)
+	GetDisbursementBatch(ctx context.Context, batchID int) (domain.DisbursementBatch, error)
+	GetDisbursementBatchesByPeriodID(ctx context.Context, periodID int) ([]domain.DisbursementBatch, error)
+	GetDisbursedAmounts(ctx context.Context, periodID int) (map[int]domain.Money, error)
+	CancelDisbursementBatch(ctx context.Context, batch domain.DisbursementBatch) (domain.DisbursementBatch, error)
+	MarkDisbursementBatchSent(ctx context.Context, batch domain.DisbursementBatch) (domain.DisbursementBatch, error)
+	UpdateDisbursementItemStatus(ctx context.Context, item domain.DisbursementItem) (domain.DisbursementItem, error)
+	GetDisbursementItems(ctx context.Context, periodID int, statuses []string) ([]domain.ReconciliationItem, error)
+	GetImportedStatementFingerprints(ctx context.Context, fingerprints []string) (map[string]bool, error)
+	SaveBankStatementImport(ctx context.Context, statement domain.BankStatementImport) (domain.BankStatementImport, error)
+	GetUnmatchedBankStatementEntries(ctx context.Context) ([]domain.BankStatementEntry, error)
+}
+
+var (
+	bankCodePattern          = regexp.MustCompile(`^[A-Z0-9]{3,11}$`)
+	bankAccountNumberPattern = regexp.MustCompile(`^[0-9]{5,20}$`)
+)
+
+func (s *AdminService) SetBankAccount(ctx context.Context, payload dto.BankAccountRequest) (domain.BankAccount, error) {
+	bankCode := strings.ToUpper(strings.TrimSpace(payload.BankCode))
+	if !bankCodePattern.MatchString(bankCode) {
+		return domain.BankAccount{}, error_const.ErrInvalidBankCode
+	}
+	// account numbers are often written with spaces or dashes between digit groups
+	accountNumber := strings.NewReplacer(" ", "", "-", "", ".", "").Replace(payload.AccountNumber)
+	if !bankAccountNumberPattern.MatchString(accountNumber) {
+		return domain.BankAccount{}, error_const.ErrInvalidBankAccountNumber
+	}
+	holder := strings.TrimSpace(payload.AccountHolder)
+	if holder == "" {
+		return domain.BankAccount{}, error_const.ErrBankAccountHolderRequired
+	}
+	if _, err := s.employeeRepository.GetEmployeeByID(ctx, payload.EmployeeID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.BankAccount{}, error_const.ErrUserNotFound
+		}
+		return domain.BankAccount{}, err
+	}
+	return s.bankAccountRepository.UpsertBankAccount(ctx, domain.BankAccount{
+		EmployeeID:    payload.EmployeeID,
+		BankCode:      bankCode,
+		AccountNumber: accountNumber,
+		AccountHolder: holder,
+		CreatedBy:     payload.ActorEmail,
+		UpdatedBy:     payload.ActorEmail,
+	})
+}
+
+func (s *AdminService) GetBankAccount(ctx context.Context, employeeID int) (domain.BankAccount, error) {
+	account, err := s.bankAccountRepository.GetBankAccount(ctx, employeeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.BankAccount{}, error_const.ErrBankAccountNotFound
+	}
+	return account, err
+}
+
+// CreateDisbursement records a batch transferring to the employees' bank accounts what a locked
+// period still owes them: per employee, the net pay of their regular and off-cycle payslips
+// less what earlier exported batches of the period paid, in one transfer each. The first batch
+// pays the period; later ones pay what corrections and re-runs added since. Employees owed
+// nothing are left out. With Reissue the batch also pays again the failed and returned payments
+// of earlier batches, to the employees' current accounts.
+func (s *AdminService) CreateDisbursement(ctx context.Context, payload dto.DisbursementRequest) (domain.DisbursementBatch, error) {
+	if _, ok := document_service.GetDisbursementFormat(payload.Format); !ok {
+		return domain.DisbursementBatch{}, error_const.ErrUnknownDisbursementFormat
+	}
+	valueDate := time.Now().UTC().Truncate(24 * time.Hour)
+	if payload.ValueDate != "" {
+		date, err := time.Parse("2006-01-02", payload.ValueDate)
+		if err != nil {
+			return domain.DisbursementBatch{}, error_const.ErrInvalidDateFormat
+		}
+		valueDate = date
+	}
+	period, err := s.getPayrollPeriod(ctx, payload.PeriodID)
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	if !period.Locked {
+		return domain.DisbursementBatch{}, error_const.ErrDisbursementPeriodNotLocked
+	}
+
+	payrolls, err := s.payrollRepository.GetPayrollsByPeriodID(ctx, period.ID)
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	currency, err := s.disbursementCurrency(payrolls)
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	disbursed, err := s.disbursementRepository.GetDisbursedAmounts(ctx, period.ID)
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	owed := domain.DisbursementOwed(payrolls, disbursed)
+	employees, err := s.employeeRepository.GetAllEmployees(ctx)
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+	names := make(map[int]string, len(employees))
+	for _, employee := range employees {
+		names[employee.ID] = employee.Name
+	}
+	accounts, err := s.bankAccountRepository.GetBankAccountsByEmployeeID(ctx)
+	if err != nil {
+		return domain.DisbursementBatch{}, err
+	}
+
+	employeeIDs := make([]int, 0, len(owed))
+	for employeeID := range owed {
+		employeeIDs = append(employeeIDs, employeeID)
+	}
+	sort.Ints(employeeIDs)
+	batch := domain.DisbursementBatch{
+		PeriodID:  period.ID,
+		Format:    payload.Format,
+		ValueDate: valueDate,
+		Source:    s.disbursementSource,
+		Currency:  currency,
+		CreatedBy: payload.ActorEmail,
+		UpdatedBy: payload.ActorEmail,
+	}
+	var missing []int
+	for _, employeeID := range employeeIDs {
+		account, ok := accounts[employeeID]
+		if !ok {
+			missing = append(missing, employeeID)
+			continue
+		}
+		batch.Items = append(batch.Items, domain.DisbursementItem{
+			Line:          len(batch.Items) + 1,
+			EmployeeID:    employeeID,
+			EmployeeName:  names[employeeID],
+			BankCode:      account.BankCode,
+			AccountNumber: account.AccountNumber,
+			AccountHolder: account.AccountHolder,
+			Amount:        owed[employeeID],
+		})
+		batch.TotalAmount = batch.TotalAmount.Add(owed[employeeID])
+	}
+	if payload.Reissue {
+		unpaid, err := s.disbursementRepository.GetDisbursementItems(ctx, 0, []string{domain.PaymentFailed, domain.PaymentReturned})
+		if err != nil {
+			return domain.DisbursementBatch{}, err
+		}
+		for _, item := range unpaid {
+			if item.ReissuedBatchID != 0 {
+				continue
+			}
+			account, ok := accounts[item.EmployeeID]
+			if !ok {
+				missing = append(missing, item.EmployeeID)
+				continue
+			}
+			batch.Items = append(batch.Items, domain.DisbursementItem{
+				Line:            len(batch.Items) + 1,
+				EmployeeID:      item.EmployeeID,
+				EmployeeName:    names[item.EmployeeID],
+				BankCode:        account.BankCode,
+				AccountNumber:   account.AccountNumber,
+				AccountHolder:   account.AccountHolder,
+				Amount:          item.Amount,
+				ReissueOfItemID: item.ID,
+			})
+			batch.TotalAmount = batch.TotalAmount.Add(item.Amount)
+		}
+	}
+	if len(missing) > 0 {
+		return domain.DisbursementBatch{}, fmt.Errorf("%w: employee IDs %v", error_const.ErrBankAccountMissing, missing)
+	}
+	if len(batch.Items) == 0 {
+		if len(disbursed) > 0 {
+			return domain.DisbursementBatch{}, error_const.ErrPeriodAlreadyDisbursed
+		}
+		return domain.DisbursementBatch{}, error_const.ErrNoNetPayToDisburse
+	}
+	batch.ItemCount = len(batch.Items)
+	batch.Checksum = domain.DisbursementChecksum(batch.Items)
+	return s.disbursementRepository.CreateDisbursementBatch(ctx, batch)
+}
+
+// disbursementCurrency is the currency the payslips of a period are paid in. A batch transfers
+// one currency, so a period paid in several cannot be disbursed.
+func (s *AdminService) disbursementCurrency(payrolls []domain.Payroll) (string, error) {
+	currency := ""
+	for _, payroll := range payrolls {
+		payslipCurrency := payroll.Payslip.Currency
+		if payslipCurrency == "" {
+			payslipCurrency = s.rounding.DefaultCurrency()
+		}
+		if currency != "" && payslipCurrency != currency {
+			return "", error_const.ErrMixedDisbursementCurrencies
+		}
+		currency = payslipCurrency
+	}
+	if currency == "" {
+		currency = s.rounding.DefaultCurrency()
+	}
+	return currency, nil
+}
+
+func (s *AdminService) GetDisbursements(ctx context.Context, periodID int) ([]domain.DisbursementBatch, error) {
+	return s.disbursementRepository.GetDisbursementBatchesByPeriodID(ctx, periodID)
+}
+
+func (s *AdminService) GetDisbursement(ctx context.Context, batchID int) (domain.DisbursementBatch, error) {
+	batch, err := s.disbursementRepository.GetDisbursementBatch(ctx, batchID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.DisbursementBatch{}, error_const.ErrDisbursementBatchNotFound
+	}
+	return batch, err
+}
+
+// GetDisbursementFile renders a batch in its format. The file is the same on every download,
+// since it is written from the stored items.
+func (s *AdminService) GetDisbursementFile(ctx context.Context, batchID int) (domain.DisbursementBatch, []byte, document_service.DisbursementFormat, error) {
+	batch, err := s.GetDisbursement(ctx, batchID)
+	if err != nil {
+		return domain.DisbursementBatch{}, nil, nil, err
+	}
+	format, ok := document_service.GetDisbursementFormat(batch.Format)
+	if !ok {
+		return domain.DisbursementBatch{}, nil, nil, error_const.ErrUnknownDisbursementFormat
+	}
+	file, err := format.Render(batch)
+	if err != nil {
+		return domain.DisbursementBatch{}, nil, nil, err
+	}
+	return batch, file, format, nil
+}
+
+// CancelDisbursement cancels an exported batch that was not sent to the bank, so the next
+// export of the period pays its amounts instead.
+func (s *AdminService) CancelDisbursement(ctx context.Context, payload dto.CancelDisbursementRequest) (domain.DisbursementBatch, error) {
+	reason := strings.TrimSpace(payload.Reason)
+	if reason == "" {
+		return domain.DisbursementBatch{}, error_const.ErrCancelReasonRequired
+	}
+	batch, err := s.disbursementRepository.CancelDisbursementBatch(ctx, domain.DisbursementBatch{
+		ID:           payload.BatchID,
+		CancelReason: reason,
+		UpdatedBy:    payload.ActorEmail,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.DisbursementBatch{}, error_const.ErrDisbursementBatchNotFound
+	}
+	return batch, err
+}
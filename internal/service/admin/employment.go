@@ -0,0 +1,86 @@
+package admin_service
+
+import (
+	"context"
+	"errors"
+	"payroll-system/internal/delivery/dto"
+	domain "payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func parseOptionalDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, error_const.ErrInvalidDateFormat
+	}
+	return &date, nil
+}
+
+// UpdateEmployment sets the employment dates and status of an employee. Payroll runs prorate
+// the base pay of the first and last period by the employed workdays, and skip inactive
+// employees and employees outside their employment dates. The cost center picks the GL accounts
+// of the employee's payroll journal lines and the company picks the payslip template.
+func (s *AdminService) UpdateEmployment(ctx context.Context, payload dto.EmploymentRequest) (*domain.Employee, error) {
+	if !domain.IsValidEmploymentStatus(payload.Status) {
+		return nil, error_const.ErrInvalidEmploymentStatus
+	}
+	startDate, err := parseOptionalDate(payload.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	endDate, err := parseOptionalDate(payload.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if startDate != nil && endDate != nil && endDate.Before(*startDate) {
+		return nil, error_const.ErrEmploymentEndBeforeStart
+	}
+	if payload.Status == domain.EmploymentStatusTerminated && endDate == nil {
+		return nil, error_const.ErrTerminationDateRequired
+	}
+
+	var costCenter, company string
+	if payload.CostCenter == nil || payload.Company == nil {
+		current, err := s.employeeRepository.GetEmployeeByID(ctx, payload.EmployeeID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, error_const.ErrUserNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		costCenter, company = current.CostCenter, current.Company
+	}
+	if payload.CostCenter != nil {
+		costCenter = strings.TrimSpace(*payload.CostCenter)
+	}
+	if payload.Company != nil {
+		company = strings.TrimSpace(*payload.Company)
+		if company != "" && !domain.IsValidCompanyCode(company) {
+			return nil, error_const.ErrInvalidCompanyCode
+		}
+	}
+
+	employee, err := s.employeeRepository.UpdateEmployment(ctx, domain.Employee{
+		ID:               payload.EmployeeID,
+		StartDate:        startDate,
+		EndDate:          endDate,
+		EmploymentStatus: payload.Status,
+		CostCenter:       costCenter,
+		Company:          company,
+		Updated_by:       payload.ActorEmail,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, error_const.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return employee, nil
+}
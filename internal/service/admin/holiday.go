@@ -0,0 +1,113 @@
+package admin_service
+
+import (
+	"context"
+	"errors"
+	"payroll-system/internal/delivery/dto"
+	domain "payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type HolidayRepository interface {
+	CreateHoliday(ctx context.Context, holiday domain.Holiday) (domain.Holiday, error)
+	UpdateHoliday(ctx context.Context, holiday domain.Holiday) (domain.Holiday, error)
+	DeleteHoliday(ctx context.Context, holidayID int) error
+	GetHolidaysBetween(ctx context.Context, start, end time.Time) ([]domain.Holiday, error)
+	ImportHolidays(ctx context.Context, year int, holidays []domain.Holiday, replace bool) ([]domain.Holiday, error)
+}
+
+func holidayFromRequest(payload dto.HolidayRequest, actorEmail string) (domain.Holiday, error) {
+	date, err := time.Parse("2006-01-02", payload.Date)
+	if err != nil {
+		return domain.Holiday{}, error_const.ErrInvalidDateFormat
+	}
+	name := strings.TrimSpace(payload.Name)
+	if name == "" {
+		return domain.Holiday{}, error_const.ErrHolidayNameRequired
+	}
+	holidayType := payload.Type
+	if holidayType == "" {
+		holidayType = domain.HolidayTypeNational
+	}
+	if !domain.IsValidHolidayType(holidayType) {
+		return domain.Holiday{}, error_const.ErrInvalidHolidayType
+	}
+	return domain.Holiday{
+		Date:      date,
+		Name:      name,
+		Type:      holidayType,
+		CreatedBy: actorEmail,
+		UpdatedBy: actorEmail,
+	}, nil
+}
+
+func holidayWriteError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return error_const.ErrHolidayNotFound
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+		return error_const.ErrHolidayAlreadyExists
+	}
+	return err
+}
+
+func (s *AdminService) CreateHoliday(ctx context.Context, payload dto.HolidayRequest) (domain.Holiday, error) {
+	holiday, err := holidayFromRequest(payload, payload.ActorEmail)
+	if err != nil {
+		return domain.Holiday{}, err
+	}
+	holiday, err = s.holidayRepository.CreateHoliday(ctx, holiday)
+	if err != nil {
+		return domain.Holiday{}, holidayWriteError(err)
+	}
+	return holiday, nil
+}
+
+func (s *AdminService) UpdateHoliday(ctx context.Context, holidayID int, payload dto.HolidayRequest) (domain.Holiday, error) {
+	holiday, err := holidayFromRequest(payload, payload.ActorEmail)
+	if err != nil {
+		return domain.Holiday{}, err
+	}
+	holiday.ID = holidayID
+	holiday, err = s.holidayRepository.UpdateHoliday(ctx, holiday)
+	if err != nil {
+		return domain.Holiday{}, holidayWriteError(err)
+	}
+	return holiday, nil
+}
+
+func (s *AdminService) DeleteHoliday(ctx context.Context, holidayID int) error {
+	if err := s.holidayRepository.DeleteHoliday(ctx, holidayID); err != nil {
+		return holidayWriteError(err)
+	}
+	return nil
+}
+
+// GetHolidays returns the holidays of a calendar year.
+func (s *AdminService) GetHolidays(ctx context.Context, year int) ([]domain.Holiday, error) {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	return s.holidayRepository.GetHolidaysBetween(ctx, start, end)
+}
+
+// ImportHolidays stores a year's holiday list in one transaction. Every date must fall in that year.
+func (s *AdminService) ImportHolidays(ctx context.Context, payload dto.HolidayImportRequest) ([]domain.Holiday, error) {
+	holidays := make([]domain.Holiday, 0, len(payload.Holidays))
+	for _, item := range payload.Holidays {
+		holiday, err := holidayFromRequest(item, payload.ActorEmail)
+		if err != nil {
+			return nil, err
+		}
+		if holiday.Date.Year() != payload.Year {
+			return nil, error_const.ErrHolidayOutsideYear
+		}
+		holidays = append(holidays, holiday)
+	}
+	return s.holidayRepository.ImportHolidays(ctx, payload.Year, holidays, payload.Replace)
+}
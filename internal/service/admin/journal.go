@@ -0,0 +1,89 @@
+package admin_service
+
+import (
+	"context"
+	"errors"
+	"payroll-system/internal/delivery/dto"
+	domain "payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	payroll_service "payroll-system/internal/service/payroll"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type GLAccountRepository interface {
+	UpsertGLAccount(ctx context.Context, account domain.GLAccount) (domain.GLAccount, error)
+	DeleteGLAccount(ctx context.Context, accountID int) error
+	GetAllGLAccounts(ctx context.Context) ([]domain.GLAccount, error)
+}
+
+func (s *AdminService) GetGLAccounts(ctx context.Context) ([]domain.GLAccount, error) {
+	return s.glAccountRepository.GetAllGLAccounts(ctx)
+}
+
+// SetGLAccount sets the account a journal component is posted to, for the employees of a cost
+// center or, without a cost center, for every employee whose cost center has no account.
+func (s *AdminService) SetGLAccount(ctx context.Context, payload dto.GLAccountRequest) (domain.GLAccount, error) {
+	if !domain.IsValidJournalComponent(payload.Component) {
+		return domain.GLAccount{}, error_const.ErrInvalidJournalComponent
+	}
+	accountCode := strings.TrimSpace(payload.AccountCode)
+	if accountCode == "" {
+		return domain.GLAccount{}, error_const.ErrGLAccountCodeRequired
+	}
+	return s.glAccountRepository.UpsertGLAccount(ctx, domain.GLAccount{
+		Component:   payload.Component,
+		CostCenter:  strings.TrimSpace(payload.CostCenter),
+		AccountCode: accountCode,
+		AccountName: strings.TrimSpace(payload.AccountName),
+		CreatedBy:   payload.ActorEmail,
+		UpdatedBy:   payload.ActorEmail,
+	})
+}
+
+func (s *AdminService) DeleteGLAccount(ctx context.Context, accountID int) error {
+	err := s.glAccountRepository.DeleteGLAccount(ctx, accountID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return error_const.ErrGLAccountNotFound
+	}
+	return err
+}
+
+// GetPayrollJournal builds the double-entry journal of the payslips of a locked period, with
+// the employees' current cost centers.
+func (s *AdminService) GetPayrollJournal(ctx context.Context, periodID int) (domain.Journal, error) {
+	period, err := s.getPayrollPeriod(ctx, periodID)
+	if err != nil {
+		return domain.Journal{}, err
+	}
+	if !period.Locked {
+		return domain.Journal{}, error_const.ErrJournalPeriodNotLocked
+	}
+	payrolls, err := s.payrollRepository.GetPayrollsByPeriodID(ctx, period.ID)
+	if err != nil {
+		return domain.Journal{}, err
+	}
+	if len(payrolls) == 0 {
+		return domain.Journal{}, error_const.ErrNoPayrollsFound
+	}
+	employees, err := s.employeeRepository.GetAllEmployees(ctx)
+	if err != nil {
+		return domain.Journal{}, err
+	}
+	costCenters := make(map[int]string, len(employees))
+	for _, employee := range employees {
+		costCenters[employee.ID] = employee.CostCenter
+	}
+	accounts, err := s.glAccountRepository.GetAllGLAccounts(ctx)
+	if err != nil {
+		return domain.Journal{}, err
+	}
+	return payroll_service.BuildJournal(payroll_service.JournalInput{
+		Currency:    s.rounding.DefaultCurrency(),
+		Period:      period,
+		Payrolls:    payrolls,
+		CostCenters: costCenters,
+		Accounts:    accounts,
+	})
+}
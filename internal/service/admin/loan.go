@@ -0,0 +1,102 @@
+package admin_service
+
+import (
+	"context"
+	"errors"
+	"payroll-system/internal/delivery/dto"
+	domain "payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type LoanRepository interface {
+	CreateLoan(ctx context.Context, loan domain.Loan) (domain.Loan, error)
+	GetLoan(ctx context.Context, loanID int) (domain.Loan, error)
+	GetEmployeeLoans(ctx context.Context, employeeID int) ([]domain.Loan, error)
+	CancelLoan(ctx context.Context, loan domain.Loan) (domain.Loan, error)
+	GetLoansDueGroupedByEmployeeID(ctx context.Context, period domain.PayrollPeriod) (map[int][]domain.Loan, error)
+}
+
+const maxLoanInstallments = 120
+
+// CreateLoan gives an employee a loan or salary advance repaid in equal installments, one per
+// payroll period from the period containing the start date.
+func (s *AdminService) CreateLoan(ctx context.Context, payload dto.LoanRequest) (domain.Loan, error) {
+	loanType := payload.Type
+	if loanType == "" {
+		loanType = domain.LoanTypeLoan
+	}
+	if !domain.IsValidLoanType(loanType) {
+		return domain.Loan{}, error_const.ErrInvalidLoanType
+	}
+	if !payload.Principal.IsPositive() {
+		return domain.Loan{}, error_const.ErrInvalidLoanPrincipal
+	}
+	installments := payload.Installments
+	if installments == 0 {
+		installments = 1
+	}
+	if installments < 0 || installments > maxLoanInstallments {
+		return domain.Loan{}, error_const.ErrInvalidLoanInstallments
+	}
+	startDate, err := time.Parse("2006-01-02", payload.StartDate)
+	if err != nil {
+		return domain.Loan{}, error_const.ErrInvalidDateFormat
+	}
+	if _, err := s.employeeRepository.GetEmployeeByID(ctx, payload.EmployeeID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Loan{}, error_const.ErrUserNotFound
+		}
+		return domain.Loan{}, err
+	}
+	return s.loanRepository.CreateLoan(ctx, domain.Loan{
+		EmployeeID:        payload.EmployeeID,
+		Type:              loanType,
+		Principal:         payload.Principal,
+		Installments:      installments,
+		InstallmentAmount: domain.InstallmentAmountFor(payload.Principal, installments),
+		StartDate:         startDate,
+		Description:       strings.TrimSpace(payload.Description),
+		CreatedBy:         payload.ActorEmail,
+		UpdatedBy:         payload.ActorEmail,
+	})
+}
+
+func (s *AdminService) GetLoan(ctx context.Context, loanID int) (domain.Loan, error) {
+	loan, err := s.loanRepository.GetLoan(ctx, loanID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Loan{}, error_const.ErrLoanNotFound
+		}
+		return domain.Loan{}, err
+	}
+	return loan, nil
+}
+
+func (s *AdminService) GetEmployeeLoans(ctx context.Context, employeeID int) ([]domain.Loan, error) {
+	return s.loanRepository.GetEmployeeLoans(ctx, employeeID)
+}
+
+// CancelLoan stops deducting a loan's installments, e.g. when the rest is waived or repaid in
+// cash. Installments already deducted stay on the loan.
+func (s *AdminService) CancelLoan(ctx context.Context, loanID int, actorEmail string) (domain.Loan, error) {
+	loan, err := s.GetLoan(ctx, loanID)
+	if err != nil {
+		return domain.Loan{}, err
+	}
+	if loan.Status != domain.LoanStatusActive {
+		return domain.Loan{}, error_const.ErrLoanNotActive
+	}
+	loan.UpdatedBy = actorEmail
+	cancelled, err := s.loanRepository.CancelLoan(ctx, loan)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Loan{}, error_const.ErrLoanNotActive
+		}
+		return domain.Loan{}, err
+	}
+	return cancelled, nil
+}
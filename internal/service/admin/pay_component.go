@@ -0,0 +1,188 @@
+package admin_service
+
+import (
+	"context"
+	"errors"
+	"payroll-system/internal/delivery/dto"
+	domain "payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type PayComponentRepository interface {
+	CreatePayComponent(ctx context.Context, component domain.PayComponent) (domain.PayComponent, error)
+	UpdatePayComponent(ctx context.Context, component domain.PayComponent) (domain.PayComponent, error)
+	GetPayComponent(ctx context.Context, componentID int) (domain.PayComponent, error)
+	GetAllPayComponents(ctx context.Context) ([]domain.PayComponent, error)
+	CreatePayComponentAssignment(ctx context.Context, assignment domain.PayComponentAssignment) (domain.PayComponentAssignment, error)
+	UpdatePayComponentAssignment(ctx context.Context, assignment domain.PayComponentAssignment) (domain.PayComponentAssignment, error)
+	DeletePayComponentAssignment(ctx context.Context, employeeID, assignmentID int) error
+	GetEmployeePayComponentAssignments(ctx context.Context, employeeID int) ([]domain.PayComponentAssignment, error)
+	GetPayComponentAssignmentsGroupedByEmployeeID(ctx context.Context, start, end time.Time) (map[int][]domain.PayComponentAssignment, error)
+}
+
+var payComponentCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,29}$`)
+
+func (s *AdminService) CreatePayComponent(ctx context.Context, payload dto.PayComponentRequest) (domain.PayComponent, error) {
+	code := strings.ToUpper(strings.TrimSpace(payload.Code))
+	if !payComponentCodePattern.MatchString(code) {
+		return domain.PayComponent{}, error_const.ErrInvalidPayComponentCode
+	}
+	if domain.IsReservedPayslipLineCode(code) {
+		return domain.PayComponent{}, error_const.ErrReservedPayComponentCode
+	}
+	if !domain.IsValidPayComponentCategory(payload.Category) {
+		return domain.PayComponent{}, error_const.ErrInvalidPayComponentCategory
+	}
+	name := strings.TrimSpace(payload.Name)
+	if name == "" {
+		return domain.PayComponent{}, error_const.ErrPayComponentNameRequired
+	}
+	component, err := s.payComponentRepository.CreatePayComponent(ctx, domain.PayComponent{
+		Code:      code,
+		Name:      name,
+		Category:  payload.Category,
+		Taxable:   payload.Taxable && payload.Category == domain.PayslipLineEarning,
+		Active:    payload.Active == nil || *payload.Active,
+		CreatedBy: payload.ActorEmail,
+		UpdatedBy: payload.ActorEmail,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+			return domain.PayComponent{}, error_const.ErrPayComponentAlreadyExists
+		}
+		return domain.PayComponent{}, err
+	}
+	return component, nil
+}
+
+// UpdatePayComponent renames a component or changes its taxable and active flags. Deactivated
+// components are left out of later payroll runs.
+func (s *AdminService) UpdatePayComponent(ctx context.Context, componentID int, payload dto.PayComponentRequest) (domain.PayComponent, error) {
+	current, err := s.getPayComponent(ctx, componentID)
+	if err != nil {
+		return domain.PayComponent{}, err
+	}
+	name := strings.TrimSpace(payload.Name)
+	if name == "" {
+		return domain.PayComponent{}, error_const.ErrPayComponentNameRequired
+	}
+	current.Name = name
+	current.Taxable = payload.Taxable && current.Category == domain.PayslipLineEarning
+	current.Active = payload.Active == nil || *payload.Active
+	current.UpdatedBy = payload.ActorEmail
+	component, err := s.payComponentRepository.UpdatePayComponent(ctx, current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.PayComponent{}, error_const.ErrPayComponentNotFound
+		}
+		return domain.PayComponent{}, err
+	}
+	return component, nil
+}
+
+func (s *AdminService) GetPayComponents(ctx context.Context) ([]domain.PayComponent, error) {
+	return s.payComponentRepository.GetAllPayComponents(ctx)
+}
+
+func (s *AdminService) getPayComponent(ctx context.Context, componentID int) (domain.PayComponent, error) {
+	component, err := s.payComponentRepository.GetPayComponent(ctx, componentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.PayComponent{}, error_const.ErrPayComponentNotFound
+		}
+		return domain.PayComponent{}, err
+	}
+	return component, nil
+}
+
+func payComponentAssignmentFromRequest(payload dto.PayComponentAssignmentRequest) (domain.PayComponentAssignment, error) {
+	if !payload.Amount.IsPositive() {
+		return domain.PayComponentAssignment{}, error_const.ErrInvalidPayComponentAmount
+	}
+	effectiveFrom, err := time.Parse("2006-01-02", payload.EffectiveFrom)
+	if err != nil {
+		return domain.PayComponentAssignment{}, error_const.ErrInvalidDateFormat
+	}
+	effectiveTo, err := parseOptionalDate(payload.EffectiveTo)
+	if err != nil {
+		return domain.PayComponentAssignment{}, err
+	}
+	if effectiveTo != nil && effectiveTo.Before(effectiveFrom) {
+		return domain.PayComponentAssignment{}, error_const.ErrStartDateAfterEndDate
+	}
+	return domain.PayComponentAssignment{
+		EmployeeID:    payload.EmployeeID,
+		ComponentID:   payload.ComponentID,
+		Amount:        payload.Amount,
+		Taxable:       payload.Taxable,
+		EffectiveFrom: effectiveFrom,
+		EffectiveTo:   effectiveTo,
+		CreatedBy:     payload.ActorEmail,
+		UpdatedBy:     payload.ActorEmail,
+	}, nil
+}
+
+func payComponentAssignmentWriteError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return error_const.ErrPayComponentAssignmentNotFound
+	}
+	return err
+}
+
+// AssignPayComponent gives an employee a recurring allowance or deduction. Payroll runs pick up
+// every assignment effective in the period.
+func (s *AdminService) AssignPayComponent(ctx context.Context, payload dto.PayComponentAssignmentRequest) (domain.PayComponentAssignment, error) {
+	assignment, err := payComponentAssignmentFromRequest(payload)
+	if err != nil {
+		return domain.PayComponentAssignment{}, err
+	}
+	component, err := s.getPayComponent(ctx, payload.ComponentID)
+	if err != nil {
+		return domain.PayComponentAssignment{}, err
+	}
+	if !component.Active {
+		return domain.PayComponentAssignment{}, error_const.ErrPayComponentInactive
+	}
+	if _, err := s.employeeRepository.GetEmployeeByID(ctx, payload.EmployeeID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.PayComponentAssignment{}, error_const.ErrUserNotFound
+		}
+		return domain.PayComponentAssignment{}, err
+	}
+	assignment, err = s.payComponentRepository.CreatePayComponentAssignment(ctx, assignment)
+	if err != nil {
+		return domain.PayComponentAssignment{}, payComponentAssignmentWriteError(err)
+	}
+	return assignment, nil
+}
+
+func (s *AdminService) UpdatePayComponentAssignment(ctx context.Context, assignmentID int, payload dto.PayComponentAssignmentRequest) (domain.PayComponentAssignment, error) {
+	assignment, err := payComponentAssignmentFromRequest(payload)
+	if err != nil {
+		return domain.PayComponentAssignment{}, err
+	}
+	assignment.ID = assignmentID
+	assignment, err = s.payComponentRepository.UpdatePayComponentAssignment(ctx, assignment)
+	if err != nil {
+		return domain.PayComponentAssignment{}, payComponentAssignmentWriteError(err)
+	}
+	return assignment, nil
+}
+
+func (s *AdminService) DeletePayComponentAssignment(ctx context.Context, employeeID, assignmentID int) error {
+	if err := s.payComponentRepository.DeletePayComponentAssignment(ctx, employeeID, assignmentID); err != nil {
+		return payComponentAssignmentWriteError(err)
+	}
+	return nil
+}
+
+func (s *AdminService) GetEmployeePayComponents(ctx context.Context, employeeID int) ([]domain.PayComponentAssignment, error) {
+	return s.payComponentRepository.GetEmployeePayComponentAssignments(ctx, employeeID)
+}
@@ -0,0 +1,168 @@
+package admin_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	domain "payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"payroll-system/internal/utils"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+)
+
+type PayrollJobRepository interface {
+	CreatePayrollJob(ctx context.Context, job domain.PayrollJob) (domain.PayrollJob, error)
+	GetPayrollJob(ctx context.Context, jobID int) (domain.PayrollJob, error)
+	GetPayrollJobsByPeriodID(ctx context.Context, periodID int) ([]domain.PayrollJob, error)
+	ClaimNextPayrollJob(ctx context.Context) (domain.PayrollJob, error)
+	RequeueStalePayrollJobs(ctx context.Context, staleAfter time.Duration) (int, error)
+	UpdatePayrollJobProgress(ctx context.Context, job domain.PayrollJob) error
+	FailPayrollJob(ctx context.Context, job domain.PayrollJob) error
+	CompletePayrollJob(ctx context.Context, job domain.PayrollJob, run domain.PayrollRun, payrolls []domain.Payroll) (domain.PayrollRun, error)
+}
+
+const (
+	payrollJobPollInterval  = 2 * time.Second
+	payrollJobStaleAfter    = 5 * time.Minute // running jobs without progress for this long were interrupted
+	payrollJobProgressEvery = 100             // employees between progress writes
+	payrollJobMaxAttempts   = 3
+)
+
+func (s *AdminService) GetPayrollJob(ctx context.Context, jobID int) (domain.PayrollJob, error) {
+	job, err := s.payrollJobRepository.GetPayrollJob(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.PayrollJob{}, error_const.ErrPayrollJobNotFound
+		}
+		return domain.PayrollJob{}, err
+	}
+	return job, nil
+}
+
+func (s *AdminService) GetPayrollJobs(ctx context.Context, periodID int) ([]domain.PayrollJob, error) {
+	if _, err := s.getPayrollPeriod(ctx, periodID); err != nil {
+		return nil, err
+	}
+	return s.payrollJobRepository.GetPayrollJobsByPeriodID(ctx, periodID)
+}
+
+// RunPayrollJobWorker processes queued payroll jobs until ctx is cancelled. Jobs left running
+// by a stopped server are queued again once they go stale, so they resume after a restart.
+func (s *AdminService) RunPayrollJobWorker(ctx context.Context) {
+	ticker := time.NewTicker(payrollJobPollInterval)
+	defer ticker.Stop()
+	for {
+		s.ProcessPayrollJobs(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessPayrollJobs requeues stale jobs and then runs queued jobs until the queue is empty.
+func (s *AdminService) ProcessPayrollJobs(ctx context.Context) {
+	requeued, err := s.payrollJobRepository.RequeueStalePayrollJobs(ctx, payrollJobStaleAfter)
+	if err != nil {
+		utils.Logger.WithError(err).Error("failed to requeue stale payroll jobs")
+	} else if requeued > 0 {
+		utils.Logger.WithField("jobs", requeued).Info("requeued interrupted payroll jobs")
+	}
+
+	for ctx.Err() == nil {
+		job, err := s.payrollJobRepository.ClaimNextPayrollJob(ctx)
+		if err != nil {
+			if !errors.Is(err, pgx.ErrNoRows) && ctx.Err() == nil {
+				utils.Logger.WithError(err).Error("failed to claim payroll job")
+			}
+			return
+		}
+		s.processPayrollJob(ctx, job)
+	}
+}
+
+func (s *AdminService) processPayrollJob(ctx context.Context, job domain.PayrollJob) {
+	log := utils.Logger.WithFields(logrus.Fields{"job_id": job.ID, "period_id": job.PeriodID, "attempt": job.Attempts})
+	log.Info("payroll job started")
+
+	err := s.executePayrollJob(ctx, &job)
+	if err == nil {
+		log.Info("payroll job succeeded")
+		return
+	}
+	if ctx.Err() != nil {
+		// shutting down: the job stays running and is requeued once stale
+		log.WithError(err).Warn("payroll job interrupted")
+		return
+	}
+	if len(job.Errors) == 0 {
+		job.Errors = []domain.PayrollJobError{{Message: err.Error()}}
+	}
+	log.WithError(err).Error("payroll job failed")
+	if err := s.payrollJobRepository.FailPayrollJob(ctx, job); err != nil {
+		log.WithError(err).Error("failed to record payroll job failure")
+	}
+}
+
+func (s *AdminService) executePayrollJob(ctx context.Context, job *domain.PayrollJob) error {
+	if job.Attempts > payrollJobMaxAttempts {
+		return fmt.Errorf("gave up after %d interrupted attempts", payrollJobMaxAttempts)
+	}
+	period, err := s.getRunnablePayrollPeriod(ctx, *job)
+	if err != nil {
+		return err
+	}
+	employees, err := s.selectPayrollEmployees(ctx, period, *job)
+	if err != nil {
+		return err
+	}
+
+	progress := func(processed, total int) {
+		job.TotalEmployees = total
+		job.ProcessedEmployees = processed
+		if processed%payrollJobProgressEvery != 0 && processed != total {
+			return
+		}
+		if err := s.payrollJobRepository.UpdatePayrollJobProgress(ctx, *job); err != nil {
+			utils.Logger.WithError(err).WithField("job_id", job.ID).Warn("failed to record payroll job progress")
+		}
+	}
+	run, err := s.calculatePayrollPeriod(ctx, period, employees, job.CreatedBy, progress)
+	if err != nil {
+		if run != nil {
+			job.Errors = run.failures
+		}
+		return err
+	}
+
+	// periods created before rule sets existed are pinned to the version they were run with
+	if run.period.RuleSetVersion == 0 && run.rules.ID != 0 {
+		run.period.RuleSetVersion = run.rules.Version
+		run.period.UpdatedBy = job.CreatedBy
+		if err := s.payrollRepository.SetPayrollPeriodRuleSetVersion(ctx, run.period); err != nil {
+			return err
+		}
+	}
+
+	payrollRun := domain.PayrollRun{
+		RunType:   job.RunType,
+		Reason:    job.Reason,
+		CreatedBy: job.CreatedBy,
+		UpdatedBy: job.CreatedBy,
+	}
+	job.UpdatedBy = job.CreatedBy
+	_, err = s.payrollJobRepository.CompletePayrollJob(ctx, *job, payrollRun, run.payrolls)
+	return err
+}
+
+// GetPayrollRuns lists the regular and off-cycle runs stored for the period.
+func (s *AdminService) GetPayrollRuns(ctx context.Context, periodID int) ([]domain.PayrollRun, error) {
+	if _, err := s.getPayrollPeriod(ctx, periodID); err != nil {
+		return nil, err
+	}
+	return s.payrollRepository.GetPayrollRunsByPeriodID(ctx, periodID)
+}
@@ -0,0 +1,89 @@
+package admin_service
+
+import (
+	"context"
+	"errors"
+	"payroll-system/internal/delivery/dto"
+	domain "payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetPayrollRun returns a run with its transitions and, while it waits for approval, the
+// payslips to review.
+func (s *AdminService) GetPayrollRun(ctx context.Context, runID int) (domain.PayrollRun, error) {
+	run, err := s.payrollRepository.GetPayrollRun(ctx, runID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.PayrollRun{}, error_const.ErrPayrollRunNotFound
+		}
+		return domain.PayrollRun{}, err
+	}
+	return run, nil
+}
+
+// ApprovePayrollRun stores the payslips of a run waiting for approval; regular runs also lock
+// the period. The run must be approved by another admin than the one who ran it. With
+// PAYSLIP_EMAIL_ON_LOCK the employees are emailed their payslips once a regular run locks the period.
+func (s *AdminService) ApprovePayrollRun(ctx context.Context, payload dto.PayrollRunDecisionRequest) (domain.PayrollRun, error) {
+	if _, err := s.getPendingPayrollRun(ctx, payload); err != nil {
+		return domain.PayrollRun{}, err
+	}
+	run, err := s.payrollRepository.ApprovePayrollRun(ctx, payload.RunID, domain.PayrollRunTransition{
+		Comment:   strings.TrimSpace(payload.Comment),
+		CreatedBy: payload.ActorEmail,
+	})
+	if err != nil {
+		return domain.PayrollRun{}, err
+	}
+	if !run.IsOffCycle() {
+		s.queuePayslipEmailsOnLock(ctx, run.PeriodID, payload.ActorEmail)
+	}
+	return run, nil
+}
+
+// RejectPayrollRun discards the payslips of a run waiting for approval, so the run can be
+// made again. Like approvals, rejections need another admin and a comment explaining them.
+func (s *AdminService) RejectPayrollRun(ctx context.Context, payload dto.PayrollRunDecisionRequest) (domain.PayrollRun, error) {
+	comment := strings.TrimSpace(payload.Comment)
+	if comment == "" {
+		return domain.PayrollRun{}, error_const.ErrRejectionCommentRequired
+	}
+	if _, err := s.getPendingPayrollRun(ctx, payload); err != nil {
+		return domain.PayrollRun{}, err
+	}
+	return s.payrollRepository.RejectPayrollRun(ctx, payload.RunID, domain.PayrollRunTransition{
+		Comment:   comment,
+		CreatedBy: payload.ActorEmail,
+	})
+}
+
+func (s *AdminService) getPendingPayrollRun(ctx context.Context, payload dto.PayrollRunDecisionRequest) (domain.PayrollRun, error) {
+	run, err := s.GetPayrollRun(ctx, payload.RunID)
+	if err != nil {
+		return domain.PayrollRun{}, err
+	}
+	if !run.IsPendingApproval() {
+		return domain.PayrollRun{}, error_const.ErrPayrollRunNotPending
+	}
+	if strings.EqualFold(run.CreatedBy, payload.ActorEmail) {
+		return domain.PayrollRun{}, error_const.ErrPayrollRunSelfApproval
+	}
+	return run, nil
+}
+
+// checkNoPendingPayrollRun refuses to run a period while one of its runs waits for approval.
+func (s *AdminService) checkNoPendingPayrollRun(ctx context.Context, periodID int) error {
+	runs, err := s.payrollRepository.GetPayrollRunsByPeriodID(ctx, periodID)
+	if err != nil {
+		return err
+	}
+	for _, run := range runs {
+		if run.IsPendingApproval() {
+			return error_const.ErrPayrollRunPendingApproval
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package admin_service
+
+import (
+	"context"
+	"errors"
+	domain "payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	document_service "payroll-system/internal/service/document"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetPayslipPDF renders any employee's payslip of a period for printing, with the payslip
+// template of the employee's company when it has one.
+func (s *AdminService) GetPayslipPDF(ctx context.Context, employeeID, periodID int) ([]byte, error) {
+	return s.renderPayslip(ctx, employeeID, periodID, domain.PayslipFormatPDF)
+}
+
+// GetPayslipHTML renders any employee's payslip of a period as a web page, with the payslip
+// template of the employee's company when it has one.
+func (s *AdminService) GetPayslipHTML(ctx context.Context, employeeID, periodID int) ([]byte, error) {
+	return s.renderPayslip(ctx, employeeID, periodID, domain.PayslipFormatHTML)
+}
+
+func (s *AdminService) renderPayslip(ctx context.Context, employeeID, periodID int, format string) ([]byte, error) {
+	payslip, template, err := s.loadPayslip(ctx, employeeID, periodID)
+	if err != nil {
+		return nil, err
+	}
+	return document_service.RenderPayslip(payslip, template, format)
+}
+
+// loadPayslip gathers what the employee's payslip of the period shows, and the template of the
+// employee's company when it has one.
+func (s *AdminService) loadPayslip(ctx context.Context, employeeID, periodID int) (document_service.PayslipDocument, *domain.PayslipTemplate, error) {
+	period, err := s.getPayrollPeriod(ctx, periodID)
+	if err != nil {
+		return document_service.PayslipDocument{}, nil, err
+	}
+	employee, err := s.employeeRepository.GetEmployeeByID(ctx, employeeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return document_service.PayslipDocument{}, nil, error_const.ErrUserNotFound
+		}
+		return document_service.PayslipDocument{}, nil, err
+	}
+	payroll, err := s.payrollRepository.GetEmployeePayslipByPeriod(ctx, domain.Payroll{
+		EmployeeID: employeeID,
+		PeriodID:   periodID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return document_service.PayslipDocument{}, nil, error_const.ErrPayslipNotFound
+		}
+		return document_service.PayslipDocument{}, nil, err
+	}
+	template, err := latestPayslipTemplate(ctx, s.templateRepository, employee.Company)
+	if err != nil {
+		return document_service.PayslipDocument{}, nil, err
+	}
+	return document_service.PayslipDocument{
+		Employer: s.withholder,
+		Employee: *employee,
+		Period:   period,
+		Payroll:  payroll,
+		Rounding: s.rounding,
+	}, template, nil
+}
@@ -0,0 +1,301 @@
+package admin_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"payroll-system/internal/delivery/dto"
+	domain "payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	document_service "payroll-system/internal/service/document"
+	"payroll-system/internal/utils"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+)
+
+type PayslipEmailRepository interface {
+	EnqueuePayslipEmails(ctx context.Context, emails []domain.PayslipEmail) (int, error)
+	ResendPayslipEmail(ctx context.Context, email domain.PayslipEmail) (domain.PayslipEmail, error)
+	GetPayslipEmailsByPeriodID(ctx context.Context, periodID int, status string) ([]domain.PayslipEmail, error)
+	ClaimDuePayslipEmails(ctx context.Context, limit int) ([]domain.PayslipEmail, error)
+	RequeueStalePayslipEmails(ctx context.Context, staleAfter time.Duration) (int, error)
+	UpdatePayslipEmailDelivery(ctx context.Context, email domain.PayslipEmail) error
+}
+
+type PayslipPasswordRepository interface {
+	GetPayslipPassword(ctx context.Context, employeeID int) (domain.PayslipPassword, error)
+}
+
+// Mailer sends one email, see utils.SMTPMailer.
+type Mailer interface {
+	Send(ctx context.Context, message utils.MailMessage) error
+}
+
+const (
+	payslipEmailPollInterval = 10 * time.Second
+	payslipEmailStaleAfter   = 15 * time.Minute // sending emails without an outcome for this long were interrupted
+	payslipEmailBatchSize    = 10
+	payslipEmailMaxAttempts  = 5
+	payslipEmailRetryAfter   = time.Minute // doubled after every failed attempt
+)
+
+// DistributePayslipEmails queues an email with the payslip of every employee paid in a locked
+// period. Employees already emailed for the period are skipped, and so are employees without
+// a usable email address, which are listed in the result.
+func (s *AdminService) DistributePayslipEmails(ctx context.Context, payload dto.PayslipEmailRequest) (domain.PayslipEmailDistribution, error) {
+	if s.mailer == nil {
+		return domain.PayslipEmailDistribution{}, error_const.ErrMailNotConfigured
+	}
+	period, err := s.getPayrollPeriod(ctx, payload.PeriodID)
+	if err != nil {
+		return domain.PayslipEmailDistribution{}, err
+	}
+	if !period.Locked {
+		return domain.PayslipEmailDistribution{}, error_const.ErrPayslipEmailPeriodNotLocked
+	}
+	payrolls, err := s.payrollRepository.GetPayrollsByPeriodID(ctx, period.ID)
+	if err != nil {
+		return domain.PayslipEmailDistribution{}, err
+	}
+	employees, err := s.employeeRepository.GetAllEmployees(ctx)
+	if err != nil {
+		return domain.PayslipEmailDistribution{}, err
+	}
+	addresses := make(map[int]string, len(employees))
+	for _, employee := range employees {
+		addresses[employee.ID] = employee.Email
+	}
+
+	protected := s.protectPayslipAttachment(payload.ProtectAttachment)
+	distribution := domain.PayslipEmailDistribution{PeriodID: period.ID}
+	var emails []domain.PayslipEmail
+	seen := make(map[int]bool, len(payrolls))
+	for _, payroll := range payrolls {
+		if seen[payroll.EmployeeID] {
+			continue
+		}
+		seen[payroll.EmployeeID] = true
+		if _, err := mail.ParseAddress(addresses[payroll.EmployeeID]); err != nil {
+			distribution.WithoutEmail = append(distribution.WithoutEmail, payroll.EmployeeID)
+			continue
+		}
+		emails = append(emails, domain.PayslipEmail{
+			PeriodID:   period.ID,
+			EmployeeID: payroll.EmployeeID,
+			Recipient:  addresses[payroll.EmployeeID],
+			Protected:  protected,
+			CreatedBy:  payload.ActorEmail,
+			UpdatedBy:  payload.ActorEmail,
+		})
+	}
+	distribution.Queued, err = s.payslipEmailRepository.EnqueuePayslipEmails(ctx, emails)
+	if err != nil {
+		return domain.PayslipEmailDistribution{}, err
+	}
+	distribution.AlreadyQueued = len(emails) - distribution.Queued
+	return distribution, nil
+}
+
+// ResendPayslipEmail queues the employee's payslip of a locked period again, to the employee's
+// current email address, whether or not the earlier email was delivered.
+func (s *AdminService) ResendPayslipEmail(ctx context.Context, payload dto.PayslipEmailResendRequest) (domain.PayslipEmail, error) {
+	if s.mailer == nil {
+		return domain.PayslipEmail{}, error_const.ErrMailNotConfigured
+	}
+	period, err := s.getPayrollPeriod(ctx, payload.PeriodID)
+	if err != nil {
+		return domain.PayslipEmail{}, err
+	}
+	if !period.Locked {
+		return domain.PayslipEmail{}, error_const.ErrPayslipEmailPeriodNotLocked
+	}
+	payslip, _, err := s.loadPayslip(ctx, payload.EmployeeID, period.ID)
+	if err != nil {
+		return domain.PayslipEmail{}, err
+	}
+	if _, err := mail.ParseAddress(payslip.Employee.Email); err != nil {
+		return domain.PayslipEmail{}, error_const.ErrEmployeeEmailMissing
+	}
+	email, err := s.payslipEmailRepository.ResendPayslipEmail(ctx, domain.PayslipEmail{
+		PeriodID:   period.ID,
+		EmployeeID: payload.EmployeeID,
+		Recipient:  payslip.Employee.Email,
+		Protected:  s.protectPayslipAttachment(payload.ProtectAttachment),
+		CreatedBy:  payload.ActorEmail,
+		UpdatedBy:  payload.ActorEmail,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.PayslipEmail{}, error_const.ErrPayslipEmailSending
+		}
+		return domain.PayslipEmail{}, err
+	}
+	return email, nil
+}
+
+// GetPayslipEmails lists the delivery status of the period's payslip emails, only those with
+// the status when it is given, e.g. failed.
+func (s *AdminService) GetPayslipEmails(ctx context.Context, periodID int, status string) ([]domain.PayslipEmail, error) {
+	if status != "" && !domain.IsValidPayslipEmailStatus(status) {
+		return nil, error_const.ErrInvalidPayslipEmailStatus
+	}
+	if _, err := s.getPayrollPeriod(ctx, periodID); err != nil {
+		return nil, err
+	}
+	return s.payslipEmailRepository.GetPayslipEmailsByPeriodID(ctx, periodID, status)
+}
+
+// queuePayslipEmailsOnLock distributes the payslips of a period the approval just locked when
+// PAYSLIP_EMAIL_ON_LOCK is set. The approval stands even if queueing fails; admins can then
+// distribute the period by hand.
+func (s *AdminService) queuePayslipEmailsOnLock(ctx context.Context, periodID int, actor string) {
+	if s.mailer == nil || !s.payslipEmailSettings.SendOnLock {
+		return
+	}
+	log := utils.Logger.WithField("period_id", periodID)
+	distribution, err := s.DistributePayslipEmails(ctx, dto.PayslipEmailRequest{PeriodID: periodID, ActorEmail: actor})
+	if err != nil {
+		log.WithError(err).Error("failed to queue payslip emails of the locked period")
+		return
+	}
+	log.WithFields(logrus.Fields{"queued": distribution.Queued, "without_email": len(distribution.WithoutEmail)}).
+		Info("queued payslip emails of the locked period")
+}
+
+// RunPayslipEmailWorker sends queued payslip emails until ctx is cancelled.
+func (s *AdminService) RunPayslipEmailWorker(ctx context.Context) {
+	ticker := time.NewTicker(payslipEmailPollInterval)
+	defer ticker.Stop()
+	for {
+		s.ProcessPayslipEmails(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessPayslipEmails requeues stale emails and then sends the emails that are due until none
+// are left. Failed attempts are retried with a growing delay, up to payslipEmailMaxAttempts.
+func (s *AdminService) ProcessPayslipEmails(ctx context.Context) {
+	requeued, err := s.payslipEmailRepository.RequeueStalePayslipEmails(ctx, payslipEmailStaleAfter)
+	if err != nil {
+		utils.Logger.WithError(err).Error("failed to requeue stale payslip emails")
+	} else if requeued > 0 {
+		utils.Logger.WithField("emails", requeued).Info("requeued interrupted payslip emails")
+	}
+
+	for ctx.Err() == nil {
+		emails, err := s.payslipEmailRepository.ClaimDuePayslipEmails(ctx, payslipEmailBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				utils.Logger.WithError(err).Error("failed to claim payslip emails")
+			}
+			return
+		}
+		if len(emails) == 0 {
+			return
+		}
+		for _, email := range emails {
+			s.processPayslipEmail(ctx, email)
+		}
+	}
+}
+
+func (s *AdminService) processPayslipEmail(ctx context.Context, email domain.PayslipEmail) {
+	log := utils.Logger.WithFields(logrus.Fields{"period_id": email.PeriodID, "employee_id": email.EmployeeID, "attempt": email.Attempts})
+
+	err := s.sendPayslipEmail(ctx, email)
+	if ctx.Err() != nil {
+		// shutting down: the email stays sending and is requeued once stale
+		return
+	}
+	now := time.Now()
+	switch {
+	case err == nil:
+		email.Status = domain.PayslipEmailSent
+		email.LastError = ""
+		email.SentAt = &now
+		log.Info("payslip email sent")
+	case isPermanentPayslipEmailError(err) || email.Attempts >= payslipEmailMaxAttempts:
+		email.Status = domain.PayslipEmailFailed
+		email.LastError = err.Error()
+		log.WithError(err).Error("payslip email failed")
+	default:
+		email.Status = domain.PayslipEmailQueued
+		email.LastError = err.Error()
+		email.NextAttemptAt = now.Add(payslipEmailRetryAfter << (email.Attempts - 1))
+		log.WithError(err).Warn("payslip email will be retried")
+	}
+	if err := s.payslipEmailRepository.UpdatePayslipEmailDelivery(ctx, email); err != nil {
+		log.WithError(err).Error("failed to record payslip email delivery")
+	}
+}
+
+func (s *AdminService) sendPayslipEmail(ctx context.Context, email domain.PayslipEmail) error {
+	payslip, template, err := s.loadPayslip(ctx, email.EmployeeID, email.PeriodID)
+	if err != nil {
+		return err
+	}
+	if email.Protected {
+		password, err := s.payslipPasswordRepository.GetPayslipPassword(ctx, email.EmployeeID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return error_const.ErrNoPayslipPassword
+		}
+		if err != nil {
+			return err
+		}
+		payslip.Password = utils.PDFPassword{User: password.User, Key: password.Key}
+	}
+	pdf, err := document_service.RenderPayslip(payslip, template, domain.PayslipFormatPDF)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, payslipMailMessage(payslip, email, pdf))
+}
+
+func payslipMailMessage(payslip document_service.PayslipDocument, email domain.PayslipEmail, pdf []byte) utils.MailMessage {
+	month := payslip.Period.EndDate.Format("January 2006")
+	subject := "Payslip " + month
+	if payslip.Employer.Name != "" {
+		subject += " – " + payslip.Employer.Name
+	}
+	var body strings.Builder
+	fmt.Fprintf(&body, "Dear %s,\n\nYour payslip for %s is attached.\n", payslip.Employee.Name, month)
+	if email.Protected {
+		body.WriteString("The PDF is password protected: open it with the payslip password you set in the employee portal.\n")
+	}
+	body.WriteString("\nThis email was sent automatically, please contact HR with any questions about your pay.\n")
+	return utils.MailMessage{
+		To:      (&mail.Address{Name: payslip.Employee.Name, Address: email.Recipient}).String(),
+		Subject: subject,
+		Body:    body.String(),
+		Attachments: []utils.MailAttachment{{
+			Filename:    fmt.Sprintf("payslip-%s.pdf", payslip.Period.EndDate.Format("2006-01")),
+			ContentType: "application/pdf",
+			Data:        pdf,
+		}},
+	}
+}
+
+// isPermanentPayslipEmailError reports whether sending the email again cannot succeed until
+// someone fixes the data, so the email fails at once instead of being retried.
+func isPermanentPayslipEmailError(err error) bool {
+	return utils.IsPermanentMailError(err) ||
+		errors.Is(err, error_const.ErrNoPayslipPassword) ||
+		errors.Is(err, error_const.ErrPayslipNotFound) ||
+		errors.Is(err, error_const.ErrUserNotFound) ||
+		errors.Is(err, error_const.ErrPayrollPeriodNotFound)
+}
+
+func (s *AdminService) protectPayslipAttachment(protect *bool) bool {
+	if protect != nil {
+		return *protect
+	}
+	return s.payslipEmailSettings.ProtectAttachment
+}
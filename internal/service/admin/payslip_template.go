@@ -0,0 +1,106 @@
+package admin_service
+
+import (
+	"context"
+	"errors"
+	"payroll-system/internal/delivery/dto"
+	domain "payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	document_service "payroll-system/internal/service/document"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type PayslipTemplateRepository interface {
+	CreatePayslipTemplate(ctx context.Context, template domain.PayslipTemplate) (domain.PayslipTemplate, error)
+	GetPayslipTemplates(ctx context.Context, company string) ([]domain.PayslipTemplate, error)
+	GetPayslipTemplate(ctx context.Context, company string, version int) (domain.PayslipTemplate, error)
+	GetLatestPayslipTemplate(ctx context.Context, company string) (domain.PayslipTemplate, error)
+}
+
+// SavePayslipTemplate adds a version of the company's payslip template once it parses and
+// refers only to fields payslips have. Earlier versions are kept.
+func (s *AdminService) SavePayslipTemplate(ctx context.Context, payload dto.PayslipTemplateRequest) (domain.PayslipTemplate, error) {
+	company := strings.TrimSpace(payload.Company)
+	if !domain.IsValidCompanyCode(company) {
+		return domain.PayslipTemplate{}, error_const.ErrInvalidCompanyCode
+	}
+	if _, err := document_service.ParsePayslipTemplate(payload.Body); err != nil {
+		return domain.PayslipTemplate{}, err
+	}
+	language := strings.TrimSpace(payload.Language)
+	if language == "" {
+		language = "en"
+	}
+	return s.templateRepository.CreatePayslipTemplate(ctx, domain.PayslipTemplate{
+		Company:   company,
+		Name:      strings.TrimSpace(payload.Name),
+		Language:  language,
+		Body:      payload.Body,
+		CreatedBy: payload.ActorEmail,
+	})
+}
+
+// GetPayslipTemplates lists the versions of a company's payslip template, newest first.
+func (s *AdminService) GetPayslipTemplates(ctx context.Context, company string) ([]domain.PayslipTemplate, error) {
+	templates, err := s.templateRepository.GetPayslipTemplates(ctx, company)
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return nil, error_const.ErrPayslipTemplateNotFound
+	}
+	return templates, nil
+}
+
+func (s *AdminService) GetPayslipTemplate(ctx context.Context, company string, version int) (domain.PayslipTemplate, error) {
+	template, err := s.templateRepository.GetPayslipTemplate(ctx, company, version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.PayslipTemplate{}, error_const.ErrPayslipTemplateNotFound
+		}
+		return domain.PayslipTemplate{}, err
+	}
+	return template, nil
+}
+
+// PreviewPayslipTemplate renders a template without saving it, with the payslip of the request
+// or a sample payslip, so admins can see their layout before employees do.
+func (s *AdminService) PreviewPayslipTemplate(ctx context.Context, payload dto.PayslipTemplatePreviewRequest) ([]byte, error) {
+	format := payload.Format
+	if format == "" {
+		format = domain.PayslipFormatHTML
+	}
+	if format != domain.PayslipFormatHTML && format != domain.PayslipFormatPDF {
+		return nil, error_const.ErrUnknownPayslipFormat
+	}
+	if _, err := document_service.ParsePayslipTemplate(payload.Body); err != nil {
+		return nil, err
+	}
+	payslip := document_service.SamplePayslipDocument()
+	if payload.Payslip != nil {
+		payslip.Payroll.Payslip = *payload.Payslip
+	}
+	language := strings.TrimSpace(payload.Language)
+	if language == "" {
+		language = "en"
+	}
+	return document_service.RenderPayslip(payslip, &domain.PayslipTemplate{Body: payload.Body, Language: language}, format)
+}
+
+// latestPayslipTemplate is the template payslips of the company are rendered with, or nil when
+// the employee has no company or the company has no template.
+func latestPayslipTemplate(ctx context.Context, repo PayslipTemplateRepository, company string) (*domain.PayslipTemplate, error) {
+	if company == "" {
+		return nil, nil
+	}
+	template, err := repo.GetLatestPayslipTemplate(ctx, company)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
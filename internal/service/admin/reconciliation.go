@@ -0,0 +1,124 @@
+package admin_service
+
+import (
+	"context"
+	"errors"
+	"payroll-system/internal/delivery/dto"
+	domain "payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	reconciliation_service "payroll-system/internal/service/reconciliation"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// MarkDisbursementSent records that a batch's file was sent to the bank. Its pending payments
+// become sent and the batch can no longer be cancelled.
+func (s *AdminService) MarkDisbursementSent(ctx context.Context, payload dto.DisbursementSentRequest) (domain.DisbursementBatch, error) {
+	batch, err := s.disbursementRepository.MarkDisbursementBatchSent(ctx, domain.DisbursementBatch{
+		ID:        payload.BatchID,
+		UpdatedBy: payload.ActorEmail,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.DisbursementBatch{}, error_const.ErrDisbursementBatchNotFound
+	}
+	return batch, err
+}
+
+// UpdatePaymentStatus records the outcome of a payment the bank statement does not show, e.g.
+// a transfer the bank rejected.
+func (s *AdminService) UpdatePaymentStatus(ctx context.Context, payload dto.PaymentStatusRequest) (domain.DisbursementItem, error) {
+	if !domain.IsValidPaymentStatus(payload.Status) {
+		return domain.DisbursementItem{}, error_const.ErrInvalidPaymentStatus
+	}
+	item, err := s.disbursementRepository.UpdateDisbursementItemStatus(ctx, domain.DisbursementItem{
+		ID:              payload.ItemID,
+		BatchID:         payload.BatchID,
+		Status:          payload.Status,
+		StatusNote:      strings.TrimSpace(payload.Note),
+		StatusUpdatedBy: payload.ActorEmail,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.DisbursementItem{}, error_const.ErrDisbursementItemNotFound
+	}
+	return item, err
+}
+
+// ImportBankStatement reads a statement of the company account and matches its entries to
+// the payments of exported batches: debits mark payments paid and credits mark them returned.
+// Entries imported before are skipped.
+func (s *AdminService) ImportBankStatement(ctx context.Context, payload dto.BankStatementImportRequest) (domain.BankStatementImport, error) {
+	statement, err := reconciliation_service.ParseStatement(payload.Format, strings.NewReader(payload.Content))
+	if err != nil {
+		return domain.BankStatementImport{}, err
+	}
+	fingerprints := make([]string, len(statement.Entries))
+	for i, entry := range statement.Entries {
+		fingerprints[i] = entry.Fingerprint
+	}
+	imported, err := s.disbursementRepository.GetImportedStatementFingerprints(ctx, fingerprints)
+	if err != nil {
+		return domain.BankStatementImport{}, err
+	}
+	for i := range statement.Entries {
+		statement.Entries[i].Duplicate = imported[statement.Entries[i].Fingerprint]
+	}
+	items, err := s.disbursementRepository.GetDisbursementItems(ctx, 0, []string{domain.PaymentPending, domain.PaymentSent, domain.PaymentPaid})
+	if err != nil {
+		return domain.BankStatementImport{}, err
+	}
+	reconciliation_service.MatchStatement(statement.Entries, items)
+
+	return s.disbursementRepository.SaveBankStatementImport(ctx, domain.BankStatementImport{
+		Format:        payload.Format,
+		AccountNumber: statement.AccountNumber,
+		Entries:       statement.Entries,
+		CreatedBy:     payload.ActorEmail,
+	})
+}
+
+// GetReconciliationReport lists the payments of a period's exported batches, or of every
+// period when periodID is 0, that are not confirmed as paid, and the statement entries that
+// matched no payment.
+func (s *AdminService) GetReconciliationReport(ctx context.Context, periodID int) (domain.ReconciliationReport, error) {
+	if periodID != 0 {
+		if _, err := s.getPayrollPeriod(ctx, periodID); err != nil {
+			return domain.ReconciliationReport{}, err
+		}
+	}
+	statuses := []string{domain.PaymentPending, domain.PaymentSent, domain.PaymentPaid, domain.PaymentFailed, domain.PaymentReturned}
+	items, err := s.disbursementRepository.GetDisbursementItems(ctx, periodID, statuses)
+	if err != nil {
+		return domain.ReconciliationReport{}, err
+	}
+	entries, err := s.disbursementRepository.GetUnmatchedBankStatementEntries(ctx)
+	if err != nil {
+		return domain.ReconciliationReport{}, err
+	}
+
+	report := domain.ReconciliationReport{
+		PeriodID:         periodID,
+		Totals:           make([]domain.PaymentStatusTotal, len(statuses)),
+		Unconfirmed:      []domain.ReconciliationItem{},
+		ToReissue:        []domain.ReconciliationItem{},
+		UnmatchedEntries: entries,
+	}
+	for i, status := range statuses {
+		report.Totals[i].Status = status
+	}
+	for _, item := range items {
+		for i := range report.Totals {
+			if report.Totals[i].Status == item.Status {
+				report.Totals[i].ItemCount++
+				report.Totals[i].Amount = report.Totals[i].Amount.Add(item.Amount)
+			}
+		}
+		switch {
+		case item.Status == domain.PaymentPending || item.Status == domain.PaymentSent:
+			report.Unconfirmed = append(report.Unconfirmed, item)
+		case domain.IsUnpaid(item.Status) && item.ReissuedBatchID == 0:
+			report.ToReissue = append(report.ToReissue, item)
+		}
+	}
+	return report, nil
+}
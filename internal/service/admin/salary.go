@@ -0,0 +1,57 @@
+package admin_service
+
+import (
+	"context"
+	"errors"
+	"payroll-system/internal/delivery/dto"
+	domain "payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type SalaryRepository interface {
+	CreateSalaryChange(ctx context.Context, change domain.SalaryChange) (domain.SalaryChange, error)
+	GetSalaryHistory(ctx context.Context, employeeID int) ([]domain.SalaryChange, error)
+	GetSalaryHistoriesGroupedByEmployeeID(ctx context.Context, until time.Time) (map[int][]domain.SalaryChange, error)
+}
+
+// CreateSalaryChange records a new salary for an employee from the given date. Payroll runs
+// prorate the base pay of a period across the salaries in effect during it.
+func (s *AdminService) CreateSalaryChange(ctx context.Context, payload dto.SalaryChangeRequest) (domain.SalaryChange, error) {
+	if !payload.Salary.IsPositive() {
+		return domain.SalaryChange{}, error_const.ErrInvalidSalary
+	}
+	effectiveFrom, err := time.Parse("2006-01-02", payload.EffectiveFrom)
+	if err != nil {
+		return domain.SalaryChange{}, error_const.ErrInvalidDateFormat
+	}
+	if _, err := s.employeeRepository.GetEmployeeByID(ctx, payload.EmployeeID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.SalaryChange{}, error_const.ErrUserNotFound
+		}
+		return domain.SalaryChange{}, err
+	}
+	change, err := s.salaryRepository.CreateSalaryChange(ctx, domain.SalaryChange{
+		EmployeeID:    payload.EmployeeID,
+		Salary:        payload.Salary,
+		EffectiveFrom: effectiveFrom,
+		Reason:        strings.TrimSpace(payload.Reason),
+		CreatedBy:     payload.ActorEmail,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+			return domain.SalaryChange{}, error_const.ErrSalaryChangeAlreadyExists
+		}
+		return domain.SalaryChange{}, err
+	}
+	return change, nil
+}
+
+func (s *AdminService) GetSalaryHistory(ctx context.Context, employeeID int) ([]domain.SalaryChange, error) {
+	return s.salaryRepository.GetSalaryHistory(ctx, employeeID)
+}
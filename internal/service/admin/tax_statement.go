@@ -0,0 +1,97 @@
+package admin_service
+
+import (
+	"context"
+	"errors"
+	"payroll-system/internal/delivery/dto"
+	domain "payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	document_service "payroll-system/internal/service/document"
+	payroll_service "payroll-system/internal/service/payroll"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type TaxStatementRepository interface {
+	GetTaxYearPayslipsGroupedByEmployeeID(ctx context.Context, taxYear int) (map[int][]domain.TaxYearPayslip, error)
+	SaveTaxStatements(ctx context.Context, statements []domain.TaxStatement) ([]domain.TaxStatement, error)
+	GetTaxStatement(ctx context.Context, employeeID, taxYear int) (domain.TaxStatement, error)
+	GetTaxStatementsByTaxYear(ctx context.Context, taxYear int) ([]domain.TaxStatement, error)
+}
+
+// GenerateTaxStatements calculates the 1721-A1 statements of a tax year from the payslips of
+// its locked periods and stores them, replacing statements generated earlier.
+func (s *AdminService) GenerateTaxStatements(ctx context.Context, payload dto.TaxStatementRequest) ([]domain.TaxStatement, error) {
+	if err := validateTaxYear(payload.TaxYear); err != nil {
+		return nil, err
+	}
+	selected := make(map[int]bool, len(payload.EmployeeIDs))
+	for _, id := range payload.EmployeeIDs {
+		selected[id] = true
+	}
+	payslips, err := s.taxStatementRepository.GetTaxYearPayslipsGroupedByEmployeeID(ctx, payload.TaxYear)
+	if err != nil {
+		return nil, err
+	}
+	employees, err := s.employeeRepository.GetAllEmployees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	taxProfiles, err := s.taxProfileRepository.GetTaxProfilesGroupedByEmployeeID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []domain.TaxStatement
+	for _, employee := range employees {
+		if len(selected) > 0 && !selected[employee.ID] {
+			continue
+		}
+		if len(payslips[employee.ID]) == 0 {
+			continue
+		}
+		statement, err := payroll_service.CalculateTaxStatement(payroll_service.TaxStatementInput{
+			Rounding:   s.rounding,
+			TaxYear:    payload.TaxYear,
+			Withholder: s.withholder,
+			Employee:   employee,
+			TaxProfile: taxProfileOrDefault(taxProfiles, employee.ID),
+			Payslips:   payslips[employee.ID],
+		})
+		if err != nil {
+			return nil, err
+		}
+		statement.GeneratedBy = payload.ActorEmail
+		statements = append(statements, statement)
+	}
+	if len(statements) == 0 {
+		return nil, error_const.ErrNoPayslipsInTaxYear
+	}
+	return s.taxStatementRepository.SaveTaxStatements(ctx, statements)
+}
+
+func (s *AdminService) GetTaxStatements(ctx context.Context, taxYear int) ([]domain.TaxStatement, error) {
+	if err := validateTaxYear(taxYear); err != nil {
+		return nil, err
+	}
+	return s.taxStatementRepository.GetTaxStatementsByTaxYear(ctx, taxYear)
+}
+
+func (s *AdminService) GetTaxStatement(ctx context.Context, employeeID, taxYear int) (domain.TaxStatement, error) {
+	statement, err := s.taxStatementRepository.GetTaxStatement(ctx, employeeID, taxYear)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.TaxStatement{}, error_const.ErrTaxStatementNotFound
+		}
+		return domain.TaxStatement{}, err
+	}
+	return statement, nil
+}
+
+func (s *AdminService) GetTaxStatementPDF(ctx context.Context, employeeID, taxYear int) ([]byte, error) {
+	statement, err := s.GetTaxStatement(ctx, employeeID, taxYear)
+	if err != nil {
+		return nil, err
+	}
+	return document_service.RenderTaxStatementPDF(statement)
+}
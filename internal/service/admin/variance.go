@@ -0,0 +1,123 @@
+package admin_service
+
+import (
+	"context"
+	"errors"
+	"payroll-system/internal/delivery/dto"
+	domain "payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	payroll_service "payroll-system/internal/service/payroll"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	defaultVarianceChangePercent     = "10"
+	defaultReimbursementSpikePercent = "100"
+	defaultReimbursementSpikeMinimum = "0"
+)
+
+// GetPayrollVariance compares the payslips of a period with a previous period's. A period that
+// has not been approved yet is compared with the payslips of its run waiting for approval.
+func (s *AdminService) GetPayrollVariance(ctx context.Context, payload dto.PayrollVarianceRequest) (domain.PayrollVarianceReport, error) {
+	thresholds, err := parseVarianceThresholds(payload)
+	if err != nil {
+		return domain.PayrollVarianceReport{}, err
+	}
+	period, err := s.getPayrollPeriod(ctx, payload.PeriodID)
+	if err != nil {
+		return domain.PayrollVarianceReport{}, err
+	}
+	var previous domain.PayrollPeriod
+	if payload.PreviousPeriodID == 0 {
+		previous, err = s.payrollRepository.GetPreviousPayrollPeriod(ctx, period)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.PayrollVarianceReport{}, error_const.ErrPreviousPayrollPeriodNotFound
+		}
+	} else {
+		previous, err = s.getPayrollPeriod(ctx, payload.PreviousPeriodID)
+	}
+	if err != nil {
+		return domain.PayrollVarianceReport{}, err
+	}
+
+	current, pending, err := s.getComparedPayrolls(ctx, period.ID)
+	if err != nil {
+		return domain.PayrollVarianceReport{}, err
+	}
+	if len(current) == 0 {
+		return domain.PayrollVarianceReport{}, error_const.ErrNoPayrollsFound
+	}
+	before, err := s.payrollRepository.GetPayrollsByPeriodID(ctx, previous.ID)
+	if err != nil {
+		return domain.PayrollVarianceReport{}, err
+	}
+	employees, err := s.employeeRepository.GetAllEmployees(ctx)
+	if err != nil {
+		return domain.PayrollVarianceReport{}, err
+	}
+	names := make(map[int]string, len(employees))
+	for _, employee := range employees {
+		names[employee.ID] = employee.Name
+	}
+
+	report := payroll_service.ComparePayrolls(before, current, thresholds)
+	report.PeriodID = period.ID
+	report.PreviousPeriodID = previous.ID
+	report.Pending = pending
+	for i := range report.Employees {
+		report.Employees[i].EmployeeName = names[report.Employees[i].EmployeeID]
+	}
+	return report, nil
+}
+
+// getComparedPayrolls returns the stored payrolls of the period, or the payrolls of its run
+// waiting for approval when none are stored yet.
+func (s *AdminService) getComparedPayrolls(ctx context.Context, periodID int) ([]domain.Payroll, bool, error) {
+	payrolls, err := s.payrollRepository.GetPayrollsByPeriodID(ctx, periodID)
+	if err != nil || len(payrolls) > 0 {
+		return payrolls, false, err
+	}
+	runs, err := s.payrollRepository.GetPayrollRunsByPeriodID(ctx, periodID)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, run := range runs {
+		if !run.IsPendingApproval() {
+			continue
+		}
+		run, err = s.payrollRepository.GetPayrollRun(ctx, run.ID)
+		if err != nil {
+			return nil, false, err
+		}
+		return run.Payrolls, true, nil
+	}
+	return nil, false, nil
+}
+
+func parseVarianceThresholds(payload dto.PayrollVarianceRequest) (domain.VarianceThresholds, error) {
+	changePercent, err := domain.NewRate(orDefault(payload.ChangePercent, defaultVarianceChangePercent))
+	if err != nil || changePercent.Sign() < 0 {
+		return domain.VarianceThresholds{}, error_const.ErrInvalidVarianceThreshold
+	}
+	spikePercent, err := domain.NewRate(orDefault(payload.ReimbursementSpikePercent, defaultReimbursementSpikePercent))
+	if err != nil || spikePercent.Sign() < 0 {
+		return domain.VarianceThresholds{}, error_const.ErrInvalidVarianceThreshold
+	}
+	spikeMinimum, err := domain.ParseMoney(orDefault(payload.ReimbursementSpikeMinimum, defaultReimbursementSpikeMinimum))
+	if err != nil || spikeMinimum.IsNegative() {
+		return domain.VarianceThresholds{}, error_const.ErrInvalidVarianceThreshold
+	}
+	return domain.VarianceThresholds{
+		ChangePercent:             changePercent,
+		ReimbursementSpikePercent: spikePercent,
+		ReimbursementSpikeMinimum: spikeMinimum,
+	}, nil
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
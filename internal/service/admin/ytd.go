@@ -0,0 +1,61 @@
+package admin_service
+
+import (
+	"context"
+	"errors"
+	"payroll-system/internal/delivery/dto"
+	domain "payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	minTaxYear = 2000
+	maxTaxYear = 2100
+)
+
+func validateTaxYear(taxYear int) error {
+	if taxYear < minTaxYear || taxYear > maxTaxYear {
+		return error_const.ErrInvalidTaxYear
+	}
+	return nil
+}
+
+// GetEmployeeYearToDate returns an employee's accumulators for a tax year. Employees without
+// payslips in a locked period of the year get zero totals.
+func (s *AdminService) GetEmployeeYearToDate(ctx context.Context, employeeID, taxYear int) (domain.TaxYearToDate, error) {
+	if err := validateTaxYear(taxYear); err != nil {
+		return domain.TaxYearToDate{}, err
+	}
+	ytd, err := s.payrollRepository.GetYearToDate(ctx, employeeID, taxYear)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.TaxYearToDate{EmployeeID: employeeID, TaxYear: taxYear}, nil
+		}
+		return domain.TaxYearToDate{}, err
+	}
+	return ytd, nil
+}
+
+func (s *AdminService) GetYearToDate(ctx context.Context, taxYear int) ([]domain.TaxYearToDate, error) {
+	if err := validateTaxYear(taxYear); err != nil {
+		return nil, err
+	}
+	return s.payrollRepository.GetYearToDateByTaxYear(ctx, taxYear)
+}
+
+// RebuildYearToDate recalculates the accumulators from the stored payslips of the locked
+// periods, e.g. after payslips were corrected outside the application.
+func (s *AdminService) RebuildYearToDate(ctx context.Context, payload dto.YearToDateRebuildRequest) (dto.YearToDateRebuildResponse, error) {
+	if payload.TaxYear != 0 {
+		if err := validateTaxYear(payload.TaxYear); err != nil {
+			return dto.YearToDateRebuildResponse{}, err
+		}
+	}
+	count, err := s.payrollRepository.RebuildYearToDate(ctx, payload.TaxYear)
+	if err != nil {
+		return dto.YearToDateRebuildResponse{}, err
+	}
+	return dto.YearToDateRebuildResponse{TaxYear: payload.TaxYear, Accumulators: count}, nil
+}
@@ -0,0 +1,197 @@
+package document_service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"payroll-system/internal/domain"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DisbursementFormat writes a disbursement batch as a bank bulk-transfer file. Every format
+// carries the batch's control totals, the item count and total amount, and its checksum.
+type DisbursementFormat interface {
+	Render(batch domain.DisbursementBatch) ([]byte, error)
+	ContentType() string
+	Extension() string
+}
+
+var (
+	disbursementFormatsMu sync.RWMutex
+	disbursementFormats   = map[string]DisbursementFormat{
+		"csv":         csvDisbursementFormat{},
+		"fixed_width": FixedWidthDisbursementFormat{},
+	}
+)
+
+// RegisterDisbursementFormat adds a bank file format, or replaces the format of that name. It is
+// meant to be called at startup.
+func RegisterDisbursementFormat(name string, format DisbursementFormat) {
+	disbursementFormatsMu.Lock()
+	defer disbursementFormatsMu.Unlock()
+	disbursementFormats[name] = format
+}
+
+func GetDisbursementFormat(name string) (DisbursementFormat, bool) {
+	disbursementFormatsMu.RLock()
+	defer disbursementFormatsMu.RUnlock()
+	format, ok := disbursementFormats[name]
+	return format, ok
+}
+
+// DisbursementFormatNames lists the registered formats in name order.
+func DisbursementFormatNames() []string {
+	disbursementFormatsMu.RLock()
+	defer disbursementFormatsMu.RUnlock()
+	names := make([]string, 0, len(disbursementFormats))
+	for name := range disbursementFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// csvDisbursementFormat is a generic CSV file: an H row with the batch and its control totals,
+// then a D row per transfer.
+//
+//	H,reference,value_date,source_bank_code,source_account_number,currency,item_count,total_amount,checksum
+//	D,line,employee_id,bank_code,account_number,account_holder,amount,remark
+type csvDisbursementFormat struct{}
+
+func (csvDisbursementFormat) ContentType() string { return "text/csv" }
+func (csvDisbursementFormat) Extension() string   { return "csv" }
+
+func (csvDisbursementFormat) Render(batch domain.DisbursementBatch) ([]byte, error) {
+	var out bytes.Buffer
+	w := csv.NewWriter(&out)
+	err := w.Write([]string{
+		"H",
+		batch.Reference,
+		batch.ValueDate.Format("2006-01-02"),
+		batch.Source.BankCode,
+		batch.Source.AccountNumber,
+		batch.Currency,
+		strconv.Itoa(batch.ItemCount),
+		batch.TotalAmount.String(),
+		batch.Checksum,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range batch.Items {
+		err := w.Write([]string{
+			"D",
+			strconv.Itoa(item.Line),
+			strconv.Itoa(item.EmployeeID),
+			item.BankCode,
+			item.AccountNumber,
+			item.AccountHolder,
+			item.Amount.String(),
+			disbursementRemark(batch),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// FixedWidthDisbursementFormat is the record layout common to Indonesian bank bulk-transfer
+// uploads: a header record, a detail record per transfer and a trailer record, each padded to
+// RecordLength and ended with CRLF. Text is upper case ASCII, left aligned and space padded;
+// numbers are right aligned and zero padded, with amounts in minor units (2 implied decimals).
+//
+//	header:  H, reference 12, value date YYYYMMDD, source bank 11, source account 20, currency 3,
+//	         item count 6, total amount 18, checksum 64
+//	detail:  D, line 6, bank 11, account 20, holder 35, amount 18, remark 35
+//	trailer: T, item count 6, total amount 18
+//
+// Banks with another layout can register their own DisbursementFormat.
+type FixedWidthDisbursementFormat struct {
+	RecordLength int // defaults to 200
+}
+
+func (FixedWidthDisbursementFormat) ContentType() string { return "text/plain" }
+func (FixedWidthDisbursementFormat) Extension() string   { return "txt" }
+
+func (f FixedWidthDisbursementFormat) Render(batch domain.DisbursementBatch) ([]byte, error) {
+	length := f.RecordLength
+	if length == 0 {
+		length = 200
+	}
+	var out bytes.Buffer
+	write := func(fields ...string) error {
+		record := strings.Join(fields, "")
+		if len(record) > length {
+			return fmt.Errorf("disbursement record is %d characters, longer than %d", len(record), length)
+		}
+		out.WriteString(record + strings.Repeat(" ", length-len(record)) + "\r\n")
+		return nil
+	}
+	err := write(
+		"H",
+		fixedText(batch.Reference, 12),
+		batch.ValueDate.Format("20060102"),
+		fixedText(batch.Source.BankCode, 11),
+		fixedText(batch.Source.AccountNumber, 20),
+		fixedText(batch.Currency, 3),
+		fixedNumber(int64(batch.ItemCount), 6),
+		fixedNumber(batch.TotalAmount.MinorUnits(), 18),
+		batch.Checksum,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range batch.Items {
+		err := write(
+			"D",
+			fixedNumber(int64(item.Line), 6),
+			fixedText(item.BankCode, 11),
+			fixedText(item.AccountNumber, 20),
+			fixedText(item.AccountHolder, 35),
+			fixedNumber(item.Amount.MinorUnits(), 18),
+			fixedText(disbursementRemark(batch), 35),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := write("T", fixedNumber(int64(batch.ItemCount), 6), fixedNumber(batch.TotalAmount.MinorUnits(), 18)); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// disbursementRemark is the transfer description the employees see on their statements.
+func disbursementRemark(batch domain.DisbursementBatch) string {
+	return fmt.Sprintf("SALARY %s %s", batch.ValueDate.Format("012006"), batch.Reference)
+}
+
+// fixedText upper cases the text, replaces characters banks reject with spaces and pads or
+// truncates it to width.
+func fixedText(text string, width int) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(text) {
+		if b.Len() == width {
+			break
+		}
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', strings.ContainsRune(" .,-/'", r):
+			b.WriteRune(r)
+		default:
+			b.WriteByte(' ')
+		}
+	}
+	return b.String() + strings.Repeat(" ", width-b.Len())
+}
+
+func fixedNumber(n int64, width int) string {
+	return fmt.Sprintf("%0*d", width, n)
+}
@@ -0,0 +1,75 @@
+package document_service
+
+import (
+	"payroll-system/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+func disbursementBatch() domain.DisbursementBatch {
+	items := []domain.DisbursementItem{
+		{Line: 1, EmployeeID: 1, BankCode: "014", AccountNumber: "1234567890", AccountHolder: "Budi Santoso", Amount: domain.NewMoney(9450000)},
+		{Line: 2, EmployeeID: 2, BankCode: "008", AccountNumber: "9876543210", AccountHolder: "Sári Dewi", Amount: domain.NewMoney(6350000)},
+	}
+	batch := domain.DisbursementBatch{
+		ID:          12,
+		ValueDate:   time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		Source:      domain.DisbursementSource{BankCode: "014", AccountNumber: "5550001111"},
+		Currency:    "IDR",
+		ItemCount:   len(items),
+		TotalAmount: domain.NewMoney(15800000),
+		Checksum:    domain.DisbursementChecksum(items),
+		Items:       items,
+	}
+	batch.Reference = batch.BatchReference()
+	return batch
+}
+
+func TestRenderDisbursementFixedWidth(t *testing.T) {
+	format, ok := GetDisbursementFormat("fixed_width")
+	if !ok {
+		t.Fatal("fixed_width format is not registered")
+	}
+	file, err := format.Render(disbursementBatch())
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	records := strings.Split(strings.TrimSuffix(string(file), "\r\n"), "\r\n")
+	if len(records) != 4 {
+		t.Fatalf("got %d records, want header, 2 details and trailer:\n%s", len(records), file)
+	}
+	for i, record := range records {
+		if len(record) != 200 {
+			t.Errorf("record %d is %d characters, want 200", i, len(record))
+		}
+	}
+	if !strings.HasPrefix(records[0], "HDISB00000012"+"20250131"+"014        "+"5550001111          "+"IDR"+"000002"+"000000001580000000") {
+		t.Errorf("header = %q", records[0])
+	}
+	if !strings.HasPrefix(records[2], "D000002"+"008        "+"9876543210          "+"S RI DEWI                          "+"000000000635000000") {
+		t.Errorf("detail = %q, want the name in upper case ASCII", records[2])
+	}
+	if !strings.HasPrefix(records[3], "T000002000000001580000000") {
+		t.Errorf("trailer = %q", records[3])
+	}
+}
+
+func TestRenderDisbursementCSV(t *testing.T) {
+	format, _ := GetDisbursementFormat("csv")
+	batch := disbursementBatch()
+	file, err := format.Render(batch)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(file)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d rows, want 3:\n%s", len(lines), file)
+	}
+	if lines[0] != "H,DISB00000012,2025-01-31,014,5550001111,IDR,2,15800000.00,"+batch.Checksum {
+		t.Errorf("header = %q", lines[0])
+	}
+	if lines[1] != "D,1,1,014,1234567890,Budi Santoso,9450000.00,SALARY 012025 DISB00000012" {
+		t.Errorf("detail = %q", lines[1])
+	}
+}
@@ -0,0 +1,29 @@
+package document_service
+
+import (
+	"fmt"
+	"payroll-system/internal/domain"
+	"strings"
+)
+
+// FormatAmount writes an amount the Indonesian way, with dots between thousands and a
+// decimal comma, e.g. 1.250.000 or 1.250.000,50. Cents are left out when there are none.
+func FormatAmount(m domain.Money) string {
+	minor := m.MinorUnits()
+	sign := ""
+	if minor < 0 {
+		sign, minor = "-", -minor
+	}
+	digits := fmt.Sprintf("%d", minor/100)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	if cents := minor % 100; cents != 0 {
+		fmt.Fprintf(&b, ",%02d", cents)
+	}
+	return sign + b.String()
+}
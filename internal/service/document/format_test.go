@@ -0,0 +1,25 @@
+package document_service
+
+import (
+	"payroll-system/internal/domain"
+	"testing"
+)
+
+func TestFormatAmount(t *testing.T) {
+	cases := map[string]string{
+		"0":          "0",
+		"950":        "950",
+		"1250000":    "1.250.000",
+		"1250000.50": "1.250.000,50",
+		"-125000.05": "-125.000,05",
+	}
+	for amount, want := range cases {
+		m, err := domain.ParseMoney(amount)
+		if err != nil {
+			t.Fatalf("ParseMoney(%q): %v", amount, err)
+		}
+		if got := FormatAmount(m); got != want {
+			t.Errorf("FormatAmount(%s) = %q, want %q", amount, got, want)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package document_service
+
+import (
+	"bytes"
+	"payroll-system/internal/utils"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// RenderHTMLPDF lays out an HTML page, such as a payslip rendered from a template, as a PDF.
+// Only what payslips need is supported: headings, paragraphs, lists, line breaks, rules, bold
+// text and tables, whose rows are split into equal columns with the first cell left aligned and
+// the others right aligned like amounts. Styles, scripts and images are ignored.
+func RenderHTMLPDF(title string, page []byte) ([]byte, error) {
+	return layoutHTML(title, page).Bytes()
+}
+
+func layoutHTML(title string, page []byte) *utils.PDF {
+	l := &htmlLayout{doc: utils.NewPDF(title), y: 50, size: 10}
+	l.doc.AddPage()
+	z := html.NewTokenizer(bytes.NewReader(page))
+	skip := 0
+	for {
+		token := z.Next()
+		if token == html.ErrorToken {
+			// io.EOF at the end of the page; anything else is as far as it can be read
+			break
+		}
+		name, _ := z.TagName()
+		tag := string(name)
+		switch token {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if skip > 0 || htmlSkipped[tag] {
+				if token == html.StartTagToken && htmlSkipped[tag] && !htmlVoid[tag] {
+					skip++
+				}
+				continue
+			}
+			l.start(tag)
+		case html.EndTagToken:
+			if skip > 0 || htmlSkipped[tag] {
+				if htmlSkipped[tag] && !htmlVoid[tag] && skip > 0 {
+					skip--
+				}
+				continue
+			}
+			l.end(tag)
+		case html.TextToken:
+			if skip == 0 {
+				l.text(string(z.Text()))
+			}
+		}
+	}
+	l.flush()
+	l.row()
+	return l.doc
+}
+
+// htmlSkipped are elements whose content is not shown.
+var htmlSkipped = map[string]bool{"head": true, "title": true, "style": true, "script": true, "meta": true, "link": true}
+
+// htmlVoid are skipped elements that have no end tag.
+var htmlVoid = map[string]bool{"meta": true, "link": true}
+
+var htmlHeadingSizes = map[string]float64{"h1": 16, "h2": 13, "h3": 11, "h4": 10}
+
+type htmlWord struct {
+	text string
+	bold bool
+}
+
+type htmlLayout struct {
+	doc   *utils.PDF
+	y     float64
+	size  float64
+	bold  int
+	words []htmlWord
+
+	inRow  bool
+	inCell bool
+	cells  [][]htmlWord
+}
+
+func (l *htmlLayout) start(tag string) {
+	switch tag {
+	case "h1", "h2", "h3", "h4":
+		l.flush()
+		l.y += 6
+		l.size = htmlHeadingSizes[tag]
+		l.bold++
+	case "p", "div", "table", "ul", "ol", "section", "br":
+		l.flush()
+	case "li":
+		l.flush()
+		l.words = append(l.words, htmlWord{text: "•"})
+	case "hr":
+		l.flush()
+		l.y = nextRow(l.doc, l.y, 14)
+		l.doc.Line(marginLeft, l.y-4, marginRight, l.y-4)
+		l.y += 8
+	case "tr":
+		l.flush()
+		l.row()
+		l.inRow = true
+	case "td", "th":
+		l.cell()
+		l.inCell = true
+		if tag == "th" {
+			l.bold++
+		}
+	case "b", "strong":
+		l.bold++
+	}
+}
+
+func (l *htmlLayout) end(tag string) {
+	switch tag {
+	case "h1", "h2", "h3", "h4":
+		l.flush()
+		l.size = 10
+		l.bold--
+		l.y += 4
+	case "p", "div", "ul", "ol", "section", "li":
+		l.flush()
+	case "td", "th":
+		l.cell()
+		if tag == "th" {
+			l.bold--
+		}
+	case "tr", "table":
+		l.row()
+	case "b", "strong":
+		l.bold--
+	}
+}
+
+func (l *htmlLayout) text(text string) {
+	if l.inRow && !l.inCell {
+		return
+	}
+	for _, word := range strings.Fields(text) {
+		l.words = append(l.words, htmlWord{text: word, bold: l.bold > 0})
+	}
+}
+
+// cell ends the table cell being collected.
+func (l *htmlLayout) cell() {
+	if l.inCell {
+		l.cells = append(l.cells, l.words)
+		l.words = nil
+		l.inCell = false
+	}
+}
+
+// row writes the table row being collected.
+func (l *htmlLayout) row() {
+	l.cell()
+	if !l.inRow {
+		return
+	}
+	l.inRow = false
+	cells := l.cells
+	l.cells = nil
+	if len(cells) == 0 {
+		return
+	}
+	width := (marginRight - marginLeft) / float64(len(cells))
+	l.y = nextRow(l.doc, l.y, 14)
+	for i, words := range cells {
+		if len(words) == 0 {
+			continue
+		}
+		texts := make([]string, len(words))
+		for j, word := range words {
+			texts[j] = word.text
+		}
+		font := utils.PDFFontRegular
+		if words[0].bold {
+			font = utils.PDFFontBold
+		}
+		l.doc.SetFont(font, 10)
+		if i == 0 {
+			l.doc.Text(marginLeft, l.y, strings.Join(texts, " "))
+		} else {
+			l.doc.TextRight(marginLeft+width*float64(i+1), l.y, strings.Join(texts, " "))
+		}
+	}
+	l.y += 14
+}
+
+// flush writes the words of the block being collected, wrapped to the page width. Runs of
+// words in the same font are written together.
+func (l *htmlLayout) flush() {
+	words := l.words
+	l.words = nil
+	if len(words) == 0 {
+		return
+	}
+	height := l.size * 1.4
+	x := marginLeft
+	l.y = nextRow(l.doc, l.y, height)
+	run, runX, runBold := "", x, words[0].bold
+	write := func() {
+		if run != "" {
+			l.doc.SetFont(htmlFont(runBold), l.size)
+			l.doc.Text(runX, l.y, run)
+		}
+	}
+	for _, word := range words {
+		l.doc.SetFont(htmlFont(word.bold), l.size)
+		space := 0.0
+		if x > marginLeft {
+			space = l.doc.TextWidth(" ")
+		}
+		width := l.doc.TextWidth(word.text)
+		if x > marginLeft && x+space+width > marginRight {
+			write()
+			l.y += height
+			l.y = nextRow(l.doc, l.y, height)
+			x, space = marginLeft, 0
+			run, runX, runBold = "", x, word.bold
+		}
+		if word.bold != runBold {
+			write()
+			runX = x + space
+			run, runBold, space = "", word.bold, 0
+			x = runX
+		}
+		if run != "" {
+			run += " "
+		}
+		run += word.text
+		x += space + width
+	}
+	write()
+	l.y += height
+}
+
+func htmlFont(bold bool) utils.PDFFont {
+	if bold {
+		return utils.PDFFontBold
+	}
+	return utils.PDFFontRegular
+}
@@ -0,0 +1,46 @@
+package document_service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"payroll-system/internal/domain"
+	"strconv"
+)
+
+// journalCSVHeader is the column layout accounting imports rely on; add columns at the end only.
+var journalCSVHeader = []string{
+	"reference", "entry_date", "line", "account_code", "account_name", "cost_center", "component", "description", "debit", "credit", "currency",
+}
+
+// RenderJournalCSV writes a row per journal line in line order. Amounts use a decimal point
+// and the side a line is not posted to is 0.00.
+func RenderJournalCSV(journal domain.Journal) ([]byte, error) {
+	var out bytes.Buffer
+	w := csv.NewWriter(&out)
+	if err := w.Write(journalCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, line := range journal.Lines {
+		err := w.Write([]string{
+			journal.Reference,
+			journal.EntryDate,
+			strconv.Itoa(line.Line),
+			line.AccountCode,
+			line.AccountName,
+			line.CostCenter,
+			line.Component,
+			line.Description,
+			line.Debit.String(),
+			line.Credit.String(),
+			journal.Currency,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,212 @@
+package document_service
+
+import (
+	"fmt"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/utils"
+	"strings"
+)
+
+// PayslipDocument is what a printed payslip shows besides the payroll record itself.
+type PayslipDocument struct {
+	Employer domain.TaxWithholder
+	Employee domain.Employee
+	Period   domain.PayrollPeriod
+	Payroll  domain.Payroll
+	Rounding domain.RoundingPolicies // for payslips calculated before they recorded their currency
+	Password utils.PDFPassword       // when set, the PDF can only be opened with it
+}
+
+// pageBottom is the lowest baseline of multi-page documents.
+const pageBottom = utils.PDFPageHeight - 60
+
+// RenderPayslipPDF lays out a payslip with the employer, the employee, the earnings and
+// deductions and the net pay in figures and in words. Long payslips continue on more pages.
+func RenderPayslipPDF(payslip PayslipDocument) ([]byte, error) {
+	p := payslip.Payroll.Payslip
+	currency := p.Currency
+	if currency == "" {
+		currency = payslip.Rounding.DefaultCurrency()
+	}
+	period := fmt.Sprintf("%s - %s", payslip.Period.StartDate.Format("02 Jan 2006"), payslip.Period.EndDate.Format("02 Jan 2006"))
+	doc := utils.NewPDF(fmt.Sprintf("Payslip %s %s", payslip.Period.EndDate.Format("2006-01"), payslip.Employee.Name))
+	doc.AddPage()
+	y := 50.0
+
+	doc.SetFont(utils.PDFFontBold, 14)
+	doc.Text(marginLeft, y, orDash(payslip.Employer.Name))
+	doc.TextRight(marginRight, y, "PAYSLIP")
+	y += 15
+	doc.SetFont(utils.PDFFontRegular, 9)
+	if payslip.Employer.NPWP != "" {
+		doc.Text(marginLeft, y, "NPWP "+payslip.Employer.NPWP)
+	}
+	doc.TextRight(marginRight, y, period)
+	y += 10
+	doc.Line(marginLeft, y, marginRight, y)
+
+	y += 20
+	doc.SetFont(utils.PDFFontRegular, 10)
+	left := [][2]string{
+		{"Employee ID", fmt.Sprintf("%d", payslip.Employee.ID)},
+		{"Name", payslip.Employee.Name},
+		{"Email", orDash(payslip.Employee.Email)},
+		{"Cost center", orDash(payslip.Employee.CostCenter)},
+	}
+	right := [][2]string{
+		{"Period", period},
+		{"Workdays", fmt.Sprintf("%d", p.TotalWorkDays)},
+		{"Attendance", fmt.Sprintf("%d", p.NumberAttendances)},
+		{"Currency", currency},
+	}
+	for i := range left {
+		doc.Text(marginLeft, y, left[i][0])
+		doc.Text(marginLeft+80, y, ": "+left[i][1])
+		doc.Text(marginLeft+290, y, right[i][0])
+		doc.Text(marginLeft+370, y, ": "+right[i][1])
+		y += 14
+	}
+
+	lines := domain.PayslipLines(p.Lines)
+	if len(lines) == 0 {
+		// payslips stored before typed lines only have the description
+		y += 10
+		y = section(doc, y, "DETAILS")
+		for _, paragraph := range strings.Split(p.Description, "\n") {
+			for _, text := range wrapText(doc, paragraph, marginRight-marginLeft-10) {
+				y = nextRow(doc, y, 14)
+				doc.Text(marginLeft+10, y, text)
+				y += 14
+			}
+		}
+	} else {
+		y += 10
+		rounding := payslip.Rounding.For(currency)
+		y = lineTable(doc, y, "EARNINGS", lines, domain.PayslipLineEarning, "Gross pay", &rounding)
+		y += 10
+		y = lineTable(doc, y, "DEDUCTIONS", lines, domain.PayslipLineDeduction, "Total deductions", nil)
+		if contributions := lines.Total(domain.PayslipLineEmployerContribution); !contributions.IsZero() {
+			y += 10
+			y = lineTable(doc, y, "EMPLOYER CONTRIBUTIONS (NOT PART OF THE PAY)", lines,
+				domain.PayslipLineEmployerContribution, "Total employer contributions", nil)
+		}
+	}
+
+	doc.SetFont(utils.PDFFontRegular, 10)
+	words := wrapText(doc, "In words: "+capitalize(AmountInWords(p.NetSalary, currency)), marginRight-marginLeft)
+	y = nextRow(doc, y+10, 40+14*float64(len(words)))
+	doc.Line(marginLeft, y, marginRight, y)
+	y += 18
+	doc.SetFont(utils.PDFFontBold, 12)
+	doc.Text(marginLeft, y, "NET PAY")
+	doc.TextRight(marginRight, y, currency+" "+FormatAmount(p.NetSalary))
+	doc.SetFont(utils.PDFFontRegular, 10)
+	for _, text := range words {
+		y += 14
+		doc.Text(marginLeft, y, text)
+	}
+	y += 8
+	doc.Line(marginLeft, y, marginRight, y)
+
+	if ytd := p.YearToDate; ytd != nil {
+		y = nextRow(doc, y+24, 40)
+		y = section(doc, y, fmt.Sprintf("YEAR TO DATE %d", ytd.TaxYear))
+		for _, row := range []struct {
+			label  string
+			amount domain.Money
+		}{
+			{"Gross pay", ytd.Gross},
+			{"PPh 21 withheld", ytd.TaxWithheld},
+			{"BPJS employee contributions", ytd.BPJSEmployee},
+			{"Net pay", ytd.Net},
+		} {
+			y = nextRow(doc, y, 14)
+			doc.Text(marginLeft+10, y, row.label)
+			doc.TextRight(marginRight, y, FormatAmount(row.amount))
+			y += 14
+		}
+	}
+
+	y = nextRow(doc, y+20, 10)
+	doc.SetFont(utils.PDFFontRegular, 8)
+	doc.Text(marginLeft, y, fmt.Sprintf("Payroll %d, rule set version %d, issued %s. This payslip is computer generated and needs no signature.",
+		payslip.Payroll.ID, p.RuleSetVersion, payslip.Payroll.CreatedAt.Format("02-01-2006")))
+	doc.SetPassword(payslip.Password)
+	return doc.Bytes()
+}
+
+// lineTable writes the payslip lines of a category with their amount, and for earnings their
+// quantity and rate rounded like an amount, then a total row. It returns where the next
+// content starts.
+func lineTable(doc *utils.PDF, y float64, title string, lines domain.PayslipLines, category, totalLabel string, rounding *domain.RoundingPolicy) float64 {
+	quantities := rounding != nil
+	// keep the heading with the first row
+	y = nextRow(doc, y, 50)
+	y = section(doc, y, title)
+	doc.SetFont(utils.PDFFontBold, 9)
+	doc.Text(marginLeft+10, y, "Description")
+	if quantities {
+		doc.TextRight(marginRight-170, y, "Qty")
+		doc.TextRight(marginRight-90, y, "Rate")
+	}
+	doc.TextRight(marginRight, y, "Amount")
+	y += 14
+	doc.SetFont(utils.PDFFontRegular, 10)
+	for _, line := range lines {
+		if line.Category != category {
+			continue
+		}
+		y = nextRow(doc, y, 14)
+		doc.Text(marginLeft+10, y, line.Label())
+		if quantities && line.Quantity.IsPositive() {
+			doc.TextRight(marginRight-170, y, line.Quantity.String())
+			doc.TextRight(marginRight-90, y, FormatAmount(rounding.Round(line.Rate.Rat())))
+		}
+		doc.TextRight(marginRight, y, FormatAmount(line.Amount))
+		y += 14
+	}
+	y = nextRow(doc, y, 14)
+	doc.SetFont(utils.PDFFontBold, 10)
+	doc.Text(marginLeft+10, y, totalLabel)
+	doc.TextRight(marginRight, y, FormatAmount(lines.Total(category)))
+	doc.SetFont(utils.PDFFontRegular, 10)
+	return y + 14
+}
+
+// nextRow returns where content of the height starts: at y, or at the top of a new page
+// when it would run into the bottom margin.
+func nextRow(doc *utils.PDF, y, height float64) float64 {
+	if y+height <= pageBottom {
+		return y
+	}
+	doc.AddPage()
+	doc.SetFont(utils.PDFFontRegular, 10)
+	return 50
+}
+
+// wrapText breaks the text into lines that fit the width in the current font.
+func wrapText(doc *utils.PDF, text string, width float64) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && doc.TextWidth(line+" "+word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,254 @@
+package document_service
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// PayslipTemplateData is what payslip templates are executed with. Templates reach the payslip
+// through .Payslip and the lines of each category through .Earnings, .Deductions and
+// .EmployerContributions.
+type PayslipTemplateData struct {
+	Language              string
+	Employer              domain.TaxWithholder
+	Employee              PayslipEmployee
+	Period                PayslipPeriod
+	Currency              string
+	Payslip               domain.Payslip
+	Earnings              []domain.PayslipLine
+	Deductions            []domain.PayslipLine
+	EmployerContributions []domain.PayslipLine
+	NetPayInWords         string
+}
+
+// PayslipEmployee is the part of the employee a payslip may show.
+type PayslipEmployee struct {
+	ID         int
+	Name       string
+	Email      string
+	CostCenter string
+	Company    string
+}
+
+type PayslipPeriod struct {
+	ID        int
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+// NewPayslipTemplateData collects the data of a payslip for a template in the language.
+func NewPayslipTemplateData(payslip PayslipDocument, language string) PayslipTemplateData {
+	p := payslip.Payroll.Payslip
+	currency := p.Currency
+	if currency == "" {
+		currency = payslip.Rounding.DefaultCurrency()
+	}
+	data := PayslipTemplateData{
+		Language: language,
+		Employer: payslip.Employer,
+		Employee: PayslipEmployee{
+			ID:         payslip.Employee.ID,
+			Name:       payslip.Employee.Name,
+			Email:      payslip.Employee.Email,
+			CostCenter: payslip.Employee.CostCenter,
+			Company:    payslip.Employee.Company,
+		},
+		Period: PayslipPeriod{
+			ID:        payslip.Period.ID,
+			StartDate: payslip.Period.StartDate,
+			EndDate:   payslip.Period.EndDate,
+		},
+		Currency:              currency,
+		Payslip:               p,
+		Earnings:              []domain.PayslipLine{},
+		Deductions:            []domain.PayslipLine{},
+		EmployerContributions: []domain.PayslipLine{},
+		NetPayInWords:         capitalize(AmountInWords(p.NetSalary, currency)),
+	}
+	for _, line := range p.Lines {
+		switch line.Category {
+		case domain.PayslipLineEarning:
+			data.Earnings = append(data.Earnings, line)
+		case domain.PayslipLineDeduction:
+			data.Deductions = append(data.Deductions, line)
+		case domain.PayslipLineEmployerContribution:
+			data.EmployerContributions = append(data.EmployerContributions, line)
+		}
+	}
+	return data
+}
+
+// payslipTemplateFuncs are the functions templates can call besides the html/template built-ins.
+var payslipTemplateFuncs = template.FuncMap{
+	"amount": FormatAmount,
+	"words":  AmountInWords,
+	"date": func(t time.Time, layout string) string {
+		return t.Format(layout)
+	},
+	"total": func(lines []domain.PayslipLine) domain.Money {
+		var total domain.Money
+		for _, line := range lines {
+			total = total.Add(line.Amount)
+		}
+		return total
+	},
+	"upper": strings.ToUpper,
+}
+
+// ParsePayslipTemplate parses a payslip template and checks that every field it refers to
+// exists in PayslipTemplateData, including fields in branches and ranges a particular payslip
+// would not reach. The template is then executed with a sample payslip to catch errors only
+// execution finds, such as a function given the wrong type.
+func ParsePayslipTemplate(body string) (*template.Template, error) {
+	tmpl, err := template.New("payslip").Funcs(payslipTemplateFuncs).Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", error_const.ErrInvalidPayslipTemplate, err)
+	}
+	if err := checkTemplateFields(tmpl, reflect.TypeOf(PayslipTemplateData{}), payslipTemplateFuncs); err != nil {
+		return nil, fmt.Errorf("%w: %v", error_const.ErrInvalidPayslipTemplate, err)
+	}
+	check, err := tmpl.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", error_const.ErrInvalidPayslipTemplate, err)
+	}
+	if err := check.Execute(io.Discard, NewPayslipTemplateData(SamplePayslipDocument(), "en")); err != nil {
+		return nil, fmt.Errorf("%w: %v", error_const.ErrInvalidPayslipTemplate, err)
+	}
+	return tmpl, nil
+}
+
+// RenderPayslipHTML executes a parsed payslip template.
+func RenderPayslipHTML(tmpl *template.Template, data PayslipTemplateData) ([]byte, error) {
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// RenderPayslip renders a payslip as HTML or PDF with the template, or when there is none with
+// DefaultPayslipTemplate for HTML and the built-in layout of RenderPayslipPDF for PDF.
+func RenderPayslip(payslip PayslipDocument, payslipTemplate *domain.PayslipTemplate, format string) ([]byte, error) {
+	if format != domain.PayslipFormatHTML && format != domain.PayslipFormatPDF {
+		return nil, error_const.ErrUnknownPayslipFormat
+	}
+	if payslipTemplate == nil && format == domain.PayslipFormatPDF {
+		return RenderPayslipPDF(payslip)
+	}
+	body, language := DefaultPayslipTemplate, "en"
+	if payslipTemplate != nil {
+		body, language = payslipTemplate.Body, payslipTemplate.Language
+	}
+	// saved templates were checked by ParsePayslipTemplate, so parsing is enough here
+	tmpl, err := template.New("payslip").Funcs(payslipTemplateFuncs).Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", error_const.ErrInvalidPayslipTemplate, err)
+	}
+	page, err := RenderPayslipHTML(tmpl, NewPayslipTemplateData(payslip, language))
+	if err != nil {
+		return nil, err
+	}
+	if format == domain.PayslipFormatHTML {
+		return page, nil
+	}
+	title := fmt.Sprintf("Payslip %s %s", payslip.Period.EndDate.Format("2006-01"), payslip.Employee.Name)
+	doc := layoutHTML(title, page)
+	doc.SetPassword(payslip.Password)
+	return doc.Bytes()
+}
+
+// SamplePayslipDocument is a payslip with every kind of line, used to preview and check templates.
+func SamplePayslipDocument() PayslipDocument {
+	line := func(code, category, description string, quantity, rate string, amount int64, taxable bool) domain.PayslipLine {
+		l := domain.PayslipLine{Code: code, Category: category, Description: description, Amount: domain.NewMoney(amount), Taxable: taxable}
+		if quantity != "" {
+			l.Quantity, l.Rate = domain.MustRate(quantity), domain.MustRate(rate)
+		}
+		return l
+	}
+	lines := []domain.PayslipLine{
+		line(domain.PayslipLineCodeWorkdays, domain.PayslipLineInformation, "Workdays", "22", "0", 0, false),
+		line(domain.PayslipLineCodeAttendance, domain.PayslipLineInformation, "Attendance", "21", "0", 0, false),
+		line(domain.PayslipLineCodeBasePay, domain.PayslipLineEarning, "Base pay (21/22 days)", "21", "454545.4545", 9545455, true),
+		line(domain.PayslipLineCodeOvertime, domain.PayslipLineEarning, "Overtime 2025-04-14", "3", "57803.4682", 173410, true),
+		line("TRANSPORT", domain.PayslipLineEarning, "Transport allowance", "", "", 500000, true),
+		line(domain.PayslipLineCodeReimbursement, domain.PayslipLineEarning, "Medical", "", "", 250000, false),
+		line(domain.DeductionCodePPh21, domain.PayslipLineDeduction, "PPh 21", "", "", 292000, false),
+		line("BPJS_JHT", domain.PayslipLineDeduction, "BPJS JHT (employee)", "", "", 200000, false),
+		line("BPJS_KES", domain.PayslipLineDeduction, "BPJS Kesehatan (employee)", "", "", 100000, false),
+		line(domain.PayslipLineCodeLoanInstallment, domain.PayslipLineDeduction, "Loan installment", "", "", 500000, false),
+		line("BPJS_JHT_EMPLOYER", domain.PayslipLineEmployerContribution, "BPJS JHT (employer)", "", "", 370000, true),
+	}
+	lineTotal := domain.PayslipLines(lines)
+	gross := lineTotal.Total(domain.PayslipLineEarning)
+	deductions := lineTotal.Total(domain.PayslipLineDeduction)
+	return PayslipDocument{
+		Employer: domain.TaxWithholder{Name: "PT Contoh Sejahtera", NPWP: "01.234.567.8-901.000"},
+		Employee: domain.Employee{ID: 1, Name: "Budi Santoso", Email: "budi@example.com", CostCenter: "ENG", Company: "CONTOH"},
+		Period: domain.PayrollPeriod{
+			ID:        1,
+			StartDate: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC),
+		},
+		Payroll: domain.Payroll{
+			ID:         1,
+			EmployeeID: 1,
+			PeriodID:   1,
+			Payslip: domain.Payslip{
+				EmployeeID:            1,
+				PeriodID:              1,
+				Currency:              "IDR",
+				NumberAttendances:     21,
+				TotalWorkDays:         22,
+				TotalSalary:           gross,
+				TaxableIncome:         gross.Sub(domain.NewMoney(250000)).Add(domain.NewMoney(370000)),
+				Tax:                   &domain.TaxDetail{Amount: domain.NewMoney(292000)},
+				EmployerContributions: domain.NewMoney(370000),
+				TotalDeductions:       deductions,
+				NetSalary:             gross.Sub(deductions),
+				YearToDate: &domain.TaxYearToDate{EmployeeID: 1, TaxYear: 2025, Months: 4,
+					Gross: gross.Mul(4), TaxWithheld: domain.NewMoney(1168000), Net: gross.Sub(deductions).Mul(4)},
+				Lines: lines,
+			},
+		},
+	}
+}
+
+// DefaultPayslipTemplate is used for the HTML payslips of employees whose company has no template.
+const DefaultPayslipTemplate = `<!DOCTYPE html>
+<html lang="{{.Language}}">
+<head><meta charset="utf-8"><title>Payslip {{date .Period.EndDate "2006-01"}} {{.Employee.Name}}</title></head>
+<body>
+<h1>{{.Employer.Name}}</h1>
+{{if .Employer.NPWP}}<p>NPWP {{.Employer.NPWP}}</p>{{end}}
+<h2>Payslip {{date .Period.StartDate "02 Jan 2006"}} - {{date .Period.EndDate "02 Jan 2006"}}</h2>
+<table>
+<tr><td>Employee</td><td>{{.Employee.Name}} ({{.Employee.ID}})</td></tr>
+{{if .Employee.CostCenter}}<tr><td>Cost center</td><td>{{.Employee.CostCenter}}</td></tr>{{end}}
+<tr><td>Attendance</td><td>{{.Payslip.NumberAttendances}} of {{.Payslip.TotalWorkDays}} workdays</td></tr>
+</table>
+<h3>Earnings</h3>
+<table>
+<tr><th>Description</th><th>Amount</th></tr>
+{{range .Earnings}}<tr><td>{{.Label}}</td><td>{{amount .Amount}}</td></tr>
+{{end}}<tr><th>Gross pay</th><th>{{amount .Payslip.TotalSalary}}</th></tr>
+</table>
+<h3>Deductions</h3>
+<table>
+<tr><th>Description</th><th>Amount</th></tr>
+{{range .Deductions}}<tr><td>{{.Label}}</td><td>{{amount .Amount}}</td></tr>
+{{end}}<tr><th>Total deductions</th><th>{{amount .Payslip.TotalDeductions}}</th></tr>
+</table>
+<hr>
+<table><tr><th>Net pay</th><th>{{.Currency}} {{amount .Payslip.NetSalary}}</th></tr></table>
+<p>In words: {{.NetPayInWords}}</p>
+</body>
+</html>
+`
@@ -0,0 +1,88 @@
+package document_service
+
+import (
+	"bytes"
+	"errors"
+	"payroll-system/internal/error_const"
+	"strings"
+	"testing"
+)
+
+func TestParsePayslipTemplate(t *testing.T) {
+	valid := []string{
+		DefaultPayslipTemplate,
+		`{{range $i, $line := .Earnings}}{{$i}} {{$line.Description}} {{$line.Quantity.String}}{{end}}`,
+		`{{with .Payslip.YearToDate}}{{amount .Gross}}{{else}}{{.Employee.Name}}{{end}}`,
+		`{{$p := .Payslip}}{{if $p.Tax}}{{amount $p.Tax.Amount}}{{end}} {{(.Period).EndDate.Year}}`,
+		`{{define "line"}}<td>{{.Description}}</td>{{end}}{{range .Deductions}}{{template "line" .}}{{end}}`,
+		`{{words .Payslip.NetSalary .Currency | upper}} {{amount (total .EmployerContributions)}}`,
+	}
+	for _, body := range valid {
+		if _, err := ParsePayslipTemplate(body); err != nil {
+			t.Errorf("ParsePayslipTemplate(%q): %v", body, err)
+		}
+	}
+
+	invalid := map[string]string{
+		`{{.Employee.Salary}}`:                                    "Salary",
+		`{{if .Payslip.Tax}}{{.Payslip.Tax.Rate}}{{end}}`:         "Rate",
+		`{{range .Deductions}}{{.Amout}}{{end}}`:                  "Amout",
+		`{{range .Earnings}}{{else}}{{.Descripton}}{{end}}`:       "Descripton",
+		`{{with .Period}}{{.EndDate.Formatted}}{{end}}`:           "Formatted",
+		`{{define "x"}}{{.Nme}}{{end}}{{template "x" .Employee}}`: "Nme",
+		`{{.Employee.password_hash}}`:                             "password_hash",
+		`{{amount .Employee.Name}}`:                               "",
+		`{{.Payslip.NetSalary`:                                    "",
+	}
+	for body, field := range invalid {
+		_, err := ParsePayslipTemplate(body)
+		if !errors.Is(err, error_const.ErrInvalidPayslipTemplate) {
+			t.Errorf("ParsePayslipTemplate(%q) = %v, want ErrInvalidPayslipTemplate", body, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("ParsePayslipTemplate(%q) = %v, want it to name %s", body, err, field)
+		}
+	}
+}
+
+func TestRenderPayslipHTML(t *testing.T) {
+	tmpl, err := ParsePayslipTemplate(DefaultPayslipTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payslip := SamplePayslipDocument()
+	payslip.Employee.Name = "Budi <Admin>"
+	page, err := RenderPayslipHTML(tmpl, NewPayslipTemplateData(payslip, "id"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<html lang="id">`,
+		"Budi &lt;Admin&gt;",
+		"<td>Transport allowance</td><td>500.000</td>",
+		"<th>IDR 9.376.865</th>",
+		"In words: Nine million three hundred seventy-six thousand eight hundred sixty-five rupiah",
+	} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("payslip HTML is missing %q:\n%s", want, page)
+		}
+	}
+
+	pdf, err := RenderHTMLPDF("Payslip", page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		t.Fatalf("not a PDF: %q", pdf[:20])
+	}
+	for _, want := range []string{"(PT Contoh Sejahtera)", "(Transport allowance)", "(500.000)", "(IDR 9.376.865)", "(Net pay)"} {
+		if !bytes.Contains(pdf, []byte(want)) {
+			t.Errorf("payslip PDF is missing %s", want)
+		}
+	}
+	// the title is not shown on the page
+	if bytes.Contains(pdf, []byte("(Payslip 2025-04")) {
+		t.Error("payslip PDF shows the head of the page")
+	}
+}
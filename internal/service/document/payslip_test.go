@@ -0,0 +1,113 @@
+package document_service
+
+import (
+	"bytes"
+	"fmt"
+	"payroll-system/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAmountInWords(t *testing.T) {
+	cases := []struct {
+		amount   string
+		currency string
+		want     string
+	}{
+		{"0", "IDR", "zero rupiah"},
+		{"9450000", "IDR", "nine million four hundred fifty thousand rupiah"},
+		{"1000015", "IDR", "one million fifteen rupiah"},
+		{"2300000000", "IDR", "two billion three hundred million rupiah"},
+		{"12.50", "USD", "twelve US dollars and 50/100"},
+		{"-101", "JPY", "minus one hundred one JPY"},
+		{"999999", "IDR", "nine hundred ninety-nine thousand nine hundred ninety-nine rupiah"},
+	}
+	for _, c := range cases {
+		m, err := domain.ParseMoney(c.amount)
+		if err != nil {
+			t.Fatalf("ParseMoney(%q): %v", c.amount, err)
+		}
+		if got := AmountInWords(m, c.currency); got != c.want {
+			t.Errorf("AmountInWords(%s, %s) = %q, want %q", c.amount, c.currency, got, c.want)
+		}
+	}
+}
+
+func payslipDocument(lines []domain.PayslipLine) PayslipDocument {
+	return PayslipDocument{
+		Employer: domain.TaxWithholder{Name: "PT Maju Jaya", NPWP: "01.234.567.8-901.000"},
+		Employee: domain.Employee{ID: 7, Name: "Budi Santoso", Email: "budi@example.com", CostCenter: "ENG"},
+		Period: domain.PayrollPeriod{
+			ID:        4,
+			StartDate: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC),
+		},
+		Payroll: domain.Payroll{
+			ID: 31,
+			Payslip: domain.Payslip{
+				Currency:          "IDR",
+				TotalWorkDays:     22,
+				NumberAttendances: 21,
+				NetSalary:         domain.NewMoney(9450000),
+				Lines:             lines,
+				Description:       "Base pay\nOvertime",
+			},
+		},
+	}
+}
+
+func TestRenderPayslipPDF(t *testing.T) {
+	lines := []domain.PayslipLine{
+		{Code: domain.PayslipLineCodeBasePay, Category: domain.PayslipLineEarning, Description: "Base pay (21/22 days)",
+			Quantity: domain.RateFromInt(21), Rate: domain.MustRate("476190.4761"), Amount: domain.NewMoney(10000000)},
+		{Code: domain.DeductionCodePPh21, Category: domain.PayslipLineDeduction, Description: "PPh 21", Amount: domain.NewMoney(350000)},
+		{Code: "BPJS_JHT", Category: domain.PayslipLineDeduction, Description: "BPJS JHT (employee)", Amount: domain.NewMoney(200000)},
+		{Code: "BPJS_JHT_EMPLOYER", Category: domain.PayslipLineEmployerContribution, Description: "BPJS JHT (employer)", Amount: domain.NewMoney(370000)},
+	}
+	pdf, err := RenderPayslipPDF(payslipDocument(lines))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatalf("not a PDF document:\n%s", pdf)
+	}
+	for _, text := range []string{
+		"(PT Maju Jaya)", "(: Budi Santoso)", "(: ENG)", "(01 Apr 2025 - 30 Apr 2025)",
+		"(Base pay \\(21/22 days\\))", "(476.190)", "(10.000.000)", "(Total deductions)", "(550.000)",
+		"(BPJS JHT \\(employer\\))", "(IDR 9.450.000)", "(In words: Nine million four hundred fifty thousand rupiah)",
+	} {
+		if !bytes.Contains(pdf, []byte(text)) {
+			t.Errorf("payslip is missing %s", text)
+		}
+	}
+	if bytes.Contains(pdf, []byte("(Overtime)")) {
+		t.Error("payslip with lines should not print the description")
+	}
+}
+
+func TestRenderPayslipPDFPages(t *testing.T) {
+	var lines []domain.PayslipLine
+	for i := 1; i <= 80; i++ {
+		lines = append(lines, domain.PayslipLine{Code: fmt.Sprintf("ALLOWANCE_%d", i), Category: domain.PayslipLineEarning,
+			Description: fmt.Sprintf("Allowance %d", i), Amount: domain.NewMoney(1000)})
+	}
+	pdf, err := RenderPayslipPDF(payslipDocument(lines))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := strings.Count(string(pdf), "/Type /Page "); count != 2 {
+		t.Errorf("got %d pages, want the lines to continue on a second page", count)
+	}
+	if !bytes.Contains(pdf, []byte("(Allowance 80)")) || !bytes.Contains(pdf, []byte("(80.000)")) {
+		t.Error("the last line and the gross pay should be on the payslip")
+	}
+
+	old, err := RenderPayslipPDF(payslipDocument(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(old, []byte("(Overtime)")) {
+		t.Error("a payslip without lines should print its description line by line")
+	}
+}
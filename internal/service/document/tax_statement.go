@@ -0,0 +1,124 @@
+package document_service
+
+import (
+	"fmt"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/utils"
+)
+
+const (
+	marginLeft  = 40.0
+	marginRight = utils.PDFPageWidth - 40
+)
+
+// RenderTaxStatementPDF lays out a 1721-A1 statement on one A4 page.
+func RenderTaxStatementPDF(statement domain.TaxStatement) ([]byte, error) {
+	doc := utils.NewPDF(fmt.Sprintf("1721-A1 %d %s", statement.TaxYear, statement.EmployeeName))
+	doc.AddPage()
+	y := 50.0
+
+	doc.SetFont(utils.PDFFontBold, 12)
+	doc.Text(marginLeft, y, "BUKTI PEMOTONGAN PAJAK PENGHASILAN PASAL 21")
+	doc.TextRight(marginRight, y, "FORMULIR 1721 - A1")
+	y += 15
+	doc.SetFont(utils.PDFFontRegular, 9)
+	doc.Text(marginLeft, y, "BAGI PEGAWAI TETAP ATAU PENERIMA PENSIUN ATAU TUNJANGAN HARI TUA/JAMINAN HARI TUA BERKALA")
+	y += 18
+	doc.SetFont(utils.PDFFontRegular, 10)
+	doc.Text(marginLeft, y, "Nomor: "+statement.Number)
+	doc.TextRight(marginRight, y, fmt.Sprintf("Masa perolehan penghasilan: %02d - %02d / %d", statement.FirstMonth, statement.LastMonth, statement.TaxYear))
+	y += 10
+	doc.Line(marginLeft, y, marginRight, y)
+
+	y += 18
+	doc.SetFont(utils.PDFFontBold, 10)
+	doc.Text(marginLeft, y, "NPWP PEMOTONG")
+	doc.SetFont(utils.PDFFontRegular, 10)
+	doc.Text(marginLeft+160, y, orDash(statement.Withholder.NPWP))
+	y += 14
+	doc.SetFont(utils.PDFFontBold, 10)
+	doc.Text(marginLeft, y, "NAMA PEMOTONG")
+	doc.SetFont(utils.PDFFontRegular, 10)
+	doc.Text(marginLeft+160, y, orDash(statement.Withholder.Name))
+
+	y += 24
+	y = section(doc, y, "A. IDENTITAS PENERIMA PENGHASILAN YANG DIPOTONG")
+	for _, field := range [][2]string{
+		{"NPWP", orDash(statement.NPWP)},
+		{"Nama", statement.EmployeeName},
+		{"Nomor pegawai", fmt.Sprintf("%d", statement.EmployeeID)},
+		{"Status / jumlah tanggungan (PTKP)", statement.PTKPStatus},
+	} {
+		doc.Text(marginLeft+10, y, field[0])
+		doc.Text(marginLeft+210, y, ": "+field[1])
+		y += 14
+	}
+
+	y += 10
+	y = section(doc, y, fmt.Sprintf("B. RINCIAN PENGHASILAN DAN PENGHITUNGAN PPh PASAL 21 (%s)", statement.Currency))
+	rows := []struct {
+		label   string
+		amount  domain.Money
+		bold    bool
+		heading bool
+	}{
+		{"PENGHASILAN BRUTO:", domain.Money{}, true, true},
+		{"1. Gaji/pensiun atau THT/JHT", statement.Salary, false, false},
+		{"2. Tunjangan PPh", statement.TaxAllowance, false, false},
+		{"3. Tunjangan lainnya, uang lembur dan sebagainya", statement.OtherAllowances, false, false},
+		{"4. Honorarium dan imbalan lain sejenisnya", statement.Honorarium, false, false},
+		{"5. Premi asuransi yang dibayar pemberi kerja", statement.InsurancePremiums, false, false},
+		{"6. Penerimaan dalam bentuk natura dan kenikmatan lainnya", statement.BenefitsInKind, false, false},
+		{"7. Tantiem, bonus, gratifikasi, jasa produksi dan THR", statement.BonusAndTHR, false, false},
+		{"8. Jumlah penghasilan bruto (1 s.d. 7)", statement.GrossIncome, true, false},
+		{"PENGURANGAN:", domain.Money{}, true, true},
+		{"9. Biaya jabatan/biaya pensiun", statement.OccupationalCost, false, false},
+		{"10. Iuran terkait pensiun atau hari tua", statement.PensionContributions, false, false},
+		{"11. Zakat/sumbangan keagamaan yang bersifat wajib", statement.Zakat, false, false},
+		{"12. Jumlah pengurangan (9 s.d. 11)", statement.TotalDeductions, true, false},
+		{"PENGHITUNGAN PPh PASAL 21:", domain.Money{}, true, true},
+		{"13. Jumlah penghasilan neto (8 - 12)", statement.NetIncome, false, false},
+		{"14. Penghasilan neto masa pajak sebelumnya", statement.PriorNetIncome, false, false},
+		{"15. Jumlah penghasilan neto untuk penghitungan PPh Pasal 21", statement.AnnualNetIncome, false, false},
+		{"16. Penghasilan tidak kena pajak (PTKP)", statement.PTKP, false, false},
+		{"17. Penghasilan kena pajak setahun (15 - 16)", statement.TaxableIncome, false, false},
+		{"18. PPh Pasal 21 atas penghasilan kena pajak setahun", statement.AnnualTax, false, false},
+		{"19. PPh Pasal 21 yang telah dipotong masa pajak sebelumnya", statement.PriorTaxWithheld, false, false},
+		{"20. PPh Pasal 21 terutang", statement.TaxDue, true, false},
+		{"21. PPh Pasal 21 yang telah dipotong dan dilunasi", statement.TaxWithheld, true, false},
+	}
+	for _, row := range rows {
+		if row.bold {
+			doc.SetFont(utils.PDFFontBold, 10)
+		}
+		doc.Text(marginLeft+10, y, row.label)
+		if !row.heading {
+			doc.TextRight(marginRight, y, FormatAmount(row.amount))
+		}
+		doc.SetFont(utils.PDFFontRegular, 10)
+		y += 16
+	}
+
+	y += 10
+	doc.Line(marginLeft, y, marginRight, y)
+	y += 18
+	doc.SetFont(utils.PDFFontRegular, 9)
+	doc.Text(marginLeft, y, fmt.Sprintf("Dibuat %s oleh %s", statement.GeneratedAt.Format("02-01-2006"), statement.GeneratedBy))
+	return doc.Bytes()
+}
+
+// section writes a bold heading with a rule under it and returns where the content starts.
+func section(doc *utils.PDF, y float64, title string) float64 {
+	doc.SetFont(utils.PDFFontBold, 10)
+	doc.Text(marginLeft, y, title)
+	doc.Line(marginLeft, y+5, marginRight, y+5)
+	doc.SetFont(utils.PDFFontRegular, 10)
+	return y + 20
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,246 @@
+package document_service
+
+import (
+	"fmt"
+	"html/template"
+	"reflect"
+	"text/template/parse"
+)
+
+// fieldChecker walks the parse trees of a template and resolves every field and method it
+// refers to against the type of the data, so a misspelt field is found even when the data at
+// hand would never reach it. Types it cannot know, such as the result of index or an
+// interface, are not checked further.
+type fieldChecker struct {
+	tmpl     *template.Template
+	funcs    template.FuncMap
+	visiting map[string]bool
+}
+
+// checkTemplateFields reports the first field of the template that the data type does not have.
+func checkTemplateFields(tmpl *template.Template, data reflect.Type, funcs template.FuncMap) error {
+	if tmpl.Tree == nil {
+		return nil
+	}
+	c := &fieldChecker{tmpl: tmpl, funcs: funcs, visiting: map[string]bool{tmpl.Name(): true}}
+	return c.walk(tmpl.Tree, tmpl.Tree.Root, data, map[string]reflect.Type{"$": data})
+}
+
+func (c *fieldChecker) walk(tree *parse.Tree, node parse.Node, dot reflect.Type, vars map[string]reflect.Type) error {
+	switch n := node.(type) {
+	case nil:
+		return nil
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := c.walk(tree, child, dot, vars); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		_, err := c.pipe(tree, n.Pipe, dot, vars)
+		return err
+	case *parse.IfNode:
+		return c.branch(tree, &n.BranchNode, dot, vars, false)
+	case *parse.WithNode:
+		return c.branch(tree, &n.BranchNode, dot, vars, false)
+	case *parse.RangeNode:
+		return c.branch(tree, &n.BranchNode, dot, vars, true)
+	case *parse.TemplateNode:
+		var arg reflect.Type
+		if n.Pipe != nil {
+			t, err := c.pipe(tree, n.Pipe, dot, vars)
+			if err != nil {
+				return err
+			}
+			arg = t
+		}
+		called := c.tmpl.Lookup(n.Name)
+		if called == nil || called.Tree == nil || c.visiting[n.Name] {
+			return nil
+		}
+		c.visiting[n.Name] = true
+		defer delete(c.visiting, n.Name)
+		return c.walk(called.Tree, called.Tree.Root, arg, map[string]reflect.Type{"$": arg})
+	}
+	return nil
+}
+
+// branch checks an if, with or range. The body of with runs with the pipeline as dot, the body
+// of range with its elements; the else branch keeps the outer dot.
+func (c *fieldChecker) branch(tree *parse.Tree, n *parse.BranchNode, dot reflect.Type, vars map[string]reflect.Type, isRange bool) error {
+	inner := copyVars(vars)
+	t, err := c.pipe(tree, n.Pipe, dot, inner)
+	if err != nil {
+		return err
+	}
+	body := dot
+	switch {
+	case isRange:
+		key, elem := rangeTypes(t)
+		if len(n.Pipe.Decl) == 2 {
+			inner[n.Pipe.Decl[0].Ident[0]] = key
+			inner[n.Pipe.Decl[1].Ident[0]] = elem
+		} else if len(n.Pipe.Decl) == 1 {
+			inner[n.Pipe.Decl[0].Ident[0]] = elem
+		}
+		body = elem
+	case n.NodeType == parse.NodeWith:
+		body = t
+	}
+	if err := c.walk(tree, n.List, body, inner); err != nil {
+		return err
+	}
+	return c.walk(tree, n.ElseList, dot, copyVars(vars))
+}
+
+// pipe checks the commands of a pipeline and returns the type of its result. Variables the
+// pipeline declares are added to vars.
+func (c *fieldChecker) pipe(tree *parse.Tree, pipe *parse.PipeNode, dot reflect.Type, vars map[string]reflect.Type) (reflect.Type, error) {
+	if pipe == nil {
+		return nil, nil
+	}
+	var result reflect.Type
+	for _, cmd := range pipe.Cmds {
+		t, err := c.command(tree, cmd, dot, vars)
+		if err != nil {
+			return nil, err
+		}
+		result = t
+	}
+	for _, decl := range pipe.Decl {
+		if !pipe.IsAssign {
+			vars[decl.Ident[0]] = result
+		}
+	}
+	return result, nil
+}
+
+func (c *fieldChecker) command(tree *parse.Tree, cmd *parse.CommandNode, dot reflect.Type, vars map[string]reflect.Type) (reflect.Type, error) {
+	var result reflect.Type
+	for i, arg := range cmd.Args {
+		t, err := c.arg(tree, arg, dot, vars)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			result = t
+		}
+	}
+	return result, nil
+}
+
+func (c *fieldChecker) arg(tree *parse.Tree, node parse.Node, dot reflect.Type, vars map[string]reflect.Type) (reflect.Type, error) {
+	switch n := node.(type) {
+	case *parse.DotNode:
+		return dot, nil
+	case *parse.FieldNode:
+		return c.fields(tree, n, dot, n.Ident)
+	case *parse.VariableNode:
+		return c.fields(tree, n, vars[n.Ident[0]], n.Ident[1:])
+	case *parse.ChainNode:
+		t, err := c.arg(tree, n.Node, dot, vars)
+		if err != nil {
+			return nil, err
+		}
+		return c.fields(tree, n, t, n.Field)
+	case *parse.PipeNode:
+		return c.pipe(tree, n, dot, copyVars(vars))
+	case *parse.IdentifierNode:
+		if fn, ok := c.funcs[n.Ident]; ok {
+			if t := reflect.TypeOf(fn); t.NumOut() > 0 {
+				return t.Out(0), nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// fields follows a chain of field and method names from the type.
+func (c *fieldChecker) fields(tree *parse.Tree, node parse.Node, t reflect.Type, names []string) (reflect.Type, error) {
+	for _, name := range names {
+		if t == nil {
+			return nil, nil
+		}
+		next, ok := fieldType(t, name)
+		if !ok {
+			location, _ := tree.ErrorContext(node)
+			return nil, fmt.Errorf("%s: %s has no field or method %s", location, t, name)
+		}
+		t = next
+	}
+	return t, nil
+}
+
+// fieldType is the type of the field, method result or map value the name refers to in t. A nil
+// type means the name may exist but its type is not known.
+func fieldType(t reflect.Type, name string) (reflect.Type, bool) {
+	if t.Kind() == reflect.Interface {
+		return nil, true
+	}
+	if method, ok := t.MethodByName(name); ok {
+		return methodResult(method.Type), true
+	}
+	if t.Kind() != reflect.Pointer {
+		if method, ok := reflect.PointerTo(t).MethodByName(name); ok {
+			return methodResult(method.Type), true
+		}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		field, ok := t.FieldByName(name)
+		if !ok || !field.IsExported() {
+			return nil, false
+		}
+		return field.Type, true
+	case reflect.Map:
+		if t.Key().Kind() == reflect.String {
+			return t.Elem(), true
+		}
+		return nil, false
+	case reflect.Interface:
+		return nil, true
+	}
+	return nil, false
+}
+
+func methodResult(method reflect.Type) reflect.Type {
+	if method.NumOut() == 0 {
+		return nil
+	}
+	return method.Out(0)
+}
+
+// rangeTypes are the key and element types of ranging over t.
+func rangeTypes(t reflect.Type) (reflect.Type, reflect.Type) {
+	if t == nil {
+		return nil, nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return reflect.TypeOf(0), t.Elem()
+	case reflect.Map:
+		return t.Key(), t.Elem()
+	case reflect.Chan:
+		return t.Elem(), nil
+	case reflect.Int:
+		return t, nil
+	}
+	return nil, nil
+}
+
+func copyVars(vars map[string]reflect.Type) map[string]reflect.Type {
+	out := make(map[string]reflect.Type, len(vars))
+	for name, t := range vars {
+		out[name] = t
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+package document_service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"payroll-system/internal/domain"
+	"strconv"
+	"strings"
+)
+
+var varianceCSVHeader = []string{
+	"employee_id", "employee_name", "status", "component", "previous", "current", "change", "change_percent", "flagged", "flags",
+}
+
+// RenderPayrollVarianceCSV writes the report with a row per employee and component, followed
+// by the period totals with the status "total". Amounts use a decimal point so spreadsheets
+// read them as numbers.
+func RenderPayrollVarianceCSV(report domain.PayrollVarianceReport) ([]byte, error) {
+	var out bytes.Buffer
+	w := csv.NewWriter(&out)
+	if err := w.Write(varianceCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, employee := range report.Employees {
+		flags := strings.Join(employee.Flags, ";")
+		for _, component := range employee.Components {
+			row := append([]string{strconv.Itoa(employee.EmployeeID), employee.EmployeeName, employee.Status}, varianceCSVColumns(component)...)
+			if err := w.Write(append(row, flags)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, component := range report.Totals {
+		row := append([]string{"", "", "total"}, varianceCSVColumns(component)...)
+		if err := w.Write(append(row, "")); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func varianceCSVColumns(component domain.ComponentVariance) []string {
+	percent := ""
+	if component.ChangePercent != nil {
+		percent = component.ChangePercent.String()
+	}
+	return []string{
+		component.Component,
+		component.Previous.String(),
+		component.Current.String(),
+		component.Change.String(),
+		percent,
+		strconv.FormatBool(component.Flagged),
+	}
+}
@@ -0,0 +1,41 @@
+package document_service
+
+import (
+	"payroll-system/internal/domain"
+	"strings"
+	"testing"
+)
+
+func TestRenderPayrollVarianceCSV(t *testing.T) {
+	percent := domain.MustRate("-12.5")
+	component := domain.ComponentVariance{
+		Component:     domain.VarianceComponentOvertime,
+		Previous:      domain.NewMoney(400000),
+		Current:       domain.NewMoney(350000),
+		Change:        domain.NewMoney(-50000),
+		ChangePercent: &percent,
+		Flagged:       true,
+	}
+	csv, err := RenderPayrollVarianceCSV(domain.PayrollVarianceReport{
+		Employees: []domain.EmployeeVariance{{
+			EmployeeID:   7,
+			EmployeeName: "Sari, A.",
+			Status:       domain.VarianceStatusExisting,
+			Components:   []domain.ComponentVariance{component},
+			Flags:        []string{domain.VarianceFlagAboveThreshold, domain.VarianceFlagReimbursementSpike},
+		}},
+		Totals: []domain.ComponentVariance{component},
+	})
+	if err != nil {
+		t.Fatalf("RenderPayrollVarianceCSV: %v", err)
+	}
+	want := strings.Join([]string{
+		"employee_id,employee_name,status,component,previous,current,change,change_percent,flagged,flags",
+		`7,"Sari, A.",existing,overtime,400000.00,350000.00,-50000.00,-12.5,true,above_threshold;reimbursement_spike`,
+		",,total,overtime,400000.00,350000.00,-50000.00,-12.5,true,",
+		"",
+	}, "\n")
+	if string(csv) != want {
+		t.Errorf("csv =\n%s\nwant\n%s", csv, want)
+	}
+}
@@ -0,0 +1,73 @@
+package document_service
+
+import (
+	"fmt"
+	"payroll-system/internal/domain"
+	"strings"
+)
+
+var (
+	ones = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten",
+		"eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen"}
+	tens   = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	scales = []string{"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion"}
+
+	currencyNames = map[string]string{"IDR": "rupiah", "USD": "US dollars", "SGD": "Singapore dollars", "EUR": "euros"}
+)
+
+// AmountInWords writes an amount as it is spelled out on a cheque, e.g. "nine million four
+// hundred fifty thousand rupiah" or "twelve US dollars and 50/100". Currencies without a name
+// are written by their code.
+func AmountInWords(m domain.Money, currency string) string {
+	minor := m.MinorUnits()
+	sign := ""
+	if minor < 0 {
+		sign, minor = "minus ", -minor
+	}
+	name, ok := currencyNames[currency]
+	if !ok {
+		name = currency
+	}
+	words := sign + NumberInWords(uint64(minor/100)) + " " + name
+	if cents := minor % 100; cents != 0 {
+		words += fmt.Sprintf(" and %02d/100", cents)
+	}
+	return words
+}
+
+// NumberInWords writes a whole number in English words with short scale names.
+func NumberInWords(n uint64) string {
+	if n == 0 {
+		return ones[0]
+	}
+	var groups []string
+	for scale := 0; n > 0; scale++ {
+		if group := n % 1000; group != 0 {
+			words := hundredsInWords(int(group))
+			if scales[scale] != "" {
+				words += " " + scales[scale]
+			}
+			groups = append([]string{words}, groups...)
+		}
+		n /= 1000
+	}
+	return strings.Join(groups, " ")
+}
+
+func hundredsInWords(n int) string {
+	var words []string
+	if n >= 100 {
+		words = append(words, ones[n/100], "hundred")
+		n %= 100
+	}
+	switch {
+	case n == 0:
+	case n < 20:
+		words = append(words, ones[n])
+	case n%10 == 0:
+		words = append(words, tens[n/10])
+	default:
+		words = append(words, tens[n/10]+"-"+ones[n%10])
+	}
+	return strings.Join(words, " ")
+}
@@ -6,8 +6,10 @@ import (
 	"payroll-system/internal/delivery/dto"
 	"payroll-system/internal/domain"
 	"payroll-system/internal/error_const"
+	document_service "payroll-system/internal/service/document"
 	"payroll-system/internal/utils"
 	"time"
+	"unicode/utf8"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -19,6 +21,8 @@ type EmployeeRepository interface {
 type PayrollRepository interface {
 	GetPayrollPeriodFromDate(ctx context.Context, date time.Time) (domain.PayrollPeriod, error)
 	GetEmployeePayslipByPeriod(ctx context.Context, payroll domain.Payroll) (domain.Payroll, error)
+	GetPayrollPeriod(ctx context.Context, period domain.PayrollPeriod) (domain.PayrollPeriod, error)
+	GetYearToDate(ctx context.Context, employeeID, taxYear int) (domain.TaxYearToDate, error)
 }
 type AttendanceRepository interface {
 	RecordAttendance(ctx context.Context, attendance domain.Attendance) error
@@ -29,6 +33,28 @@ type OvertimeRepository interface {
 type ReimbursementRepository interface {
 	SubmitReimbursement(ctx context.Context, reimbursement domain.Reimbursement) error
 }
+type HolidayRepository interface {
+	GetHolidaysBetween(ctx context.Context, start, end time.Time) ([]domain.Holiday, error)
+}
+type LoanRepository interface {
+	GetLoan(ctx context.Context, loanID int) (domain.Loan, error)
+	GetEmployeeLoans(ctx context.Context, employeeID int) ([]domain.Loan, error)
+}
+type BonusRepository interface {
+	GetEmployeeBonusPayslipsByPeriod(ctx context.Context, employeeID, periodID int) ([]domain.BonusPayslip, error)
+}
+type TaxStatementRepository interface {
+	GetTaxStatement(ctx context.Context, employeeID, taxYear int) (domain.TaxStatement, error)
+}
+type PayslipTemplateRepository interface {
+	GetLatestPayslipTemplate(ctx context.Context, company string) (domain.PayslipTemplate, error)
+}
+type PayslipPasswordRepository interface {
+	SetPayslipPassword(ctx context.Context, password domain.PayslipPassword) error
+}
+
+// payslipPasswordMinLength is the fewest characters a payslip password may have.
+const payslipPasswordMinLength = 8
 
 type EmployeeService struct {
 	empRepo           EmployeeRepository
@@ -36,17 +62,48 @@ type EmployeeService struct {
 	attendanceRepo    AttendanceRepository
 	overtimeRepo      OvertimeRepository
 	reimbursementRepo ReimbursementRepository
+	holidayRepo       HolidayRepository
+	loanRepo          LoanRepository
+	bonusRepo         BonusRepository
+	taxStatementRepo  TaxStatementRepository
+	templateRepo      PayslipTemplateRepository
+	passwordRepo      PayslipPasswordRepository
+	withholder        domain.TaxWithholder
+	rounding          domain.RoundingPolicies
 }
 
-func NewEmployeeService(empRepo EmployeeRepository, payrollRepo PayrollRepository,
-	attendanceRepo AttendanceRepository, overtimeRepo OvertimeRepository,
-	reimbursementRepo ReimbursementRepository) *EmployeeService {
+// EmployeeDependencies holds what NewEmployeeService wires into the service.
+type EmployeeDependencies struct {
+	EmployeeRepository        EmployeeRepository
+	PayrollRepository         PayrollRepository
+	AttendanceRepository      AttendanceRepository
+	OvertimeRepository        OvertimeRepository
+	ReimbursementRepository   ReimbursementRepository
+	HolidayRepository         HolidayRepository
+	LoanRepository            LoanRepository
+	BonusRepository           BonusRepository
+	TaxStatementRepository    TaxStatementRepository
+	PayslipTemplateRepository PayslipTemplateRepository
+	PayslipPasswordRepository PayslipPasswordRepository
+	TaxWithholder             domain.TaxWithholder    // the employer named on payslips and statements
+	RoundingPolicies          domain.RoundingPolicies // for payslips that do not record their currency
+}
+
+func NewEmployeeService(deps EmployeeDependencies) *EmployeeService {
 	return &EmployeeService{
-		empRepo:           empRepo,
-		payrollRepo:       payrollRepo,
-		attendanceRepo:    attendanceRepo,
-		overtimeRepo:      overtimeRepo,
-		reimbursementRepo: reimbursementRepo,
+		empRepo:           deps.EmployeeRepository,
+		payrollRepo:       deps.PayrollRepository,
+		attendanceRepo:    deps.AttendanceRepository,
+		overtimeRepo:      deps.OvertimeRepository,
+		reimbursementRepo: deps.ReimbursementRepository,
+		holidayRepo:       deps.HolidayRepository,
+		loanRepo:          deps.LoanRepository,
+		bonusRepo:         deps.BonusRepository,
+		taxStatementRepo:  deps.TaxStatementRepository,
+		templateRepo:      deps.PayslipTemplateRepository,
+		passwordRepo:      deps.PayslipPasswordRepository,
+		withholder:        deps.TaxWithholder,
+		rounding:          deps.RoundingPolicies,
 	}
 }
 
@@ -62,6 +119,9 @@ func (s *EmployeeService) LoginAsEmployee(ctx context.Context, credentials dto.L
 	if !validPassword {
 		return "", error_const.ErrInvalidCredentials
 	}
+	if !admin.CanLogin(time.Now()) {
+		return "", error_const.ErrEmployeeInactive
+	}
 	token, err := utils.GenerateJWT(admin.ID, admin.Email, admin.Role)
 	if err != nil {
 		return "", err
@@ -80,9 +140,16 @@ func (s *EmployeeService) RecordAttendance(ctx context.Context, payload dto.Atte
 	if err != nil {
 		return error_const.ErrInvalidDateFormat
 	}
-	if attendanceDate.Weekday() == time.Saturday || attendanceDate.Weekday() == time.Sunday {
+	if domain.IsWeekend(attendanceDate) {
 		return error_const.ErrAttendanceOnWeekend
 	}
+	holidays, err := s.holidayRepo.GetHolidaysBetween(ctx, attendanceDate, attendanceDate)
+	if err != nil {
+		return err
+	}
+	if domain.NewHolidayCalendar(holidays).IsHoliday(attendanceDate) {
+		return error_const.ErrAttendanceOnHoliday
+	}
 
 	attendance.Date = attendanceDate
 	attendance.EmployeeID = payload.EmployeeID
@@ -150,7 +217,7 @@ func (s *EmployeeService) SubmitReimbursement(ctx context.Context, payload dto.R
 	if payload.EmployeeID == 0 {
 		return error_const.ErrInvalidCredentials
 	}
-	if payload.Amount <= 0 {
+	if !payload.Amount.IsPositive() {
 		return error_const.ErrInvalidReimbursementAmount
 	}
 	reimbursement.EmployeeID = payload.EmployeeID
@@ -173,15 +240,146 @@ func (s *EmployeeService) SubmitReimbursement(ctx context.Context, payload dto.R
 }
 
 func (s *EmployeeService) GetPayslip(ctx context.Context, payload dto.PayrollRequest) (interface{}, error) {
+	payroll, err := s.getPayroll(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	return payroll, nil
+}
+
+// GetPayslipPDF renders the employee's payslip of the period for printing.
+func (s *EmployeeService) GetPayslipPDF(ctx context.Context, payload dto.PayrollRequest) ([]byte, error) {
+	return s.renderPayslip(ctx, payload, domain.PayslipFormatPDF)
+}
+
+// GetPayslipHTML renders the employee's payslip of the period as a web page.
+func (s *EmployeeService) GetPayslipHTML(ctx context.Context, payload dto.PayrollRequest) ([]byte, error) {
+	return s.renderPayslip(ctx, payload, domain.PayslipFormatHTML)
+}
+
+// renderPayslip uses the payslip template of the employee's company, or the built-in layout
+// when the employee has no company or the company has no template.
+func (s *EmployeeService) renderPayslip(ctx context.Context, payload dto.PayrollRequest, format string) ([]byte, error) {
+	payroll, err := s.getPayroll(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	employee, err := s.empRepo.GetEmployee(ctx, domain.Employee{Email: payload.ActorEmail})
+	if err != nil {
+		return nil, err
+	}
+	period, err := s.payrollRepo.GetPayrollPeriod(ctx, domain.PayrollPeriod{ID: payroll.PeriodID})
+	if err != nil {
+		return nil, err
+	}
+	var template *domain.PayslipTemplate
+	if employee.Company != "" {
+		latest, err := s.templateRepo.GetLatestPayslipTemplate(ctx, employee.Company)
+		if err == nil {
+			template = &latest
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+	}
+	return document_service.RenderPayslip(document_service.PayslipDocument{
+		Employer: s.withholder,
+		Employee: employee,
+		Period:   period,
+		Payroll:  payroll,
+		Rounding: s.rounding,
+	}, template, format)
+}
+
+func (s *EmployeeService) getPayroll(ctx context.Context, payload dto.PayrollRequest) (domain.Payroll, error) {
 	payroll, err := s.payrollRepo.GetEmployeePayslipByPeriod(ctx, domain.Payroll{
 		EmployeeID: payload.EmployeeID,
 		PeriodID:   payload.PeriodID,
 	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, error_const.ErrPayslipNotFound
+			return domain.Payroll{}, error_const.ErrPayslipNotFound
 		}
-		return nil, err
+		return domain.Payroll{}, err
 	}
 	return payroll, nil
 }
+
+// GetBonusPayslips lists the employee's THR and bonus payslips paid in the period.
+func (s *EmployeeService) GetBonusPayslips(ctx context.Context, employeeID, periodID int) ([]domain.BonusPayslip, error) {
+	return s.bonusRepo.GetEmployeeBonusPayslipsByPeriod(ctx, employeeID, periodID)
+}
+
+// GetYearToDate returns the employee's gross, tax, BPJS and net pay of the tax year so far,
+// counting the payslips of locked periods.
+func (s *EmployeeService) GetYearToDate(ctx context.Context, employeeID, taxYear int) (domain.TaxYearToDate, error) {
+	ytd, err := s.payrollRepo.GetYearToDate(ctx, employeeID, taxYear)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.TaxYearToDate{EmployeeID: employeeID, TaxYear: taxYear}, nil
+		}
+		return domain.TaxYearToDate{}, err
+	}
+	return ytd, nil
+}
+
+// GetTaxStatement returns the employee's 1721-A1 statement of a tax year once it has been generated.
+func (s *EmployeeService) GetTaxStatement(ctx context.Context, employeeID, taxYear int) (domain.TaxStatement, error) {
+	statement, err := s.taxStatementRepo.GetTaxStatement(ctx, employeeID, taxYear)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.TaxStatement{}, error_const.ErrTaxStatementNotFound
+		}
+		return domain.TaxStatement{}, err
+	}
+	return statement, nil
+}
+
+func (s *EmployeeService) GetTaxStatementPDF(ctx context.Context, employeeID, taxYear int) ([]byte, error) {
+	statement, err := s.GetTaxStatement(ctx, employeeID, taxYear)
+	if err != nil {
+		return nil, err
+	}
+	return document_service.RenderTaxStatementPDF(statement)
+}
+
+// GetLoans lists the employee's loans and salary advances with their outstanding balances.
+func (s *EmployeeService) GetLoans(ctx context.Context, employeeID int) ([]domain.Loan, error) {
+	return s.loanRepo.GetEmployeeLoans(ctx, employeeID)
+}
+
+// GetLoan returns one of the employee's loans with the installments repaid so far. Loans of
+// other employees are reported as not found.
+func (s *EmployeeService) GetLoan(ctx context.Context, employeeID, loanID int) (domain.Loan, error) {
+	loan, err := s.loanRepo.GetLoan(ctx, loanID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Loan{}, error_const.ErrLoanNotFound
+		}
+		return domain.Loan{}, err
+	}
+	if loan.EmployeeID != employeeID {
+		return domain.Loan{}, error_const.ErrLoanNotFound
+	}
+	return loan, nil
+}
+
+// SetPayslipPassword sets the password the employee's emailed payslips are protected with.
+// Only hashes derived from it are stored, so it cannot be read back; forgetting it means
+// setting a new one, which opens the payslips emailed from then on.
+func (s *EmployeeService) SetPayslipPassword(ctx context.Context, payload dto.PayslipPasswordRequest) error {
+	if payload.EmployeeID == 0 {
+		return error_const.ErrInvalidCredentials
+	}
+	if utf8.RuneCountInString(payload.Password) < payslipPasswordMinLength {
+		return error_const.ErrPayslipPasswordTooShort
+	}
+	password, err := utils.NewPDFPassword(payload.Password)
+	if err != nil {
+		return err
+	}
+	return s.passwordRepo.SetPayslipPassword(ctx, domain.PayslipPassword{
+		EmployeeID: payload.EmployeeID,
+		User:       password.User,
+		Key:        password.Key,
+	})
+}
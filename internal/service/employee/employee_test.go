@@ -18,9 +18,9 @@ func TestLoginAsEmployee_InvalidCredentials(t *testing.T) {
 	mockEmpRepo.Employee = domain.Employee{}
 	mockEmpRepo.Err = error_const.ErrInvalidCredentials
 
-	svc := NewEmployeeService(
-		mockEmpRepo, nil, nil, nil, nil,
-	)
+	svc := NewEmployeeService(EmployeeDependencies{
+		EmployeeRepository: mockEmpRepo,
+	})
 	_, err := svc.LoginAsEmployee(context.Background(), dto.LoginRequest{Email: "", Password: ""})
 	if err == nil {
 		t.Error("expected error for invalid credentials")
@@ -33,9 +33,9 @@ func TestGetPayslip_NotFound(t *testing.T) {
 	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
 	mockPayrollRepo.Err = error_const.ErrPayslipNotFound
 
-	svc := NewEmployeeService(
-		nil, mockPayrollRepo, nil, nil, nil,
-	)
+	svc := NewEmployeeService(EmployeeDependencies{
+		PayrollRepository: mockPayrollRepo,
+	})
 	_, err := svc.GetPayslip(context.Background(), dto.PayrollRequest{EmployeeID: 0, PeriodID: 0})
 	if err == nil {
 		t.Error("expected error for payslip not found")
@@ -0,0 +1,121 @@
+package payroll_service
+
+import (
+	"math/big"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"time"
+)
+
+// BonusInput is everything needed for one employee's THR or bonus payslip. The period figures
+// are the regular payslip and earlier bonus payslips of the same period, which the irregular
+// income is taxed together with. Currency selects one of the rounding policies; empty means
+// their default currency.
+type BonusInput struct {
+	Rounding      domain.RoundingPolicies
+	Currency      string
+	Employee      domain.Employee
+	Period        domain.PayrollPeriod
+	Kind          string
+	ReferenceDate time.Time    // THR: the date tenure is counted to
+	MonthlyWage   domain.Money // THR: base salary and fixed allowances on the reference date
+	Amounts       []domain.BonusAmount
+	TaxProfile    domain.TaxProfile
+	TaxYearToDate domain.TaxYearToDate // payslips of earlier periods in the tax year
+	PeriodToDate  domain.TaxYearToDate // the regular and earlier bonus payslips of the period
+}
+
+// CalculateBonusPayslip returns the THR or bonus payslip of an employee. THR is the monthly
+// wage times the twelfths the employee is entitled to; bonuses are the uploaded amounts.
+func CalculateBonusPayslip(input BonusInput) (payslip domain.Payslip, err error) {
+	defer domain.RecoverMoneyOverflow(&err)
+	rounding := input.Rounding.For(input.Currency)
+	payslip.EmployeeID = input.Employee.ID
+	payslip.PeriodID = input.Period.ID
+	payslip.Currency = rounding.Currency
+
+	var lines domain.PayslipLines
+	switch input.Kind {
+	case domain.BonusKindTHR:
+		months := input.Employee.THRMonths(input.ReferenceDate)
+		if months <= 0 || !input.MonthlyWage.IsPositive() {
+			return payslip, error_const.ErrNoTHREntitlement
+		}
+		rate := new(big.Rat).Quo(input.MonthlyWage.Rat(), big.NewRat(12, 1))
+		lines = append(lines, domain.PayslipLine{
+			Code:        domain.PayslipLineCodeTHR,
+			Category:    domain.PayslipLineEarning,
+			Description: "THR",
+			Quantity:    domain.RateFromInt(int64(months)),
+			Unit:        domain.PayslipLineUnitMonths,
+			Rate:        domain.RateFromRat(rate),
+			Amount:      rounding.Round(new(big.Rat).Mul(rate, big.NewRat(int64(months), 1))),
+			Taxable:     true,
+		})
+	case domain.BonusKindBonus:
+		for _, amount := range input.Amounts {
+			description := amount.Description
+			if description == "" {
+				description = "Bonus"
+			}
+			lines = append(lines, domain.NewAmountLine(domain.PayslipLineCodeBonus, domain.PayslipLineEarning, description, amount.Amount, true))
+		}
+		if !lines.Total(domain.PayslipLineEarning).IsPositive() {
+			return payslip, error_const.ErrInvalidBonusAmount
+		}
+	default:
+		return payslip, error_const.ErrInvalidBonusKind
+	}
+
+	payslip.TaxableIncome = lines.TaxableIncome()
+	tax := CalculateIrregularPPh21(IrregularTaxInput{
+		Profile:        input.TaxProfile,
+		Amount:         payslip.TaxableIncome,
+		PeriodTaxable:  input.PeriodToDate.TaxableIncome,
+		PeriodWithheld: input.PeriodToDate.TaxWithheld,
+		YearToDate:     input.TaxYearToDate,
+		Pension:        input.TaxYearToDate.Pension.Add(input.PeriodToDate.Pension),
+		IsYearEnd:      IsTaxYearEnd(input.Employee, input.Period),
+	}, rounding)
+	payslip.Tax = &tax
+	lines = append(lines, domain.NewAmountLine(domain.DeductionCodePPh21, domain.PayslipLineDeduction, "PPh 21 income tax", tax.Amount, false))
+	payslip.Lines = lines
+	payslip.SetTotalsFromLines()
+	yearToDate := input.TaxYearToDate.Plus(input.PeriodToDate).Add(payslip, false)
+	yearToDate.EmployeeID, yearToDate.TaxYear = input.Employee.ID, input.Period.EndDate.Year()
+	payslip.YearToDate = &yearToDate
+
+	return payslip, nil
+}
+
+// IrregularTaxInput is the data needed to withhold PPh 21 on THR or a bonus.
+type IrregularTaxInput struct {
+	Profile        domain.TaxProfile
+	Amount         domain.Money // taxable irregular income of this payslip
+	PeriodTaxable  domain.Money
+	PeriodWithheld domain.Money
+	YearToDate     domain.TaxYearToDate // earlier periods only
+	Pension        domain.Money         // employee pension contributions for the year including the period
+	IsYearEnd      bool
+}
+
+// CalculateIrregularPPh21 withholds PPh 21 on irregular income the way PMK 168/2023 does: the
+// income is added to the gross of the month it is paid in and the tax of the month is
+// recalculated, so the TER rate of the higher gross applies. The payslip withholds the
+// difference with the tax already withheld in the period. At the end of the employee's tax
+// year the annual tax is recalculated with the irregular income included.
+func CalculateIrregularPPh21(input IrregularTaxInput, rounding domain.RoundingPolicy) domain.TaxDetail {
+	ytd := input.YearToDate
+	ytd.TaxWithheld = ytd.TaxWithheld.Add(input.PeriodWithheld)
+	detail := CalculatePPh21(TaxInput{
+		Profile:      input.Profile,
+		MonthlyGross: input.PeriodTaxable.Add(input.Amount),
+		YearToDate:   ytd,
+		Pension:      input.Pension,
+		IsYearEnd:    input.IsYearEnd,
+	}, rounding)
+	if !input.IsYearEnd {
+		detail.Amount = detail.Amount.Sub(input.PeriodWithheld)
+	}
+	return detail
+}
@@ -0,0 +1,78 @@
+package payroll_service
+
+import (
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"testing"
+	"time"
+)
+
+func TestCalculateBonusPayslip_THRProrated(t *testing.T) {
+	start := time.Date(2025, time.September, 15, 0, 0, 0, 0, time.UTC)
+	referenceDate := time.Date(2026, time.March, 20, 0, 0, 0, 0, time.UTC)
+	period := domain.PayrollPeriod{
+		ID:        3,
+		StartDate: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC),
+	}
+	profile := domain.TaxProfile{NPWP: "0123456789012345", MaritalStatus: domain.MaritalStatusSingle}
+	input := BonusInput{
+		Employee:      domain.Employee{ID: 1, StartDate: &start},
+		Period:        period,
+		Kind:          domain.BonusKindTHR,
+		ReferenceDate: referenceDate,
+		MonthlyWage:   domain.NewMoney(12000000),
+		TaxProfile:    profile,
+		PeriodToDate:  domain.TaxYearToDate{Months: 1, TaxableIncome: domain.NewMoney(10000000), TaxWithheld: domain.NewMoney(200000)},
+	}
+	payslip, err := CalculateBonusPayslip(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// six full months of service: 6/12 of the monthly wage
+	if payslip.TotalSalary != domain.NewMoney(6000000) {
+		t.Errorf("THR = %s, want 6000000.00", payslip.TotalSalary)
+	}
+	// March is taxed on 16,000,000 at the 7% TER A rate instead of 10,000,000 at 2%: 1,120,000 less
+	// the 200,000 withheld on the regular payslip
+	wantTax := domain.NewMoney(920000)
+	if payslip.Tax.Amount != wantTax {
+		t.Errorf("PPh 21 = %s, want %s", payslip.Tax.Amount, wantTax)
+	}
+	if payslip.NetSalary != payslip.TotalSalary.Sub(wantTax) {
+		t.Errorf("net = %s, want %s", payslip.NetSalary, payslip.TotalSalary.Sub(wantTax))
+	}
+
+	input.ReferenceDate = start.AddDate(0, 0, 20)
+	if _, err := CalculateBonusPayslip(input); err != error_const.ErrNoTHREntitlement {
+		t.Errorf("expected ErrNoTHREntitlement below one month of service, got %v", err)
+	}
+}
+
+func TestCalculateBonusPayslip_Bonus(t *testing.T) {
+	payslip, err := CalculateBonusPayslip(BonusInput{
+		Employee: domain.Employee{ID: 2},
+		Period:   domain.PayrollPeriod{ID: 12, EndDate: time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)},
+		Kind:     domain.BonusKindBonus,
+		Amounts: []domain.BonusAmount{
+			{EmployeeID: 2, Amount: domain.NewMoney(20000000), Description: "Annual performance bonus"},
+		},
+		TaxProfile:    domain.TaxProfile{NPWP: "0123456789012345", MaritalStatus: domain.MaritalStatusSingle},
+		TaxYearToDate: domain.TaxYearToDate{Months: 11, TaxableIncome: domain.NewMoney(110000000), TaxWithheld: domain.NewMoney(2200000)},
+		PeriodToDate:  domain.TaxYearToDate{Months: 1, TaxableIncome: domain.NewMoney(10000000), TaxWithheld: domain.NewMoney(800000)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payslip.Tax.Method != domain.TaxMethodAnnual {
+		t.Fatalf("expected the December true-up, got %s", payslip.Tax.Method)
+	}
+	// 140,000,000 - 6,000,000 biaya jabatan - 54,000,000 PTKP = 80,000,000 PKP:
+	// 5% of 60,000,000 + 15% of 20,000,000 = 6,000,000, of which 3,000,000 is already withheld
+	if payslip.Tax.Amount != domain.NewMoney(3000000) {
+		t.Errorf("PPh 21 = %s, want 3000000.00", payslip.Tax.Amount)
+	}
+	if payslip.NetSalary != domain.NewMoney(17000000) {
+		t.Errorf("net = %s, want 17000000.00", payslip.NetSalary)
+	}
+}
@@ -0,0 +1,24 @@
+package payroll_service
+
+import (
+	"math/big"
+	"payroll-system/internal/domain"
+)
+
+// CalculateBPJS applies each program's rates to the wage, capped where the program has a ceiling.
+func CalculateBPJS(wage domain.Money, rates []domain.BPJSRate, rounding domain.RoundingPolicy) []domain.BPJSContribution {
+	contributions := make([]domain.BPJSContribution, 0, len(rates))
+	for _, rate := range rates {
+		base := wage
+		if rate.WageCap.IsPositive() && base.GreaterThan(rate.WageCap) {
+			base = rate.WageCap
+		}
+		contributions = append(contributions, domain.BPJSContribution{
+			Program:        rate.Program,
+			Wage:           base,
+			EmployeeAmount: rounding.Round(new(big.Rat).Mul(base.Rat(), rate.EmployeeRate.Rat())),
+			EmployerAmount: rounding.Round(new(big.Rat).Mul(base.Rat(), rate.EmployerRate.Rat())),
+		})
+	}
+	return contributions
+}
@@ -0,0 +1,57 @@
+package payroll_service
+
+import (
+	"payroll-system/internal/domain"
+	"testing"
+)
+
+func TestCalculateBPJS_WageCaps(t *testing.T) {
+	rates := []domain.BPJSRate{
+		{Program: domain.BPJSProgramJHT, EmployeeRate: domain.MustRate("0.02"), EmployerRate: domain.MustRate("0.037")},
+		{Program: domain.BPJSProgramJP, EmployeeRate: domain.MustRate("0.01"), EmployerRate: domain.MustRate("0.02"), WageCap: domain.NewMoney(10547400)},
+		{Program: domain.BPJSProgramKesehatan, EmployeeRate: domain.MustRate("0.01"), EmployerRate: domain.MustRate("0.04"), WageCap: domain.NewMoney(12000000)},
+	}
+	contributions := CalculateBPJS(domain.NewMoney(15000000), rates, domain.DefaultRoundingPolicy)
+	want := map[string][2]int64{
+		domain.BPJSProgramJHT:       {300000, 555000},
+		domain.BPJSProgramJP:        {105474, 210948},
+		domain.BPJSProgramKesehatan: {120000, 480000},
+	}
+	for _, c := range contributions {
+		w := want[c.Program]
+		if c.EmployeeAmount != domain.NewMoney(w[0]) || c.EmployerAmount != domain.NewMoney(w[1]) {
+			t.Errorf("%s: expected %d/%d, got %s/%s", c.Program, w[0], w[1], c.EmployeeAmount, c.EmployerAmount)
+		}
+	}
+}
+
+func TestRuleBasedCalculator_BPJSDeductionsAndTaxableBenefits(t *testing.T) {
+	payslip, err := NewRuleBasedCalculator(domain.RoundingPolicies{}).Calculate(CalculationInput{
+		Employee:      domain.Employee{ID: 1, Salary: domain.NewMoney(10000000)},
+		Rules:         DefaultPayRuleSet,
+		Attendances:   20,
+		TotalWorkDays: 20,
+		TaxProfile:    domain.TaxProfile{NPWP: "0123456789012345"},
+		BPJSRates: []domain.BPJSRate{
+			{Program: domain.BPJSProgramJHT, EmployeeRate: domain.MustRate("0.02"), EmployerRate: domain.MustRate("0.037")},
+			{Program: domain.BPJSProgramJKK, EmployerRate: domain.MustRate("0.0024")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// JKK paid by the employer is a taxable benefit, JHT is not
+	if payslip.TaxableIncome != domain.NewMoney(10024000) {
+		t.Errorf("expected taxable income 10024000, got %s", payslip.TaxableIncome)
+	}
+	if payslip.EmployerContributions != domain.NewMoney(394000) {
+		t.Errorf("expected employer contributions 394000, got %s", payslip.EmployerContributions)
+	}
+	// PPh 21 at TER A 2% of 10,024,000 plus the 200,000 JHT employee share
+	if payslip.TotalDeductions != domain.NewMoney(400480) {
+		t.Errorf("expected deductions 400480, got %s", payslip.TotalDeductions)
+	}
+	if payslip.NetSalary != domain.NewMoney(9599520) {
+		t.Errorf("expected net salary 9599520, got %s", payslip.NetSalary)
+	}
+}
@@ -0,0 +1,344 @@
+package payroll_service
+
+import (
+	"math/big"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"time"
+)
+
+// DefaultPayRuleSet reproduces the rules that used to be hard-coded in RunPayrollPeriod.
+// It is seeded as version 1 and used when no rule set is stored.
+var DefaultPayRuleSet = domain.PayRuleSet{
+	Version:            1,
+	Description:        "Default rule set",
+	BasePayFormula:     domain.BasePayFormulaAttendanceRatio,
+	HourlyDivisor:      domain.RateFromInt(20), // Assuming 20 working days in a month
+	OvertimeMultiplier: domain.RateFromInt(2),  // Overtime is paid at double rate
+}
+
+// CalculationInput is everything the calculator needs to produce a single payslip.
+// Currency selects the rounding policy; empty means the default currency.
+type CalculationInput struct {
+	Currency       string
+	Employee       domain.Employee
+	Period         domain.PayrollPeriod
+	Rules          domain.PayRuleSet
+	Attendances    int
+	TotalWorkDays  int // workdays of the whole period; the payslip shows the employee's eligible days
+	Overtimes      []domain.Overtime
+	Reimbursements []domain.Reimbursement
+	TaxProfile     domain.TaxProfile
+	TaxYearToDate  domain.TaxYearToDate // earlier payslips of the same tax year
+	BPJSRates      []domain.BPJSRate    // rates effective for the period, one per program
+	Holidays       domain.HolidayCalendar
+	SalaryHistory  []domain.SalaryChange           // empty means Employee.Salary for the whole period
+	PayComponents  []domain.PayComponentAssignment // recurring allowances and deductions effective in the period
+	Loans          []domain.Loan                   // active loans, with the balance left before this period
+}
+
+type PayrollCalculator interface {
+	Calculate(input CalculationInput) (domain.Payslip, error)
+	ValidateRuleSet(rules domain.PayRuleSet) error
+}
+
+// RuleBasedCalculator computes payslips from the pay rule set given in the input, rounding
+// amounts by the policy of the input's currency.
+type RuleBasedCalculator struct {
+	rounding domain.RoundingPolicies
+	formulas BasePayFormulas
+}
+
+func NewRuleBasedCalculator(rounding domain.RoundingPolicies) *RuleBasedCalculator {
+	return &RuleBasedCalculator{rounding: rounding, formulas: DefaultBasePayFormulas()}
+}
+
+// RegisterBasePayFormula lets pay rule sets name the formula. Register formulas before the
+// calculator is used; it is not safe for concurrent use with Calculate.
+func (c *RuleBasedCalculator) RegisterBasePayFormula(name string, formula BasePayFormula) {
+	c.formulas[name] = formula
+}
+
+func (c *RuleBasedCalculator) ValidateRuleSet(rules domain.PayRuleSet) error {
+	if !rules.HourlyDivisor.IsPositive() || !rules.OvertimeMultiplier.IsPositive() || rules.RestDayOvertimeMultiplier.Sign() < 0 {
+		return error_const.ErrInvalidPayRuleSet
+	}
+	if _, ok := c.formulas[rules.BasePayFormula]; !ok {
+		return error_const.ErrUnknownBasePayFormula
+	}
+	return nil
+}
+
+func (c *RuleBasedCalculator) Calculate(input CalculationInput) (payslip domain.Payslip, err error) {
+	defer domain.RecoverMoneyOverflow(&err)
+	rules := input.Rules
+	if err := c.ValidateRuleSet(rules); err != nil {
+		return payslip, err
+	}
+	basePay := c.formulas[rules.BasePayFormula]
+	if input.TotalWorkDays <= 0 {
+		return payslip, error_const.ErrNoWorkdaysInPeriod
+	}
+	employee := input.Employee
+	rounding := c.rounding.For(input.Currency)
+
+	payslip.EmployeeID = employee.ID
+	payslip.PeriodID = input.Period.ID
+	payslip.RuleSetVersion = rules.Version
+	payslip.Currency = rounding.Currency
+	payslip.NumberAttendances = input.Attendances
+
+	employedFrom, employedTo, employed := employee.EmploymentWithin(input.Period.StartDate, input.Period.EndDate)
+	if !employed {
+		return payslip, error_const.ErrEmployeeNotEmployed
+	}
+	payslip.TotalWorkDays = input.TotalWorkDays
+	if !employedFrom.Equal(input.Period.StartDate) || !employedTo.Equal(input.Period.EndDate) {
+		payslip.TotalWorkDays = input.Holidays.Workdays(employedFrom, employedTo)
+	}
+
+	segments := salarySegments(input, employedFrom, employedTo, payslip.TotalWorkDays)
+	if len(segments) == 0 {
+		return payslip, error_const.ErrNoSalaryInPeriod
+	}
+	// the salary at the end of the period is the monthly salary for overtime and BPJS
+	monthlySalary := segments[len(segments)-1].Salary
+
+	lines := domain.PayslipLines{
+		{Code: domain.PayslipLineCodeWorkdays, Category: domain.PayslipLineInformation, Description: "Workdays", Quantity: domain.RateFromInt(int64(payslip.TotalWorkDays)), Unit: domain.PayslipLineUnitDays},
+		{Code: domain.PayslipLineCodeAttendance, Category: domain.PayslipLineInformation, Description: "Attendance", Quantity: domain.RateFromInt(int64(payslip.NumberAttendances)), Unit: domain.PayslipLineUnitDays},
+	}
+
+	// each salary is paid at its daily rate over the period's workdays for the days the rule
+	// set's formula pays out of those it was in effect
+	for i, segment := range segments {
+		dailyRate := new(big.Rat).Quo(segment.Salary.Rat(), big.NewRat(int64(input.TotalWorkDays), 1))
+		paidDays := basePay(BasePayInput{
+			SegmentWorkdays:  segment.Workdays,
+			EmployedWorkdays: payslip.TotalWorkDays,
+			Attendances:      payslip.NumberAttendances,
+		})
+		segments[i].Amount = rounding.Round(new(big.Rat).Mul(paidDays, dailyRate))
+		from, to := segment.StartDate, segment.EndDate
+		lines = append(lines, domain.PayslipLine{
+			Code:        domain.PayslipLineCodeBasePay,
+			Category:    domain.PayslipLineEarning,
+			Description: "Base salary",
+			Quantity:    domain.RateFromRat(paidDays),
+			Unit:        domain.PayslipLineUnitDays,
+			Rate:        domain.RateFromRat(dailyRate),
+			Amount:      segments[i].Amount,
+			Taxable:     true,
+			Formula:     rules.BasePayFormula,
+			StartDate:   &from,
+			EndDate:     &to,
+		})
+	}
+	payslip.SalarySegments = segments
+	payslip.SalaryByAttendance = lines.TotalOf(domain.PayslipLineCodeBasePay)
+
+	// the overtime rate is kept exact and only each overtime amount is rounded
+	for _, o := range input.Overtimes {
+		restDay := !input.Holidays.IsWorkday(o.Date)
+		hourlySalary := new(big.Rat).Quo(salaryOn(segments, o.Date).Rat(), rules.HourlyDivisor.Rat())
+		overtimeRate := new(big.Rat).Mul(hourlySalary, rules.OvertimeMultiplierFor(restDay).Rat())
+		amount := rounding.Round(new(big.Rat).Mul(overtimeRate, big.NewRat(int64(o.Hours), 1)))
+		payslip.OvertimesRecap = append(payslip.OvertimesRecap, domain.OvertimeRecap{
+			Date:    o.Date,
+			Hours:   o.Hours,
+			Amount:  amount,
+			RestDay: restDay,
+		})
+		code, description := domain.PayslipLineCodeOvertime, "Overtime"
+		if restDay {
+			code, description = domain.PayslipLineCodeRestDayOvertime, "Rest day overtime"
+		}
+		date := o.Date
+		lines = append(lines, domain.PayslipLine{
+			Code:        code,
+			Category:    domain.PayslipLineEarning,
+			Description: description,
+			Quantity:    domain.RateFromInt(int64(o.Hours)),
+			Unit:        domain.PayslipLineUnitHours,
+			Rate:        domain.RateFromRat(overtimeRate),
+			Amount:      amount,
+			Taxable:     true,
+			StartDate:   &date,
+			EndDate:     &date,
+		})
+	}
+	overtimeSalary := lines.TotalOf(domain.PayslipLineCodeOvertime, domain.PayslipLineCodeRestDayOvertime)
+	payslip.OvetimeTotalSalary = overtimeSalary
+
+	// reimbursements are not income, so they are not taxed
+	payslip.Reimbursements = input.Reimbursements
+	for _, r := range payslip.Reimbursements {
+		lines = append(lines, domain.NewAmountLine(domain.PayslipLineCodeReimbursement, domain.PayslipLineEarning, r.Description, r.Amount, false))
+	}
+	payslip.ReimbursementsTotalSalary = lines.TotalOf(domain.PayslipLineCodeReimbursement)
+
+	componentLines := payComponentLines(input, employedFrom, employedTo, rounding)
+	for _, line := range componentLines {
+		if line.Category == domain.PayslipLineEarning {
+			lines = append(lines, line)
+		}
+	}
+
+	// BPJS contributions are based on the fixed monthly salary; the employer paid JKK, JKM and
+	// Kesehatan premiums are taxable
+	payslip.BPJS = CalculateBPJS(monthlySalary, input.BPJSRates, rounding)
+	ratesByProgram := make(map[string]domain.BPJSRate, len(input.BPJSRates))
+	for _, rate := range input.BPJSRates {
+		ratesByProgram[rate.Program] = rate
+	}
+	var pension domain.Money
+	for _, contribution := range payslip.BPJS {
+		if contribution.IsPension() {
+			pension = pension.Add(contribution.EmployeeAmount)
+		}
+		if contribution.EmployerAmount.IsZero() {
+			continue
+		}
+		lines = append(lines, domain.PayslipLine{
+			Code:        "BPJS_" + contribution.Program,
+			Category:    domain.PayslipLineEmployerContribution,
+			Description: "BPJS " + contribution.Program + " employer contribution",
+			Quantity:    domain.RateFromRat(contribution.Wage.Rat()),
+			Unit:        domain.PayslipLineUnitWage,
+			Rate:        ratesByProgram[contribution.Program].EmployerRate,
+			Amount:      contribution.EmployerAmount,
+			Taxable:     contribution.IsTaxableBenefit(),
+		})
+	}
+
+	payslip.TaxableIncome = lines.TaxableIncome()
+	tax := CalculatePPh21(TaxInput{
+		Profile:      input.TaxProfile,
+		MonthlyGross: payslip.TaxableIncome,
+		YearToDate:   input.TaxYearToDate,
+		Pension:      input.TaxYearToDate.Pension.Add(pension),
+		IsYearEnd:    IsTaxYearEnd(input.Employee, input.Period),
+	}, rounding)
+	payslip.Tax = &tax
+	lines = append(lines, domain.NewAmountLine(domain.DeductionCodePPh21, domain.PayslipLineDeduction, "PPh 21 income tax", tax.Amount, false))
+	for _, contribution := range payslip.BPJS {
+		if contribution.EmployeeAmount.IsZero() {
+			continue
+		}
+		lines = append(lines, domain.PayslipLine{
+			Code:        "BPJS_" + contribution.Program,
+			Category:    domain.PayslipLineDeduction,
+			Description: "BPJS " + contribution.Program + " employee contribution",
+			Quantity:    domain.RateFromRat(contribution.Wage.Rat()),
+			Unit:        domain.PayslipLineUnitWage,
+			Rate:        ratesByProgram[contribution.Program].EmployeeRate,
+			Amount:      contribution.EmployeeAmount,
+		})
+	}
+	for _, line := range componentLines {
+		if line.Category == domain.PayslipLineDeduction {
+			lines = append(lines, line)
+		}
+	}
+	payslip.LoanInstallments, lines = loanInstallments(input.Loans, lines)
+	payslip.Lines = lines
+	payslip.SetTotalsFromLines()
+	yearToDate := input.TaxYearToDate.Add(payslip, true)
+	yearToDate.EmployeeID, yearToDate.TaxYear = input.Employee.ID, input.Period.EndDate.Year()
+	payslip.YearToDate = &yearToDate
+
+	return payslip, nil
+}
+
+// loanInstallments deducts the due installment of each loan, oldest first. Installments are
+// capped at what is left of the net pay, so loans never make it negative; an installment that
+// could not be deducted in full is recorded with its due amount.
+func loanInstallments(loans []domain.Loan, lines domain.PayslipLines) ([]domain.LoanInstallment, domain.PayslipLines) {
+	var installments []domain.LoanInstallment
+	available := lines.Total(domain.PayslipLineEarning).Sub(lines.Total(domain.PayslipLineDeduction))
+	for _, loan := range loans {
+		due := loan.DueInstallment()
+		if !due.IsPositive() {
+			continue
+		}
+		amount := due
+		if amount.GreaterThan(available) {
+			amount = available
+		}
+		if amount.IsNegative() {
+			amount = domain.Money{}
+		}
+		installments = append(installments, domain.LoanInstallment{LoanID: loan.ID, Due: due, Amount: amount})
+		if amount.IsZero() {
+			continue
+		}
+		available = available.Sub(amount)
+		line := domain.NewAmountLine(domain.PayslipLineCodeLoanInstallment, domain.PayslipLineDeduction, loan.Label()+" installment", amount, false)
+		line.LoanID = loan.ID
+		lines = append(lines, line)
+	}
+	return installments, lines
+}
+
+// payComponentLines returns a line for each recurring allowance or deduction of the employee.
+// An assignment effective for the whole period is paid in full; otherwise its monthly amount is
+// prorated by the workdays it was effective while the employee was employed.
+func payComponentLines(input CalculationInput, employedFrom, employedTo time.Time, rounding domain.RoundingPolicy) []domain.PayslipLine {
+	var lines []domain.PayslipLine
+	for _, assignment := range input.PayComponents {
+		from, to, ok := assignment.EffectiveWithin(employedFrom, employedTo)
+		if !ok {
+			continue
+		}
+		component := assignment.Component
+		if from.Equal(input.Period.StartDate) && to.Equal(input.Period.EndDate) {
+			lines = append(lines, domain.NewAmountLine(component.Code, component.Category, component.Name, assignment.Amount, assignment.IsTaxable()))
+			continue
+		}
+		dailyRate := new(big.Rat).Quo(assignment.Amount.Rat(), big.NewRat(int64(input.TotalWorkDays), 1))
+		days := big.NewRat(int64(input.Holidays.Workdays(from, to)), 1)
+		lines = append(lines, domain.PayslipLine{
+			Code:        component.Code,
+			Category:    component.Category,
+			Description: component.Name,
+			Quantity:    domain.RateFromRat(days),
+			Unit:        domain.PayslipLineUnitDays,
+			Rate:        domain.RateFromRat(dailyRate),
+			Amount:      rounding.Round(new(big.Rat).Mul(days, dailyRate)),
+			Taxable:     assignment.IsTaxable(),
+			StartDate:   &from,
+			EndDate:     &to,
+		})
+	}
+	return lines
+}
+
+// salarySegments splits the employed days of the period by the employee's salary history and
+// counts the workdays of each segment. Without a history the employee's salary is paid for
+// all employed days.
+func salarySegments(input CalculationInput, from, to time.Time, employedWorkdays int) []domain.SalarySegment {
+	if len(input.SalaryHistory) == 0 {
+		return []domain.SalarySegment{{
+			StartDate: from,
+			EndDate:   to,
+			Salary:    input.Employee.Salary,
+			Workdays:  employedWorkdays,
+		}}
+	}
+	segments := domain.SalarySegments(input.SalaryHistory, from, to)
+	for i := range segments {
+		segments[i].Workdays = input.Holidays.Workdays(segments[i].StartDate, segments[i].EndDate)
+	}
+	return segments
+}
+
+// salaryOn returns the salary of the segment the date falls in.
+func salaryOn(segments []domain.SalarySegment, date time.Time) domain.Money {
+	salary := segments[0].Salary
+	for _, segment := range segments[1:] {
+		if !segment.StartDate.After(date) {
+			salary = segment.Salary
+		}
+	}
+	return salary
+}
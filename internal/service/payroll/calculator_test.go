@@ -0,0 +1,492 @@
+package payroll_service
+
+import (
+	"math/big"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"testing"
+	"time"
+)
+
+// The golden values below were produced by the hard-coded calculation that
+// RunPayrollPeriod used before pay rules became configurable, rounded half-up
+// to the whole rupiah as the IDR rounding policy requires.
+func TestRuleBasedCalculator_DefaultRulesGolden(t *testing.T) {
+	date := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name           string
+		salary         string
+		attendances    int
+		workdays       int
+		overtimeHours  []int
+		reimbursements []string
+		wantAttendance string
+		wantOvertime   string
+		wantReimburse  string
+		wantTotal      string
+		wantPaidDays   string
+	}{
+		{
+			name: "partial attendance with overtime and reimbursements", salary: "5010000",
+			attendances: 18, workdays: 21, overtimeHours: []int{2, 3}, reimbursements: []string{"150000", "25000"},
+			wantAttendance: "4294286", wantOvertime: "2505000", wantReimburse: "175000", wantTotal: "6974286",
+			wantPaidDays: "18",
+		},
+		{
+			name: "full attendance only", salary: "5000000",
+			attendances: 21, workdays: 21,
+			wantAttendance: "5000000", wantOvertime: "0", wantReimburse: "0", wantTotal: "5000000",
+			wantPaidDays: "21",
+		},
+		{
+			name: "no attendance", salary: "7350000",
+			attendances: 0, workdays: 22, overtimeHours: []int{1}, reimbursements: []string{"99999.99"},
+			wantAttendance: "0", wantOvertime: "735000", wantReimburse: "99999.99", wantTotal: "834999.99",
+			wantPaidDays: "0",
+		},
+	}
+
+	calculator := NewRuleBasedCalculator(domain.RoundingPolicies{})
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var overtimes []domain.Overtime
+			for _, h := range tc.overtimeHours {
+				overtimes = append(overtimes, domain.Overtime{EmployeeID: 1, Hours: h, Date: date})
+			}
+			var reimbursements []domain.Reimbursement
+			for _, a := range tc.reimbursements {
+				reimbursements = append(reimbursements, domain.Reimbursement{EmployeeID: 1, Amount: mustMoney(t, a), Date: date})
+			}
+			payslip, err := calculator.Calculate(CalculationInput{
+				Employee:       domain.Employee{ID: 1, Salary: mustMoney(t, tc.salary)},
+				Period:         domain.PayrollPeriod{ID: 7},
+				Rules:          DefaultPayRuleSet,
+				Attendances:    tc.attendances,
+				TotalWorkDays:  tc.workdays,
+				Overtimes:      overtimes,
+				Reimbursements: reimbursements,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if payslip.SalaryByAttendance != mustMoney(t, tc.wantAttendance) {
+				t.Errorf("attendance salary: expected %s, got %s", tc.wantAttendance, payslip.SalaryByAttendance)
+			}
+			if payslip.OvetimeTotalSalary != mustMoney(t, tc.wantOvertime) {
+				t.Errorf("overtime salary: expected %s, got %s", tc.wantOvertime, payslip.OvetimeTotalSalary)
+			}
+			if payslip.ReimbursementsTotalSalary != mustMoney(t, tc.wantReimburse) {
+				t.Errorf("reimbursements: expected %s, got %s", tc.wantReimburse, payslip.ReimbursementsTotalSalary)
+			}
+			if payslip.TotalSalary != mustMoney(t, tc.wantTotal) {
+				t.Errorf("total salary: expected %s, got %s", tc.wantTotal, payslip.TotalSalary)
+			}
+			base := payslip.Lines[2]
+			dailyRate := domain.RateFromRat(new(big.Rat).Quo(mustMoney(t, tc.salary).Rat(), big.NewRat(int64(tc.workdays), 1)))
+			if base.Code != domain.PayslipLineCodeBasePay || base.Quantity.String() != tc.wantPaidDays || base.Unit != domain.PayslipLineUnitDays ||
+				base.Rate.Cmp(dailyRate) != 0 || base.Formula != domain.BasePayFormulaAttendanceRatio {
+				t.Errorf("base pay line: expected %s days at %s by %s, got %+v", tc.wantPaidDays, dailyRate, domain.BasePayFormulaAttendanceRatio, base)
+			}
+			if payslip.RuleSetVersion != DefaultPayRuleSet.Version {
+				t.Errorf("expected rule set version %d, got %d", DefaultPayRuleSet.Version, payslip.RuleSetVersion)
+			}
+		})
+	}
+}
+
+func TestRuleBasedCalculator_CustomRules(t *testing.T) {
+	rules := domain.PayRuleSet{
+		Version:            2,
+		BasePayFormula:     domain.BasePayFormulaFullSalary,
+		HourlyDivisor:      domain.RateFromInt(173),
+		OvertimeMultiplier: domain.MustRate("1.5"),
+	}
+	payslip, err := NewRuleBasedCalculator(domain.RoundingPolicies{}).Calculate(CalculationInput{
+		Employee:      domain.Employee{ID: 1, Salary: domain.NewMoney(6920000)},
+		Rules:         rules,
+		Attendances:   10,
+		TotalWorkDays: 20,
+		Overtimes:     []domain.Overtime{{Hours: 2}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payslip.SalaryByAttendance != domain.NewMoney(6920000) {
+		t.Errorf("expected full salary, got %s", payslip.SalaryByAttendance)
+	}
+	if payslip.OvetimeTotalSalary != domain.NewMoney(120000) {
+		t.Errorf("expected overtime 120000, got %s", payslip.OvetimeTotalSalary)
+	}
+	if payslip.RuleSetVersion != 2 {
+		t.Errorf("expected rule set version 2, got %d", payslip.RuleSetVersion)
+	}
+}
+
+func TestRuleBasedCalculator_InvalidRules(t *testing.T) {
+	rules := DefaultPayRuleSet
+	rules.BasePayFormula = "unknown"
+	_, err := NewRuleBasedCalculator(domain.RoundingPolicies{}).Calculate(CalculationInput{Rules: rules, TotalWorkDays: 20})
+	if err != error_const.ErrUnknownBasePayFormula {
+		t.Errorf("expected ErrUnknownBasePayFormula, got %v", err)
+	}
+}
+
+func TestRuleBasedCalculator_RegisteredFormula(t *testing.T) {
+	calculator := NewRuleBasedCalculator(domain.RoundingPolicies{})
+	rules := DefaultPayRuleSet
+	rules.BasePayFormula = "attendance_plus_two"
+	if err := calculator.ValidateRuleSet(rules); err != error_const.ErrUnknownBasePayFormula {
+		t.Fatalf("expected ErrUnknownBasePayFormula before registering, got %v", err)
+	}
+	// two days of paid leave on top of the attendances, up to the workdays of the segment
+	calculator.RegisterBasePayFormula(rules.BasePayFormula, func(input BasePayInput) *big.Rat {
+		return attendanceRatio(BasePayInput{
+			SegmentWorkdays:  input.SegmentWorkdays,
+			EmployedWorkdays: input.EmployedWorkdays,
+			Attendances:      min(input.Attendances+2, input.EmployedWorkdays),
+		})
+	})
+	payslip, err := calculator.Calculate(CalculationInput{
+		Employee:      domain.Employee{ID: 1, Salary: domain.NewMoney(2000000)},
+		Rules:         rules,
+		Attendances:   15,
+		TotalWorkDays: 20,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payslip.SalaryByAttendance != domain.NewMoney(1700000) || payslip.Lines[2].Formula != rules.BasePayFormula {
+		t.Errorf("expected 17 of 20 days paid by %s, got %s %+v", rules.BasePayFormula, payslip.SalaryByAttendance, payslip.Lines[2])
+	}
+}
+
+func TestRuleBasedCalculator_RoundingPolicy(t *testing.T) {
+	input := CalculationInput{
+		Employee:      domain.Employee{ID: 1, Salary: domain.NewMoney(1000000)},
+		Rules:         DefaultPayRuleSet,
+		Attendances:   2,
+		TotalWorkDays: 3,
+	}
+	payslip, err := NewRuleBasedCalculator(domain.RoundingPolicies{}).Calculate(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1,000,000 x 2 / 3 = 666,666.67 rounds half-up to the whole rupiah
+	if payslip.SalaryByAttendance != domain.NewMoney(666667) || payslip.Currency != "IDR" {
+		t.Errorf("expected 666667.00 IDR, got %s %s", payslip.SalaryByAttendance, payslip.Currency)
+	}
+
+	input.Currency = "USD"
+	payslip, err = NewRuleBasedCalculator(domain.RoundingPolicies{}).Calculate(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payslip.SalaryByAttendance != domain.MoneyFromMinorUnits(66666667) {
+		t.Errorf("expected 666666.67 USD, got %s", payslip.SalaryByAttendance)
+	}
+}
+
+func mustMoney(t *testing.T, s string) domain.Money {
+	t.Helper()
+	m, err := domain.ParseMoney(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestCalculateRestDayOvertime(t *testing.T) {
+	rules := DefaultPayRuleSet
+	rules.RestDayOvertimeMultiplier = domain.RateFromInt(3)
+	holiday := time.Date(2025, 6, 6, 0, 0, 0, 0, time.UTC) // Friday, Idul Adha
+	input := CalculationInput{
+		Employee:      domain.Employee{ID: 1, Salary: domain.NewMoney(4000000)},
+		Period:        domain.PayrollPeriod{ID: 1, StartDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)},
+		Rules:         rules,
+		Attendances:   20,
+		TotalWorkDays: 20,
+		Overtimes: []domain.Overtime{
+			{Date: time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC), Hours: 2}, // Thursday
+			{Date: holiday, Hours: 2},
+			{Date: time.Date(2025, 6, 7, 0, 0, 0, 0, time.UTC), Hours: 1}, // Saturday
+		},
+		Holidays: domain.NewHolidayCalendar([]domain.Holiday{{Date: holiday, Name: "Idul Adha"}}),
+	}
+
+	payslip, err := NewRuleBasedCalculator(domain.RoundingPolicies{}).Calculate(input)
+	if err != nil {
+		t.Fatalf("Calculate: %v", err)
+	}
+	// 4,000,000 / 20 = 200,000 per hour: 2 x 2 on the workday, 3 x 3 on the rest days
+	want := []struct {
+		amount  domain.Money
+		restDay bool
+	}{
+		{domain.NewMoney(800000), false},
+		{domain.NewMoney(1200000), true},
+		{domain.NewMoney(600000), true},
+	}
+	for i, w := range want {
+		recap := payslip.OvertimesRecap[i]
+		if recap.Amount != w.amount || recap.RestDay != w.restDay {
+			t.Errorf("overtime %d = %s rest day %v, want %s rest day %v", i, recap.Amount, recap.RestDay, w.amount, w.restDay)
+		}
+	}
+	if payslip.OvetimeTotalSalary != domain.NewMoney(2600000) {
+		t.Errorf("overtime total = %s, want 2600000.00", payslip.OvetimeTotalSalary)
+	}
+}
+
+func TestCalculateSalarySegments(t *testing.T) {
+	raise := time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC) // Monday
+	input := CalculationInput{
+		Employee:      domain.Employee{ID: 1, Salary: domain.NewMoney(6300000)},
+		Period:        domain.PayrollPeriod{ID: 1, StartDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)},
+		Rules:         DefaultPayRuleSet,
+		Attendances:   21,
+		TotalWorkDays: 21,
+		Overtimes: []domain.Overtime{
+			{Date: time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC), Hours: 2},
+			{Date: time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC), Hours: 1},
+		},
+		SalaryHistory: []domain.SalaryChange{
+			{EmployeeID: 1, Salary: domain.NewMoney(6300000), EffectiveFrom: raise},
+			{EmployeeID: 1, Salary: domain.NewMoney(4200000), EffectiveFrom: time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	payslip, err := NewRuleBasedCalculator(domain.RoundingPolicies{}).Calculate(input)
+	if err != nil {
+		t.Fatalf("Calculate: %v", err)
+	}
+	// 10 of the 21 workdays at 4,200,000 and 11 at 6,300,000
+	want := []domain.SalarySegment{
+		{StartDate: input.Period.StartDate, EndDate: raise.AddDate(0, 0, -1), Salary: domain.NewMoney(4200000), Workdays: 10, Amount: domain.NewMoney(2000000)},
+		{StartDate: raise, EndDate: input.Period.EndDate, Salary: domain.NewMoney(6300000), Workdays: 11, Amount: domain.NewMoney(3300000)},
+	}
+	if len(payslip.SalarySegments) != len(want) {
+		t.Fatalf("expected %d segments, got %+v", len(want), payslip.SalarySegments)
+	}
+	for i, w := range want {
+		if payslip.SalarySegments[i] != w {
+			t.Errorf("segment %d = %+v, want %+v", i, payslip.SalarySegments[i], w)
+		}
+	}
+	if payslip.SalaryByAttendance != domain.NewMoney(5300000) {
+		t.Errorf("attendance salary = %s, want 5300000.00", payslip.SalaryByAttendance)
+	}
+	// overtime is paid at the salary in effect on the day: 210,000 x 2 x 2 and 315,000 x 2
+	if payslip.OvetimeTotalSalary != domain.NewMoney(1470000) {
+		t.Errorf("overtime total = %s, want 1470000.00", payslip.OvetimeTotalSalary)
+	}
+
+	input.SalaryHistory = []domain.SalaryChange{{EmployeeID: 1, Salary: domain.NewMoney(6300000), EffectiveFrom: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)}}
+	if _, err := NewRuleBasedCalculator(domain.RoundingPolicies{}).Calculate(input); err != error_const.ErrNoSalaryInPeriod {
+		t.Errorf("expected ErrNoSalaryInPeriod, got %v", err)
+	}
+}
+
+func TestCalculatePayslipLines(t *testing.T) {
+	payslip, err := NewRuleBasedCalculator(domain.RoundingPolicies{}).Calculate(CalculationInput{
+		Employee:       domain.Employee{ID: 1, Salary: domain.NewMoney(10000000)},
+		Rules:          DefaultPayRuleSet,
+		Attendances:    20,
+		TotalWorkDays:  20,
+		Overtimes:      []domain.Overtime{{Date: time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC), Hours: 2}},
+		Reimbursements: []domain.Reimbursement{{Amount: domain.NewMoney(150000), Description: "Taxi"}},
+		BPJSRates: []domain.BPJSRate{
+			{Program: domain.BPJSProgramJHT, EmployeeRate: domain.MustRate("0.02"), EmployerRate: domain.MustRate("0.037")},
+			{Program: domain.BPJSProgramJKK, EmployerRate: domain.MustRate("0.0024")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		code, category string
+		quantity, rate string
+		amount         int64
+		taxable        bool
+	}{
+		{domain.PayslipLineCodeWorkdays, domain.PayslipLineInformation, "20", "0", 0, false},
+		{domain.PayslipLineCodeAttendance, domain.PayslipLineInformation, "20", "0", 0, false},
+		{domain.PayslipLineCodeBasePay, domain.PayslipLineEarning, "20", "500000", 10000000, true},
+		{domain.PayslipLineCodeOvertime, domain.PayslipLineEarning, "2", "1000000", 2000000, true},
+		{domain.PayslipLineCodeReimbursement, domain.PayslipLineEarning, "1", "150000", 150000, false},
+		{"BPJS_JHT", domain.PayslipLineEmployerContribution, "10000000", "0.037", 370000, false},
+		{"BPJS_JKK", domain.PayslipLineEmployerContribution, "10000000", "0.0024", 24000, true},
+		{domain.DeductionCodePPh21, domain.PayslipLineDeduction, "1", payslip.Tax.Amount.String(), 0, false},
+		{"BPJS_JHT", domain.PayslipLineDeduction, "10000000", "0.02", 200000, false},
+	}
+	if len(payslip.Lines) != len(want) {
+		t.Fatalf("expected %d lines, got %+v", len(want), payslip.Lines)
+	}
+	for i, w := range want {
+		line := payslip.Lines[i]
+		if line.Code != w.code || line.Category != w.category || line.Taxable != w.taxable {
+			t.Errorf("line %d = %s/%s taxable %v, want %s/%s taxable %v", i, line.Code, line.Category, line.Taxable, w.code, w.category, w.taxable)
+		}
+		if line.Quantity.Cmp(domain.MustRate(w.quantity)) != 0 || line.Rate.Cmp(domain.MustRate(w.rate)) != 0 {
+			t.Errorf("line %d %s = %s x %s, want %s x %s", i, line.Code, line.Quantity, line.Rate, w.quantity, w.rate)
+		}
+		if w.code != domain.DeductionCodePPh21 && line.Amount != domain.NewMoney(w.amount) {
+			t.Errorf("line %d %s amount = %s, want %d", i, line.Code, line.Amount, w.amount)
+		}
+	}
+
+	lines := domain.PayslipLines(payslip.Lines)
+	if payslip.TotalSalary != lines.Total(domain.PayslipLineEarning) || payslip.TotalSalary != domain.NewMoney(12150000) {
+		t.Errorf("gross = %s, want the 12150000.00 of earning lines", payslip.TotalSalary)
+	}
+	if payslip.NetSalary != payslip.TotalSalary.Sub(lines.Total(domain.PayslipLineDeduction)) {
+		t.Errorf("net = %s, want gross minus deduction lines", payslip.NetSalary)
+	}
+	// the reimbursement is not taxed, the JKK premium is
+	if payslip.TaxableIncome != domain.NewMoney(12024000) {
+		t.Errorf("taxable income = %s, want 12024000.00", payslip.TaxableIncome)
+	}
+}
+
+func TestCalculatePayComponents(t *testing.T) {
+	june := domain.PayrollPeriod{ID: 1, StartDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)}
+	payslip, err := NewRuleBasedCalculator(domain.RoundingPolicies{}).Calculate(CalculationInput{
+		Employee:      domain.Employee{ID: 1, Salary: domain.NewMoney(6300000)},
+		Period:        june,
+		Rules:         DefaultPayRuleSet,
+		Attendances:   21,
+		TotalWorkDays: 21,
+		PayComponents: []domain.PayComponentAssignment{
+			{
+				Component:     domain.PayComponent{Code: "TRANSPORT", Name: "Transport allowance", Category: domain.PayslipLineEarning, Taxable: true, Active: true},
+				Amount:        domain.NewMoney(420000),
+				EffectiveFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				Component:     domain.PayComponent{Code: "MEAL", Name: "Meal allowance", Category: domain.PayslipLineEarning, Active: true},
+				Amount:        domain.NewMoney(630000),
+				EffectiveFrom: time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				Component:     domain.PayComponent{Code: "COOP", Name: "Cooperative dues", Category: domain.PayslipLineDeduction, Active: true},
+				Amount:        domain.NewMoney(100000),
+				EffectiveFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				Component:     domain.PayComponent{Code: "HOUSING", Name: "Housing allowance", Category: domain.PayslipLineEarning, Taxable: true, Active: true},
+				Amount:        domain.NewMoney(1000000),
+				EffectiveFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				EffectiveTo:   func() *time.Time { d := time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC); return &d }(),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := domain.PayslipLines(payslip.Lines)
+	if got := lines.TotalOf("TRANSPORT"); got != domain.NewMoney(420000) {
+		t.Errorf("transport = %s, want the full 420000.00", got)
+	}
+	// the meal allowance starts on 16 June: 11 of 21 workdays at 30,000
+	if got := lines.TotalOf("MEAL"); got != domain.NewMoney(330000) {
+		t.Errorf("meal = %s, want 330000.00", got)
+	}
+	if got := lines.TotalOf("HOUSING"); !got.IsZero() {
+		t.Errorf("housing ended in May, got %s", got)
+	}
+	last := payslip.Lines[len(payslip.Lines)-1]
+	if last.Code != "COOP" || last.Category != domain.PayslipLineDeduction || last.Amount != domain.NewMoney(100000) {
+		t.Errorf("last line = %+v, want the COOP deduction", last)
+	}
+	// only the taxable transport allowance is added to the base pay
+	if payslip.TaxableIncome != domain.NewMoney(6720000) {
+		t.Errorf("taxable income = %s, want 6720000.00", payslip.TaxableIncome)
+	}
+	if payslip.NetSalary != domain.NewMoney(7050000).Sub(payslip.Tax.Amount).Sub(domain.NewMoney(100000)) {
+		t.Errorf("net = %s, want gross less tax and dues", payslip.NetSalary)
+	}
+}
+
+func TestCalculateLoanInstallments(t *testing.T) {
+	loans := []domain.Loan{
+		{ID: 1, Type: domain.LoanTypeLoan, InstallmentAmount: domain.NewMoney(1000000), Outstanding: domain.NewMoney(300000)},
+		{ID: 2, Type: domain.LoanTypeLoan, InstallmentAmount: domain.NewMoney(1000000), Outstanding: domain.NewMoney(5000000)},
+		{ID: 3, Type: domain.LoanTypeAdvance, InstallmentAmount: domain.NewMoney(10000000), Outstanding: domain.NewMoney(10000000)},
+		{ID: 4, Type: domain.LoanTypeLoan, InstallmentAmount: domain.NewMoney(500000), Outstanding: domain.NewMoney(500000)},
+	}
+	payslip, err := NewRuleBasedCalculator(domain.RoundingPolicies{}).Calculate(CalculationInput{
+		Employee:      domain.Employee{ID: 1, Salary: domain.NewMoney(6000000)},
+		Rules:         DefaultPayRuleSet,
+		Attendances:   20,
+		TotalWorkDays: 20,
+		Loans:         loans,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	netBeforeLoans := domain.NewMoney(6000000).Sub(payslip.Tax.Amount)
+	want := []domain.LoanInstallment{
+		{LoanID: 1, Due: domain.NewMoney(300000), Amount: domain.NewMoney(300000)}, // the rest of the balance
+		{LoanID: 2, Due: domain.NewMoney(1000000), Amount: domain.NewMoney(1000000)},
+		{LoanID: 3, Due: domain.NewMoney(10000000), Amount: netBeforeLoans.Sub(domain.NewMoney(1300000))}, // capped
+		{LoanID: 4, Due: domain.NewMoney(500000)},                                                         // nothing left
+	}
+	if len(payslip.LoanInstallments) != len(want) {
+		t.Fatalf("expected %d installments, got %+v", len(want), payslip.LoanInstallments)
+	}
+	for i, w := range want {
+		if payslip.LoanInstallments[i] != w {
+			t.Errorf("installment %d = %+v, want %+v", i, payslip.LoanInstallments[i], w)
+		}
+	}
+	lines := domain.PayslipLines(payslip.Lines)
+	if got := lines.TotalOf(domain.PayslipLineCodeLoanInstallment); got != netBeforeLoans {
+		t.Errorf("loan lines = %s, want %s", got, netBeforeLoans)
+	}
+	if !payslip.NetSalary.IsZero() {
+		t.Errorf("net = %s, want 0.00", payslip.NetSalary)
+	}
+}
+
+func TestCalculateYearToDate(t *testing.T) {
+	earlier := domain.TaxYearToDate{
+		EmployeeID:    1,
+		Months:        5,
+		Gross:         domain.NewMoney(50000000),
+		TaxableIncome: domain.NewMoney(50000000),
+		TaxWithheld:   domain.NewMoney(1000000),
+		BPJSEmployee:  domain.NewMoney(1000000),
+		BPJSEmployer:  domain.NewMoney(1850000),
+		Pension:       domain.NewMoney(1000000),
+		Net:           domain.NewMoney(48000000),
+	}
+	payslip, err := NewRuleBasedCalculator(domain.RoundingPolicies{}).Calculate(CalculationInput{
+		Employee:      domain.Employee{ID: 1, Salary: domain.NewMoney(10000000)},
+		Period:        domain.PayrollPeriod{ID: 6, EndDate: time.Date(2025, time.June, 30, 0, 0, 0, 0, time.UTC)},
+		Rules:         DefaultPayRuleSet,
+		Attendances:   20,
+		TotalWorkDays: 20,
+		TaxYearToDate: earlier,
+		BPJSRates: []domain.BPJSRate{
+			{Program: domain.BPJSProgramJHT, EmployeeRate: domain.MustRate("0.02"), EmployerRate: domain.MustRate("0.037")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ytd := payslip.YearToDate
+	if ytd == nil {
+		t.Fatal("expected the payslip to carry year-to-date totals")
+	}
+	if ytd.TaxYear != 2025 || ytd.Months != 6 {
+		t.Errorf("year to date = %d, %d months, want 2025, 6 months", ytd.TaxYear, ytd.Months)
+	}
+	if ytd.Gross != domain.NewMoney(60000000) || ytd.Net != earlier.Net.Add(payslip.NetSalary) {
+		t.Errorf("gross %s net %s, want 60000000.00 and %s", ytd.Gross, ytd.Net, earlier.Net.Add(payslip.NetSalary))
+	}
+	if ytd.TaxWithheld != earlier.TaxWithheld.Add(payslip.Tax.Amount) {
+		t.Errorf("tax withheld = %s, want %s", ytd.TaxWithheld, earlier.TaxWithheld.Add(payslip.Tax.Amount))
+	}
+	if ytd.BPJSEmployee != domain.NewMoney(1200000) || ytd.BPJSEmployer != domain.NewMoney(2220000) || ytd.Pension != domain.NewMoney(1200000) {
+		t.Errorf("BPJS = %s employee, %s employer, %s pension, want 1200000.00, 2220000.00, 1200000.00", ytd.BPJSEmployee, ytd.BPJSEmployer, ytd.Pension)
+	}
+}
@@ -0,0 +1,41 @@
+package payroll_service
+
+import (
+	"math/big"
+	"payroll-system/internal/domain"
+)
+
+// BasePayInput is what a base pay formula decides the paid days of a salary segment from.
+type BasePayInput struct {
+	SegmentWorkdays  int // workdays the salary was in effect while the employee was employed
+	EmployedWorkdays int // workdays the employee was employed in the period
+	Attendances      int
+}
+
+// BasePayFormula returns how many workdays of a salary segment are paid at its daily rate.
+type BasePayFormula func(input BasePayInput) *big.Rat
+
+// BasePayFormulas are the formulas a pay rule set can name in base_pay_formula.
+type BasePayFormulas map[string]BasePayFormula
+
+// DefaultBasePayFormulas returns the formulas every calculator starts with.
+func DefaultBasePayFormulas() BasePayFormulas {
+	return BasePayFormulas{
+		domain.BasePayFormulaAttendanceRatio: attendanceRatio,
+		domain.BasePayFormulaFullSalary:      fullSalary,
+	}
+}
+
+// attendanceRatio pays the workdays of the segment in the ratio of attendances to the
+// workdays the employee was employed.
+func attendanceRatio(input BasePayInput) *big.Rat {
+	if input.EmployedWorkdays == 0 {
+		return new(big.Rat)
+	}
+	return big.NewRat(int64(input.SegmentWorkdays)*int64(input.Attendances), int64(input.EmployedWorkdays))
+}
+
+// fullSalary pays every workday of the segment regardless of attendance.
+func fullSalary(input BasePayInput) *big.Rat {
+	return big.NewRat(int64(input.SegmentWorkdays), 1)
+}
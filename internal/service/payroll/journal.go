@@ -0,0 +1,154 @@
+package payroll_service
+
+import (
+	"fmt"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"sort"
+	"strings"
+)
+
+// JournalInput is the stored payrolls of a locked period with the cost center of each employee
+// and the configured GL accounts. Currency is used when the payslips do not record one.
+type JournalInput struct {
+	Currency    string
+	Period      domain.PayrollPeriod
+	Payrolls    []domain.Payroll
+	CostCenters map[int]string // by employee ID, empty when not assigned
+	Accounts    []domain.GLAccount
+}
+
+type journalKey struct {
+	component  string
+	costCenter string
+}
+
+// BuildJournal posts the payslip lines of a period to a double-entry journal. Earnings are
+// debited to salary, overtime or reimbursement expense and the employer's BPJS share to BPJS
+// expense. PPh 21 is credited to tax payable, both BPJS shares to BPJS payable, loan
+// installments to loan receivable, other deductions to other deductions payable and the net
+// pay to net salary payable. Amounts are summed per component and cost center, and each is
+// posted to the account of its cost center or else the component's default account.
+func BuildJournal(input JournalInput) (domain.Journal, error) {
+	journal := domain.Journal{
+		Reference: fmt.Sprintf("PAYROLL-%d-%s", input.Period.ID, input.Period.EndDate.Format("200601")),
+		PeriodID:  input.Period.ID,
+		EntryDate: input.Period.EndDate.Format("2006-01-02"),
+		Currency:  input.Currency,
+		Lines:     []domain.JournalLine{},
+	}
+	amounts := make(map[journalKey]domain.Money)
+	post := func(component, costCenter string, amount domain.Money) {
+		key := journalKey{component: component, costCenter: costCenter}
+		amounts[key] = amounts[key].Add(amount)
+	}
+	for _, payroll := range input.Payrolls {
+		if payroll.Payslip.Currency != "" {
+			journal.Currency = payroll.Payslip.Currency
+		}
+		costCenter := input.CostCenters[payroll.EmployeeID]
+		var net domain.Money
+		for _, line := range payroll.Payslip.Lines {
+			switch line.Category {
+			case domain.PayslipLineEarning:
+				post(earningJournalComponent(line.Code), costCenter, line.Amount)
+				net = net.Add(line.Amount)
+			case domain.PayslipLineDeduction:
+				post(deductionJournalComponent(line.Code), costCenter, line.Amount)
+				net = net.Sub(line.Amount)
+			case domain.PayslipLineEmployerContribution:
+				post(domain.JournalBPJSExpense, costCenter, line.Amount)
+				post(domain.JournalBPJSPayable, costCenter, line.Amount)
+			}
+		}
+		post(domain.JournalNetSalaryPayable, costCenter, net)
+	}
+
+	keys := make([]journalKey, 0, len(amounts))
+	for key, amount := range amounts {
+		if !amount.IsZero() {
+			keys = append(keys, key)
+		}
+	}
+	order := make(map[string]int, len(domain.JournalComponents))
+	for i, component := range domain.JournalComponents {
+		order[component] = i
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].component != keys[j].component {
+			return order[keys[i].component] < order[keys[j].component]
+		}
+		return keys[i].costCenter < keys[j].costCenter
+	})
+
+	accounts := make(map[journalKey]domain.GLAccount, len(input.Accounts))
+	for _, account := range input.Accounts {
+		accounts[journalKey{component: account.Component, costCenter: account.CostCenter}] = account
+	}
+	for _, key := range keys {
+		account, ok := accounts[key]
+		if !ok {
+			account, ok = accounts[journalKey{component: key.component}]
+		}
+		if !ok {
+			return domain.Journal{}, fmt.Errorf("%w: %s", error_const.ErrGLAccountNotConfigured, key.component)
+		}
+		line := domain.JournalLine{
+			Line:        len(journal.Lines) + 1,
+			AccountCode: account.AccountCode,
+			AccountName: account.AccountName,
+			CostCenter:  key.costCenter,
+			Component:   key.component,
+			Description: journalDescription(key, input.Period),
+		}
+		// a negative amount, such as a net pay below zero, is posted to the other side
+		amount := amounts[key]
+		debit := domain.IsDebitJournalComponent(key.component) != amount.IsNegative()
+		if amount.IsNegative() {
+			amount = amount.Neg()
+		}
+		if debit {
+			line.Debit = amount
+			journal.TotalDebit = journal.TotalDebit.Add(amount)
+		} else {
+			line.Credit = amount
+			journal.TotalCredit = journal.TotalCredit.Add(amount)
+		}
+		journal.Lines = append(journal.Lines, line)
+	}
+	if journal.TotalDebit != journal.TotalCredit {
+		return domain.Journal{}, error_const.ErrUnbalancedJournal
+	}
+	return journal, nil
+}
+
+func earningJournalComponent(code string) string {
+	switch code {
+	case domain.PayslipLineCodeOvertime, domain.PayslipLineCodeRestDayOvertime:
+		return domain.JournalOvertimeExpense
+	case domain.PayslipLineCodeReimbursement:
+		return domain.JournalReimbursementExpense
+	}
+	return domain.JournalSalaryExpense
+}
+
+func deductionJournalComponent(code string) string {
+	switch {
+	case code == domain.DeductionCodePPh21:
+		return domain.JournalTaxPayable
+	case strings.HasPrefix(code, "BPJS_"):
+		return domain.JournalBPJSPayable
+	case code == domain.PayslipLineCodeLoanInstallment:
+		return domain.JournalLoanReceivable
+	}
+	return domain.JournalOtherDeductionsPayable
+}
+
+func journalDescription(key journalKey, period domain.PayrollPeriod) string {
+	description := fmt.Sprintf("Payroll %s to %s %s", period.StartDate.Format("2006-01-02"), period.EndDate.Format("2006-01-02"),
+		strings.ReplaceAll(key.component, "_", " "))
+	if key.costCenter != "" {
+		description += " " + key.costCenter
+	}
+	return description
+}
@@ -0,0 +1,101 @@
+package payroll_service
+
+import (
+	"errors"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"testing"
+	"time"
+)
+
+func journalPayroll(employeeID int, lines ...domain.PayslipLine) domain.Payroll {
+	payslip := domain.Payslip{Currency: "IDR", Lines: lines}
+	payslip.SetTotalsFromLines()
+	return domain.Payroll{EmployeeID: employeeID, Payslip: payslip}
+}
+
+func TestBuildJournal(t *testing.T) {
+	amount := func(code, category string, units int64) domain.PayslipLine {
+		return domain.NewAmountLine(code, category, code, domain.NewMoney(units), false)
+	}
+	payrolls := []domain.Payroll{
+		journalPayroll(1,
+			amount(domain.PayslipLineCodeBasePay, domain.PayslipLineEarning, 10000000),
+			amount(domain.PayslipLineCodeOvertime, domain.PayslipLineEarning, 500000),
+			amount(domain.PayslipLineCodeReimbursement, domain.PayslipLineEarning, 200000),
+			amount("BPJS_JHT", domain.PayslipLineDeduction, 200000),
+			amount("BPJS_JHT", domain.PayslipLineEmployerContribution, 370000),
+			amount(domain.DeductionCodePPh21, domain.PayslipLineDeduction, 300000),
+			amount(domain.PayslipLineCodeLoanInstallment, domain.PayslipLineDeduction, 1000000),
+		),
+		journalPayroll(2,
+			amount(domain.PayslipLineCodeBasePay, domain.PayslipLineEarning, 6000000),
+			amount("MEAL", domain.PayslipLineEarning, 400000),
+			amount("COOP", domain.PayslipLineDeduction, 50000),
+		),
+	}
+	accounts := []domain.GLAccount{
+		{Component: domain.JournalSalaryExpense, AccountCode: "6100"},
+		{Component: domain.JournalSalaryExpense, CostCenter: "SALES", AccountCode: "6110"},
+		{Component: domain.JournalOvertimeExpense, AccountCode: "6120"},
+		{Component: domain.JournalReimbursementExpense, AccountCode: "6130"},
+		{Component: domain.JournalBPJSExpense, AccountCode: "6140"},
+		{Component: domain.JournalTaxPayable, AccountCode: "2110"},
+		{Component: domain.JournalBPJSPayable, AccountCode: "2120"},
+		{Component: domain.JournalLoanReceivable, AccountCode: "1310"},
+		{Component: domain.JournalOtherDeductionsPayable, AccountCode: "2130"},
+		{Component: domain.JournalNetSalaryPayable, AccountCode: "2100"},
+	}
+	input := JournalInput{
+		Period: domain.PayrollPeriod{
+			ID:        7,
+			StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		},
+		Payrolls:    payrolls,
+		CostCenters: map[int]string{1: "SALES"},
+		Accounts:    accounts,
+	}
+
+	journal, err := BuildJournal(input)
+	if err != nil {
+		t.Fatalf("BuildJournal: %v", err)
+	}
+	if journal.Reference != "PAYROLL-7-202501" || journal.EntryDate != "2025-01-31" || journal.Currency != "IDR" {
+		t.Errorf("journal = %+v, want reference PAYROLL-7-202501 dated 2025-01-31 in IDR", journal)
+	}
+	if journal.TotalDebit != journal.TotalCredit || journal.TotalDebit.String() != "17470000.00" {
+		t.Errorf("totals = %s / %s, want 17470000.00 on both sides", journal.TotalDebit, journal.TotalCredit)
+	}
+
+	want := []struct {
+		account, costCenter, debit, credit string
+	}{
+		{"6100", "", "6400000.00", "0.00"},
+		{"6110", "SALES", "10000000.00", "0.00"},
+		{"6120", "SALES", "500000.00", "0.00"},
+		{"6130", "SALES", "200000.00", "0.00"},
+		{"6140", "SALES", "370000.00", "0.00"},
+		{"2110", "SALES", "0.00", "300000.00"},
+		{"2120", "SALES", "0.00", "570000.00"},
+		{"1310", "SALES", "0.00", "1000000.00"},
+		{"2130", "", "0.00", "50000.00"},
+		{"2100", "", "0.00", "6350000.00"},
+		{"2100", "SALES", "0.00", "9200000.00"},
+	}
+	if len(journal.Lines) != len(want) {
+		t.Fatalf("got %d lines, want %d: %+v", len(journal.Lines), len(want), journal.Lines)
+	}
+	for i, w := range want {
+		line := journal.Lines[i]
+		if line.Line != i+1 || line.AccountCode != w.account || line.CostCenter != w.costCenter ||
+			line.Debit.String() != w.debit || line.Credit.String() != w.credit {
+			t.Errorf("line %d = %+v, want %+v", i+1, line, w)
+		}
+	}
+
+	input.Accounts = accounts[1:]
+	if _, err := BuildJournal(input); !errors.Is(err, error_const.ErrGLAccountNotConfigured) {
+		t.Errorf("expected ErrGLAccountNotConfigured without a default salary account, got %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+package payroll_service
+
+import (
+	"math/big"
+	"payroll-system/internal/domain"
+	"time"
+)
+
+// PPh 21 withholding following PP 58/2023 and PMK 168/2023. January to November
+// use the monthly TER (average effective rate) tables; December, or the month an
+// employee leaves, recomputes the annual tax with the Article 17 progressive rates
+// and withholds the remainder.
+
+type terBracket struct {
+	upTo int64 // inclusive upper bound of the monthly gross income, 0 for no bound
+	rate string
+}
+
+var terTables = map[string][]terBracket{
+	"A": {
+		{5400000, "0"}, {5650000, "0.0025"}, {5950000, "0.005"}, {6300000, "0.0075"},
+		{6750000, "0.01"}, {7500000, "0.0125"}, {8550000, "0.015"}, {9650000, "0.0175"},
+		{10050000, "0.02"}, {10350000, "0.0225"}, {10700000, "0.025"}, {11050000, "0.03"},
+		{11600000, "0.035"}, {12500000, "0.04"}, {13750000, "0.05"}, {15100000, "0.06"},
+		{16950000, "0.07"}, {19750000, "0.08"}, {24150000, "0.09"}, {26450000, "0.10"},
+		{28000000, "0.11"}, {30050000, "0.12"}, {32400000, "0.13"}, {35400000, "0.14"},
+		{39100000, "0.15"}, {43850000, "0.16"}, {47800000, "0.17"}, {51400000, "0.18"},
+		{56300000, "0.19"}, {62200000, "0.20"}, {68600000, "0.21"}, {77500000, "0.22"},
+		{89000000, "0.23"}, {103000000, "0.24"}, {125000000, "0.25"}, {157000000, "0.26"},
+		{206000000, "0.27"}, {337000000, "0.28"}, {454000000, "0.29"}, {550000000, "0.30"},
+		{695000000, "0.31"}, {910000000, "0.32"}, {1400000000, "0.33"}, {0, "0.34"},
+	},
+	"B": {
+		{6200000, "0"}, {6500000, "0.0025"}, {6850000, "0.005"}, {7300000, "0.0075"},
+		{9200000, "0.01"}, {10750000, "0.015"}, {11250000, "0.02"}, {11600000, "0.025"},
+		{12600000, "0.03"}, {13600000, "0.04"}, {14950000, "0.05"}, {16400000, "0.06"},
+		{18450000, "0.07"}, {21850000, "0.08"}, {26000000, "0.09"}, {27700000, "0.10"},
+		{29350000, "0.11"}, {31450000, "0.12"}, {33950000, "0.13"}, {37100000, "0.14"},
+		{41100000, "0.15"}, {45800000, "0.16"}, {49500000, "0.17"}, {53800000, "0.18"},
+		{58500000, "0.19"}, {64000000, "0.20"}, {71000000, "0.21"}, {80000000, "0.22"},
+		{93000000, "0.23"}, {109000000, "0.24"}, {129000000, "0.25"}, {163000000, "0.26"},
+		{211000000, "0.27"}, {374000000, "0.28"}, {459000000, "0.29"}, {555000000, "0.30"},
+		{704000000, "0.31"}, {957000000, "0.32"}, {1405000000, "0.33"}, {0, "0.34"},
+	},
+	"C": {
+		{6600000, "0"}, {6950000, "0.0025"}, {7350000, "0.005"}, {7800000, "0.0075"},
+		{8850000, "0.01"}, {9800000, "0.0125"}, {10950000, "0.015"}, {11200000, "0.0175"},
+		{12050000, "0.02"}, {12950000, "0.03"}, {14150000, "0.04"}, {15550000, "0.05"},
+		{17050000, "0.06"}, {19500000, "0.07"}, {22700000, "0.08"}, {26600000, "0.09"},
+		{28100000, "0.10"}, {30100000, "0.11"}, {32600000, "0.12"}, {35400000, "0.13"},
+		{38900000, "0.14"}, {43000000, "0.15"}, {47400000, "0.16"}, {51200000, "0.17"},
+		{55800000, "0.18"}, {60400000, "0.19"}, {66700000, "0.20"}, {74500000, "0.21"},
+		{83200000, "0.22"}, {95600000, "0.23"}, {110000000, "0.24"}, {134000000, "0.25"},
+		{169000000, "0.26"}, {221000000, "0.27"}, {390000000, "0.28"}, {463000000, "0.29"},
+		{561000000, "0.30"}, {709000000, "0.31"}, {965000000, "0.32"}, {1419000000, "0.33"},
+		{0, "0.34"},
+	},
+}
+
+// Article 17 progressive brackets on the annual taxable income (PKP).
+var progressiveBrackets = []terBracket{
+	{60000000, "0.05"}, {250000000, "0.15"}, {500000000, "0.25"}, {5000000000, "0.30"}, {0, "0.35"},
+}
+
+var (
+	ptkpBase             = domain.NewMoney(54000000)
+	ptkpPerFamilyMember  = domain.NewMoney(4500000)
+	occupationalRate     = domain.MustRate("0.05")
+	occupationalMonthCap = domain.NewMoney(500000) // biaya jabatan, at most 6,000,000 a year
+	noNPWPSurcharge      = domain.MustRate("1.2")  // 20% higher withholding without an NPWP
+)
+
+// TERCategory maps a PTKP status to its TER table.
+func TERCategory(profile domain.TaxProfile) string {
+	switch profile.PTKPStatus() {
+	case "TK/0", "TK/1", "K/0":
+		return "A"
+	case "TK/2", "TK/3", "K/1", "K/2":
+		return "B"
+	default:
+		return "C"
+	}
+}
+
+// TERRate returns the monthly effective rate for the gross income.
+func TERRate(category string, monthlyGross domain.Money) domain.Rate {
+	table := terTables[category]
+	for _, bracket := range table {
+		if bracket.upTo == 0 || !monthlyGross.GreaterThan(domain.NewMoney(bracket.upTo)) {
+			return domain.MustRate(bracket.rate)
+		}
+	}
+	return domain.MustRate(table[len(table)-1].rate)
+}
+
+// PTKP returns the annual non-taxable income for the profile.
+func PTKP(profile domain.TaxProfile) domain.Money {
+	ptkp := ptkpBase
+	if profile.MaritalStatus == domain.MaritalStatusMarried {
+		ptkp = ptkp.Add(ptkpPerFamilyMember)
+	}
+	dependents := profile.Dependents
+	if dependents > 3 {
+		dependents = 3
+	}
+	return ptkp.Add(ptkpPerFamilyMember.Mul(int64(dependents)))
+}
+
+// AnnualIncomeTax applies the Article 17 progressive rates to the annual taxable income.
+func AnnualIncomeTax(pkp domain.Money) *big.Rat {
+	tax := new(big.Rat)
+	lower := domain.ZeroMoney
+	for _, bracket := range progressiveBrackets {
+		if !pkp.GreaterThan(lower) {
+			break
+		}
+		upper := pkp
+		if bracket.upTo != 0 && pkp.GreaterThan(domain.NewMoney(bracket.upTo)) {
+			upper = domain.NewMoney(bracket.upTo)
+		}
+		slice := upper.Sub(lower).Rat()
+		tax.Add(tax, slice.Mul(slice, domain.MustRate(bracket.rate).Rat()))
+		lower = upper
+	}
+	return tax
+}
+
+// TaxInput is the data needed to withhold PPh 21 for one payslip.
+type TaxInput struct {
+	Profile      domain.TaxProfile
+	MonthlyGross domain.Money // taxable gross income of this payslip
+	YearToDate   domain.TaxYearToDate
+	Pension      domain.Money // employee pension contributions (JHT and JP) for the year, deductible annually
+	IsYearEnd    bool
+}
+
+// IsTaxYearEnd reports whether the period is the employee's last of the tax year: the period
+// ending in December, or the one the employee leaves in. Under PMK 168/2023 both recalculate
+// the annual tax with the progressive rates, as the 1721-A1 statement given to leavers does.
+func IsTaxYearEnd(employee domain.Employee, period domain.PayrollPeriod) bool {
+	if period.EndDate.Month() == time.December {
+		return true
+	}
+	return employee.EndDate != nil && !employee.EndDate.Before(period.StartDate) && !employee.EndDate.After(period.EndDate)
+}
+
+// CalculatePPh21 returns the amount to withhold and how it was derived. The December
+// true-up can be negative when more tax was withheld during the year than is due.
+func CalculatePPh21(input TaxInput, rounding domain.RoundingPolicy) domain.TaxDetail {
+	detail := domain.TaxDetail{
+		PTKPStatus: input.Profile.PTKPStatus(),
+		HasNPWP:    input.Profile.HasNPWP(),
+	}
+	surcharge := func(tax *big.Rat) *big.Rat {
+		if detail.HasNPWP {
+			return tax
+		}
+		return tax.Mul(tax, noNPWPSurcharge.Rat())
+	}
+
+	if !input.IsYearEnd {
+		detail.Method = domain.TaxMethodTER
+		detail.TERCategory = TERCategory(input.Profile)
+		detail.TERRate = TERRate(detail.TERCategory, input.MonthlyGross)
+		tax := new(big.Rat).Mul(input.MonthlyGross.Rat(), detail.TERRate.Rat())
+		detail.Amount = rounding.Round(surcharge(tax))
+		return detail
+	}
+
+	detail.Method = domain.TaxMethodAnnual
+	annual := CalculateAnnualPPh21(input.Profile, input.YearToDate.TaxableIncome.Add(input.MonthlyGross),
+		input.YearToDate.Months+1, input.Pension, rounding)
+	detail.AnnualTaxable = annual.TaxableIncome
+	detail.AnnualTax = annual.Tax
+	detail.WithheldYTD = input.YearToDate.TaxWithheld
+	detail.Amount = detail.AnnualTax.Sub(detail.WithheldYTD)
+	return detail
+}
+
+// AnnualPPh21 is the annual PPh 21 calculation of the December true-up and of the 1721-A1
+// statement.
+type AnnualPPh21 struct {
+	Gross         domain.Money
+	Occupational  domain.Money // biaya jabatan
+	Pension       domain.Money
+	NetIncome     domain.Money
+	PTKP          domain.Money
+	TaxableIncome domain.Money // PKP, rounded down to the thousand rupiah
+	Tax           domain.Money
+}
+
+// CalculateAnnualPPh21 applies the progressive rates to the gross income of the months worked
+// in the tax year, less biaya jabatan, pension contributions and PTKP.
+func CalculateAnnualPPh21(profile domain.TaxProfile, gross domain.Money, months int, pension domain.Money, rounding domain.RoundingPolicy) AnnualPPh21 {
+	annual := AnnualPPh21{Gross: gross, Pension: pension, PTKP: PTKP(profile)}
+	annual.Occupational = rounding.Round(new(big.Rat).Mul(gross.Rat(), occupationalRate.Rat()))
+	if maxOccupational := occupationalMonthCap.Mul(int64(months)); annual.Occupational.GreaterThan(maxOccupational) {
+		annual.Occupational = maxOccupational
+	}
+	annual.NetIncome = gross.Sub(annual.Occupational).Sub(pension)
+	pkp := annual.NetIncome.Sub(annual.PTKP)
+	if pkp.IsNegative() {
+		pkp = domain.ZeroMoney
+	}
+	// PKP is rounded down to the thousand rupiah
+	annual.TaxableIncome = domain.NewMoney(pkp.MinorUnits() / 100 / 1000 * 1000)
+	tax := AnnualIncomeTax(annual.TaxableIncome)
+	if !profile.HasNPWP() {
+		tax.Mul(tax, noNPWPSurcharge.Rat())
+	}
+	annual.Tax = rounding.Round(tax)
+	return annual
+}
@@ -0,0 +1,107 @@
+package payroll_service
+
+import (
+	"payroll-system/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestTERRate_Brackets(t *testing.T) {
+	cases := []struct {
+		category string
+		gross    int64
+		want     string
+	}{
+		{"A", 5400000, "0"},
+		{"A", 5400001, "0.0025"},
+		{"A", 10000000, "0.02"},
+		{"A", 2000000000, "0.34"},
+		{"B", 6200000, "0"},
+		{"B", 9200000, "0.01"},
+		{"C", 12950000, "0.03"},
+	}
+	for _, tc := range cases {
+		got := TERRate(tc.category, domain.NewMoney(tc.gross))
+		if got.Cmp(domain.MustRate(tc.want)) != 0 {
+			t.Errorf("%s %d: expected %s, got %s", tc.category, tc.gross, tc.want, got)
+		}
+	}
+}
+
+func TestTERCategory(t *testing.T) {
+	cases := map[string]domain.TaxProfile{
+		"A": {MaritalStatus: domain.MaritalStatusMarried, Dependents: 0},
+		"B": {MaritalStatus: domain.MaritalStatusSingle, Dependents: 2},
+		"C": {MaritalStatus: domain.MaritalStatusMarried, Dependents: 5},
+	}
+	for want, profile := range cases {
+		if got := TERCategory(profile); got != want {
+			t.Errorf("%s: expected category %s, got %s", profile.PTKPStatus(), want, got)
+		}
+	}
+}
+
+func TestCalculatePPh21_MonthlyAndDecemberTrueUp(t *testing.T) {
+	rounding := domain.DefaultRoundingPolicy
+	profile := domain.TaxProfile{NPWP: "0123456789012345", MaritalStatus: domain.MaritalStatusSingle}
+	monthly := domain.NewMoney(10000000)
+
+	ter := CalculatePPh21(TaxInput{Profile: profile, MonthlyGross: monthly}, rounding)
+	if ter.Method != domain.TaxMethodTER || ter.Amount != domain.NewMoney(200000) {
+		t.Fatalf("expected TER withholding of 200000, got %s %s", ter.Method, ter.Amount)
+	}
+
+	// 12 x 10,000,000 - 6,000,000 biaya jabatan - 54,000,000 PTKP = 60,000,000 PKP, taxed at 5%
+	december := CalculatePPh21(TaxInput{
+		Profile:      profile,
+		MonthlyGross: monthly,
+		YearToDate: domain.TaxYearToDate{
+			Months:        11,
+			TaxableIncome: monthly.Mul(11),
+			TaxWithheld:   ter.Amount.Mul(11),
+		},
+		IsYearEnd: true,
+	}, rounding)
+	if december.AnnualTaxable != domain.NewMoney(60000000) {
+		t.Errorf("expected PKP 60000000, got %s", december.AnnualTaxable)
+	}
+	if december.AnnualTax != domain.NewMoney(3000000) {
+		t.Errorf("expected annual tax 3000000, got %s", december.AnnualTax)
+	}
+	if december.Amount != domain.NewMoney(800000) {
+		t.Errorf("expected December withholding 800000, got %s", december.Amount)
+	}
+}
+
+func TestCalculatePPh21_NoNPWPSurcharge(t *testing.T) {
+	detail := CalculatePPh21(TaxInput{
+		Profile:      domain.TaxProfile{MaritalStatus: domain.MaritalStatusSingle},
+		MonthlyGross: domain.NewMoney(10000000),
+	}, domain.DefaultRoundingPolicy)
+	if detail.Amount != domain.NewMoney(240000) {
+		t.Errorf("expected 120%% of 200000, got %s", detail.Amount)
+	}
+}
+
+func TestIsTaxYearEnd(t *testing.T) {
+	june := domain.PayrollPeriod{StartDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)}
+	december := domain.PayrollPeriod{StartDate: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)}
+	leaves := func(date time.Time) domain.Employee { return domain.Employee{EndDate: &date} }
+	cases := []struct {
+		name     string
+		employee domain.Employee
+		period   domain.PayrollPeriod
+		want     bool
+	}{
+		{"employed in June", domain.Employee{}, june, false},
+		{"employed in December", domain.Employee{}, december, true},
+		{"leaves on the last day of June", leaves(june.EndDate), june, true},
+		{"leaves mid June", leaves(time.Date(2025, 6, 13, 0, 0, 0, 0, time.UTC)), june, true},
+		{"leaves in July", leaves(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)), june, false},
+	}
+	for _, tc := range cases {
+		if got := IsTaxYearEnd(tc.employee, tc.period); got != tc.want {
+			t.Errorf("%s: IsTaxYearEnd = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package payroll_service
+
+import (
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+)
+
+// TaxStatementInput is an employee's payslips of the locked periods of a tax year.
+type TaxStatementInput struct {
+	Rounding   domain.RoundingPolicies
+	Currency   string
+	TaxYear    int
+	Withholder domain.TaxWithholder
+	Employee   domain.Employee
+	TaxProfile domain.TaxProfile
+	Payslips   []domain.TaxYearPayslip
+}
+
+// CalculateTaxStatement sums the taxable income, pension contributions and PPh 21 of the
+// payslips into a 1721-A1 statement and recalculates the annual tax over the months worked.
+// Base pay is reported as salary, THR and bonuses as bonus, taxable BPJS premiums paid by the
+// employer as insurance premiums and every other taxable earning as other allowances.
+func CalculateTaxStatement(input TaxStatementInput) (statement domain.TaxStatement, err error) {
+	defer domain.RecoverMoneyOverflow(&err)
+	if len(input.Payslips) == 0 {
+		return domain.TaxStatement{}, error_const.ErrNoPayslipsInTaxYear
+	}
+	rounding := input.Rounding.For(input.Currency)
+	statement = domain.TaxStatement{
+		TaxYear:      input.TaxYear,
+		Withholder:   input.Withholder,
+		EmployeeID:   input.Employee.ID,
+		EmployeeName: input.Employee.Name,
+		NPWP:         input.TaxProfile.NPWP,
+		PTKPStatus:   input.TaxProfile.PTKPStatus(),
+		Currency:     rounding.Currency,
+	}
+
+	months := make(map[int]bool)
+	for _, p := range input.Payslips {
+		month := int(p.PeriodEnd.Month())
+		if p.Regular {
+			months[p.PeriodID] = true
+		}
+		if statement.FirstMonth == 0 || month < statement.FirstMonth {
+			statement.FirstMonth = month
+		}
+		if month > statement.LastMonth {
+			statement.LastMonth = month
+		}
+		for _, line := range p.Payslip.Lines {
+			if !line.Taxable {
+				continue
+			}
+			switch {
+			case line.Category == domain.PayslipLineEmployerContribution:
+				statement.InsurancePremiums = statement.InsurancePremiums.Add(line.Amount)
+			case line.Category != domain.PayslipLineEarning:
+				// deductions are not income
+			case line.Code == domain.PayslipLineCodeBasePay:
+				statement.Salary = statement.Salary.Add(line.Amount)
+			case line.Code == domain.PayslipLineCodeTHR || line.Code == domain.PayslipLineCodeBonus:
+				statement.BonusAndTHR = statement.BonusAndTHR.Add(line.Amount)
+			default:
+				statement.OtherAllowances = statement.OtherAllowances.Add(line.Amount)
+			}
+		}
+		for _, contribution := range p.Payslip.BPJS {
+			if contribution.IsPension() {
+				statement.PensionContributions = statement.PensionContributions.Add(contribution.EmployeeAmount)
+			}
+		}
+		if p.Payslip.Tax != nil {
+			statement.TaxWithheld = statement.TaxWithheld.Add(p.Payslip.Tax.Amount)
+		}
+	}
+	statement.GrossIncome = statement.Salary.Add(statement.TaxAllowance).Add(statement.OtherAllowances).
+		Add(statement.Honorarium).Add(statement.InsurancePremiums).Add(statement.BenefitsInKind).Add(statement.BonusAndTHR)
+
+	// a year of only THR or bonus payslips still counts as a month for biaya jabatan
+	workedMonths := len(months)
+	if workedMonths == 0 {
+		workedMonths = 1
+	}
+	annual := CalculateAnnualPPh21(input.TaxProfile, statement.GrossIncome, workedMonths, statement.PensionContributions, rounding)
+	statement.OccupationalCost = annual.Occupational
+	statement.TotalDeductions = statement.OccupationalCost.Add(statement.PensionContributions).Add(statement.Zakat)
+	statement.NetIncome = statement.GrossIncome.Sub(statement.TotalDeductions)
+	statement.AnnualNetIncome = statement.NetIncome.Add(statement.PriorNetIncome)
+	statement.PTKP = annual.PTKP
+	statement.TaxableIncome = annual.TaxableIncome
+	statement.AnnualTax = annual.Tax
+	statement.TaxDue = statement.AnnualTax.Sub(statement.PriorTaxWithheld)
+	return statement, nil
+}
@@ -0,0 +1,81 @@
+package payroll_service
+
+import (
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"testing"
+	"time"
+)
+
+func TestCalculateTaxStatement(t *testing.T) {
+	profile := domain.TaxProfile{EmployeeID: 1, NPWP: "0123456789012345", MaritalStatus: domain.MaritalStatusSingle}
+	var payslips []domain.TaxYearPayslip
+	for month := time.January; month <= time.December; month++ {
+		payslip := domain.Payslip{
+			Lines: []domain.PayslipLine{
+				domain.NewAmountLine(domain.PayslipLineCodeBasePay, domain.PayslipLineEarning, "Base salary", domain.NewMoney(10000000), true),
+				domain.NewAmountLine(domain.PayslipLineCodeReimbursement, domain.PayslipLineEarning, "Taxi", domain.NewMoney(250000), false),
+				domain.NewAmountLine("BPJS_"+domain.BPJSProgramJKK, domain.PayslipLineEmployerContribution, "BPJS JKK", domain.NewMoney(50000), true),
+				domain.NewAmountLine("BPJS_"+domain.BPJSProgramJHT, domain.PayslipLineEmployerContribution, "BPJS JHT", domain.NewMoney(370000), false),
+			},
+			BPJS: []domain.BPJSContribution{
+				{Program: domain.BPJSProgramJHT, EmployeeAmount: domain.NewMoney(200000)},
+				{Program: domain.BPJSProgramJP, EmployeeAmount: domain.NewMoney(100000)},
+			},
+			Tax: &domain.TaxDetail{Amount: domain.NewMoney(200000)},
+		}
+		payslips = append(payslips, domain.TaxYearPayslip{
+			PeriodID:  int(month),
+			PeriodEnd: time.Date(2025, month, 28, 0, 0, 0, 0, time.UTC),
+			Regular:   true,
+			Payslip:   payslip,
+		})
+	}
+	payslips = append(payslips, domain.TaxYearPayslip{
+		PeriodID:  3,
+		PeriodEnd: time.Date(2025, time.March, 28, 0, 0, 0, 0, time.UTC),
+		Payslip: domain.Payslip{
+			Lines: []domain.PayslipLine{domain.NewAmountLine(domain.PayslipLineCodeTHR, domain.PayslipLineEarning, "THR", domain.NewMoney(10000000), true)},
+			Tax:   &domain.TaxDetail{Amount: domain.NewMoney(700000)},
+		},
+	})
+
+	statement, err := CalculateTaxStatement(TaxStatementInput{
+		TaxYear:    2025,
+		Employee:   domain.Employee{ID: 1, Name: "Budi"},
+		TaxProfile: profile,
+		Payslips:   payslips,
+	})
+	if err != nil {
+		t.Fatalf("CalculateTaxStatement: %v", err)
+	}
+	if statement.FirstMonth != 1 || statement.LastMonth != 12 || statement.PTKPStatus != "TK/0" {
+		t.Errorf("months %d-%d, PTKP %s, want 1-12 and TK/0", statement.FirstMonth, statement.LastMonth, statement.PTKPStatus)
+	}
+	expected := map[string][2]domain.Money{
+		"salary":             {statement.Salary, domain.NewMoney(120000000)},
+		"insurance premiums": {statement.InsurancePremiums, domain.NewMoney(600000)},
+		"bonus and THR":      {statement.BonusAndTHR, domain.NewMoney(10000000)},
+		"other allowances":   {statement.OtherAllowances, domain.Money{}},
+		"gross income":       {statement.GrossIncome, domain.NewMoney(130600000)},
+		// 5% of the gross is capped at 500.000 a month
+		"occupational cost": {statement.OccupationalCost, domain.NewMoney(6000000)},
+		"pension":           {statement.PensionContributions, domain.NewMoney(3600000)},
+		"net income":        {statement.NetIncome, domain.NewMoney(121000000)},
+		"PTKP":              {statement.PTKP, domain.NewMoney(54000000)},
+		"taxable income":    {statement.TaxableIncome, domain.NewMoney(67000000)},
+		// 5% of 60.000.000 and 15% of 7.000.000
+		"annual tax":   {statement.AnnualTax, domain.NewMoney(4050000)},
+		"tax due":      {statement.TaxDue, domain.NewMoney(4050000)},
+		"tax withheld": {statement.TaxWithheld, domain.NewMoney(3100000)},
+	}
+	for name, amounts := range expected {
+		if amounts[0] != amounts[1] {
+			t.Errorf("%s = %s, want %s", name, amounts[0], amounts[1])
+		}
+	}
+
+	if _, err := CalculateTaxStatement(TaxStatementInput{TaxYear: 2025, TaxProfile: profile}); err != error_const.ErrNoPayslipsInTaxYear {
+		t.Errorf("expected ErrNoPayslipsInTaxYear, got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package payroll_service
+
+import (
+	"math/big"
+	"payroll-system/internal/domain"
+	"sort"
+)
+
+// varianceComponentNames are the compared components in the order of varianceComponents.
+var varianceComponentNames = []string{
+	domain.VarianceComponentBase,
+	domain.VarianceComponentOvertime,
+	domain.VarianceComponentReimbursement,
+	domain.VarianceComponentTotal,
+}
+
+const reimbursementComponent = 2 // index in varianceComponentNames
+
+// ComparePayrolls compares the payslips of a period with those of a previous period per
+// employee, ordered by employee ID, and in total. A component of an employee paid in both
+// periods is flagged when it changed by more than the change threshold, or appeared where it
+// was zero before. Employees paid in only one of the periods are flagged as new or missing,
+// and reimbursements that grew by more than the spike threshold are flagged as spikes.
+func ComparePayrolls(previous, current []domain.Payroll, thresholds domain.VarianceThresholds) domain.PayrollVarianceReport {
+	previousByEmployee := varianceComponentsByEmployee(previous)
+	currentByEmployee := varianceComponentsByEmployee(current)
+	employeeIDs := make([]int, 0, len(currentByEmployee))
+	for id := range currentByEmployee {
+		employeeIDs = append(employeeIDs, id)
+	}
+	for id := range previousByEmployee {
+		if _, ok := currentByEmployee[id]; !ok {
+			employeeIDs = append(employeeIDs, id)
+		}
+	}
+	sort.Ints(employeeIDs)
+
+	report := domain.PayrollVarianceReport{
+		Thresholds: thresholds,
+		Employees:  make([]domain.EmployeeVariance, 0, len(employeeIDs)),
+	}
+	previousTotals := make([]domain.Money, len(varianceComponentNames))
+	currentTotals := make([]domain.Money, len(varianceComponentNames))
+	for _, id := range employeeIDs {
+		before, paidBefore := previousByEmployee[id]
+		after, paidNow := currentByEmployee[id]
+		if !paidBefore {
+			before = make([]domain.Money, len(varianceComponentNames))
+		}
+		if !paidNow {
+			after = make([]domain.Money, len(varianceComponentNames))
+		}
+		employee := domain.EmployeeVariance{EmployeeID: id, Status: domain.VarianceStatusExisting, Flags: []string{}}
+		switch {
+		case !paidBefore:
+			employee.Status = domain.VarianceStatusNew
+			employee.Flags = append(employee.Flags, domain.VarianceFlagNewEmployee)
+		case !paidNow:
+			employee.Status = domain.VarianceStatusMissing
+			employee.Flags = append(employee.Flags, domain.VarianceFlagMissingEmployee)
+		}
+		flagged := false
+		for i, name := range varianceComponentNames {
+			component := compareComponent(name, before[i], after[i], thresholds.ChangePercent)
+			if employee.Status != domain.VarianceStatusExisting {
+				component.Flagged = false
+			}
+			flagged = flagged || component.Flagged
+			employee.Components = append(employee.Components, component)
+			previousTotals[i] = previousTotals[i].Add(before[i])
+			currentTotals[i] = currentTotals[i].Add(after[i])
+		}
+		if flagged {
+			employee.Flags = append(employee.Flags, domain.VarianceFlagAboveThreshold)
+		}
+		if paidNow && isReimbursementSpike(before[reimbursementComponent], after[reimbursementComponent], thresholds) {
+			employee.Flags = append(employee.Flags, domain.VarianceFlagReimbursementSpike)
+		}
+		if len(employee.Flags) > 0 {
+			report.FlaggedEmployees++
+		}
+		report.Employees = append(report.Employees, employee)
+	}
+	for i, name := range varianceComponentNames {
+		report.Totals = append(report.Totals, compareComponent(name, previousTotals[i], currentTotals[i], thresholds.ChangePercent))
+	}
+	return report
+}
+
+// varianceComponents returns the compared amounts of a payslip in the order of varianceComponentNames.
+// varianceComponentsByEmployee adds up the components of each employee's payslips, so
+// off-cycle payslips count with the regular one.
+func varianceComponentsByEmployee(payrolls []domain.Payroll) map[int][]domain.Money {
+	result := make(map[int][]domain.Money, len(payrolls))
+	for _, payroll := range payrolls {
+		components := varianceComponents(payroll.Payslip)
+		if sums, ok := result[payroll.EmployeeID]; ok {
+			for i := range sums {
+				components[i] = components[i].Add(sums[i])
+			}
+		}
+		result[payroll.EmployeeID] = components
+	}
+	return result
+}
+
+func varianceComponents(p domain.Payslip) []domain.Money {
+	lines := domain.PayslipLines(p.Lines)
+	return []domain.Money{
+		lines.TotalOf(domain.PayslipLineCodeBasePay),
+		lines.TotalOf(domain.PayslipLineCodeOvertime, domain.PayslipLineCodeRestDayOvertime),
+		lines.TotalOf(domain.PayslipLineCodeReimbursement),
+		p.TotalSalary,
+	}
+}
+
+func compareComponent(name string, previous, current domain.Money, threshold domain.Rate) domain.ComponentVariance {
+	component := domain.ComponentVariance{
+		Component: name,
+		Previous:  previous,
+		Current:   current,
+		Change:    current.Sub(previous),
+	}
+	if previous.IsZero() {
+		component.Flagged = !current.IsZero()
+		return component
+	}
+	percent := changePercent(previous, current)
+	component.ChangePercent = &percent
+	component.Flagged = new(big.Rat).Abs(percent.Rat()).Cmp(threshold.Rat()) > 0
+	return component
+}
+
+// changePercent is the change from previous to current in percent of previous, to two decimals.
+func changePercent(previous, current domain.Money) domain.Rate {
+	percent := new(big.Rat).Quo(current.Sub(previous).Rat(), previous.Rat())
+	percent.Mul(percent, big.NewRat(100, 1))
+	return domain.MustRate(percent.FloatString(2))
+}
+
+func isReimbursementSpike(previous, current domain.Money, thresholds domain.VarianceThresholds) bool {
+	if !current.IsPositive() || current.LessThan(thresholds.ReimbursementSpikeMinimum) {
+		return false
+	}
+	if previous.IsZero() {
+		return true
+	}
+	return changePercent(previous, current).Cmp(thresholds.ReimbursementSpikePercent) > 0
+}
@@ -0,0 +1,66 @@
+package payroll_service
+
+import (
+	"payroll-system/internal/domain"
+	"testing"
+)
+
+func variancePayroll(employeeID int, base, overtime, reimbursement int64) domain.Payroll {
+	payslip := domain.Payslip{Lines: []domain.PayslipLine{
+		domain.NewAmountLine(domain.PayslipLineCodeBasePay, domain.PayslipLineEarning, "Base salary", domain.NewMoney(base), true),
+	}}
+	if overtime > 0 {
+		payslip.Lines = append(payslip.Lines, domain.NewAmountLine(domain.PayslipLineCodeOvertime, domain.PayslipLineEarning, "Overtime", domain.NewMoney(overtime), true))
+	}
+	if reimbursement > 0 {
+		payslip.Lines = append(payslip.Lines, domain.NewAmountLine(domain.PayslipLineCodeReimbursement, domain.PayslipLineEarning, "Taxi", domain.NewMoney(reimbursement), false))
+	}
+	payslip.SetTotalsFromLines()
+	return domain.Payroll{EmployeeID: employeeID, Payslip: payslip}
+}
+
+func TestComparePayrolls(t *testing.T) {
+	previous := []domain.Payroll{
+		variancePayroll(1, 10000000, 0, 200000),
+		variancePayroll(2, 8000000, 400000, 0),
+		variancePayroll(3, 6000000, 0, 0),
+	}
+	current := []domain.Payroll{
+		variancePayroll(1, 10500000, 0, 900000), // base +5%, reimbursement +350%
+		variancePayroll(2, 8000000, 200000, 0),  // overtime -50%
+		variancePayroll(4, 5000000, 0, 0),
+	}
+	report := ComparePayrolls(previous, current, domain.VarianceThresholds{
+		ChangePercent:             domain.MustRate("10"),
+		ReimbursementSpikePercent: domain.MustRate("100"),
+	})
+
+	if len(report.Employees) != 4 || report.FlaggedEmployees != 4 {
+		t.Fatalf("report = %+v, want 4 employees, all flagged", report)
+	}
+	flags := func(i int) []string { return report.Employees[i].Flags }
+	first := report.Employees[0]
+	if first.Components[0].ChangePercent == nil || first.Components[0].ChangePercent.String() != "5" || first.Components[0].Flagged {
+		t.Errorf("base = %+v, want an unflagged 5%% change", first.Components[0])
+	}
+	if len(flags(0)) != 2 || flags(0)[0] != domain.VarianceFlagAboveThreshold || flags(0)[1] != domain.VarianceFlagReimbursementSpike {
+		t.Errorf("employee 1 flags = %v, want above_threshold and reimbursement_spike", flags(0))
+	}
+	if overtime := report.Employees[1].Components[1]; overtime.ChangePercent.String() != "-50" || !overtime.Flagged || overtime.Change != domain.NewMoney(-200000) {
+		t.Errorf("overtime = %+v, want a flagged -50%% change", overtime)
+	}
+	if report.Employees[2].Status != domain.VarianceStatusMissing || flags(2)[0] != domain.VarianceFlagMissingEmployee {
+		t.Errorf("employee 3 = %+v, want missing", report.Employees[2])
+	}
+	if report.Employees[3].Status != domain.VarianceStatusNew || len(flags(3)) != 1 || flags(3)[0] != domain.VarianceFlagNewEmployee {
+		t.Errorf("employee 4 = %+v, want only flagged as new", report.Employees[3])
+	}
+
+	total := report.Totals[3]
+	if total.Component != domain.VarianceComponentTotal || total.Previous != domain.NewMoney(24600000) || total.Current != domain.NewMoney(24600000) {
+		t.Errorf("total = %+v, want 24600000.00 in both periods", total)
+	}
+	if total.ChangePercent.String() != "0" || total.Flagged {
+		t.Errorf("total = %+v, want an unflagged 0%% change", total)
+	}
+}
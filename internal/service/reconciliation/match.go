@@ -0,0 +1,79 @@
+package reconciliation_service
+
+import (
+	"payroll-system/internal/domain"
+	"regexp"
+	"strings"
+)
+
+var accountNumberPattern = regexp.MustCompile(`\d{5,20}`)
+
+// MatchStatement matches the entries to the payments in order and sets each matched entry's
+// item and new status. A debit pays a pending or sent payment and a credit returns a sent or
+// paid one; a payment is matched once per status, so a debit and a later credit of the same
+// transfer mark it paid and then returned. The amount must be equal and the entry must name
+// exactly one candidate: by the account number, or by the batch reference among the candidates
+// with that account number, or among all of them when the account number is not on the entry.
+// Other entries are not matched. The items' statuses are updated in place.
+func MatchStatement(entries []domain.BankStatementEntry, items []domain.ReconciliationItem) {
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Duplicate {
+			continue
+		}
+		status := domain.PaymentPaid
+		if entry.Direction == domain.StatementCredit {
+			status = domain.PaymentReturned
+		}
+		var candidates []int
+		for j, item := range items {
+			if item.Amount == entry.Amount && domain.CanChangePaymentStatus(item.Status, status) {
+				candidates = append(candidates, j)
+			}
+		}
+		byAccount := filter(candidates, items, func(item domain.ReconciliationItem) bool {
+			return mentionsAccount(*entry, item.AccountNumber)
+		})
+		if len(byAccount) != 1 {
+			if len(byAccount) > 0 {
+				candidates = byAccount
+			}
+			text := strings.ToUpper(entry.Reference + " " + entry.Description)
+			candidates = filter(candidates, items, func(item domain.ReconciliationItem) bool {
+				return strings.Contains(text, item.BatchReference)
+			})
+			if len(candidates) != 1 {
+				continue
+			}
+		} else {
+			candidates = byAccount
+		}
+		item := &items[candidates[0]]
+		item.Status = status
+		item.BankReference = entry.Reference
+		entry.MatchedItemID = item.ID
+		entry.MatchedStatus = status
+	}
+}
+
+func filter(candidates []int, items []domain.ReconciliationItem, test func(domain.ReconciliationItem) bool) []int {
+	var kept []int
+	for _, j := range candidates {
+		if test(items[j]) {
+			kept = append(kept, j)
+		}
+	}
+	return kept
+}
+
+func mentionsAccount(entry domain.BankStatementEntry, accountNumber string) bool {
+	if entry.AccountNumber == accountNumber {
+		return true
+	}
+	for _, number := range accountNumberPattern.FindAllString(entry.Reference+" "+entry.Description, -1) {
+		if number == accountNumber {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package reconciliation_service
+
+import (
+	"payroll-system/internal/domain"
+	"testing"
+)
+
+func reconciliationItem(id int, accountNumber string, amount int64, status string) domain.ReconciliationItem {
+	return domain.ReconciliationItem{
+		DisbursementItem: domain.DisbursementItem{ID: id, AccountNumber: accountNumber, Amount: domain.NewMoney(amount), Status: status},
+		BatchReference:   "DISB00000001",
+		PeriodID:         4,
+	}
+}
+
+func TestMatchStatement(t *testing.T) {
+	items := []domain.ReconciliationItem{
+		reconciliationItem(1, "1234567890", 9450000, domain.PaymentSent),
+		reconciliationItem(2, "9876543210", 6350000, domain.PaymentSent),
+		reconciliationItem(3, "1111122222", 5000000, domain.PaymentSent),
+		reconciliationItem(4, "3333344444", 5000000, domain.PaymentSent),
+	}
+	entries := []domain.BankStatementEntry{
+		// matched by the account number on the entry
+		{Direction: domain.StatementDebit, Amount: domain.NewMoney(9450000), AccountNumber: "1234567890", Reference: "TRF001"},
+		// matched by the batch reference, the only sent payment of that amount
+		{Direction: domain.StatementDebit, Amount: domain.NewMoney(6350000), Description: "salary disb00000001"},
+		// two payments of the amount in the batch and no account number: ambiguous
+		{Direction: domain.StatementDebit, Amount: domain.NewMoney(5000000), Description: "SALARY DISB00000001"},
+		// the account number in the description tells them apart
+		{Direction: domain.StatementDebit, Amount: domain.NewMoney(5000000), Description: "SALARY 3333344444"},
+		// the paid payment comes back
+		{Direction: domain.StatementCredit, Amount: domain.NewMoney(9450000), Reference: "RTN77", Description: "RETURN 1234567890"},
+		// already imported
+		{Direction: domain.StatementDebit, Amount: domain.NewMoney(5000000), AccountNumber: "1111122222", Duplicate: true},
+		// no payment of the amount
+		{Direction: domain.StatementDebit, Amount: domain.NewMoney(1000), AccountNumber: "1234567890"},
+	}
+	MatchStatement(entries, items)
+
+	want := []struct {
+		itemID int
+		status string
+	}{
+		{1, domain.PaymentPaid},
+		{2, domain.PaymentPaid},
+		{0, ""},
+		{4, domain.PaymentPaid},
+		{1, domain.PaymentReturned},
+		{0, ""},
+		{0, ""},
+	}
+	for i, w := range want {
+		if entries[i].MatchedItemID != w.itemID || entries[i].MatchedStatus != w.status {
+			t.Errorf("entry %d matched item %d as %q, want item %d as %q", i+1, entries[i].MatchedItemID, entries[i].MatchedStatus, w.itemID, w.status)
+		}
+	}
+	if items[0].Status != domain.PaymentReturned || items[0].BankReference != "RTN77" {
+		t.Errorf("item 1 = %+v, want returned with the return's reference", items[0])
+	}
+	if items[2].Status != domain.PaymentSent {
+		t.Errorf("item 3 = %+v, want it still sent", items[2])
+	}
+}
@@ -0,0 +1,243 @@
+package reconciliation_service
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Statement is a parsed bank statement of the company account.
+type Statement struct {
+	AccountNumber string
+	Entries       []domain.BankStatementEntry
+}
+
+// ParseStatement reads a statement in one of the domain.BankStatementFormat formats and sets
+// each entry's fingerprint, so an entry imported twice is recognised.
+func ParseStatement(format string, r io.Reader) (Statement, error) {
+	var statement Statement
+	var err error
+	switch format {
+	case domain.BankStatementFormatCSV:
+		statement, err = ParseCSVStatement(r)
+	case domain.BankStatementFormatMT940:
+		statement, err = ParseMT940Statement(r)
+	default:
+		return Statement{}, error_const.ErrUnknownStatementFormat
+	}
+	if err != nil {
+		return Statement{}, err
+	}
+	if len(statement.Entries) == 0 {
+		return Statement{}, error_const.ErrEmptyStatement
+	}
+	setFingerprints(statement)
+	return statement, nil
+}
+
+// setFingerprints hashes the account and the booking of each entry. Identical bookings on
+// the same day, such as two equal transfers, are told apart by their occurrence.
+func setFingerprints(statement Statement) {
+	seen := make(map[string]int)
+	for i := range statement.Entries {
+		e := &statement.Entries[i]
+		key := fmt.Sprintf("%s|%s|%s|%d|%s|%s|%s", statement.AccountNumber, e.BookingDate.Format("2006-01-02"), e.Direction,
+			e.Amount.MinorUnits(), e.Reference, e.Description, e.AccountNumber)
+		seen[key]++
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d", key, seen[key])))
+		e.Fingerprint = hex.EncodeToString(sum[:])
+	}
+}
+
+// ParseCSVStatement reads a CSV statement with a header row. The columns are matched by name,
+// in any order: date (YYYY-MM-DD or DD/MM/YYYY), amount, and optionally direction (D or C,
+// debit or credit), reference, description and account_number. Without a direction column a
+// negative amount is a debit. Amounts use a decimal point; commas are read as thousands
+// separators.
+func ParseCSVStatement(r io.Reader) (Statement, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return Statement{}, fmt.Errorf("%w: %v", error_const.ErrInvalidStatement, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	dateColumn, hasDate := columns["date"]
+	amountColumn, hasAmount := columns["amount"]
+	if !hasDate || !hasAmount {
+		return Statement{}, fmt.Errorf("%w: the header needs date and amount columns", error_const.ErrInvalidStatement)
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var statement Statement
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Statement{}, fmt.Errorf("%w: line %d: %v", error_const.ErrInvalidStatement, line, err)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if dateColumn >= len(record) || amountColumn >= len(record) {
+			return Statement{}, fmt.Errorf("%w: line %d has too few columns", error_const.ErrInvalidStatement, line)
+		}
+		date, err := parseStatementDate(strings.TrimSpace(record[dateColumn]))
+		if err != nil {
+			return Statement{}, fmt.Errorf("%w: line %d: %v", error_const.ErrInvalidStatement, line, err)
+		}
+		amount, err := domain.ParseMoney(strings.ReplaceAll(strings.TrimSpace(record[amountColumn]), ",", ""))
+		if err != nil {
+			return Statement{}, fmt.Errorf("%w: line %d: invalid amount", error_const.ErrInvalidStatement, line)
+		}
+		direction := signedDirection(amount)
+		if amount.IsNegative() {
+			amount = amount.Neg()
+		}
+		switch strings.ToUpper(field(record, "direction")) {
+		case "":
+		case "D", "DR", "DB", "DEBIT":
+			direction = domain.StatementDebit
+		case "C", "CR", "CREDIT":
+			direction = domain.StatementCredit
+		default:
+			return Statement{}, fmt.Errorf("%w: line %d: direction must be D or C", error_const.ErrInvalidStatement, line)
+		}
+		statement.Entries = append(statement.Entries, domain.BankStatementEntry{
+			Line:          len(statement.Entries) + 1,
+			BookingDate:   date,
+			Direction:     direction,
+			Amount:        amount,
+			Reference:     field(record, "reference"),
+			Description:   field(record, "description"),
+			AccountNumber: field(record, "account_number"),
+		})
+	}
+	return statement, nil
+}
+
+// signedDirection returns the direction of a signed amount: negative amounts are debits.
+func signedDirection(amount domain.Money) string {
+	if amount.IsNegative() {
+		return domain.StatementDebit
+	}
+	return domain.StatementCredit
+}
+
+func parseStatementDate(value string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "02/01/2006"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// statementLinePattern reads the :61: field: value date YYMMDD, optional entry date MMDD,
+// debit/credit mark, optional funds code, amount with a decimal comma, transaction type,
+// the account owner's reference and an optional bank reference after //.
+var statementLinePattern = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)([A-Z])?(\d+,\d{0,2})([NF][A-Z0-9]{3})([^/]*)(?://(.*))?$`)
+
+// ParseMT940Statement reads a SWIFT MT940 statement. Each :61: statement line becomes an
+// entry, described by the :86: field that follows it. RD (reversal of a debit) is read as a
+// credit and RC as a debit. Several statements in one file are read in order.
+func ParseMT940Statement(r io.Reader) (Statement, error) {
+	type field struct {
+		tag   string
+		value string
+	}
+	var fields []field
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		line = strings.TrimPrefix(line, "{4:")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "-") || strings.HasPrefix(line, "{") {
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			if end := strings.Index(line[1:], ":"); end > 0 {
+				fields = append(fields, field{tag: line[1 : end+1], value: line[end+2:]})
+				continue
+			}
+		}
+		if len(fields) == 0 {
+			return Statement{}, fmt.Errorf("%w: text before the first field", error_const.ErrInvalidStatement)
+		}
+		fields[len(fields)-1].value += "\n" + line
+	}
+	if err := scanner.Err(); err != nil {
+		return Statement{}, fmt.Errorf("%w: %v", error_const.ErrInvalidStatement, err)
+	}
+
+	var statement Statement
+	var entry *domain.BankStatementEntry
+	for _, f := range fields {
+		switch f.tag {
+		case "25":
+			statement.AccountNumber = strings.TrimSpace(f.value)
+		case "61":
+			firstLine, supplementary, _ := strings.Cut(f.value, "\n")
+			m := statementLinePattern.FindStringSubmatch(strings.TrimSpace(firstLine))
+			if m == nil {
+				return Statement{}, fmt.Errorf("%w: invalid :61: line %q", error_const.ErrInvalidStatement, firstLine)
+			}
+			date, err := time.Parse("060102", m[1])
+			if err != nil {
+				return Statement{}, fmt.Errorf("%w: invalid :61: date %q", error_const.ErrInvalidStatement, m[1])
+			}
+			amount, err := domain.ParseMoney(strings.Replace(m[5], ",", ".", 1))
+			if err != nil {
+				return Statement{}, fmt.Errorf("%w: invalid :61: amount %q", error_const.ErrInvalidStatement, m[5])
+			}
+			direction := domain.StatementCredit
+			if m[3] == "D" || m[3] == "RC" {
+				direction = domain.StatementDebit
+			}
+			reference := strings.TrimSpace(m[7])
+			if reference == "NONREF" {
+				reference = ""
+			}
+			if reference == "" {
+				reference = strings.TrimSpace(m[8])
+			}
+			statement.Entries = append(statement.Entries, domain.BankStatementEntry{
+				Line:        len(statement.Entries) + 1,
+				BookingDate: date,
+				Direction:   direction,
+				Amount:      amount,
+				Reference:   reference,
+				Description: strings.TrimSpace(supplementary),
+			})
+			entry = &statement.Entries[len(statement.Entries)-1]
+		case "86":
+			if entry != nil {
+				description := strings.Join(strings.Fields(strings.ReplaceAll(f.value, "\n", " ")), " ")
+				entry.Description = strings.TrimSpace(entry.Description + " " + description)
+				entry = nil
+			}
+		default:
+			// balances and statement numbers are not needed to match payments
+			entry = nil
+		}
+	}
+	return statement, nil
+}
@@ -0,0 +1,98 @@
+package reconciliation_service
+
+import (
+	"errors"
+	"payroll-system/internal/domain"
+	"payroll-system/internal/error_const"
+	"strings"
+	"testing"
+)
+
+func TestParseCSVStatement(t *testing.T) {
+	csv := "Date,Amount,Reference,Description,Account_Number\n" +
+		"2025-04-30,\"-9,450,000.00\",TRF001,SALARY 042025 DISB00000001,1234567890\n" +
+		"\n" +
+		"02/05/2025,6350000,RTN77,RETURN ACCOUNT CLOSED,9876543210\n"
+	statement, err := ParseStatement(domain.BankStatementFormatCSV, strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ParseStatement: %v", err)
+	}
+	if len(statement.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(statement.Entries))
+	}
+	debit, credit := statement.Entries[0], statement.Entries[1]
+	if debit.Direction != domain.StatementDebit || debit.Amount.String() != "9450000.00" || debit.AccountNumber != "1234567890" {
+		t.Errorf("entry 1 = %+v, want a debit of 9450000.00 to 1234567890", debit)
+	}
+	if credit.Direction != domain.StatementCredit || credit.BookingDate.Format("2006-01-02") != "2025-05-02" || credit.Line != 2 {
+		t.Errorf("entry 2 = %+v, want a credit booked on 2025-05-02", credit)
+	}
+	if debit.Fingerprint == "" || debit.Fingerprint == credit.Fingerprint {
+		t.Errorf("fingerprints %q and %q should be set and differ", debit.Fingerprint, credit.Fingerprint)
+	}
+
+	again, _ := ParseStatement(domain.BankStatementFormatCSV, strings.NewReader(csv))
+	if again.Entries[0].Fingerprint != debit.Fingerprint {
+		t.Error("the same statement should give the same fingerprints")
+	}
+	twice, _ := ParseStatement(domain.BankStatementFormatCSV, strings.NewReader("date,amount\n2025-04-30,-100\n2025-04-30,-100\n"))
+	if twice.Entries[0].Fingerprint == twice.Entries[1].Fingerprint {
+		t.Error("two equal bookings on a day should have different fingerprints")
+	}
+}
+
+func TestParseCSVStatementErrors(t *testing.T) {
+	tests := map[string]string{
+		"missing amount column": "date,reference\n2025-04-30,TRF001\n",
+		"invalid date":          "date,amount\n30-04-2025,100\n",
+		"invalid amount":        "date,amount\n2025-04-30,ten\n",
+		"invalid direction":     "date,amount,direction\n2025-04-30,100,X\n",
+	}
+	for name, csv := range tests {
+		if _, err := ParseStatement(domain.BankStatementFormatCSV, strings.NewReader(csv)); !errors.Is(err, error_const.ErrInvalidStatement) {
+			t.Errorf("%s: expected ErrInvalidStatement, got %v", name, err)
+		}
+	}
+	if _, err := ParseStatement(domain.BankStatementFormatCSV, strings.NewReader("date,amount\n")); err != error_const.ErrEmptyStatement {
+		t.Errorf("expected ErrEmptyStatement, got %v", err)
+	}
+	if _, err := ParseStatement("bai2", strings.NewReader("")); err != error_const.ErrUnknownStatementFormat {
+		t.Errorf("expected ErrUnknownStatementFormat, got %v", err)
+	}
+}
+
+func TestParseMT940Statement(t *testing.T) {
+	mt940 := "{1:F01BANKIDJAXXXX0000000000}{2:I940BANKIDJAXXXXN}{4:\r\n" +
+		":20:STMT250430\r\n" +
+		":25:5550001111\r\n" +
+		":28C:00120/001\r\n" +
+		":60F:C250429IDR100000000,00\r\n" +
+		":61:2504300430D9450000,00NTRFDISB00000001//TRF001\r\n" +
+		":86:SALARY 042025 1234567890\r\n" +
+		"BUDI SANTOSO\r\n" +
+		":61:250502C6350000,NTRFNONREF//RTN77\r\n" +
+		":86:RETURN 9876543210 ACCOUNT CLOSED\r\n" +
+		":62F:C250502IDR96900000,00\r\n" +
+		"-}\r\n"
+	statement, err := ParseStatement(domain.BankStatementFormatMT940, strings.NewReader(mt940))
+	if err != nil {
+		t.Fatalf("ParseStatement: %v", err)
+	}
+	if statement.AccountNumber != "5550001111" || len(statement.Entries) != 2 {
+		t.Fatalf("statement = %+v, want 2 entries of account 5550001111", statement)
+	}
+	debit, credit := statement.Entries[0], statement.Entries[1]
+	if debit.Direction != domain.StatementDebit || debit.Amount.String() != "9450000.00" || debit.Reference != "DISB00000001" {
+		t.Errorf("entry 1 = %+v, want a debit of 9450000.00 with the owner's reference", debit)
+	}
+	if debit.Description != "SALARY 042025 1234567890 BUDI SANTOSO" {
+		t.Errorf("entry 1 description = %q, want the :86: field on one line", debit.Description)
+	}
+	if credit.Direction != domain.StatementCredit || credit.Reference != "RTN77" || credit.BookingDate.Format("2006-01-02") != "2025-05-02" {
+		t.Errorf("entry 2 = %+v, want a credit with the bank's reference for NONREF", credit)
+	}
+
+	if _, err := ParseStatement(domain.BankStatementFormatMT940, strings.NewReader(":25:5550001111\n:61:250430X100,00NTRFREF\n")); !errors.Is(err, error_const.ErrInvalidStatement) {
+		t.Errorf("expected ErrInvalidStatement for an invalid :61: line, got %v", err)
+	}
+}
@@ -1,17 +1,99 @@
 package tests
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"net/textproto"
 	"payroll-system/internal/delivery/dto"
 	"payroll-system/internal/domain"
 	"payroll-system/internal/error_const"
 	"payroll-system/internal/mocks"
 	admin_service "payroll-system/internal/service/admin"
+	employee_service "payroll-system/internal/service/employee"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
 )
 
+// newAdminService builds an AdminService on empty mocks. The repositories set in overrides,
+// those a test prepares or asserts on, replace them; the mailer stays nil unless overridden.
+func newAdminService(t *testing.T, overrides admin_service.AdminDependencies) *admin_service.AdminService {
+	ctrl := gomock.NewController(t)
+	deps := overrides
+	if deps.AdminRepository == nil {
+		deps.AdminRepository = mocks.NewMockAdminRepository(ctrl)
+	}
+	if deps.EmployeeRepository == nil {
+		deps.EmployeeRepository = mocks.NewMockEmployeeRepository(ctrl)
+	}
+	if deps.PayrollRepository == nil {
+		deps.PayrollRepository = mocks.NewMockPayrollRepository(ctrl)
+	}
+	if deps.AttendanceRepository == nil {
+		deps.AttendanceRepository = mocks.NewMockAttendanceRepository(ctrl)
+	}
+	if deps.OvertimeRepository == nil {
+		deps.OvertimeRepository = mocks.NewMockOvertimeRepository(ctrl)
+	}
+	if deps.ReimbursementRepository == nil {
+		deps.ReimbursementRepository = mocks.NewMockReimbursementRepository(ctrl)
+	}
+	if deps.PayRuleRepository == nil {
+		deps.PayRuleRepository = mocks.NewMockPayRuleRepository(ctrl)
+	}
+	if deps.TaxProfileRepository == nil {
+		deps.TaxProfileRepository = mocks.NewMockTaxProfileRepository(ctrl)
+	}
+	if deps.BPJSRepository == nil {
+		deps.BPJSRepository = mocks.NewMockBPJSRepository(ctrl)
+	}
+	if deps.PayrollJobRepository == nil {
+		deps.PayrollJobRepository = mocks.NewMockPayrollJobRepository(ctrl)
+	}
+	if deps.HolidayRepository == nil {
+		deps.HolidayRepository = mocks.NewMockHolidayRepository(ctrl)
+	}
+	if deps.SalaryRepository == nil {
+		deps.SalaryRepository = mocks.NewMockSalaryRepository(ctrl)
+	}
+	if deps.PayComponentRepository == nil {
+		deps.PayComponentRepository = mocks.NewMockPayComponentRepository(ctrl)
+	}
+	if deps.LoanRepository == nil {
+		deps.LoanRepository = mocks.NewMockLoanRepository(ctrl)
+	}
+	if deps.BonusRepository == nil {
+		deps.BonusRepository = mocks.NewMockBonusRepository(ctrl)
+	}
+	if deps.TaxStatementRepository == nil {
+		deps.TaxStatementRepository = mocks.NewMockTaxStatementRepository(ctrl)
+	}
+	if deps.GLAccountRepository == nil {
+		deps.GLAccountRepository = mocks.NewMockGLAccountRepository(ctrl)
+	}
+	if deps.BankAccountRepository == nil {
+		deps.BankAccountRepository = mocks.NewMockBankAccountRepository(ctrl)
+	}
+	if deps.DisbursementRepository == nil {
+		deps.DisbursementRepository = mocks.NewMockDisbursementRepository(ctrl)
+	}
+	if deps.PayslipTemplateRepository == nil {
+		deps.PayslipTemplateRepository = mocks.NewMockPayslipTemplateRepository(ctrl)
+	}
+	if deps.PayslipEmailRepository == nil {
+		deps.PayslipEmailRepository = mocks.NewMockPayslipEmailRepository(ctrl)
+	}
+	if deps.PayslipPasswordRepository == nil {
+		deps.PayslipPasswordRepository = mocks.NewMockPayslipPasswordRepository(ctrl)
+	}
+	return admin_service.NewAdminService(deps)
+}
+
 func TestLoginAsAdmin_InvalidCredentials(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -19,10 +101,9 @@ func TestLoginAsAdmin_InvalidCredentials(t *testing.T) {
 	mockAdminRepo.Admin = domain.Admin{}
 	mockAdminRepo.Err = error_const.ErrInvalidCredentials
 
-	svc := admin_service.NewAdminService(
-		mockAdminRepo,
-		nil, nil, nil, nil, nil,
-	)
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		AdminRepository: mockAdminRepo,
+	})
 	_, err := svc.LoginAsAdmin(context.Background(), dto.LoginRequest{Email: "", Password: ""})
 	if err == nil {
 		t.Error("expected error for invalid credentials")
@@ -35,21 +116,1098 @@ func TestViewPayrollSummary_NoPayrolls(t *testing.T) {
 	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
 	mockPayrollRepo.Payrolls = []domain.Payroll{}
 	mockPayrollRepo.Err = nil
-	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
-	mockAttendanceRepo := mocks.NewMockAttendanceRepository(ctrl)
-	mockOvertimeRepo := mocks.NewMockOvertimeRepository(ctrl)
-	mockReimbursementRepo := mocks.NewMockReimbursementRepository(ctrl)
-
-	svc := admin_service.NewAdminService(
-		nil,
-		mockEmpRepo,
-		mockPayrollRepo,
-		mockAttendanceRepo,
-		mockOvertimeRepo,
-		mockReimbursementRepo,
-	)
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		PayrollRepository: mockPayrollRepo,
+	})
 	_, err := svc.ViewPayrollSummary(context.Background(), 9999)
 	if err != error_const.ErrNoPayrollsFound {
 		t.Errorf("expected ErrNoPayrollsFound, got %v", err)
 	}
 }
+
+func TestPreviewPayrollPeriod_LockedPeriod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
+	mockPayrollRepo.PayrollPeriod = domain.PayrollPeriod{ID: 1, Locked: true}
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		PayrollRepository: mockPayrollRepo,
+	})
+	_, err := svc.PreviewPayrollPeriod(context.Background(), dto.PayrollRequest{PeriodID: 1})
+	if err != error_const.ErrPayrollPeriodLocked {
+		t.Errorf("expected ErrPayrollPeriodLocked, got %v", err)
+	}
+}
+
+func TestReopenPayrollPeriod_RequiresReason(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
+	mockPayrollRepo.PayrollPeriod = domain.PayrollPeriod{ID: 1, Locked: true}
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		PayrollRepository: mockPayrollRepo,
+	})
+	_, err := svc.ReopenPayrollPeriod(context.Background(), dto.ReopenPayrollPeriodRequest{PeriodID: 1, Reason: "  "})
+	if err != error_const.ErrReopenReasonRequired {
+		t.Errorf("expected ErrReopenReasonRequired, got %v", err)
+	}
+}
+
+func TestReopenPayrollPeriod_NotLocked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
+	mockPayrollRepo.PayrollPeriod = domain.PayrollPeriod{ID: 1}
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		PayrollRepository: mockPayrollRepo,
+	})
+	_, err := svc.ReopenPayrollPeriod(context.Background(), dto.ReopenPayrollPeriodRequest{PeriodID: 1, Reason: "late overtime approval"})
+	if err != error_const.ErrPayrollPeriodNotLocked {
+		t.Errorf("expected ErrPayrollPeriodNotLocked, got %v", err)
+	}
+}
+
+func TestReopenPayrollPeriod_QueuesReRunAndRecordsItsDiff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
+	mockPayrollRepo.PayrollPeriod = domain.PayrollPeriod{ID: 1, Locked: true}
+	mockPayrollRepo.Payrolls = []domain.Payroll{{EmployeeID: 1, PeriodID: 1, RunType: domain.PayrollRunRegular}}
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		PayrollRepository: mockPayrollRepo,
+	})
+	ctx := context.Background()
+	reopen, err := svc.ReopenPayrollPeriod(ctx, dto.ReopenPayrollPeriodRequest{PeriodID: 1, Reason: " late overtime approval ", ActorEmail: "admin@example.com"})
+	if err != nil {
+		t.Fatalf("ReopenPayrollPeriod: %v", err)
+	}
+	if reopen.Reason != "late overtime approval" || mockPayrollRepo.PayrollPeriod.Locked {
+		t.Errorf("reopen = %+v, period locked %v; want the trimmed reason and an unlocked period", reopen, mockPayrollRepo.PayrollPeriod.Locked)
+	}
+	if reopen.JobID == 0 || len(mockPayrollRepo.ReopenJobs) != 1 || mockPayrollRepo.ReopenJobs[0].RunType != domain.PayrollRunRegular || mockPayrollRepo.ReopenJobs[0].CreatedBy != "admin@example.com" {
+		t.Errorf("reopen = %+v, jobs %+v; want a regular re-run queued by the admin", reopen, mockPayrollRepo.ReopenJobs)
+	}
+	if len(mockPayrollRepo.Payrolls) != 1 {
+		t.Errorf("the stored payslips must stay until a re-run is approved, got %d", len(mockPayrollRepo.Payrolls))
+	}
+
+	// the re-run completed and waits for approval
+	diff := []domain.PayslipDiff{{EmployeeID: 1, Change: domain.PayslipChangeChanged}}
+	mockPayrollRepo.Runs = []domain.PayrollRun{{ID: 7, PeriodID: 1, RunType: domain.PayrollRunRegular, Status: domain.PayrollRunPendingApproval,
+		CreatedBy: "admin@example.com", Diff: diff, Payrolls: []domain.Payroll{{EmployeeID: 1, PeriodID: 1}}}}
+	if _, err := svc.ApprovePayrollRun(ctx, dto.PayrollRunDecisionRequest{RunID: 7, ActorEmail: "checker@example.com"}); err != nil {
+		t.Fatalf("ApprovePayrollRun: %v", err)
+	}
+	reopens, err := svc.GetPayrollReopens(ctx, 1)
+	if err != nil || len(reopens) != 1 {
+		t.Fatalf("GetPayrollReopens = %+v, %v", reopens, err)
+	}
+	if got := reopens[0]; got.RunID == nil || *got.RunID != 7 || got.VoidedPayrolls != 1 || len(got.Diff) != 1 || got.Diff[0].EmployeeID != 1 {
+		t.Errorf("reopen = %+v, want the approved run 7, one voided payslip and the diff of employee 1", got)
+	}
+}
+
+func TestPayrollJob_RunsQueuedJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
+	mockEmpRepo.Employees[1] = &domain.Employee{ID: 1, Salary: domain.NewMoney(5000000)}
+	mockEmpRepo.Employees[2] = &domain.Employee{ID: 2, Salary: domain.NewMoney(7000000)}
+	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
+	mockPayrollRepo.PayrollPeriod = domain.PayrollPeriod{
+		ID:        1,
+		StartDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+	mockAttendanceRepo := mocks.NewMockAttendanceRepository(ctrl)
+	mockAttendanceRepo.Attendance[1] = 20
+	mockAttendanceRepo.Attendance[2] = 21
+	mockPayRuleRepo := mocks.NewMockPayRuleRepository(ctrl)
+	mockPayRuleRepo.Err = pgx.ErrNoRows // no stored rules, default rules apply
+	mockJobRepo := mocks.NewMockPayrollJobRepository(ctrl)
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		EmployeeRepository:   mockEmpRepo,
+		PayrollRepository:    mockPayrollRepo,
+		AttendanceRepository: mockAttendanceRepo,
+		PayRuleRepository:    mockPayRuleRepo,
+		PayrollJobRepository: mockJobRepo,
+	})
+	queued, err := svc.RunPayrollPeriod(context.Background(), dto.PayrollRequest{PeriodID: 1, ActorEmail: "admin@example.com"})
+	if err != nil {
+		t.Fatalf("RunPayrollPeriod: %v", err)
+	}
+	if queued.Status != domain.PayrollJobQueued || len(mockJobRepo.Completed) != 0 {
+		t.Fatalf("expected a queued job and nothing calculated yet, got %+v", queued)
+	}
+
+	svc.ProcessPayrollJobs(context.Background())
+
+	job, err := svc.GetPayrollJob(context.Background(), queued.ID)
+	if err != nil {
+		t.Fatalf("GetPayrollJob: %v", err)
+	}
+	if job.Status != domain.PayrollJobSucceeded {
+		t.Fatalf("job status = %s, errors %v", job.Status, job.Errors)
+	}
+	if job.TotalEmployees != 2 || job.ProcessedEmployees != 2 || len(mockJobRepo.Completed) != 2 {
+		t.Errorf("progress = %d/%d with %d payrolls, want 2/2 with 2", job.ProcessedEmployees, job.TotalEmployees, len(mockJobRepo.Completed))
+	}
+}
+
+func TestGetPayrollJob_NotFound(t *testing.T) {
+
+	svc := newAdminService(t, admin_service.AdminDependencies{})
+	_, err := svc.GetPayrollJob(context.Background(), 42)
+	if err != error_const.ErrPayrollJobNotFound {
+		t.Errorf("expected ErrPayrollJobNotFound, got %v", err)
+	}
+}
+
+func TestPayrollJob_PaysOnlyEmployedEmployees(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	lastDay := time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC)
+	hired := time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC)
+	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
+	mockEmpRepo.Employees[1] = &domain.Employee{ID: 1, Salary: domain.NewMoney(5000000), EmploymentStatus: domain.EmploymentStatusActive}
+	mockEmpRepo.Employees[2] = &domain.Employee{ID: 2, Salary: domain.NewMoney(5000000), EmploymentStatus: domain.EmploymentStatusInactive}
+	mockEmpRepo.Employees[3] = &domain.Employee{ID: 3, Salary: domain.NewMoney(5000000), EmploymentStatus: domain.EmploymentStatusTerminated, EndDate: &lastDay}
+	mockEmpRepo.Employees[4] = &domain.Employee{ID: 4, Salary: domain.NewMoney(4200000), EmploymentStatus: domain.EmploymentStatusActive, StartDate: &hired}
+	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
+	mockPayrollRepo.PayrollPeriod = domain.PayrollPeriod{
+		ID:        1,
+		StartDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+	mockAttendanceRepo := mocks.NewMockAttendanceRepository(ctrl)
+	mockAttendanceRepo.Attendance[1] = 21
+	mockAttendanceRepo.Attendance[4] = 11
+	mockPayRuleRepo := mocks.NewMockPayRuleRepository(ctrl)
+	mockPayRuleRepo.Err = pgx.ErrNoRows
+	mockJobRepo := mocks.NewMockPayrollJobRepository(ctrl)
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		EmployeeRepository:   mockEmpRepo,
+		PayrollRepository:    mockPayrollRepo,
+		AttendanceRepository: mockAttendanceRepo,
+		PayRuleRepository:    mockPayRuleRepo,
+		PayrollJobRepository: mockJobRepo,
+	})
+	ctx := context.Background()
+	if _, err := svc.RunPayrollPeriod(ctx, dto.PayrollRequest{PeriodID: 1, EmployeeID: 3, Reason: "final pay", ActorEmail: "admin@example.com"}); !errors.Is(err, error_const.ErrEmployeeNotEmployed) {
+		t.Fatalf("expected ErrEmployeeNotEmployed for an employee who left before the period, got %v", err)
+	}
+	if _, err := svc.RunPayrollPeriod(ctx, dto.PayrollRequest{PeriodID: 1, ActorEmail: "admin@example.com"}); err != nil {
+		t.Fatalf("RunPayrollPeriod: %v", err)
+	}
+	svc.ProcessPayrollJobs(ctx)
+
+	if len(mockJobRepo.Completed) != 2 {
+		t.Fatalf("expected payslips for employees 1 and 4, got %d", len(mockJobRepo.Completed))
+	}
+	// June 2025 has 21 workdays; employee 4 is employed for 11 of them
+	hire := mockJobRepo.Completed[1].Payslip
+	if hire.EmployeeID != 4 || hire.TotalWorkDays != 11 || hire.SalaryByAttendance != domain.NewMoney(2200000) {
+		t.Errorf("new hire payslip = employee %d, %d workdays, %s base pay; want employee 4, 11 workdays, 2200000.00",
+			hire.EmployeeID, hire.TotalWorkDays, hire.SalaryByAttendance)
+	}
+}
+
+func TestPayrollJob_OffCycleRunForLockedPeriod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
+	mockEmpRepo.Employees[1] = &domain.Employee{ID: 1, Salary: domain.NewMoney(5000000)}
+	mockEmpRepo.Employees[2] = &domain.Employee{ID: 2, Salary: domain.NewMoney(7000000)}
+	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
+	mockPayrollRepo.PayrollPeriod = domain.PayrollPeriod{
+		ID:        1,
+		StartDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+		Locked:    true,
+	}
+	mockPayRuleRepo := mocks.NewMockPayRuleRepository(ctrl)
+	mockPayRuleRepo.Err = pgx.ErrNoRows
+	mockJobRepo := mocks.NewMockPayrollJobRepository(ctrl)
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		EmployeeRepository:   mockEmpRepo,
+		PayrollRepository:    mockPayrollRepo,
+		PayRuleRepository:    mockPayRuleRepo,
+		PayrollJobRepository: mockJobRepo,
+	})
+	ctx := context.Background()
+	if _, err := svc.RunPayrollPeriod(ctx, dto.PayrollRequest{PeriodID: 1, EmployeeID: 2, ActorEmail: "admin@example.com"}); err != error_const.ErrOffCycleReasonRequired {
+		t.Fatalf("expected ErrOffCycleReasonRequired, got %v", err)
+	}
+	if _, err := svc.RunPayrollPeriod(ctx, dto.PayrollRequest{PeriodID: 1, ActorEmail: "admin@example.com"}); err != error_const.ErrPayrollPeriodLocked {
+		t.Fatalf("regular run of a locked period: expected ErrPayrollPeriodLocked, got %v", err)
+	}
+	job, err := svc.RunPayrollPeriod(ctx, dto.PayrollRequest{PeriodID: 1, EmployeeID: 2, Reason: "late joiner", ActorEmail: "admin@example.com"})
+	if err != nil {
+		t.Fatalf("RunPayrollPeriod: %v", err)
+	}
+	if job.RunType != domain.PayrollRunOffCycle {
+		t.Fatalf("run type = %s, want off_cycle", job.RunType)
+	}
+
+	svc.ProcessPayrollJobs(ctx)
+
+	if len(mockJobRepo.Completed) != 1 || mockJobRepo.Completed[0].EmployeeID != 2 {
+		t.Fatalf("expected only employee 2 to be paid, got %+v", mockJobRepo.Completed)
+	}
+	if run := mockJobRepo.Runs[0]; run.RunType != domain.PayrollRunOffCycle || run.Reason != "late joiner" {
+		t.Errorf("run = %+v, want off-cycle run with its reason", run)
+	}
+	if run := mockJobRepo.Runs[0]; !run.IsPendingApproval() {
+		t.Errorf("off-cycle run status = %s, want pending_approval", run.Status)
+	}
+}
+
+func TestPreviewPayrollPeriod_RegularRunSkipsOffCyclePaid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
+	mockEmpRepo.Employees[1] = &domain.Employee{ID: 1, Salary: domain.NewMoney(5000000)}
+	mockEmpRepo.Employees[2] = &domain.Employee{ID: 2, Salary: domain.NewMoney(7000000)}
+	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
+	mockPayrollRepo.PayrollPeriod = domain.PayrollPeriod{
+		ID:        1,
+		StartDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+	// a reopened period: employee 1 has a regular payslip, employee 2 was paid off-cycle
+	mockPayrollRepo.Payrolls = []domain.Payroll{
+		{EmployeeID: 1, PeriodID: 1, RunType: domain.PayrollRunRegular},
+		{EmployeeID: 2, PeriodID: 1, RunType: domain.PayrollRunOffCycle},
+	}
+	mockPayRuleRepo := mocks.NewMockPayRuleRepository(ctrl)
+	mockPayRuleRepo.Err = pgx.ErrNoRows
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		EmployeeRepository: mockEmpRepo,
+		PayrollRepository:  mockPayrollRepo,
+		PayRuleRepository:  mockPayRuleRepo,
+	})
+	ctx := context.Background()
+	preview, err := svc.PreviewPayrollPeriod(ctx, dto.PayrollRequest{PeriodID: 1, ActorEmail: "admin@example.com"})
+	if err != nil {
+		t.Fatalf("PreviewPayrollPeriod: %v", err)
+	}
+	if preview.EmployeeCount != 1 || preview.Payslips[0].EmployeeID != 1 {
+		t.Errorf("regular run pays %d employees, want only employee 1 whose regular payslip it replaces", preview.EmployeeCount)
+	}
+	preview, err = svc.PreviewPayrollPeriod(ctx, dto.PayrollRequest{PeriodID: 1, IncludeEmployeeIDs: []int{2}, ActorEmail: "admin@example.com"})
+	if err != nil {
+		t.Fatalf("PreviewPayrollPeriod: %v", err)
+	}
+	if preview.EmployeeCount != 2 {
+		t.Errorf("regular run including employee 2 pays %d employees, want 2", preview.EmployeeCount)
+	}
+}
+
+func TestPayComponents_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPayComponentRepo := mocks.NewMockPayComponentRepository(ctrl)
+	mockPayComponentRepo.Components[1] = domain.PayComponent{ID: 1, Code: "OLD_BONUS", Category: domain.PayslipLineEarning}
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		PayComponentRepository: mockPayComponentRepo,
+	})
+	ctx := context.Background()
+	for _, code := range []string{"base_pay", "BPJS_JHT", "PPH21"} {
+		if _, err := svc.CreatePayComponent(ctx, dto.PayComponentRequest{Code: code, Name: "x", Category: domain.PayslipLineEarning, ActorEmail: "admin@example.com"}); err != error_const.ErrReservedPayComponentCode {
+			t.Errorf("code %s: expected ErrReservedPayComponentCode, got %v", code, err)
+		}
+	}
+	if _, err := svc.CreatePayComponent(ctx, dto.PayComponentRequest{Code: "LOAN", Name: "Loan", Category: domain.PayslipLineInformation, ActorEmail: "admin@example.com"}); err != error_const.ErrInvalidPayComponentCategory {
+		t.Errorf("expected ErrInvalidPayComponentCategory, got %v", err)
+	}
+	component, err := svc.CreatePayComponent(ctx, dto.PayComponentRequest{Code: "coop", Name: "Cooperative dues", Category: domain.PayslipLineDeduction, Taxable: true, ActorEmail: "admin@example.com"})
+	if err != nil {
+		t.Fatalf("CreatePayComponent: %v", err)
+	}
+	if component.Code != "COOP" || component.Taxable || !component.Active {
+		t.Errorf("component = %+v, want active non-taxable COOP", component)
+	}
+
+	assignment := dto.PayComponentAssignmentRequest{EmployeeID: 1, ComponentID: 1, Amount: domain.NewMoney(100000), EffectiveFrom: "2025-06-01", ActorEmail: "admin@example.com"}
+	if _, err := svc.AssignPayComponent(ctx, assignment); err != error_const.ErrPayComponentInactive {
+		t.Errorf("expected ErrPayComponentInactive, got %v", err)
+	}
+	assignment.ComponentID = 99
+	if _, err := svc.AssignPayComponent(ctx, assignment); err != error_const.ErrPayComponentNotFound {
+		t.Errorf("expected ErrPayComponentNotFound, got %v", err)
+	}
+	assignment.EffectiveTo = "2025-05-31"
+	if _, err := svc.AssignPayComponent(ctx, assignment); err != error_const.ErrStartDateAfterEndDate {
+		t.Errorf("expected ErrStartDateAfterEndDate, got %v", err)
+	}
+}
+
+func TestCreateLoan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
+	mockEmpRepo.Employees[1] = &domain.Employee{ID: 1, Salary: domain.NewMoney(5000000)}
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		EmployeeRepository: mockEmpRepo,
+	})
+	ctx := context.Background()
+	request := dto.LoanRequest{EmployeeID: 1, Principal: domain.NewMoney(1000000), Installments: 3, StartDate: "2025-06-01", ActorEmail: "admin@example.com"}
+	if _, err := svc.CreateLoan(ctx, dto.LoanRequest{EmployeeID: 1, Type: "gift", Principal: request.Principal, StartDate: request.StartDate}); err != error_const.ErrInvalidLoanType {
+		t.Errorf("expected ErrInvalidLoanType, got %v", err)
+	}
+	if _, err := svc.CreateLoan(ctx, dto.LoanRequest{EmployeeID: 1, StartDate: request.StartDate}); err != error_const.ErrInvalidLoanPrincipal {
+		t.Errorf("expected ErrInvalidLoanPrincipal, got %v", err)
+	}
+	loan, err := svc.CreateLoan(ctx, request)
+	if err != nil {
+		t.Fatalf("CreateLoan: %v", err)
+	}
+	// installments are rounded up so three of them cover the principal
+	installment, _ := domain.ParseMoney("333333.34")
+	if loan.Type != domain.LoanTypeLoan || loan.InstallmentAmount != installment || loan.Outstanding != request.Principal {
+		t.Errorf("loan = %+v, want a 1000000.00 loan in installments of 333333.34", loan)
+	}
+	if _, err := svc.CancelLoan(ctx, loan.ID, "admin@example.com"); err != nil {
+		t.Fatalf("CancelLoan: %v", err)
+	}
+	if _, err := svc.CancelLoan(ctx, loan.ID, "admin@example.com"); err != error_const.ErrLoanNotActive {
+		t.Errorf("expected ErrLoanNotActive, got %v", err)
+	}
+}
+
+func TestRunBonus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
+	mockPayrollRepo.PayrollPeriod = domain.PayrollPeriod{
+		ID:        1,
+		StartDate: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC),
+	}
+	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
+	halfYear := time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
+	lastWeek := time.Date(2026, time.March, 24, 0, 0, 0, 0, time.UTC)
+	mockEmpRepo.Employees[1] = &domain.Employee{ID: 1, Salary: domain.NewMoney(12000000), StartDate: &halfYear}
+	mockEmpRepo.Employees[2] = &domain.Employee{ID: 2, Salary: domain.NewMoney(8000000), StartDate: &lastWeek}
+	mockBonusRepo := mocks.NewMockBonusRepository(ctrl)
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		EmployeeRepository: mockEmpRepo,
+		PayrollRepository:  mockPayrollRepo,
+		BonusRepository:    mockBonusRepo,
+	})
+	ctx := context.Background()
+	thr := dto.BonusRunRequest{PeriodID: 1, Kind: domain.BonusKindTHR, ActorEmail: "admin@example.com"}
+	if _, err := svc.RunBonus(ctx, thr); err != error_const.ErrPayrollPeriodNotRun {
+		t.Errorf("expected ErrPayrollPeriodNotRun before the period is locked, got %v", err)
+	}
+	mockPayrollRepo.PayrollPeriod.Locked = true
+
+	if _, err := svc.RunBonus(ctx, dto.BonusRunRequest{PeriodID: 1, Kind: "gift"}); err != error_const.ErrInvalidBonusKind {
+		t.Errorf("expected ErrInvalidBonusKind, got %v", err)
+	}
+	bonus := dto.BonusRunRequest{PeriodID: 1, Kind: domain.BonusKindBonus, Amounts: []domain.BonusAmount{
+		{EmployeeID: 1, Amount: domain.NewMoney(1000000)},
+		{EmployeeID: 1, Amount: domain.NewMoney(2000000)},
+	}}
+	if _, err := svc.RunBonus(ctx, bonus); !errors.Is(err, error_const.ErrDuplicateBonusEmployee) {
+		t.Errorf("expected ErrDuplicateBonusEmployee, got %v", err)
+	}
+	bonus.Amounts = []domain.BonusAmount{{EmployeeID: 2, Amount: domain.NewMoney(-1)}}
+	if _, err := svc.RunBonus(ctx, bonus); !errors.Is(err, error_const.ErrInvalidBonusAmount) {
+		t.Errorf("expected ErrInvalidBonusAmount, got %v", err)
+	}
+
+	// employee 2 has less than a month of service and gets no THR
+	run, err := svc.RunBonus(ctx, thr)
+	if err != nil {
+		t.Fatalf("RunBonus: %v", err)
+	}
+	if run.ID == 0 || run.EmployeeCount != 1 || len(run.Payslips) != 1 || run.Payslips[0].EmployeeID != 1 {
+		t.Fatalf("run = %+v, want THR for employee 1 only", run)
+	}
+	if run.TotalGross != domain.NewMoney(6000000) {
+		t.Errorf("THR = %s, want 6/12 of 12000000.00", run.TotalGross)
+	}
+	if len(mockBonusRepo.Payslips) != 1 || mockBonusRepo.Payslips[0].RunID != run.ID {
+		t.Errorf("stored payslips = %+v, want one payslip of run %d", mockBonusRepo.Payslips, run.ID)
+	}
+}
+
+func TestYearToDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
+	mockPayrollRepo.YearToDate = []domain.TaxYearToDate{
+		{EmployeeID: 1, TaxYear: 2025, Months: 12, Gross: domain.NewMoney(120000000)},
+		{EmployeeID: 1, TaxYear: 2026, Months: 2, Gross: domain.NewMoney(20000000)},
+	}
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		PayrollRepository: mockPayrollRepo,
+	})
+	ctx := context.Background()
+	ytd, err := svc.GetEmployeeYearToDate(ctx, 1, 2026)
+	if err != nil || ytd.Months != 2 || ytd.Gross != domain.NewMoney(20000000) {
+		t.Errorf("GetEmployeeYearToDate = %+v, %v, want the 2026 totals", ytd, err)
+	}
+	// employees without payslips in the year get zero totals
+	ytd, err = svc.GetEmployeeYearToDate(ctx, 2, 2026)
+	if err != nil || ytd.EmployeeID != 2 || ytd.TaxYear != 2026 || !ytd.Gross.IsZero() {
+		t.Errorf("GetEmployeeYearToDate = %+v, %v, want zero totals", ytd, err)
+	}
+	if _, err := svc.RebuildYearToDate(ctx, dto.YearToDateRebuildRequest{TaxYear: 26}); err != error_const.ErrInvalidTaxYear {
+		t.Errorf("expected ErrInvalidTaxYear, got %v", err)
+	}
+	rebuild, err := svc.RebuildYearToDate(ctx, dto.YearToDateRebuildRequest{})
+	if err != nil || rebuild.Accumulators != 2 {
+		t.Errorf("RebuildYearToDate = %+v, %v, want every tax year rebuilt", rebuild, err)
+	}
+}
+
+func TestGenerateTaxStatements(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
+	mockEmpRepo.Employees[1] = &domain.Employee{ID: 1, Name: "Budi", Salary: domain.NewMoney(10000000)}
+	mockEmpRepo.Employees[2] = &domain.Employee{ID: 2, Name: "Sari", Salary: domain.NewMoney(8000000)}
+	mockEmpRepo.Employees[3] = &domain.Employee{ID: 3, Name: "Joko", Salary: domain.NewMoney(8000000)}
+	mockTaxStatementRepo := mocks.NewMockTaxStatementRepository(ctrl)
+	for _, employeeID := range []int{2, 1} {
+		mockTaxStatementRepo.Payslips[employeeID] = []domain.TaxYearPayslip{{
+			PeriodID:  1,
+			PeriodEnd: time.Date(2025, time.June, 30, 0, 0, 0, 0, time.UTC),
+			Regular:   true,
+			Payslip: domain.Payslip{Lines: []domain.PayslipLine{
+				domain.NewAmountLine(domain.PayslipLineCodeBasePay, domain.PayslipLineEarning, "Base salary", domain.NewMoney(10000000), true),
+			}},
+		}}
+	}
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		EmployeeRepository:     mockEmpRepo,
+		TaxStatementRepository: mockTaxStatementRepo,
+		TaxWithholder:          domain.TaxWithholder{Name: "PT Contoh", NPWP: "011234567089000"},
+	})
+	ctx := context.Background()
+	if _, err := svc.GenerateTaxStatements(ctx, dto.TaxStatementRequest{TaxYear: 2024}); err != error_const.ErrNoPayslipsInTaxYear {
+		t.Errorf("expected ErrNoPayslipsInTaxYear, got %v", err)
+	}
+
+	// employee 3 was not paid in the year and gets no statement
+	statements, err := svc.GenerateTaxStatements(ctx, dto.TaxStatementRequest{TaxYear: 2025, ActorEmail: "admin@example.com"})
+	if err != nil {
+		t.Fatalf("GenerateTaxStatements: %v", err)
+	}
+	if len(statements) != 2 || statements[0].EmployeeID != 1 || statements[1].EmployeeID != 2 {
+		t.Fatalf("statements = %+v, want employees 1 and 2", statements)
+	}
+	first := statements[0]
+	if first.Number != "1.1-06.25-0000001" || first.Withholder.Name != "PT Contoh" || first.GeneratedBy != "admin@example.com" {
+		t.Errorf("statement = %+v, want number 1.1-06.25-0000001 withheld by PT Contoh", first)
+	}
+
+	// regenerating keeps the number of the statement
+	statements, err = svc.GenerateTaxStatements(ctx, dto.TaxStatementRequest{TaxYear: 2025, EmployeeIDs: []int{1}})
+	if err != nil || len(statements) != 1 || statements[0].Number != first.Number {
+		t.Errorf("GenerateTaxStatements = %+v, %v, want statement %s again", statements, err, first.Number)
+	}
+	if _, err := svc.GetTaxStatement(ctx, 3, 2025); err != error_const.ErrTaxStatementNotFound {
+		t.Errorf("expected ErrTaxStatementNotFound, got %v", err)
+	}
+	pdf, err := svc.GetTaxStatementPDF(ctx, 1, 2025)
+	if err != nil || !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		t.Errorf("GetTaxStatementPDF = %d bytes, %v, want a PDF", len(pdf), err)
+	}
+}
+
+func TestAdminPayslipPDF(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
+	mockEmpRepo.Employees[2] = &domain.Employee{ID: 2, Name: "Sari", CostCenter: "OPS"}
+	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
+	mockPayrollRepo.PayrollPeriod = domain.PayrollPeriod{ID: 4}
+	mockPayrollRepo.Payslip = domain.Payroll{ID: 9, EmployeeID: 2, PeriodID: 4, Payslip: domain.Payslip{NetSalary: domain.NewMoney(6350000)}}
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		EmployeeRepository: mockEmpRepo,
+		PayrollRepository:  mockPayrollRepo,
+	})
+	pdf, err := svc.GetPayslipPDF(context.Background(), 2, 4)
+	if err != nil || !bytes.Contains(pdf, []byte("(: Sari)")) || !bytes.Contains(pdf, []byte("(: OPS)")) {
+		t.Errorf("GetPayslipPDF = %d bytes, %v, want Sari's payslip", len(pdf), err)
+	}
+	mockPayrollRepo.Err = pgx.ErrNoRows
+	if _, err := svc.GetPayslipPDF(context.Background(), 2, 4); err != error_const.ErrPayrollPeriodNotFound {
+		t.Errorf("expected ErrPayrollPeriodNotFound, got %v", err)
+	}
+}
+
+func TestPayslipTemplates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
+	mockEmpRepo.Employees[2] = &domain.Employee{ID: 2, Name: "Sari", CostCenter: "OPS"}
+	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
+	mockPayrollRepo.PayrollPeriod = domain.PayrollPeriod{ID: 4}
+	mockPayrollRepo.Payslip = domain.Payroll{ID: 9, EmployeeID: 2, PeriodID: 4, Payslip: domain.Payslip{NetSalary: domain.NewMoney(6350000)}}
+	mockTemplateRepo := mocks.NewMockPayslipTemplateRepository(ctrl)
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		EmployeeRepository:        mockEmpRepo,
+		PayrollRepository:         mockPayrollRepo,
+		PayslipTemplateRepository: mockTemplateRepo,
+	})
+	ctx := context.Background()
+	if _, err := svc.SavePayslipTemplate(ctx, dto.PayslipTemplateRequest{Company: "acme corp", Body: "<p>hi</p>"}); err != error_const.ErrInvalidCompanyCode {
+		t.Errorf("expected ErrInvalidCompanyCode, got %v", err)
+	}
+	_, err := svc.SavePayslipTemplate(ctx, dto.PayslipTemplateRequest{Company: "ACME", Body: "{{range .Deductions}}{{.Ammount}}{{end}}"})
+	if !errors.Is(err, error_const.ErrInvalidPayslipTemplate) || len(mockTemplateRepo.Templates) != 0 {
+		t.Errorf("expected ErrInvalidPayslipTemplate and nothing saved, got %v", err)
+	}
+	for _, body := range []string{"<p>ACME v1 {{.Employee.Name}}</p>", "<p>ACME v2 {{.Employee.Name}} {{amount .Payslip.NetSalary}}</p>"} {
+		if _, err := svc.SavePayslipTemplate(ctx, dto.PayslipTemplateRequest{Company: "ACME", Body: body, ActorEmail: "admin@example.com"}); err != nil {
+			t.Fatalf("SavePayslipTemplate: %v", err)
+		}
+	}
+	templates, err := svc.GetPayslipTemplates(ctx, "ACME")
+	if err != nil || len(templates) != 2 || templates[0].Version != 2 || templates[0].Language != "en" {
+		t.Fatalf("GetPayslipTemplates = %+v, %v, want versions 2 and 1", templates, err)
+	}
+	if _, err := svc.GetPayslipTemplate(ctx, "ACME", 3); err != error_const.ErrPayslipTemplateNotFound {
+		t.Errorf("expected ErrPayslipTemplateNotFound, got %v", err)
+	}
+
+	// without a company the built-in layout is used
+	page, err := svc.GetPayslipHTML(ctx, 2, 4)
+	if err != nil || !bytes.Contains(page, []byte("Sari (2)")) {
+		t.Errorf("GetPayslipHTML = %s, %v, want the default template", page, err)
+	}
+	mockEmpRepo.Employees[2].Company = "ACME"
+	page, err = svc.GetPayslipHTML(ctx, 2, 4)
+	if err != nil || string(page) != "<p>ACME v2 Sari 6.350.000</p>" {
+		t.Errorf("GetPayslipHTML = %s, %v, want the latest ACME template", page, err)
+	}
+	pdf, err := svc.GetPayslipPDF(ctx, 2, 4)
+	if err != nil || !bytes.Contains(pdf, []byte("(ACME v2 Sari 6.350.000)")) {
+		t.Errorf("GetPayslipPDF = %d bytes, %v, want the latest ACME template", len(pdf), err)
+	}
+
+	preview, err := svc.PreviewPayslipTemplate(ctx, dto.PayslipTemplatePreviewRequest{Body: "<h1>{{.Employer.Name}}</h1>", Format: "pdf"})
+	if err != nil || !bytes.HasPrefix(preview, []byte("%PDF-")) || !bytes.Contains(preview, []byte("(PT Contoh Sejahtera)")) {
+		t.Errorf("PreviewPayslipTemplate = %d bytes, %v, want a PDF of the sample payslip", len(preview), err)
+	}
+	if _, err := svc.PreviewPayslipTemplate(ctx, dto.PayslipTemplatePreviewRequest{Body: "<p></p>", Format: "docx"}); err != error_const.ErrUnknownPayslipFormat {
+		t.Errorf("expected ErrUnknownPayslipFormat, got %v", err)
+	}
+
+	company := "not a code!"
+	if _, err := svc.UpdateEmployment(ctx, dto.EmploymentRequest{EmployeeID: 2, Status: domain.EmploymentStatusActive, Company: &company}); err != error_const.ErrInvalidCompanyCode {
+		t.Errorf("expected ErrInvalidCompanyCode, got %v", err)
+	}
+}
+
+func TestPayslipEmails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
+	mockEmpRepo.Employees[1] = &domain.Employee{ID: 1, Name: "Budi", Email: "budi@example.com"}
+	mockEmpRepo.Employees[2] = &domain.Employee{ID: 2, Name: "Sari"}
+	mockEmpRepo.Employees[3] = &domain.Employee{ID: 3, Name: "Rina", Email: "rina@example.com"}
+	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
+	mockPayrollRepo.PayrollPeriod = domain.PayrollPeriod{
+		ID:        5,
+		StartDate: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC),
+	}
+	mockPayrollRepo.Payslip = domain.Payroll{ID: 7, PeriodID: 5, Payslip: domain.Payslip{NetSalary: domain.NewMoney(9450000)}}
+	mockPayrollRepo.Runs = []domain.PayrollRun{{ID: 1, PeriodID: 5, RunType: domain.PayrollRunRegular, Status: domain.PayrollRunPendingApproval,
+		CreatedBy: "maker@example.com", Payrolls: []domain.Payroll{{EmployeeID: 1, PeriodID: 5}, {EmployeeID: 2, PeriodID: 5}, {EmployeeID: 3, PeriodID: 5}}}}
+	mockPasswordRepo := mocks.NewMockPayslipPasswordRepository(ctrl)
+	mockEmailRepo := mocks.NewMockPayslipEmailRepository(ctrl)
+	mailer := mocks.NewMockMailer()
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		EmployeeRepository:        mockEmpRepo,
+		PayrollRepository:         mockPayrollRepo,
+		PayslipEmailRepository:    mockEmailRepo,
+		PayslipPasswordRepository: mockPasswordRepo,
+		Mailer:                    mailer,
+		PayslipEmailSettings:      domain.PayslipEmailSettings{SendOnLock: true},
+	})
+	ctx := context.Background()
+	if _, err := svc.DistributePayslipEmails(ctx, dto.PayslipEmailRequest{PeriodID: 5, ActorEmail: "hr@example.com"}); err != error_const.ErrPayslipEmailPeriodNotLocked {
+		t.Errorf("expected ErrPayslipEmailPeriodNotLocked, got %v", err)
+	}
+	withoutMail := admin_service.NewAdminService(admin_service.AdminDependencies{
+		EmployeeRepository:     mockEmpRepo,
+		PayrollRepository:      mockPayrollRepo,
+		PayslipEmailRepository: mockEmailRepo,
+	})
+	if _, err := withoutMail.DistributePayslipEmails(ctx, dto.PayslipEmailRequest{PeriodID: 5}); err != error_const.ErrMailNotConfigured {
+		t.Errorf("expected ErrMailNotConfigured, got %v", err)
+	}
+
+	// approving the run locks the period and queues the emails
+	if _, err := svc.ApprovePayrollRun(ctx, dto.PayrollRunDecisionRequest{RunID: 1, ActorEmail: "checker@example.com"}); err != nil {
+		t.Fatalf("ApprovePayrollRun: %v", err)
+	}
+	if len(mockEmailRepo.Emails) != 2 || mockEmailRepo.Emails[0].CreatedBy != "checker@example.com" {
+		t.Fatalf("queued %+v, want Budi and Rina", mockEmailRepo.Emails)
+	}
+	distribution, err := svc.DistributePayslipEmails(ctx, dto.PayslipEmailRequest{PeriodID: 5, ActorEmail: "hr@example.com"})
+	if err != nil || distribution.Queued != 0 || distribution.AlreadyQueued != 2 || len(distribution.WithoutEmail) != 1 || distribution.WithoutEmail[0] != 2 {
+		t.Errorf("DistributePayslipEmails = %+v, %v, want nothing queued twice and Sari without email", distribution, err)
+	}
+
+	// a server that is down is retried later, a rejected mailbox is not
+	mailer.Errors["rina@example.com"] = errors.New("connection reset by peer")
+	svc.ProcessPayslipEmails(ctx)
+	if len(mailer.Sent) != 1 {
+		t.Fatalf("sent %d emails, want Budi's", len(mailer.Sent))
+	}
+	message := mailer.Sent[0]
+	if message.To != `"Budi" <budi@example.com>` || message.Subject != "Payslip April 2025" || len(message.Attachments) != 1 ||
+		message.Attachments[0].Filename != "payslip-2025-04.pdf" || !bytes.HasPrefix(message.Attachments[0].Data, []byte("%PDF-")) {
+		t.Errorf("message = %+v, want Budi's April payslip attached", message)
+	}
+	budi, rina := mockEmailRepo.Emails[0], mockEmailRepo.Emails[1]
+	if budi.Status != domain.PayslipEmailSent || budi.SentAt == nil {
+		t.Errorf("Budi's email = %+v, want sent", budi)
+	}
+	if rina.Status != domain.PayslipEmailQueued || rina.Attempts != 1 || !rina.NextAttemptAt.After(time.Now()) || rina.LastError == "" {
+		t.Errorf("Rina's email = %+v, want queued for a later retry", rina)
+	}
+	svc.ProcessPayslipEmails(ctx)
+	if mockEmailRepo.Emails[1].Attempts != 1 {
+		t.Error("the retry must wait until it is due")
+	}
+	mailer.Errors["rina@example.com"] = &textproto.Error{Code: 550, Msg: "mailbox unavailable"}
+	mockEmailRepo.Emails[1].NextAttemptAt = time.Now().Add(-time.Second)
+	svc.ProcessPayslipEmails(ctx)
+	failed, err := svc.GetPayslipEmails(ctx, 5, domain.PayslipEmailFailed)
+	if err != nil || len(failed) != 1 || failed[0].EmployeeID != 3 || failed[0].Attempts != 2 {
+		t.Errorf("GetPayslipEmails(failed) = %+v, %v, want Rina's email", failed, err)
+	}
+	if _, err := svc.GetPayslipEmails(ctx, 5, "bounced"); err != error_const.ErrInvalidPayslipEmailStatus {
+		t.Errorf("expected ErrInvalidPayslipEmailStatus, got %v", err)
+	}
+
+	// protected payslips open with the employee's own password, so employees without one cannot get them
+	delete(mailer.Errors, "rina@example.com")
+	protect := true
+	resend := dto.PayslipEmailResendRequest{PeriodID: 5, EmployeeID: 3, ProtectAttachment: &protect, ActorEmail: "hr@example.com"}
+	if _, err := svc.ResendPayslipEmail(ctx, dto.PayslipEmailResendRequest{PeriodID: 5, EmployeeID: 2, ActorEmail: "hr@example.com"}); err != error_const.ErrEmployeeEmailMissing {
+		t.Errorf("expected ErrEmployeeEmailMissing, got %v", err)
+	}
+	email, err := svc.ResendPayslipEmail(ctx, resend)
+	if err != nil || email.Status != domain.PayslipEmailQueued || email.Attempts != 0 || !email.Protected {
+		t.Fatalf("ResendPayslipEmail = %+v, %v, want a fresh protected email", email, err)
+	}
+	svc.ProcessPayslipEmails(ctx)
+	if rina := mockEmailRepo.Emails[1]; rina.Status != domain.PayslipEmailFailed || rina.LastError != error_const.ErrNoPayslipPassword.Error() {
+		t.Errorf("Rina's email = %+v, want failed for the missing password", rina)
+	}
+	employeeSvc := employee_service.NewEmployeeService(employee_service.EmployeeDependencies{PayslipPasswordRepository: mockPasswordRepo})
+	if err := employeeSvc.SetPayslipPassword(ctx, dto.PayslipPasswordRequest{EmployeeID: 3, Password: "short"}); err != error_const.ErrPayslipPasswordTooShort {
+		t.Errorf("expected ErrPayslipPasswordTooShort, got %v", err)
+	}
+	if err := employeeSvc.SetPayslipPassword(ctx, dto.PayslipPasswordRequest{EmployeeID: 3, Password: "kucing-oren-3"}); err != nil {
+		t.Fatalf("SetPayslipPassword: %v", err)
+	}
+	if stored := mockPasswordRepo.Passwords[3]; len(stored.User) != 48 || len(stored.Key) != 32 || bytes.Contains(append(stored.User, stored.Key...), []byte("kucing-oren-3")) {
+		t.Errorf("stored password = %+v, want the derived entry and key only", stored)
+	}
+	if _, err := svc.ResendPayslipEmail(ctx, resend); err != nil {
+		t.Fatalf("ResendPayslipEmail: %v", err)
+	}
+	svc.ProcessPayslipEmails(ctx)
+	message = mailer.Sent[len(mailer.Sent)-1]
+	if mockEmailRepo.Emails[1].Status != domain.PayslipEmailSent || !bytes.Contains(message.Attachments[0].Data, []byte("/Encrypt")) ||
+		!bytes.Contains(message.Attachments[0].Data, []byte(fmt.Sprintf("/U <%x>", mockPasswordRepo.Passwords[3].User))) || !strings.Contains(message.Body, "payslip password") {
+		t.Errorf("Rina's email = %+v, want a sent, protected payslip", mockEmailRepo.Emails[1])
+	}
+}
+
+func TestPayrollRunApproval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
+	mockPayrollRepo.PayrollPeriod = domain.PayrollPeriod{
+		ID:        1,
+		StartDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+	mockPayrollRepo.Runs = []domain.PayrollRun{
+		{ID: 1, PeriodID: 1, RunType: domain.PayrollRunRegular, Status: domain.PayrollRunPendingApproval, CreatedBy: "maker@example.com",
+			Payrolls: []domain.Payroll{{EmployeeID: 1, PeriodID: 1}, {EmployeeID: 2, PeriodID: 1}}},
+		{ID: 2, PeriodID: 2, RunType: domain.PayrollRunRegular, Status: domain.PayrollRunPendingApproval, CreatedBy: "maker@example.com",
+			Payrolls: []domain.Payroll{{EmployeeID: 1, PeriodID: 2}}},
+		{ID: 3, PeriodID: 1, RunType: domain.PayrollRunOffCycle, Status: domain.PayrollRunPendingApproval, CreatedBy: "maker@example.com",
+			Reason: "late joiner", Payrolls: []domain.Payroll{{EmployeeID: 3, PeriodID: 1}}},
+	}
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		PayrollRepository: mockPayrollRepo,
+	})
+	ctx := context.Background()
+	if _, err := svc.RunPayrollPeriod(ctx, dto.PayrollRequest{PeriodID: 1, ActorEmail: "maker@example.com"}); err != error_const.ErrPayrollRunPendingApproval {
+		t.Errorf("expected ErrPayrollRunPendingApproval while a run waits for approval, got %v", err)
+	}
+	if _, err := svc.ApprovePayrollRun(ctx, dto.PayrollRunDecisionRequest{RunID: 1, ActorEmail: "Maker@example.com"}); err != error_const.ErrPayrollRunSelfApproval {
+		t.Errorf("expected ErrPayrollRunSelfApproval, got %v", err)
+	}
+	if _, err := svc.ApprovePayrollRun(ctx, dto.PayrollRunDecisionRequest{RunID: 9, ActorEmail: "checker@example.com"}); err != error_const.ErrPayrollRunNotFound {
+		t.Errorf("expected ErrPayrollRunNotFound, got %v", err)
+	}
+	if mockPayrollRepo.PayrollPeriod.Locked || len(mockPayrollRepo.Payrolls) != 0 {
+		t.Fatal("the period must stay open until the run is approved")
+	}
+
+	// off-cycle runs need a second admin as well, and never lock the period
+	if _, err := svc.ApprovePayrollRun(ctx, dto.PayrollRunDecisionRequest{RunID: 3, ActorEmail: "maker@example.com"}); err != error_const.ErrPayrollRunSelfApproval {
+		t.Errorf("off-cycle run: expected ErrPayrollRunSelfApproval, got %v", err)
+	}
+	run, err := svc.ApprovePayrollRun(ctx, dto.PayrollRunDecisionRequest{RunID: 3, ActorEmail: "checker@example.com"})
+	if err != nil {
+		t.Fatalf("ApprovePayrollRun off-cycle: %v", err)
+	}
+	if run.Status != domain.PayrollRunApproved || mockPayrollRepo.PayrollPeriod.Locked || len(mockPayrollRepo.Payrolls) != 1 {
+		t.Errorf("off-cycle run %s, period locked %v, %d payrolls, want approved, open and 1", run.Status, mockPayrollRepo.PayrollPeriod.Locked, len(mockPayrollRepo.Payrolls))
+	}
+
+	run, err = svc.ApprovePayrollRun(ctx, dto.PayrollRunDecisionRequest{RunID: 1, Comment: "checked", ActorEmail: "checker@example.com"})
+	if err != nil {
+		t.Fatalf("ApprovePayrollRun: %v", err)
+	}
+	if run.Status != domain.PayrollRunApproved || !mockPayrollRepo.PayrollPeriod.Locked || len(mockPayrollRepo.Payrolls) != 3 {
+		t.Errorf("run %s, period locked %v, %d payrolls, want approved, locked and 3", run.Status, mockPayrollRepo.PayrollPeriod.Locked, len(mockPayrollRepo.Payrolls))
+	}
+	last := run.Transitions[len(run.Transitions)-1]
+	if last.FromStatus != domain.PayrollRunPendingApproval || last.ToStatus != domain.PayrollRunApproved || last.CreatedBy != "checker@example.com" {
+		t.Errorf("transition = %+v, want pending_approval to approved by the checker", last)
+	}
+	if _, err := svc.RejectPayrollRun(ctx, dto.PayrollRunDecisionRequest{RunID: 1, Comment: "too late", ActorEmail: "checker@example.com"}); err != error_const.ErrPayrollRunNotPending {
+		t.Errorf("expected ErrPayrollRunNotPending, got %v", err)
+	}
+
+	if _, err := svc.RejectPayrollRun(ctx, dto.PayrollRunDecisionRequest{RunID: 2, Comment: " ", ActorEmail: "checker@example.com"}); err != error_const.ErrRejectionCommentRequired {
+		t.Errorf("expected ErrRejectionCommentRequired, got %v", err)
+	}
+	run, err = svc.RejectPayrollRun(ctx, dto.PayrollRunDecisionRequest{RunID: 2, Comment: "overtime missing", ActorEmail: "checker@example.com"})
+	if err != nil || run.Status != domain.PayrollRunRejected || run.Transitions[0].Comment != "overtime missing" {
+		t.Errorf("RejectPayrollRun = %+v, %v, want a rejected run with the comment", run, err)
+	}
+	if len(mockPayrollRepo.Payrolls) != 3 {
+		t.Errorf("rejected payslips must be discarded, got %d payrolls", len(mockPayrollRepo.Payrolls))
+	}
+}
+
+func TestPayrollVariance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	payroll := func(employeeID, periodID int, base int64) domain.Payroll {
+		payslip := domain.Payslip{Lines: []domain.PayslipLine{
+			domain.NewAmountLine(domain.PayslipLineCodeBasePay, domain.PayslipLineEarning, "Base salary", domain.NewMoney(base), true),
+		}}
+		payslip.SetTotalsFromLines()
+		return domain.Payroll{EmployeeID: employeeID, PeriodID: periodID, Payslip: payslip}
+	}
+	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
+	mockPayrollRepo.PayrollPeriod = domain.PayrollPeriod{ID: 2}
+	mockPayrollRepo.Payrolls = []domain.Payroll{payroll(1, 1, 10000000)}
+	mockPayrollRepo.Runs = []domain.PayrollRun{{ID: 5, PeriodID: 2, Status: domain.PayrollRunPendingApproval,
+		Payrolls: []domain.Payroll{payroll(1, 2, 12000000), payroll(2, 2, 5000000)}}}
+	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
+	mockEmpRepo.Employees[1] = &domain.Employee{ID: 1, Name: "Budi"}
+	mockEmpRepo.Employees[2] = &domain.Employee{ID: 2, Name: "Sari"}
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		EmployeeRepository: mockEmpRepo,
+		PayrollRepository:  mockPayrollRepo,
+	})
+	ctx := context.Background()
+	if _, err := svc.GetPayrollVariance(ctx, dto.PayrollVarianceRequest{PeriodID: 2}); err != error_const.ErrPreviousPayrollPeriodNotFound {
+		t.Errorf("expected ErrPreviousPayrollPeriodNotFound, got %v", err)
+	}
+	mockPayrollRepo.PreviousPayrollPeriod = domain.PayrollPeriod{ID: 1}
+	if _, err := svc.GetPayrollVariance(ctx, dto.PayrollVarianceRequest{PeriodID: 2, ChangePercent: "-5"}); err != error_const.ErrInvalidVarianceThreshold {
+		t.Errorf("expected ErrInvalidVarianceThreshold, got %v", err)
+	}
+
+	// the period is not approved yet, so its pending run is compared
+	report, err := svc.GetPayrollVariance(ctx, dto.PayrollVarianceRequest{PeriodID: 2, ChangePercent: "25"})
+	if err != nil {
+		t.Fatalf("GetPayrollVariance: %v", err)
+	}
+	if !report.Pending || report.PreviousPeriodID != 1 || len(report.Employees) != 2 {
+		t.Fatalf("report = %+v, want the pending run compared with period 1", report)
+	}
+	if report.Employees[0].EmployeeName != "Budi" || len(report.Employees[0].Flags) != 0 {
+		t.Errorf("employee 1 = %+v, want a 20%% raise below the 25%% threshold", report.Employees[0])
+	}
+	if report.Employees[1].Status != domain.VarianceStatusNew || report.FlaggedEmployees != 1 {
+		t.Errorf("employee 2 = %+v, want the only flagged employee, as new", report.Employees[1])
+	}
+}
+
+func TestPayrollJournal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	payslip := domain.Payslip{Lines: []domain.PayslipLine{
+		domain.NewAmountLine(domain.PayslipLineCodeBasePay, domain.PayslipLineEarning, "Base salary", domain.NewMoney(10000000), true),
+		domain.NewAmountLine(domain.DeductionCodePPh21, domain.PayslipLineDeduction, "PPh 21", domain.NewMoney(250000), false),
+	}}
+	payslip.SetTotalsFromLines()
+	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
+	mockPayrollRepo.PayrollPeriod = domain.PayrollPeriod{ID: 3, EndDate: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)}
+	mockPayrollRepo.Payrolls = []domain.Payroll{{EmployeeID: 1, PeriodID: 3, Payslip: payslip}}
+	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
+	mockEmpRepo.Employees[1] = &domain.Employee{ID: 1, Name: "Budi", CostCenter: "OPS"}
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		EmployeeRepository: mockEmpRepo,
+		PayrollRepository:  mockPayrollRepo,
+	})
+	ctx := context.Background()
+	if _, err := svc.GetPayrollJournal(ctx, 3); err != error_const.ErrJournalPeriodNotLocked {
+		t.Errorf("expected ErrJournalPeriodNotLocked, got %v", err)
+	}
+	mockPayrollRepo.PayrollPeriod.Locked = true
+	if _, err := svc.GetPayrollJournal(ctx, 3); !errors.Is(err, error_const.ErrGLAccountNotConfigured) {
+		t.Errorf("expected ErrGLAccountNotConfigured, got %v", err)
+	}
+
+	if _, err := svc.SetGLAccount(ctx, dto.GLAccountRequest{Component: "wages", AccountCode: "6100"}); err != error_const.ErrInvalidJournalComponent {
+		t.Errorf("expected ErrInvalidJournalComponent, got %v", err)
+	}
+	for _, account := range []dto.GLAccountRequest{
+		{Component: domain.JournalSalaryExpense, AccountCode: "6100"},
+		{Component: domain.JournalSalaryExpense, CostCenter: "OPS", AccountCode: "6105"},
+		{Component: domain.JournalTaxPayable, AccountCode: "2110"},
+		{Component: domain.JournalNetSalaryPayable, AccountCode: "2100"},
+	} {
+		account.ActorEmail = "admin@example.com"
+		if _, err := svc.SetGLAccount(ctx, account); err != nil {
+			t.Fatalf("SetGLAccount(%+v): %v", account, err)
+		}
+	}
+	journal, err := svc.GetPayrollJournal(ctx, 3)
+	if err != nil {
+		t.Fatalf("GetPayrollJournal: %v", err)
+	}
+	if len(journal.Lines) != 3 || journal.Lines[0].AccountCode != "6105" || journal.Lines[0].CostCenter != "OPS" {
+		t.Fatalf("lines = %+v, want salary posted to the OPS account", journal.Lines)
+	}
+	if journal.TotalDebit.String() != "10000000.00" || journal.TotalCredit != journal.TotalDebit {
+		t.Errorf("totals = %s / %s, want a balanced 10000000.00", journal.TotalDebit, journal.TotalCredit)
+	}
+	if err := svc.DeleteGLAccount(ctx, 99); err != error_const.ErrGLAccountNotFound {
+		t.Errorf("expected ErrGLAccountNotFound, got %v", err)
+	}
+}
+
+func TestDisbursement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	payroll := func(employeeID int, net int64) domain.Payroll {
+		return domain.Payroll{EmployeeID: employeeID, PeriodID: 4, Payslip: domain.Payslip{NetSalary: domain.NewMoney(net)}}
+	}
+	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
+	mockPayrollRepo.PayrollPeriod = domain.PayrollPeriod{ID: 4}
+	correction := payroll(1, -200000)
+	correction.RunType = domain.PayrollRunOffCycle
+	mockPayrollRepo.Payrolls = []domain.Payroll{payroll(2, 6350000), payroll(1, 9450000), correction, payroll(3, 0)}
+	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
+	mockEmpRepo.Employees[1] = &domain.Employee{ID: 1, Name: "Budi"}
+	mockEmpRepo.Employees[2] = &domain.Employee{ID: 2, Name: "Sari"}
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		EmployeeRepository: mockEmpRepo,
+		PayrollRepository:  mockPayrollRepo,
+	})
+	ctx := context.Background()
+	request := dto.DisbursementRequest{PeriodID: 4, Format: "fixed_width", ValueDate: "2025-04-30", ActorEmail: "admin@example.com"}
+	if _, err := svc.CreateDisbursement(ctx, dto.DisbursementRequest{PeriodID: 4, Format: "mt101"}); err != error_const.ErrUnknownDisbursementFormat {
+		t.Errorf("expected ErrUnknownDisbursementFormat, got %v", err)
+	}
+	if _, err := svc.CreateDisbursement(ctx, request); err != error_const.ErrDisbursementPeriodNotLocked {
+		t.Errorf("expected ErrDisbursementPeriodNotLocked, got %v", err)
+	}
+	mockPayrollRepo.PayrollPeriod.Locked = true
+
+	if _, err := svc.SetBankAccount(ctx, dto.BankAccountRequest{EmployeeID: 1, BankCode: "014", AccountNumber: "12AB", AccountHolder: "Budi"}); err != error_const.ErrInvalidBankAccountNumber {
+		t.Errorf("expected ErrInvalidBankAccountNumber, got %v", err)
+	}
+	account, err := svc.SetBankAccount(ctx, dto.BankAccountRequest{EmployeeID: 1, BankCode: "014", AccountNumber: "123-456 7890", AccountHolder: " Budi Santoso ", ActorEmail: "admin@example.com"})
+	if err != nil || account.AccountNumber != "1234567890" || account.AccountHolder != "Budi Santoso" {
+		t.Fatalf("SetBankAccount = %+v, %v, want the number without separators", account, err)
+	}
+	if _, err := svc.CreateDisbursement(ctx, request); !errors.Is(err, error_const.ErrBankAccountMissing) {
+		t.Errorf("expected ErrBankAccountMissing for employee 2, got %v", err)
+	}
+	if _, err := svc.SetBankAccount(ctx, dto.BankAccountRequest{EmployeeID: 2, BankCode: "008", AccountNumber: "9876543210", AccountHolder: "Sari Dewi", ActorEmail: "admin@example.com"}); err != nil {
+		t.Fatalf("SetBankAccount: %v", err)
+	}
+
+	batch, err := svc.CreateDisbursement(ctx, request)
+	if err != nil {
+		t.Fatalf("CreateDisbursement: %v", err)
+	}
+	// employee 1 is paid once, net of the correction; employee 3 has no net pay, so needs no
+	// bank account and is left out
+	if batch.ItemCount != 2 || batch.TotalAmount.String() != "15600000.00" || batch.Items[0].Amount.String() != "9250000.00" || batch.Items[1].EmployeeName != "Sari" {
+		t.Fatalf("batch = %+v, want employees 1 and 2 totalling 15600000.00", batch)
+	}
+	if batch.Checksum != domain.DisbursementChecksum(batch.Items) || batch.ValueDate.Format("2006-01-02") != "2025-04-30" {
+		t.Errorf("batch = %+v, want the items' checksum and the requested value date", batch)
+	}
+	if _, err := svc.CreateDisbursement(ctx, request); err != error_const.ErrPeriodAlreadyDisbursed {
+		t.Errorf("expected ErrPeriodAlreadyDisbursed, got %v", err)
+	}
+	_, file, format, err := svc.GetDisbursementFile(ctx, batch.ID)
+	if err != nil || format.Extension() != "txt" || !bytes.HasPrefix(file, []byte("H"+batch.Reference)) {
+		t.Errorf("GetDisbursementFile = %q, %v, want a fixed-width file", file, err)
+	}
+
+	if _, err := svc.CancelDisbursement(ctx, dto.CancelDisbursementRequest{BatchID: batch.ID}); err != error_const.ErrCancelReasonRequired {
+		t.Errorf("expected ErrCancelReasonRequired, got %v", err)
+	}
+	cancelled, err := svc.CancelDisbursement(ctx, dto.CancelDisbursementRequest{BatchID: batch.ID, Reason: "wrong value date", ActorEmail: "admin@example.com"})
+	if err != nil || cancelled.Status != domain.DisbursementBatchCancelled {
+		t.Fatalf("CancelDisbursement = %+v, %v", cancelled, err)
+	}
+	request.Format = "csv"
+	if _, err := svc.CreateDisbursement(ctx, request); err != nil {
+		t.Errorf("expected a cancelled batch to allow a new export, got %v", err)
+	}
+
+	// an off-cycle payslip approved after the export is paid by a supplementary batch
+	bonus := payroll(2, 100000)
+	bonus.RunType = domain.PayrollRunOffCycle
+	mockPayrollRepo.Payrolls = append(mockPayrollRepo.Payrolls, bonus)
+	supplementary, err := svc.CreateDisbursement(ctx, request)
+	if err != nil || supplementary.ItemCount != 1 || supplementary.Items[0].EmployeeID != 2 || supplementary.TotalAmount.String() != "100000.00" {
+		t.Fatalf("CreateDisbursement = %+v, %v, want only the 100000.00 not yet paid to employee 2", supplementary, err)
+	}
+
+	mockPayrollRepo.Payrolls[0].Payslip.Currency = "USD"
+	if _, err := svc.CreateDisbursement(ctx, request); err != error_const.ErrMixedDisbursementCurrencies {
+		t.Errorf("expected ErrMixedDisbursementCurrencies, got %v", err)
+	}
+}
+
+func TestReconciliation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
+	mockPayrollRepo.PayrollPeriod = domain.PayrollPeriod{ID: 4, Locked: true}
+	mockPayrollRepo.Payrolls = []domain.Payroll{
+		{EmployeeID: 1, PeriodID: 4, Payslip: domain.Payslip{NetSalary: domain.NewMoney(9450000)}},
+		{EmployeeID: 2, PeriodID: 4, Payslip: domain.Payslip{NetSalary: domain.NewMoney(6350000)}},
+	}
+	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
+	mockEmpRepo.Employees[1] = &domain.Employee{ID: 1, Name: "Budi"}
+	mockEmpRepo.Employees[2] = &domain.Employee{ID: 2, Name: "Sari"}
+
+	svc := newAdminService(t, admin_service.AdminDependencies{
+		EmployeeRepository: mockEmpRepo,
+		PayrollRepository:  mockPayrollRepo,
+	})
+	ctx := context.Background()
+	for _, account := range []dto.BankAccountRequest{
+		{EmployeeID: 1, BankCode: "014", AccountNumber: "1234567890", AccountHolder: "Budi Santoso", ActorEmail: "admin@example.com"},
+		{EmployeeID: 2, BankCode: "008", AccountNumber: "9876543210", AccountHolder: "Sari Dewi", ActorEmail: "admin@example.com"},
+	} {
+		if _, err := svc.SetBankAccount(ctx, account); err != nil {
+			t.Fatalf("SetBankAccount: %v", err)
+		}
+	}
+	batch, err := svc.CreateDisbursement(ctx, dto.DisbursementRequest{PeriodID: 4, Format: "csv", ActorEmail: "admin@example.com"})
+	if err != nil {
+		t.Fatalf("CreateDisbursement: %v", err)
+	}
+	if _, err := svc.MarkDisbursementSent(ctx, dto.DisbursementSentRequest{BatchID: batch.ID, ActorEmail: "admin@example.com"}); err != nil {
+		t.Fatalf("MarkDisbursementSent: %v", err)
+	}
+	if _, err := svc.CancelDisbursement(ctx, dto.CancelDisbursementRequest{BatchID: batch.ID, Reason: "too late"}); err != error_const.ErrDisbursementBatchSent {
+		t.Errorf("expected ErrDisbursementBatchSent, got %v", err)
+	}
+
+	statement := "date,amount,reference,description\n" +
+		"2025-04-30,-9450000.00,TRF001,SALARY 1234567890\n" +
+		"2025-04-30,-6350000.00,TRF002,SALARY 9876543210\n" +
+		"2025-05-02,6350000.00,RTN77,RETURN 9876543210 ACCOUNT CLOSED\n" +
+		"2025-05-02,-25000.00,FEE,TRANSFER FEE\n"
+	imported, err := svc.ImportBankStatement(ctx, dto.BankStatementImportRequest{Format: "csv", Content: statement, ActorEmail: "admin@example.com"})
+	if err != nil {
+		t.Fatalf("ImportBankStatement: %v", err)
+	}
+	if imported.EntryCount != 4 || imported.MatchedCount != 3 || imported.DuplicateCount != 0 {
+		t.Errorf("import = %+v, want 3 of 4 entries matched", imported)
+	}
+	again, err := svc.ImportBankStatement(ctx, dto.BankStatementImportRequest{Format: "csv", Content: statement, ActorEmail: "admin@example.com"})
+	if err != nil || again.DuplicateCount != 4 || again.MatchedCount != 0 {
+		t.Errorf("import again = %+v, %v, want every entry skipped as a duplicate", again, err)
+	}
+
+	report, err := svc.GetReconciliationReport(ctx, 4)
+	if err != nil {
+		t.Fatalf("GetReconciliationReport: %v", err)
+	}
+	if len(report.ToReissue) != 1 || report.ToReissue[0].EmployeeID != 2 || report.ToReissue[0].Status != domain.PaymentReturned {
+		t.Errorf("to reissue = %+v, want Sari's returned payment", report.ToReissue)
+	}
+	if len(report.Unconfirmed) != 0 || len(report.UnmatchedEntries) != 1 || report.UnmatchedEntries[0].Reference != "FEE" {
+		t.Errorf("report = %+v, want no unconfirmed payments and the fee unmatched", report)
+	}
+	for _, total := range report.Totals {
+		if total.Status == domain.PaymentPaid && (total.ItemCount != 1 || total.Amount.String() != "9450000.00") {
+			t.Errorf("paid total = %+v, want Budi's payment", total)
+		}
+	}
+
+	paidItem := batch.Items[0].ID // Budi's
+	if _, err := svc.UpdatePaymentStatus(ctx, dto.PaymentStatusRequest{BatchID: batch.ID, ItemID: paidItem, Status: "cancelled"}); err != error_const.ErrInvalidPaymentStatus {
+		t.Errorf("expected ErrInvalidPaymentStatus, got %v", err)
+	}
+	if _, err := svc.UpdatePaymentStatus(ctx, dto.PaymentStatusRequest{BatchID: batch.ID, ItemID: paidItem, Status: domain.PaymentFailed}); err != error_const.ErrPaymentStatusChange {
+		t.Errorf("expected ErrPaymentStatusChange for a paid payment, got %v", err)
+	}
+	if _, err := svc.UpdatePaymentStatus(ctx, dto.PaymentStatusRequest{BatchID: batch.ID, ItemID: 99, Status: domain.PaymentPaid}); err != error_const.ErrDisbursementItemNotFound {
+		t.Errorf("expected ErrDisbursementItemNotFound, got %v", err)
+	}
+
+	// the next run pays Sari again once her account is fixed
+	if _, err := svc.SetBankAccount(ctx, dto.BankAccountRequest{EmployeeID: 2, BankCode: "009", AccountNumber: "5556667778", AccountHolder: "Sari Dewi", ActorEmail: "admin@example.com"}); err != nil {
+		t.Fatalf("SetBankAccount: %v", err)
+	}
+	mockPayrollRepo.PayrollPeriod = domain.PayrollPeriod{ID: 5, Locked: true}
+	mockPayrollRepo.Payrolls = []domain.Payroll{
+		{EmployeeID: 1, PeriodID: 5, Payslip: domain.Payslip{NetSalary: domain.NewMoney(9450000)}},
+	}
+	next, err := svc.CreateDisbursement(ctx, dto.DisbursementRequest{PeriodID: 5, Format: "csv", Reissue: true, ActorEmail: "admin@example.com"})
+	if err != nil {
+		t.Fatalf("CreateDisbursement with reissue: %v", err)
+	}
+	if next.ItemCount != 2 || next.TotalAmount.String() != "15800000.00" || next.Items[1].ReissueOfItemID != report.ToReissue[0].ID ||
+		next.Items[1].AccountNumber != "5556667778" {
+		t.Fatalf("batch = %+v, want Sari's returned payment paid again to her new account", next)
+	}
+	report, _ = svc.GetReconciliationReport(ctx, 4)
+	if len(report.ToReissue) != 0 {
+		t.Errorf("to reissue = %+v, want none after the reissue", report.ToReissue)
+	}
+}
@@ -1,15 +1,19 @@
 package tests
 
 import (
+	"bytes"
 	"context"
 	"payroll-system/internal/delivery/dto"
 	"payroll-system/internal/domain"
 	"payroll-system/internal/error_const"
 	"payroll-system/internal/mocks"
 	employee_service "payroll-system/internal/service/employee"
+	"payroll-system/internal/utils"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
 )
 
 func TestLoginAsEmployee_InvalidCredentials(t *testing.T) {
@@ -19,39 +23,83 @@ func TestLoginAsEmployee_InvalidCredentials(t *testing.T) {
 	mockEmpRepo.Employee = domain.Employee{}
 	mockEmpRepo.Err = error_const.ErrInvalidCredentials
 
-	svc := employee_service.NewEmployeeService(
-		mockEmpRepo, nil, nil, nil, nil,
-	)
+	svc := employee_service.NewEmployeeService(employee_service.EmployeeDependencies{
+		EmployeeRepository: mockEmpRepo,
+	})
 	_, err := svc.LoginAsEmployee(context.Background(), dto.LoginRequest{Email: "", Password: ""})
 	if err == nil {
 		t.Error("expected error for invalid credentials")
 	}
 }
 
+func TestLoginAsEmployee_Inactive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	hash, err := utils.HashPassword("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
+	mockEmpRepo.Employee = domain.Employee{ID: 1, Email: "emp@example.com", Password_hash: hash, EmploymentStatus: domain.EmploymentStatusInactive}
+
+	svc := employee_service.NewEmployeeService(employee_service.EmployeeDependencies{
+		EmployeeRepository: mockEmpRepo,
+	})
+	_, err = svc.LoginAsEmployee(context.Background(), dto.LoginRequest{Email: "emp@example.com", Password: "secret"})
+	if err != error_const.ErrEmployeeInactive {
+		t.Errorf("expected ErrEmployeeInactive, got %v", err)
+	}
+}
+
 func TestGetPayslip_NotFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
 	mockPayrollRepo.Err = error_const.ErrPayslipNotFound
 
-	svc := employee_service.NewEmployeeService(
-		nil, mockPayrollRepo, nil, nil, nil,
-	)
+	svc := employee_service.NewEmployeeService(employee_service.EmployeeDependencies{
+		PayrollRepository: mockPayrollRepo,
+	})
 	_, err := svc.GetPayslip(context.Background(), dto.PayrollRequest{EmployeeID: 0, PeriodID: 0})
 	if err == nil {
 		t.Error("expected error for payslip not found")
 	}
 }
 
+func TestGetPayslipPDF(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
+	mockEmpRepo.Employee = domain.Employee{ID: 1, Name: "Budi", Email: "budi@example.com"}
+	mockPayrollRepo := mocks.NewMockPayrollRepository(ctrl)
+	mockPayrollRepo.PayrollPeriod = domain.PayrollPeriod{ID: 4, StartDate: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC)}
+	mockPayrollRepo.Payslip = domain.Payroll{ID: 9, EmployeeID: 1, PeriodID: 4, Payslip: domain.Payslip{Currency: "IDR", NetSalary: domain.NewMoney(5000000)}}
+
+	svc := employee_service.NewEmployeeService(employee_service.EmployeeDependencies{
+		EmployeeRepository: mockEmpRepo,
+		PayrollRepository:  mockPayrollRepo,
+	})
+	pdf, err := svc.GetPayslipPDF(context.Background(), dto.PayrollRequest{EmployeeID: 1, PeriodID: 4, ActorEmail: "budi@example.com"})
+	if err != nil || !bytes.HasPrefix(pdf, []byte("%PDF-")) || !bytes.Contains(pdf, []byte("(In words: Five million rupiah)")) {
+		t.Errorf("GetPayslipPDF = %d bytes, %v, want a PDF with the net pay in words", len(pdf), err)
+	}
+
+	mockPayrollRepo.Err = pgx.ErrNoRows
+	if _, err := svc.GetPayslipPDF(context.Background(), dto.PayrollRequest{EmployeeID: 1, PeriodID: 5}); err != error_const.ErrPayslipNotFound {
+		t.Errorf("expected ErrPayslipNotFound, got %v", err)
+	}
+}
+
 func TestRecordAttendance_InvalidEmployeeID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
 	mockAttendanceRepo := mocks.NewMockAttendanceRepository(ctrl)
 
-	svc := employee_service.NewEmployeeService(
-		mockEmpRepo, nil, mockAttendanceRepo, nil, nil,
-	)
+	svc := employee_service.NewEmployeeService(employee_service.EmployeeDependencies{
+		EmployeeRepository:   mockEmpRepo,
+		AttendanceRepository: mockAttendanceRepo,
+	})
 	err := svc.RecordAttendance(context.Background(), dto.AttendanceRequest{EmployeeID: 0, Date: "2025-06-04"})
 	if err != error_const.ErrInvalidCredentials {
 		t.Errorf("expected ErrInvalidCredentials, got %v", err)
@@ -64,9 +112,10 @@ func TestSubmitOvertime_InvalidHours(t *testing.T) {
 	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
 	mockOvertimeRepo := mocks.NewMockOvertimeRepository(ctrl)
 
-	svc := employee_service.NewEmployeeService(
-		mockEmpRepo, nil, nil, mockOvertimeRepo, nil,
-	)
+	svc := employee_service.NewEmployeeService(employee_service.EmployeeDependencies{
+		EmployeeRepository: mockEmpRepo,
+		OvertimeRepository: mockOvertimeRepo,
+	})
 	err := svc.SubmitOvertime(context.Background(), dto.OvertimeRequest{EmployeeID: 1, Hours: 0})
 	if err != error_const.ErrInvalidOvertimeHours {
 		t.Errorf("expected ErrInvalidOvertimeHours, got %v", err)
@@ -79,11 +128,31 @@ func TestSubmitReimbursement_InvalidAmount(t *testing.T) {
 	mockEmpRepo := mocks.NewMockEmployeeRepository(ctrl)
 	mockReimbursementRepo := mocks.NewMockReimbursementRepository(ctrl)
 
-	svc := employee_service.NewEmployeeService(
-		mockEmpRepo, nil, nil, nil, mockReimbursementRepo,
-	)
-	err := svc.SubmitReimbursement(context.Background(), dto.ReimbursementRequest{EmployeeID: 1, Amount: 0})
+	svc := employee_service.NewEmployeeService(employee_service.EmployeeDependencies{
+		EmployeeRepository:      mockEmpRepo,
+		ReimbursementRepository: mockReimbursementRepo,
+	})
+	err := svc.SubmitReimbursement(context.Background(), dto.ReimbursementRequest{EmployeeID: 1, Amount: domain.NewMoney(0)})
 	if err != error_const.ErrInvalidReimbursementAmount {
 		t.Errorf("expected ErrInvalidReimbursementAmount, got %v", err)
 	}
 }
+
+func TestRecordAttendance_OnHoliday(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAttendanceRepo := mocks.NewMockAttendanceRepository(ctrl)
+	mockHolidayRepo := mocks.NewMockHolidayRepository(ctrl)
+	mockHolidayRepo.Holidays = []domain.Holiday{
+		{Date: time.Date(2025, 6, 6, 0, 0, 0, 0, time.UTC), Name: "Idul Adha", Type: domain.HolidayTypeNational},
+	}
+
+	svc := employee_service.NewEmployeeService(employee_service.EmployeeDependencies{
+		AttendanceRepository: mockAttendanceRepo,
+		HolidayRepository:    mockHolidayRepo,
+	})
+	err := svc.RecordAttendance(context.Background(), dto.AttendanceRequest{EmployeeID: 1, Date: "2025-06-06"})
+	if err != error_const.ErrAttendanceOnHoliday {
+		t.Errorf("expected ErrAttendanceOnHoliday, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"payroll-system/internal/error_const"
+)
+
+// keySecretMinLength is the shortest server secret accepted for sealing keys.
+const keySecretMinLength = 32
+
+var errSealedKeyInvalid = errors.New("sealed key cannot be opened with this secret")
+
+// KeySealer encrypts keys kept in the database with a server-side secret, AES-256-GCM under the
+// SHA-256 of the secret, so a copy of the database alone does not give them away. Each key is
+// sealed for a context, e.g. its owner, and only opens for the same context.
+type KeySealer struct {
+	aead cipher.AEAD
+}
+
+func NewKeySealer(secret string) (*KeySealer, error) {
+	if len(secret) < keySecretMinLength {
+		return nil, error_const.ErrInvalidPayslipKeySecret
+	}
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &KeySealer{aead: aead}, nil
+}
+
+// Seal encrypts the key for the context, the random nonce first.
+func (s *KeySealer) Seal(key, context []byte) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("seal key: %w", err)
+	}
+	return s.aead.Seal(nonce, nonce, key, context), nil
+}
+
+// Open decrypts a key sealed for the context.
+func (s *KeySealer) Open(sealed, context []byte) ([]byte, error) {
+	if len(sealed) < s.aead.NonceSize() {
+		return nil, errSealedKeyInvalid
+	}
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	key, err := s.aead.Open(nil, nonce, ciphertext, context)
+	if err != nil {
+		return nil, errSealedKeyInvalid
+	}
+	return key, nil
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"bytes"
+	"payroll-system/internal/error_const"
+	"testing"
+)
+
+func TestKeySealer(t *testing.T) {
+	if _, err := NewKeySealer("too short"); err != error_const.ErrInvalidPayslipKeySecret {
+		t.Errorf("expected ErrInvalidPayslipKeySecret, got %v", err)
+	}
+	sealer, err := NewKeySealer("0123456789abcdef0123456789abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := bytes.Repeat([]byte{7}, 32)
+	sealed, err := sealer.Seal(key, []byte("employee:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(sealed, key) {
+		t.Error("the sealed key must not contain the key")
+	}
+	opened, err := sealer.Open(sealed, []byte("employee:1"))
+	if err != nil || !bytes.Equal(opened, key) {
+		t.Errorf("Open = %x, %v, want the key", opened, err)
+	}
+	if _, err := sealer.Open(sealed, []byte("employee:2")); err == nil {
+		t.Error("a key sealed for one employee must not open for another")
+	}
+	other, _ := NewKeySealer("another secret of at least 32 characters")
+	if _, err := other.Open(sealed, []byte("employee:1")); err == nil {
+		t.Error("a key must not open with another secret")
+	}
+}
@@ -0,0 +1,229 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MailMessage is a plain text email with optional attachments.
+type MailMessage struct {
+	To          string
+	Subject     string
+	Body        string
+	Attachments []MailAttachment
+}
+
+type MailAttachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// How the connection to the SMTP server is encrypted.
+const (
+	SMTPTLSStartTLS = "starttls" // upgrade after connecting; fails when the server does not offer it
+	SMTPTLSImplicit = "implicit" // TLS from the start, usually on port 465
+	SMTPTLSNone     = "none"     // plain text, only for local catchers such as Mailpit
+)
+
+// ErrSMTPStartTLSUnavailable is returned when TLS is required but the server does not offer
+// STARTTLS, so the message is not sent in plain text.
+var ErrSMTPStartTLSUnavailable = errors.New("SMTP server does not offer STARTTLS")
+
+func IsValidSMTPTLS(mode string) bool {
+	switch mode {
+	case SMTPTLSStartTLS, SMTPTLSImplicit, SMTPTLSNone:
+		return true
+	}
+	return false
+}
+
+// SMTPConfig is the server outgoing mail is sent through. Without a username the mail is sent
+// unauthenticated, as local catchers such as Mailpit expect.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	TLS      string // defaults to SMTPTLSStartTLS
+	Username string
+	Password string
+	From     string
+	Timeout  time.Duration // per message, defaults to 30 seconds
+}
+
+// SMTPMailer sends mail through an SMTP server over TLS, unless the config turns it off.
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+func NewSMTPMailer(config SMTPConfig) *SMTPMailer {
+	if config.TLS == "" {
+		config.TLS = SMTPTLSStartTLS
+	}
+	if config.Port == 0 {
+		config.Port = 587
+		if config.TLS == SMTPTLSImplicit {
+			config.Port = 465
+		}
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+	return &SMTPMailer{config: config}
+}
+
+// Send delivers the message in one SMTP session. Errors the server answers with keep their
+// *textproto.Error, see IsPermanentMailError.
+func (m *SMTPMailer) Send(ctx context.Context, message MailMessage) error {
+	from, err := mail.ParseAddress(m.config.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", m.config.From, err)
+	}
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient %q: %w", message.To, err)
+	}
+	data, err := buildMailMessage(from, to, message)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.config.Timeout)
+	defer cancel()
+	address := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	tlsConfig := &tls.Config{ServerName: m.config.Host}
+	var conn net.Conn
+	if m.config.TLS == SMTPTLSImplicit {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = new(net.Dialer).DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if m.config.TLS == SMTPTLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return ErrSMTPStartTLSUnavailable
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if m.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// IsPermanentMailError reports whether the server rejected the message for good, e.g. an
+// unknown mailbox, so sending it again would not help.
+func IsPermanentMailError(err error) bool {
+	var smtpErr *textproto.Error
+	return errors.As(err, &smtpErr) && smtpErr.Code >= 500
+}
+
+// buildMailMessage writes the message as MIME: a quoted-printable text part followed by the
+// base64 encoded attachments.
+func buildMailMessage(from, to *mail.Address, message MailMessage) ([]byte, error) {
+	var out bytes.Buffer
+	body := multipart.NewWriter(&out)
+	header := func(name, value string) {
+		fmt.Fprintf(&out, "%s: %s\r\n", name, value)
+	}
+	header("From", from.String())
+	header("To", to.String())
+	header("Subject", mime.QEncoding.Encode("utf-8", message.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", messageID(from.Address))
+	header("MIME-Version", "1.0")
+	header("Content-Type", fmt.Sprintf("multipart/mixed; boundary=%q", body.Boundary()))
+	out.WriteString("\r\n")
+
+	part, err := body.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	text := quotedprintable.NewWriter(part)
+	if _, err := text.Write([]byte(message.Body)); err != nil {
+		return nil, err
+	}
+	if err := text.Close(); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range message.Attachments {
+		part, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(attachment.ContentType, map[string]string{"name": attachment.Filename})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoded := base64.StdEncoding.EncodeToString(attachment.Data)
+		for len(encoded) > 76 {
+			fmt.Fprintf(part, "%s\r\n", encoded[:76])
+			encoded = encoded[76:]
+		}
+		fmt.Fprintf(part, "%s\r\n", encoded)
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func messageID(sender string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(sender, "@"); at >= 0 {
+		domain = sender[at+1:]
+	}
+	random := make([]byte, 12)
+	_, _ = rand.Read(random)
+	return fmt.Sprintf("<%d.%x@%s>", time.Now().UnixNano(), random, domain)
+}
@@ -0,0 +1,175 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+// smtpCatcher is a minimal SMTP server that keeps the messages it receives, like a local
+// mail catcher. Recipients in reject are refused with a permanent error.
+type smtpCatcher struct {
+	listener net.Listener
+	messages chan []byte
+	reject   map[string]bool
+}
+
+func newSMTPCatcher(t *testing.T) *smtpCatcher {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &smtpCatcher{listener: listener, messages: make(chan []byte, 10), reject: map[string]bool{}}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go c.serve(conn)
+		}
+	}()
+	return c
+}
+
+func (c *smtpCatcher) port() int {
+	return c.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (c *smtpCatcher) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+	reply("220 catcher ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250-catcher")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			if c.reject[strings.Trim(strings.TrimSpace(line)[8:], "<>")] {
+				reply("550 mailbox unavailable")
+			} else {
+				reply("250 OK")
+			}
+		case command == "DATA":
+			reply("354 go ahead")
+			var data bytes.Buffer
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			c.messages <- data.Bytes()
+			reply("250 queued")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPMailer(t *testing.T) {
+	catcher := newSMTPCatcher(t)
+	mailer := NewSMTPMailer(SMTPConfig{Host: "127.0.0.1", Port: catcher.port(), TLS: SMTPTLSNone, From: "Payroll <payroll@example.com>"})
+	pdf := bytes.Repeat([]byte("%PDF-1.4 payslip "), 20)
+	err := mailer.Send(context.Background(), MailMessage{
+		To:          "Budi Santoso <budi@example.com>",
+		Subject:     "Slip gaji April 2025 – PT Maju",
+		Body:        "Dear Budi,\n\nYour payslip is attached.\n",
+		Attachments: []MailAttachment{{Filename: "payslip-2025-04.pdf", ContentType: "application/pdf", Data: pdf}},
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(<-catcher.messages))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if subject != "Slip gaji April 2025 – PT Maju" || msg.Header.Get("To") != `"Budi Santoso" <budi@example.com>` {
+		t.Errorf("subject %q, to %q", subject, msg.Header.Get("To"))
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("content type %q: %v", mediaType, err)
+	}
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	text, err := parts.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(text)
+	if string(body) != "Dear Budi,\r\n\r\nYour payslip is attached.\r\n" {
+		t.Errorf("body = %q", body)
+	}
+	attachment, err := parts.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(base64.NewDecoder(base64.StdEncoding, attachment))
+	if attachment.FileName() != "payslip-2025-04.pdf" || !bytes.Equal(data, pdf) {
+		t.Errorf("attachment %q has %d bytes, want the PDF", attachment.FileName(), len(data))
+	}
+}
+
+func TestSMTPMailerErrors(t *testing.T) {
+	catcher := newSMTPCatcher(t)
+	catcher.reject["gone@example.com"] = true
+	mailer := NewSMTPMailer(SMTPConfig{Host: "127.0.0.1", Port: catcher.port(), TLS: SMTPTLSNone, From: "payroll@example.com"})
+
+	err := mailer.Send(context.Background(), MailMessage{To: "gone@example.com", Subject: "Payslip"})
+	if err == nil || !IsPermanentMailError(err) {
+		t.Errorf("expected a permanent error for a rejected mailbox, got %v", err)
+	}
+	if err := mailer.Send(context.Background(), MailMessage{To: "not an address"}); err == nil {
+		t.Error("expected an error for an invalid recipient")
+	}
+
+	// a server that is down is worth trying again later
+	closed := NewSMTPMailer(SMTPConfig{Host: "127.0.0.1", Port: catcher.port(), TLS: SMTPTLSNone, From: "payroll@example.com"})
+	catcher.listener.Close()
+	err = closed.Send(context.Background(), MailMessage{To: "budi@example.com", Subject: "Payslip"})
+	if err == nil || IsPermanentMailError(err) {
+		t.Errorf("expected a temporary error, got %v", err)
+	}
+}
+
+func TestSMTPMailerRequiresTLS(t *testing.T) {
+	catcher := newSMTPCatcher(t)
+	mailer := NewSMTPMailer(SMTPConfig{Host: "127.0.0.1", Port: catcher.port(), From: "payroll@example.com"})
+
+	// the catcher does not offer STARTTLS, so the payslip must not go out in plain text
+	err := mailer.Send(context.Background(), MailMessage{To: "budi@example.com", Subject: "Payslip"})
+	if !errors.Is(err, ErrSMTPStartTLSUnavailable) {
+		t.Fatalf("expected ErrSMTPStartTLSUnavailable, got %v", err)
+	}
+	select {
+	case <-catcher.messages:
+		t.Error("the message was sent without TLS")
+	default:
+	}
+}
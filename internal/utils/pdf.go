@@ -0,0 +1,240 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A4 page size in points.
+const (
+	PDFPageWidth  = 595.28
+	PDFPageHeight = 841.89
+)
+
+type PDFFont int
+
+const (
+	PDFFontRegular PDFFont = iota // Helvetica
+	PDFFontBold                   // Helvetica-Bold
+)
+
+// PDF writes simple text documents with the standard Helvetica fonts, which every PDF reader
+// has built in, so no fonts need to be embedded. Coordinates are in points from the top left
+// corner of the page.
+type PDF struct {
+	title    string
+	pages    []*bytes.Buffer
+	font     PDFFont
+	fontSize float64
+	password PDFPassword
+}
+
+func NewPDF(title string) *PDF {
+	return &PDF{title: title, font: PDFFontRegular, fontSize: 10}
+}
+
+// SetPassword protects the document so readers ask for the password before showing it. The
+// zero PDFPassword leaves the document unprotected.
+func (p *PDF) SetPassword(password PDFPassword) {
+	p.password = password
+}
+
+// AddPage starts a new page; drawing goes to the last page added.
+func (p *PDF) AddPage() {
+	p.pages = append(p.pages, new(bytes.Buffer))
+}
+
+func (p *PDF) SetFont(font PDFFont, size float64) {
+	p.font, p.fontSize = font, size
+}
+
+// Text draws the text with its baseline at y, starting at x.
+func (p *PDF) Text(x, y float64, text string) {
+	page := p.page()
+	fmt.Fprintf(page, "BT /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		int(p.font)+1, pdfNumber(p.fontSize), pdfNumber(x), pdfNumber(PDFPageHeight-y), pdfEscape(winAnsi(text)))
+}
+
+// TextRight draws the text so that it ends at x, e.g. for amounts in a column.
+func (p *PDF) TextRight(x, y float64, text string) {
+	p.Text(x-p.TextWidth(text), y, text)
+}
+
+// TextWidth returns the width of the text in the current font and size.
+func (p *PDF) TextWidth(text string) float64 {
+	widths := &helveticaWidths
+	if p.font == PDFFontBold {
+		widths = &helveticaBoldWidths
+	}
+	var units int
+	for _, c := range winAnsi(text) {
+		if c >= 32 && c <= 126 {
+			units += widths[c-32]
+		} else {
+			units += 556
+		}
+	}
+	return float64(units) * p.fontSize / 1000
+}
+
+// Line draws a thin line between the two points.
+func (p *PDF) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(p.page(), "0.5 w %s %s m %s %s l S\n",
+		pdfNumber(x1), pdfNumber(PDFPageHeight-y1), pdfNumber(x2), pdfNumber(PDFPageHeight-y2))
+}
+
+func (p *PDF) page() *bytes.Buffer {
+	if len(p.pages) == 0 {
+		p.AddPage()
+	}
+	return p.pages[len(p.pages)-1]
+}
+
+// Bytes returns the finished document, encrypted when it has a password.
+func (p *PDF) Bytes() ([]byte, error) {
+	p.page()
+	var encryption *pdfEncryption
+	if p.password.User != nil {
+		var err error
+		if encryption, err = newPDFEncryption(p.password); err != nil {
+			return nil, err
+		}
+	}
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// AES-256 encryption came with PDF 2.0, which readers know as the extension level 8 of 1.7
+	catalog := "<< /Type /Catalog /Pages 2 0 R >>"
+	if encryption == nil {
+		out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	} else {
+		out.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+		catalog = "<< /Type /Catalog /Pages 2 0 R /Extensions << /ADBE << /BaseVersion /1.7 /ExtensionLevel 8 >> >> >>"
+	}
+	// objects 1-4 are the catalog, page tree, fonts and info; each page is followed by its content
+	kids := make([]string, len(p.pages))
+	for i := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	object(catalog)
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	if encryption == nil {
+		object(fmt.Sprintf("<< /Title (%s) /Producer (payroll-system) >>", pdfEscape(winAnsi(p.title))))
+	} else {
+		title, err := encryption.encrypt(winAnsi(p.title))
+		if err != nil {
+			return nil, err
+		}
+		producer, err := encryption.encrypt([]byte("payroll-system"))
+		if err != nil {
+			return nil, err
+		}
+		object(fmt.Sprintf("<< /Title <%x> /Producer <%x> >>", title, producer))
+	}
+	for i, content := range p.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfNumber(PDFPageWidth), pdfNumber(PDFPageHeight), 7+2*i))
+		// content ends with a newline; encrypted content needs one before endstream
+		stream, eol := content.Bytes(), ""
+		if encryption != nil {
+			encrypted, err := encryption.encrypt(stream)
+			if err != nil {
+				return nil, err
+			}
+			stream, eol = encrypted, "\n"
+		}
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s%sendstream", len(stream), stream, eol))
+	}
+	trailer := ""
+	if encryption != nil {
+		object(encryption.dictionary())
+		trailer = fmt.Sprintf(" /Encrypt %d 0 R /ID [<%x> <%x>]", len(offsets), encryption.id, encryption.id)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R%s >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, trailer, xref)
+	return out.Bytes(), nil
+}
+
+func pdfNumber(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "" || s == "-" {
+		return "0"
+	}
+	return s
+}
+
+func pdfEscape(text []byte) string {
+	var b strings.Builder
+	for _, c := range text {
+		switch c {
+		case '\\', '(', ')':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n', '\r', '\t':
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// winAnsi converts the text to the WinAnsiEncoding of the standard fonts. Characters the
+// encoding does not have are replaced with a question mark.
+func winAnsi(text string) []byte {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r < 128 || (r >= 160 && r <= 255):
+			out = append(out, byte(r))
+		case r == '€':
+			out = append(out, 0x80)
+		case r == '‘', r == '’':
+			out = append(out, '\'')
+		case r == '“', r == '”':
+			out = append(out, '"')
+		case r == '–':
+			out = append(out, 0x96)
+		case r == '—':
+			out = append(out, 0x97)
+		case r == '•':
+			out = append(out, 0x95)
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+// Advance widths of the characters 32 to 126 in 1/1000 of the font size, from the Adobe font metrics.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
@@ -0,0 +1,205 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"unicode/utf8"
+)
+
+// pdfPermissions allows everything, printing and copying included, once the document is opened.
+const pdfPermissions int32 = -4
+
+// pdfMaxPasswordLength is how many bytes of a password the security handler uses.
+const pdfMaxPasswordLength = 127
+
+var errPDFPasswordEncoding = errors.New("PDF password must be UTF-8")
+var errPDFPasswordInvalid = errors.New("PDF password must have a 48-byte user entry and a 32-byte key")
+
+// PDFPassword is what encrypting documents for a user password needs: the U entry readers
+// check the entered password against, and the key that encrypts each document's file key into
+// the UE entry. Both are hashes of the password with random salts, so they can be kept instead
+// of the password, which they do not reveal; they do open the documents encrypted with them.
+type PDFPassword struct {
+	User []byte
+	Key  []byte
+}
+
+// NewPDFPassword derives the U entry and key of a user password (algorithm 8).
+func NewPDFPassword(password string) (PDFPassword, error) {
+	if !utf8.ValidString(password) {
+		return PDFPassword{}, errPDFPasswordEncoding
+	}
+	salts, err := pdfRandom(16)
+	if err != nil {
+		return PDFPassword{}, err
+	}
+	validation, err := pdfHash(pdfPassword(password), salts[:8], nil)
+	if err != nil {
+		return PDFPassword{}, err
+	}
+	key, err := pdfHash(pdfPassword(password), salts[8:], nil)
+	if err != nil {
+		return PDFPassword{}, err
+	}
+	return PDFPassword{User: append(validation, salts...), Key: key}, nil
+}
+
+// pdfEncryption is the standard security handler of PDF 2.0 (revision 6, AES-256), which
+// Acrobat X and later and every current PDF reader open. The file key and the owner password
+// are random, so only the user password opens the document.
+type pdfEncryption struct {
+	id             []byte
+	key            []byte
+	owner          []byte
+	ownerEncrypted []byte
+	user           []byte
+	userEncrypted  []byte
+	permsEncrypted []byte
+}
+
+func newPDFEncryption(password PDFPassword) (*pdfEncryption, error) {
+	if len(password.User) != 48 || len(password.Key) != 32 {
+		return nil, errPDFPasswordInvalid
+	}
+	random, err := pdfRandom(16 + 32 + 32 + 16)
+	if err != nil {
+		return nil, err
+	}
+	e := &pdfEncryption{id: random[:16], key: random[16:48], user: password.User}
+	ownerPassword, ownerSalts := random[48:80], random[80:]
+
+	if e.userEncrypted, err = pdfEncryptKey(password.Key, e.key); err != nil {
+		return nil, err
+	}
+	// algorithm 9: O and OE hash the U entry in
+	validation, err := pdfHash(ownerPassword, ownerSalts[:8], e.user)
+	if err != nil {
+		return nil, err
+	}
+	e.owner = append(validation, ownerSalts...)
+	ownerKey, err := pdfHash(ownerPassword, ownerSalts[8:], e.user)
+	if err != nil {
+		return nil, err
+	}
+	if e.ownerEncrypted, err = pdfEncryptKey(ownerKey, e.key); err != nil {
+		return nil, err
+	}
+	if e.permsEncrypted, err = pdfPerms(e.key, pdfPermissions); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// encrypt encrypts a string or stream with AES-256 in CBC mode, the random IV first.
+func (e *pdfEncryption) encrypt(data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(e.key)
+	if err != nil {
+		return nil, err
+	}
+	iv, err := pdfRandom(aes.BlockSize)
+	if err != nil {
+		return nil, err
+	}
+	padding := aes.BlockSize - len(data)%aes.BlockSize
+	out := append(iv, data...)
+	out = append(out, bytes.Repeat([]byte{byte(padding)}, padding)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(out[aes.BlockSize:], out[aes.BlockSize:])
+	return out, nil
+}
+
+func (e *pdfEncryption) dictionary() string {
+	return fmt.Sprintf("<< /Filter /Standard /V 5 /R 6 /Length 256 /CF << /StdCF << /AuthEvent /DocOpen /CFM /AESV3 /Length 32 >> >> "+
+		"/StmF /StdCF /StrF /StdCF /O <%x> /U <%x> /OE <%x> /UE <%x> /P %d /Perms <%x> >>",
+		e.owner, e.user, e.ownerEncrypted, e.userEncrypted, pdfPermissions, e.permsEncrypted)
+}
+
+// pdfPassword is the password as the handler uses it: UTF-8, at most 127 bytes.
+func pdfPassword(password string) []byte {
+	b := []byte(password)
+	if len(b) > pdfMaxPasswordLength {
+		b = b[:pdfMaxPasswordLength]
+		for !utf8.Valid(b) {
+			b = b[:len(b)-1]
+		}
+	}
+	return b
+}
+
+// pdfEncryptKey encrypts the file key with a password's key for the UE or OE entry.
+func pdfEncryptKey(passwordKey, fileKey []byte) ([]byte, error) {
+	block, err := aes.NewCipher(passwordKey)
+	if err != nil {
+		return nil, err
+	}
+	encrypted := make([]byte, len(fileKey))
+	cipher.NewCBCEncrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(encrypted, fileKey)
+	return encrypted, nil
+}
+
+// pdfHash is the password hash of revision 6 (algorithm 2.B of the specification).
+func pdfHash(password, salt, userKey []byte) ([]byte, error) {
+	sum := sha256.Sum256(append(append(append([]byte{}, password...), salt...), userKey...))
+	k := sum[:]
+	var e []byte
+	for round := 0; round < 64 || int(e[len(e)-1]) > round-32; round++ {
+		k1 := bytes.Repeat(append(append(append([]byte{}, password...), k...), userKey...), 64)
+		block, err := aes.NewCipher(k[:16])
+		if err != nil {
+			return nil, err
+		}
+		e = make([]byte, len(k1))
+		cipher.NewCBCEncrypter(block, k[16:32]).CryptBlocks(e, k1)
+
+		// the first 16 bytes as a number modulo 3 pick the next hash
+		remainder := 0
+		for _, b := range e[:16] {
+			remainder += int(b)
+		}
+		var h hash.Hash
+		switch remainder % 3 {
+		case 0:
+			h = sha256.New()
+		case 1:
+			h = sha512.New384()
+		default:
+			h = sha512.New()
+		}
+		h.Write(e)
+		k = h.Sum(nil)
+	}
+	return k[:32], nil
+}
+
+// pdfPerms is the Perms entry: the permissions encrypted with the file key, so readers can
+// tell they were not changed.
+func pdfPerms(fileKey []byte, permissions int32) ([]byte, error) {
+	perms := binary.LittleEndian.AppendUint32(nil, uint32(permissions))
+	perms = append(perms, 0xFF, 0xFF, 0xFF, 0xFF, 'T', 'a', 'd', 'b')
+	random, err := pdfRandom(4)
+	if err != nil {
+		return nil, err
+	}
+	perms = append(perms, random...)
+	block, err := aes.NewCipher(fileKey)
+	if err != nil {
+		return nil, err
+	}
+	block.Encrypt(perms, perms)
+	return perms, nil
+}
+
+func pdfRandom(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("encrypt PDF: %w", err)
+	}
+	return b, nil
+}
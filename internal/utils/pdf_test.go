@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestPDF(t *testing.T) {
+	doc := NewPDF("Statement (2025)")
+	doc.SetFont(PDFFontBold, 12)
+	doc.Text(40, 50, "Gaji (pokok) \\ Rp")
+	doc.SetFont(PDFFontRegular, 10)
+	doc.TextRight(555, 70, "1.000")
+	doc.Line(40, 80, 555, 80)
+	doc.AddPage()
+	doc.Text(40, 50, "Halaman 2 – akhir")
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatalf("not a PDF document:\n%s", out)
+	}
+	for _, want := range []string{
+		`(Gaji \(pokok\) \\ Rp) Tj`,
+		"/Count 2",
+		"/Title (Statement \\(2025\\))",
+		"(Halaman 2 \x96 akhir) Tj",
+	} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("document does not contain %q", want)
+		}
+	}
+
+	// every xref entry must point at the start of its object
+	match := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	if match == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(match[1]))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	if len(entries) != 9 {
+		t.Fatalf("expected 9 objects, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		if !bytes.HasPrefix(out[offset:], []byte(fmt.Sprintf("%d 0 obj", i+1))) {
+			t.Errorf("xref entry %d points at %q", i+1, out[offset:offset+10])
+		}
+	}
+}
+
+func TestPDFTextWidth(t *testing.T) {
+	doc := NewPDF("")
+	doc.SetFont(PDFFontRegular, 10)
+	if got := doc.TextWidth("1.000"); got != 25.02 {
+		t.Errorf("width = %v, want 25.02", got)
+	}
+}
+
+func TestPDFPassword(t *testing.T) {
+	doc := NewPDF("Payslip")
+	doc.Text(40, 50, "Net pay 9.450.000")
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	password, err := NewPDFPassword("rahasia-Budi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.SetPassword(password)
+	protected, err := doc.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(protected, []byte("(Net pay 9.450.000)")) || bytes.Contains(protected, []byte("(Payslip)")) {
+		t.Fatal("protected document shows its text")
+	}
+	match := regexp.MustCompile(`/V 5 /R 6 .*/CFM /AESV3 .*/O <([0-9a-f]{96})> /U <([0-9a-f]{96})> /OE <([0-9a-f]{64})> /UE <([0-9a-f]{64})> /P -4 /Perms <([0-9a-f]{32})>`).FindSubmatch(protected)
+	if match == nil || !bytes.HasPrefix(protected, []byte("%PDF-1.7\n")) || !bytes.Contains(protected, []byte("/Encrypt 8 0 R")) {
+		t.Fatalf("missing encryption dictionary:\n%s", protected)
+	}
+	user, userEncrypted, perms := unhex(t, match[2]), unhex(t, match[4]), unhex(t, match[5])
+	if !bytes.Equal(user, password.User) {
+		t.Error("the U entry must be the one derived from the password")
+	}
+
+	// readers hash the entered password with the validation salt and compare the U entry
+	if hash, _ := pdfHash([]byte("rahasia-Budi"), user[32:40], nil); !bytes.Equal(hash, user[:32]) {
+		t.Fatal("the password does not open the document")
+	}
+	if hash, _ := pdfHash([]byte("rahasia-budi"), user[32:40], nil); bytes.Equal(hash, user[:32]) {
+		t.Fatal("a wrong password opens the document")
+	}
+	// then decrypt the file key from UE with the hash of the key salt
+	intermediate, _ := pdfHash([]byte("rahasia-Budi"), user[40:48], nil)
+	key := decryptCBC(t, intermediate, make([]byte, 16), userEncrypted)
+	block, _ := aes.NewCipher(key)
+	block.Decrypt(perms, perms)
+	if !bytes.Equal(perms[:12], []byte{0xFC, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 'T', 'a', 'd', 'b'}) {
+		t.Errorf("decrypted permissions = %x", perms)
+	}
+
+	plain := regexp.MustCompile(`(?s)7 0 obj\n<< /Length (\d+) >>\nstream\n`)
+	offset := plain.FindSubmatchIndex(protected)
+	length, _ := strconv.Atoi(string(protected[offset[2]:offset[3]]))
+	stream := protected[offset[1] : offset[1]+length]
+	content := decryptCBC(t, key, stream[:16], stream[16:])
+	content = content[:len(content)-int(content[len(content)-1])]
+	if !bytes.Contains(out, content) || !bytes.Contains(content, []byte("(Net pay 9.450.000) Tj")) {
+		t.Errorf("decrypted content = %q", content)
+	}
+	if !bytes.Contains(protected[offset[1]+length:], []byte("\nendstream")) {
+		t.Error("stream length does not end at endstream")
+	}
+}
+
+func decryptCBC(t *testing.T, key, iv, data []byte) []byte {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, data)
+	return out
+}
+
+func unhex(t *testing.T, s []byte) []byte {
+	b, err := hex.DecodeString(string(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
@@ -1,13 +0,0 @@
-package utils
-
-import "time"
-
-func GetTotalWorkdays(start, end time.Time) int {
-	days := 0
-	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
-		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
-			days++
-		}
-	}
-	return days
-}